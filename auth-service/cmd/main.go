@@ -1,7 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -10,14 +16,20 @@ import (
 	"github.com/lera-guryan2222/fooorum/auth-service/internal/config"
 	grpchandler "github.com/lera-guryan2222/fooorum/auth-service/internal/delivery/grpc"
 	delivery "github.com/lera-guryan2222/fooorum/auth-service/internal/delivery/http"
+	authmetrics "github.com/lera-guryan2222/fooorum/auth-service/internal/metrics"
 	user "github.com/lera-guryan2222/fooorum/auth-service/internal/proto"
 	"github.com/lera-guryan2222/fooorum/auth-service/internal/repository"
 
 	"github.com/lera-guryan2222/fooorum/auth-service/internal/usecase"
-	logg "github.com/lera-guryan2222/logger"
+	"github.com/lera-guryan2222/fooorum/platform/health"
+	"github.com/lera-guryan2222/fooorum/platform/lifecycle"
+	logg "github.com/lera-guryan2222/fooorum/platform/logger"
+	"github.com/lera-guryan2222/fooorum/platform/metrics"
+	"github.com/lera-guryan2222/fooorum/platform/tracing"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // @title Auth Service API
@@ -56,50 +68,71 @@ func main() {
 		"log_level", cfg.Logger.LogLevel,
 	)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), "auth-service", cfg.Tracing)
+	if err != nil {
+		log.Fatalw("failed to initialize tracing", "error", err)
+	}
+
 	repo, err := repository.NewPostgres(cfg)
 	if err != nil {
 		log.Fatalw("failed to initialize repository", "error", err)
 	}
 
-	if err := repo.RunMigrations(); err != nil {
-		log.Fatalw("failed to run migrations", "error", err)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(repo, os.Args[2:], os.Stdout); err != nil {
+			log.Fatalw("migrate command failed", "error", err)
+		}
+		return
+	}
+
+	if cfg.Migrations.Enable {
+		if err := repo.RunMigrations(); err != nil {
+			log.Fatalw("failed to run migrations", "error", err)
+		}
 	}
 
+	promMetrics := metrics.New("auth")
+	promMetrics.RegisterDBStats(repo.DB(), cfg.Postgres.DBName)
+
 	authUC := usecase.NewAuthUseCase(
 		repo,
 		cfg.Auth.SecretKey,
 		cfg.Auth.AccessTokenDuration,
 		cfg.Auth.RefreshTokenDuration,
 	)
+	authUC = authmetrics.InstrumentAuthUseCase(promMetrics.Registerer(), "auth", authUC)
+
+	healthSrv := health.New(cfg.Health.CheckTimeout, user.UserService_ServiceDesc.ServiceName)
+	healthSrv.Register("postgres", health.DBChecker(repo))
 
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(logg.GRPCLoggingInterceptor(log)),
+		grpc.ChainUnaryInterceptor(
+			logg.GRPCLoggingInterceptor(log),
+			promMetrics.UnaryServerInterceptor(),
+		),
+		tracing.ServerOption(),
 	)
 	user.RegisterUserServiceServer(
 		grpcServer,
 		grpchandler.NewUserServer(repo),
 	)
+	healthpb.RegisterHealthServer(grpcServer, healthSrv.GRPCServer())
 
-	go func() {
-		grpcPort := cfg.GRPC.Port
-		if grpcPort == "" {
-			grpcPort = "50051"
-		}
-
-		lis, err := net.Listen("tcp", ":"+grpcPort)
-		if err != nil {
-			log.Fatalw("failed to listen gRPC", "port", grpcPort, "error", err)
-		}
+	grpcPort := cfg.GRPC.Port
+	if grpcPort == "" {
+		grpcPort = "50051"
+	}
 
-		log.Infow("gRPC server started", "port", grpcPort)
-		if err := grpcServer.Serve(lis); err != nil {
-			log.Fatalw("gRPC server failed", "error", err)
-		}
-	}()
+	grpcLis, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.Fatalw("failed to listen gRPC", "port", grpcPort, "error", err)
+	}
 
 	router := gin.New()
 	router.Use(
+		tracing.GinMiddleware("auth-service"),
 		logg.GinLogger(log),
+		promMetrics.GinMiddleware(),
 		gin.Recovery(),
 	)
 
@@ -116,18 +149,17 @@ func main() {
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
+	router.GET("/livez", healthSrv.LivenessHandler())
+	router.GET("/readyz", healthSrv.ReadinessHandler())
+	router.GET("/health", healthSrv.ReadinessHandler())
+	router.GET("/metrics", promMetrics.Handler())
 
 	auth := router.Group("/auth")
 	{
 		auth.POST("/register", authHandler.Register)
 		auth.POST("/login", authHandler.Login)
 		auth.GET("/validate", authHandler.ValidateToken)
-		auth.GET("/health", func(c *gin.Context) {
-			c.JSON(200, gin.H{"status": "auth ok"})
-		})
+		auth.GET("/health", healthSrv.ReadinessHandler())
 
 		protected := auth.Group("")
 		protected.Use(delivery.AuthMiddleware(cfg))
@@ -137,8 +169,48 @@ func main() {
 		}
 	}
 
-	log.Infow("HTTP server starting", "port", cfg.Server.Port)
-	if err := router.Run(":" + cfg.Server.Port); err != nil {
-		log.Fatalw("HTTP server failed", "error", err)
+	httpSrv := &http.Server{
+		Addr:              ":" + cfg.Server.Port,
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	app := lifecycle.New(log, cfg.Server.ShutdownTimeout)
+	app.Go("health_watch", func(ctx context.Context) error {
+		healthSrv.Watch(ctx, cfg.Health.CheckInterval)
+		return nil
+	})
+	app.Go("grpc_server", func(context.Context) error {
+		log.Infow("gRPC server started", "port", grpcPort)
+		return grpcServer.Serve(grpcLis)
+	})
+	app.Go("http_server", func(context.Context) error {
+		log.Infow("HTTP server starting", "port", cfg.Server.Port)
+		if err := httpSrv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+
+	// Остановка выполняется в обратном порядке: снимаем readiness,
+	// останавливаем HTTP и gRPC, дожидаемся фоновых задач, затем закрываем
+	// пул соединений с БД и в самом конце отправляем оставшиеся спаны
+	app.OnStop("tracing", shutdownTracing)
+	app.OnStop("postgres", func(context.Context) error { return repo.Close() })
+	app.OnStop("background_tasks", app.StopTasks)
+	app.OnStop("grpc_server", lifecycle.StopGRPC(grpcServer))
+	app.OnStop("http_server", httpSrv.Shutdown)
+	app.OnStop("health", func(context.Context) error {
+		healthSrv.Shutdown()
+		return nil
+	})
+
+	sigCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := app.Wait(sigCtx); err != nil {
+		log.Errorw("shutdown finished with errors", "error", err)
+		return
 	}
+	log.Info("auth service stopped")
 }
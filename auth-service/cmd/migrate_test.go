@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/auth-service/internal/mocks"
+	"github.com/lera-guryan2222/fooorum/auth-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunMigrate(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		mockSetup   func(*mocks.MockCompositeRepository)
+		expectedOut string
+		expectedErr string
+	}{
+		{
+			name: "Up",
+			args: []string{"up"},
+			mockSetup: func(m *mocks.MockCompositeRepository) {
+				m.On("RunMigrations").Return(nil)
+			},
+			expectedOut: "migrations applied\n",
+		},
+		{
+			name: "DownDefaultStep",
+			args: []string{"down"},
+			mockSetup: func(m *mocks.MockCompositeRepository) {
+				m.On("RollbackMigrations", 1).Return(nil)
+			},
+			expectedOut: "rolled back 1 migration(s)\n",
+		},
+		{
+			name: "DownSteps",
+			args: []string{"down", "2"},
+			mockSetup: func(m *mocks.MockCompositeRepository) {
+				m.On("RollbackMigrations", 2).Return(nil)
+			},
+			expectedOut: "rolled back 2 migration(s)\n",
+		},
+		{
+			name:        "DownInvalidSteps",
+			args:        []string{"down", "zero"},
+			mockSetup:   func(m *mocks.MockCompositeRepository) {},
+			expectedErr: `invalid number of steps "zero"`,
+		},
+		{
+			name: "Status",
+			args: []string{"status"},
+			mockSetup: func(m *mocks.MockCompositeRepository) {
+				m.On("MigrationStatus").Return(&repository.MigrationStatus{
+					Version:   1,
+					Applied:   true,
+					Available: []uint{1, 2},
+				}, nil)
+			},
+			expectedOut: "current version: 1 (dirty: false)\n  001  applied\n  002  pending\n",
+		},
+		{
+			name: "Force",
+			args: []string{"force", "2"},
+			mockSetup: func(m *mocks.MockCompositeRepository) {
+				m.On("ForceMigrationVersion", 2).Return(nil)
+			},
+			expectedOut: "forced version 2\n",
+		},
+		{
+			name: "UpError",
+			args: []string{"up"},
+			mockSetup: func(m *mocks.MockCompositeRepository) {
+				m.On("RunMigrations").Return(errors.New("dirty database"))
+			},
+			expectedErr: "dirty database",
+		},
+		{
+			name:        "UnknownCommand",
+			args:        []string{"sideways"},
+			mockSetup:   func(m *mocks.MockCompositeRepository) {},
+			expectedErr: `unknown migrate command "sideways"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockCompositeRepository)
+			tt.mockSetup(repo)
+
+			var out bytes.Buffer
+			err := runMigrate(repo, tt.args, &out)
+
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOut, out.String())
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
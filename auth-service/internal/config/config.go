@@ -3,7 +3,8 @@ package config
 import (
 	"time"
 
-	"github.com/lera-guryan2222/logger"
+	"github.com/lera-guryan2222/fooorum/platform/logger"
+	"github.com/lera-guryan2222/fooorum/platform/tracing"
 )
 
 // PostgresConfig содержит конфигурацию для подключения к PostgreSQL
@@ -20,7 +21,8 @@ type PostgresConfig struct {
 type Config struct {
 	Postgres PostgresConfig
 	Server   struct {
-		Port string `yaml:"port"`
+		Port            string        `yaml:"port"`
+		ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
 	} `yaml:"server"`
 
 	Auth struct {
@@ -35,6 +37,11 @@ type Config struct {
 	GRPC   struct {
 		Port string `yaml:"port"`
 	} `yaml:"grpc"`
+	Health struct {
+		CheckTimeout  time.Duration `yaml:"check_timeout"`
+		CheckInterval time.Duration `yaml:"check_interval"`
+	} `yaml:"health"`
+	Tracing tracing.Config `yaml:"tracing"`
 }
 
 func Load() *Config {
@@ -50,6 +57,7 @@ func Load() *Config {
 
 	// Server
 	cfg.Server.Port = "8080"
+	cfg.Server.ShutdownTimeout = 15 * time.Second
 
 	// Auth
 	cfg.Auth.AccessTokenDuration = 24 * time.Hour
@@ -67,6 +75,15 @@ func Load() *Config {
 	// GRPC
 	cfg.GRPC.Port = "50051"
 
+	// Health
+	cfg.Health.CheckTimeout = 2 * time.Second
+	cfg.Health.CheckInterval = 10 * time.Second
+
+	// Tracing
+	cfg.Tracing.Exporter = tracing.ExporterNone
+	cfg.Tracing.Endpoint = "localhost:4317"
+	cfg.Tracing.SampleRatio = 1
+
 	cfg.Migrations.Enable = false
 
 	return cfg
@@ -0,0 +1,68 @@
+// Package metrics exposes the authentication outcomes of auth-service. The HTTP,
+// gRPC and database metrics shared by the services live in platform/metrics.
+package metrics
+
+import (
+	"context"
+
+	"github.com/lera-guryan2222/fooorum/auth-service/internal/usecase"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+// authUseCase counts logins and token refreshes around the real use case.
+type authUseCase struct {
+	usecase.AuthUseCase
+
+	logins    *prometheus.CounterVec
+	refreshes *prometheus.CounterVec
+}
+
+// InstrumentAuthUseCase wraps uc so that every Login and RefreshTokens call
+// is counted by result and registers the counters with reg. Failed logins are
+// exported as result="failure".
+func InstrumentAuthUseCase(reg prometheus.Registerer, namespace string, uc usecase.AuthUseCase) usecase.AuthUseCase {
+	a := &authUseCase{
+		AuthUseCase: uc,
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_total",
+			Help:      "Login attempts by result.",
+		}, []string{"result"}),
+		refreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "token_refreshes_total",
+			Help:      "Token refresh attempts by result.",
+		}, []string{"result"}),
+	}
+	// Pre-create both series so that rate() works before the first failure.
+	for _, result := range []string{resultSuccess, resultFailure} {
+		a.logins.WithLabelValues(result)
+		a.refreshes.WithLabelValues(result)
+	}
+	reg.MustRegister(a.logins, a.refreshes)
+	return a
+}
+
+func (a *authUseCase) Login(ctx context.Context, email, password string) (*usecase.AuthResponse, error) {
+	resp, err := a.AuthUseCase.Login(ctx, email, password)
+	a.logins.WithLabelValues(result(err)).Inc()
+	return resp, err
+}
+
+func (a *authUseCase) RefreshTokens(ctx context.Context, refreshToken string) (*usecase.AuthResponse, error) {
+	resp, err := a.AuthUseCase.RefreshTokens(ctx, refreshToken)
+	a.refreshes.WithLabelValues(result(err)).Inc()
+	return resp, err
+}
+
+func result(err error) string {
+	if err != nil {
+		return resultFailure
+	}
+	return resultSuccess
+}
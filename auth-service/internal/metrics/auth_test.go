@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/auth-service/internal/mocks"
+	"github.com/lera-guryan2222/fooorum/auth-service/internal/usecase"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func scrape(t *testing.T, reg *prometheus.Registry) string {
+	w := httptest.NewRecorder()
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestInstrumentAuthUseCase(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	mockUC := new(mocks.MockAuthUseCase)
+	uc := InstrumentAuthUseCase(reg, "auth", mockUC)
+
+	mockUC.On("Login", mock.Anything, "test@example.com", "password").Return(&usecase.AuthResponse{}, nil)
+	mockUC.On("Login", mock.Anything, "test@example.com", "wrong").Return(nil, errors.New("неверный пароль"))
+	mockUC.On("RefreshTokens", mock.Anything, "expired").Return(nil, errors.New("refresh token expired"))
+	mockUC.On("Logout", mock.Anything, "token").Return(nil)
+
+	_, err := uc.Login(context.Background(), "test@example.com", "password")
+	assert.NoError(t, err)
+	_, err = uc.Login(context.Background(), "test@example.com", "wrong")
+	assert.Error(t, err)
+	_, err = uc.RefreshTokens(context.Background(), "expired")
+	assert.Error(t, err)
+	assert.NoError(t, uc.Logout(context.Background(), "token"))
+
+	body := scrape(t, reg)
+	assert.Contains(t, body, `auth_logins_total{result="success"} 1`)
+	assert.Contains(t, body, `auth_logins_total{result="failure"} 1`)
+	assert.Contains(t, body, `auth_token_refreshes_total{result="failure"} 1`)
+	assert.Contains(t, body, `auth_token_refreshes_total{result="success"} 0`)
+	mockUC.AssertExpectations(t)
+}
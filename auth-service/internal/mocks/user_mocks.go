@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/lera-guryan2222/fooorum/auth-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/auth-service/internal/repository"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -76,3 +77,21 @@ func (m *MockCompositeRepository) RunMigrations() error {
 	args := m.Called()
 	return args.Error(0)
 }
+
+func (m *MockCompositeRepository) RollbackMigrations(steps int) error {
+	args := m.Called(steps)
+	return args.Error(0)
+}
+
+func (m *MockCompositeRepository) MigrationStatus() (*repository.MigrationStatus, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.MigrationStatus), args.Error(1)
+}
+
+func (m *MockCompositeRepository) ForceMigrationVersion(version int) error {
+	args := m.Called(version)
+	return args.Error(0)
+}
@@ -4,14 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
-	"os"
 
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/lera-guryan2222/fooorum/auth-service/internal/config"
 	"github.com/lera-guryan2222/fooorum/auth-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/platform/tracing"
 	_ "github.com/lib/pq"
 )
 
@@ -120,7 +116,7 @@ func NewPostgres(cfg *config.Config) (*Postgres, error) {
 		cfg.Postgres.SSLMode,
 	)
 
-	db, err := sql.Open("postgres", connStr)
+	db, err := tracing.OpenDB("postgres", connStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open db: %w", err)
 	}
@@ -132,49 +128,19 @@ func NewPostgres(cfg *config.Config) (*Postgres, error) {
 	return &Postgres{db: db, cfg: cfg}, nil
 }
 
-func (p *Postgres) RunMigrations() error {
-	// Абсолютный путь к директории с миграциями
-	migrationsDir := "C:\\Users\\usr09\\OneDrive\\Рабочий стол\\forumforum\\auth-service\\migrations"
-
-	// Проверка существования директории с миграциями
-	entries, err := os.ReadDir(migrationsDir)
-	if err != nil {
-		return fmt.Errorf("cannot read migrations dir: %w", err)
-	}
-	log.Printf("Found %d migration files", len(entries))
-
-	// Инициализация миграций
-	d, err := iofs.New(os.DirFS(migrationsDir), ".")
-	if err != nil {
-		return fmt.Errorf("failed to create iofs: %w", err)
-	}
-
-	driver, err := postgres.WithInstance(p.db, &postgres.Config{})
-	if err != nil {
-		return fmt.Errorf("failed to create postgres driver: %w", err)
-	}
-
-	m, err := migrate.NewWithInstance(
-		"iofs",
-		d,
-		"postgres",
-		driver,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
-	}
-
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to apply migrations: %w", err)
-	}
+// PingContext проверяет доступность базы данных
+func (p *Postgres) PingContext(ctx context.Context) error {
+	return p.db.PingContext(ctx)
+}
 
-	version, dirty, err := m.Version()
-	if err != nil && err != migrate.ErrNilVersion {
-		return fmt.Errorf("failed to get migration version: %w", err)
-	}
+// DB возвращает пул соединений для экспорта метрик
+func (p *Postgres) DB() *sql.DB {
+	return p.db
+}
 
-	log.Printf("Migrations applied. Current version: %d, dirty: %v", version, dirty)
-	return nil
+// Close закрывает пул соединений с базой данных
+func (p *Postgres) Close() error {
+	return p.db.Close()
 }
 
 func (p *Postgres) CreateUser(ctx context.Context, user *entity.User) error {
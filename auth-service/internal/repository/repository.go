@@ -26,6 +26,9 @@ type TokenRepository interface {
 // MigrationManager отвечает за управление миграциями
 type MigrationManager interface {
 	RunMigrations() error
+	RollbackMigrations(steps int) error
+	MigrationStatus() (*MigrationStatus, error)
+	ForceMigrationVersion(version int) error
 }
 
 // CompositeRepository объединяет все репозитории
@@ -8,6 +8,7 @@ import (
 
 	"github.com/lera-guryan2222/fooorum/auth-service/internal/config"
 	"github.com/lera-guryan2222/fooorum/auth-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/auth-service/migrations"
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
@@ -374,3 +375,9 @@ func TestRunMigrations(t *testing.T) {
 	err = repo.RunMigrations()
 	assert.NoError(t, err)
 }
+
+func TestAvailableMigrations(t *testing.T) {
+	versions, err := availableMigrations(migrations.FS)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 2, 3}, versions)
+}
@@ -1,5 +1,2 @@
-DROP TABLE IF EXISTS users;
 DROP TABLE IF EXISTS refresh_tokens;
-DROP TABLE IF EXISTS posts;
-DROP TABLE IF EXISTS comments;
-DROP TABLE IF EXISTS chat_messages; 
\ No newline at end of file
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,2 @@
+-- Таблицы форума принадлежат forum-service и при откате auth-service не удаляются.
+SELECT 1;
@@ -0,0 +1,4 @@
+-- Таблицы posts, comments и chat_messages раньше создавались миграцией 001,
+-- теперь ими владеет forum-service. Схема не меняется: существующие таблицы
+-- остаются с данными, а миграции auth-service больше их не создают и не удаляют.
+SELECT 1;
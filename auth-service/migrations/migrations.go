@@ -0,0 +1,11 @@
+// Package migrations содержит SQL-миграции схемы auth-service,
+// встроенные в бинарник через embed.FS.
+package migrations
+
+import "embed"
+
+// FS содержит файлы миграций в формате golang-migrate
+// (<версия>_<имя>.up.sql / <версия>_<имя>.down.sql).
+//
+//go:embed *.sql
+var FS embed.FS
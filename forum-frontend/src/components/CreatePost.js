@@ -7,6 +7,9 @@ import axios from 'axios';
 const CreatePost = ({ postToEdit, onEditComplete }) => {
   const [title, setTitle] = useState('');
   const [content, setContent] = useState('');
+  const [categories, setCategories] = useState([]);
+  const [categoryId, setCategoryId] = useState('');
+  const [tags, setTags] = useState('');
   const [error, setError] = useState('');
   const [isLoading, setIsLoading] = useState(false);
   const navigate = useNavigate();
@@ -14,10 +17,28 @@ const CreatePost = ({ postToEdit, onEditComplete }) => {
 
   const isEditMode = Boolean(postToEdit);
 
+  useEffect(() => {
+    if (isEditMode) return;
+    // Flatten the category tree, indenting subcategories by depth
+    const flatten = (nodes, depth = 0) => nodes.flatMap(node => [
+      { id: node.id, name: `${'\u00A0\u00A0'.repeat(depth)}${node.name}`, readOnly: node.read_only },
+      ...flatten(node.children || [], depth + 1)
+    ]);
+    axios.get('http://localhost:8081/categories')
+      .then(response => {
+        const flat = flatten(response.data || []);
+        setCategories(flat);
+        const firstWritable = flat.find(category => !category.readOnly) || flat[0];
+        if (firstWritable) setCategoryId(String(firstWritable.id));
+      })
+      .catch(err => console.error('[DEBUG CreatePost] Failed to load categories:', err));
+  }, [isEditMode]);
+
   useEffect(() => {
     if (isEditMode && postToEdit) {
       setTitle(postToEdit.title || '');
       setContent(postToEdit.content || '');
+      setTags((postToEdit.tags || []).join(', '));
     }
   }, [isEditMode, postToEdit]);
 
@@ -37,13 +58,16 @@ const CreatePost = ({ postToEdit, onEditComplete }) => {
       if (token) {
         console.log('[DEBUG CreatePost] Token snippet (first 10, last 10):', token.substring(0, 10) + '...' + token.substring(token.length - 10));
       }
-      const postData = { title, content };
+      const tagList = tags.split(',').map(tag => tag.trim()).filter(Boolean);
+      const postData = isEditMode
+        ? { title, content, tags: tagList }
+        : { title, content, tags: tagList, category_id: Number(categoryId) };
 
       if (isEditMode) {
         console.log(`[DEBUG CreatePost] Attempting to PUT /posts/${postToEdit.id} with token.`);
         const response = await axios.put(`http://localhost:8081/posts/${postToEdit.id}`, 
           postData,
-          { headers: { 'Authorization': `Bearer ${token}` } }
+          { headers: { 'Authorization': `Bearer ${token}`, 'If-Match': `"${postToEdit.version}"` } }
         );
         if (onEditComplete) {
           const updatedPostData = { 
@@ -116,6 +140,42 @@ const CreatePost = ({ postToEdit, onEditComplete }) => {
       )}
 
       <form onSubmit={handleSubmit}>
+        {!isEditMode && (
+          <div style={{ marginBottom: theme.spacing.lg }}>
+            <label
+              htmlFor="category"
+              style={{
+                display: 'block',
+                marginBottom: theme.spacing.xs,
+                color: theme.colors.text.secondary,
+                fontSize: theme.typography.sizes.sm
+              }}
+            >
+              Category
+            </label>
+            <select
+              id="category"
+              value={categoryId}
+              onChange={(e) => setCategoryId(e.target.value)}
+              required
+              style={{
+                width: '100%',
+                padding: theme.spacing.md,
+                borderRadius: theme.borderRadius.md,
+                border: `1px solid ${theme.colors.text.light}`,
+                fontSize: theme.typography.sizes.base,
+                outline: 'none'
+              }}
+            >
+              {categories.map(category => (
+                <option key={category.id} value={category.id}>
+                  {category.name}{category.readOnly ? ' (read-only)' : ''}
+                </option>
+              ))}
+            </select>
+          </div>
+        )}
+
         <div style={{ marginBottom: theme.spacing.lg }}>
           <label
             htmlFor="title"
@@ -187,6 +247,35 @@ const CreatePost = ({ postToEdit, onEditComplete }) => {
           />
         </div>
 
+        <div style={{ marginBottom: theme.spacing.xl }}>
+          <label
+            htmlFor="tags"
+            style={{
+              display: 'block',
+              marginBottom: theme.spacing.xs,
+              color: theme.colors.text.secondary,
+              fontSize: theme.typography.sizes.sm
+            }}
+          >
+            Tags (comma separated, up to 5)
+          </label>
+          <input
+            id="tags"
+            type="text"
+            value={tags}
+            onChange={(e) => setTags(e.target.value)}
+            placeholder="go, concurrency"
+            style={{
+              width: '100%',
+              padding: theme.spacing.md,
+              borderRadius: theme.borderRadius.md,
+              border: `1px solid ${theme.colors.text.light}`,
+              fontSize: theme.typography.sizes.base,
+              outline: 'none'
+            }}
+          />
+        </div>
+
         <div style={{
           display: 'flex',
           gap: theme.spacing.md,
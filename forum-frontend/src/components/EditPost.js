@@ -8,6 +8,7 @@ const EditPost = () => {
   const { id } = useParams();
   const [title, setTitle] = useState('');
   const [content, setContent] = useState('');
+  const [version, setVersion] = useState(null);
   const [error, setError] = useState('');
   const [isLoading, setIsLoading] = useState(true);
   const navigate = useNavigate();
@@ -22,7 +23,7 @@ const EditPost = () => {
         });
         
         // Проверяем права на редактирование
-        const post = response.data;
+        const post = response.data.post;
         const canEdit = currentUser?.role === 'moderator' || 
                        currentUser?.role === 'admin' || 
                        post.author_id === currentUser?.userId;
@@ -34,6 +35,7 @@ const EditPost = () => {
 
         setTitle(post.title);
         setContent(post.content);
+        setVersion(post.version);
       } catch (err) {
         setError(err.response?.data?.error || 'Failed to load post. Please try again later.');
       } finally {
@@ -63,10 +65,20 @@ const EditPost = () => {
       const token = localStorage.getItem('access_token');
       await axios.put(`http://localhost:8081/posts/${id}`, 
         { title, content },
-        { headers: { 'Authorization': `Bearer ${token}` } }
+        { headers: { 'Authorization': `Bearer ${token}`, 'If-Match': `"${version}"` } }
       );
       navigate('/');
     } catch (err) {
+      if (err.response?.status === 412) {
+        // Пост успел измениться: показываем актуальную версию, правку нужно повторить
+        const current = err.response.data.post;
+        setTitle(current.title);
+        setContent(current.content);
+        setVersion(current.version);
+        setError('The post was changed by someone else. Review the current version and try again.');
+        setIsLoading(false);
+        return;
+      }
       setError(err.response?.data?.error || 'Failed to update post. Please try again.');
       setIsLoading(false);
     }
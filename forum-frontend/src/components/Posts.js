@@ -53,16 +53,18 @@ const Posts = () => {
         headers: token ? { Authorization: `Bearer ${token}` } : {}
       });
 
-      if (!Array.isArray(response.data)) {
-        console.error("[DEBUG] Ответ от сервера не является массивом:", response.data);
+      // Сервер отдает страницу: { posts: [...], next_cursor: "..." }
+      const pagePosts = response.data && response.data.posts;
+      if (!Array.isArray(pagePosts)) {
+        console.error("[DEBUG] Ответ от сервера не содержит массив постов:", response.data);
         setError("Некорректный формат данных от сервера");
         return;
       }
 
-      console.log("[DEBUG] Получено постов:", response.data.length);
+      console.log("[DEBUG] Получено постов:", pagePosts.length);
 
       // Создаем базовые объекты постов
-      const postsWithoutComments = response.data
+      const postsWithoutComments = pagePosts
         .filter(post => post && typeof post === 'object')
         .map(post => ({
           id: post.id,
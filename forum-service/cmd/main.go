@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -14,15 +16,23 @@ import (
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/config"
 	grpcDelivery "github.com/lera-guryan2222/fooorum/forum-service/internal/delivery/grpcserver"
 	delivery "github.com/lera-guryan2222/fooorum/forum-service/internal/delivery/http"
+	forummetrics "github.com/lera-guryan2222/fooorum/forum-service/internal/metrics"
 	forumPostProto "github.com/lera-guryan2222/fooorum/forum-service/internal/proto/post"
+	searchProto "github.com/lera-guryan2222/fooorum/forum-service/internal/proto/search"
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/repository"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/storage"
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
-	logg "github.com/lera-guryan2222/logger"
+	"github.com/lera-guryan2222/fooorum/platform/health"
+	"github.com/lera-guryan2222/fooorum/platform/lifecycle"
+	logg "github.com/lera-guryan2222/fooorum/platform/logger"
+	"github.com/lera-guryan2222/fooorum/platform/metrics"
+	"github.com/lera-guryan2222/fooorum/platform/tracing"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // @title Forum Service API
@@ -62,18 +72,89 @@ func main() {
 	}
 	gin.DisableConsoleColor()
 
+	// Initialize tracing before anything that opens connections
+	shutdownTracing, err := tracing.Setup(ctx, "forum-service", cfg.Tracing)
+	if err != nil {
+		log.Fatalf("failed to initialize tracing: %v", err)
+	}
+
 	// Initialize repository
 	repo, err := repository.NewPostgres(cfg)
 	if err != nil {
 		log.Fatalf("failed to initialize repository: %v", err)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(repo, os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("migrate command failed: %v", err)
+		}
+		return
+	}
+
+	if cfg.Migrations.Enable {
+		if err := repo.RunMigrations(); err != nil {
+			log.Fatalf("failed to run migrations: %v", err)
+		}
+	}
+
+	// Initialize metrics
+	promMetrics := metrics.New("forum")
+	promMetrics.RegisterDBStats(repo.DB(), cfg.Postgres.DBName)
+
 	// Initialize use cases
-	postUC := usecase.NewPostUseCase(repo, repo)
-	commentUC := usecase.NewCommentUseCase(repo)
+	postUC := usecase.NewPostUseCase(repo, repo, repo)
+	commentUC := usecase.NewCommentUseCase(repo, repo)
 	authUC := usecase.NewAuthUseCase(*repo, cfg)
 	chatUC := usecase.NewChatUseCase(repo, authUC)
 	userUC := usecase.NewUserUseCase(repo)
+	searchUC := usecase.NewSearchUseCase(repo)
+	categoryUC := usecase.NewCategoryUseCase(repo, repo)
+	tagUC := usecase.NewTagUseCase(repo, repo)
+	revisionUC := usecase.NewPostRevisionUseCase(repo, repo, repo)
+	voteUC := usecase.NewVoteUseCase(repo)
+	rankingUC := usecase.NewPostRankingUseCase(repo)
+	notificationHub := usecase.NewNotificationHub()
+	notificationUC := usecase.NewNotificationUseCase(repo, repo, notificationHub)
+	commentUC.SetNotifier(notificationUC)
+	mentionUC := usecase.NewMentionUseCase(repo, repo, notificationHub)
+	postUC.SetMentionTracker(mentionUC)
+	commentUC.SetMentionTracker(mentionUC)
+	revisionUC.SetMentionTracker(mentionUC)
+	chatUC.SetMentionTracker(mentionUC)
+	moderationUC := usecase.NewModerationUseCase(repo, repo, notificationHub)
+	chatUC.SetBanChecker(moderationUC)
+	trashUC := usecase.NewTrashUseCase(repo, repo, cfg.Trash.Retention)
+	viewUC := usecase.NewViewUseCase(repo, usecase.ViewConfig{
+		DedupWindow: cfg.Views.DedupWindow,
+		Retention:   cfg.Views.Retention,
+		MaxViewers:  cfg.Views.MaxViewers,
+	})
+	feedUC := usecase.NewFeedUseCase(repo, repo, repo, repo, usecase.FeedConfig{
+		Title: cfg.Feeds.Title,
+		Items: cfg.Feeds.Items,
+	})
+	chatUC.SetMetrics(forummetrics.NewChatMetrics(promMetrics.Registerer(), "forum", chatUC.ConnectedClients))
+
+	// Initialize attachment storage
+	var blobStore storage.BlobStore
+	switch cfg.Uploads.Backend {
+	case "s3":
+		blobStore = storage.NewS3Store(cfg.Uploads.S3, nil)
+	default:
+		blobStore, err = storage.NewLocalStore(cfg.Uploads.Dir)
+		if err != nil {
+			log.Fatalf("failed to initialize upload storage: %v", err)
+		}
+	}
+	attachmentUC := usecase.NewAttachmentUseCase(repo, blobStore,
+		storage.NewURLSigner([]byte(cfg.Uploads.SigningKey)), usecase.AttachmentConfig{
+			MaxSize:   cfg.Uploads.MaxSize,
+			URLTTL:    cfg.Uploads.URLTTL,
+			OrphanTTL: cfg.Uploads.OrphanTTL,
+		})
+	postUC.SetAttachments(attachmentUC)
+	commentUC.SetAttachments(attachmentUC)
+	chatUC.SetAttachments(attachmentUC)
 
 	// Initialize gRPC connection to auth-service
 	authAddr := os.Getenv("AUTH_SERVICE_GRPC_ADDR")
@@ -87,51 +168,77 @@ func main() {
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithBlock(),
 		grpc.WithTimeout(5*time.Second),
+		grpc.WithUnaryInterceptor(promMetrics.UnaryClientInterceptor()),
+		tracing.DialOption(),
 	)
 	if err != nil {
 		log.Fatalf("failed to connect to auth service: %v", err)
 	}
-	defer authConn.Close()
+
+	// Initialize health checks
+	healthSrv := health.New(cfg.Health.CheckTimeout, forumPostProto.PostService_ServiceDesc.ServiceName)
+	healthSrv.Register("postgres", health.DBChecker(repo))
+	healthSrv.Register("auth_service", health.GRPCChecker(authConn, ""))
+	healthSrv.Register("chat_hub", health.CheckerFunc(chatUC.HubHealth))
 
 	// Initialize gRPC server
-	grpcSrv := grpc.NewServer()
+	grpcSrv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			logg.GRPCLoggingInterceptor(log),
+			promMetrics.UnaryServerInterceptor(),
+		),
+		tracing.ServerOption(),
+	)
 	forumPostProto.RegisterPostServiceServer(
 		grpcSrv,
 		grpcDelivery.NewPostServer(postUC, authConn),
 	)
+	searchProto.RegisterSearchServiceServer(grpcSrv, grpcDelivery.NewSearchServer(searchUC))
+	healthpb.RegisterHealthServer(grpcSrv, healthSrv.GRPCServer())
 
-	// Start gRPC server in goroutine
-	go func() {
-		lis, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
-		if err != nil {
-			log.Fatalf("failed to listen: %v", err)
-		}
-
-		if err := grpcSrv.Serve(lis); err != nil {
-			log.Fatalf("failed to serve gRPC: %v", err)
-		}
-	}()
+	grpcLis, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
 
 	// Initialize HTTP server
 	router := gin.New()
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
+	router.Use(tracing.GinMiddleware("forum-service"))
 	router.Use(logg.GinLogger(log))
+	router.Use(promMetrics.GinMiddleware())
 	router.Use(gin.Recovery())
 	// Add Swagger route
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Health probes
+	router.GET("/livez", healthSrv.LivenessHandler())
+	router.GET("/readyz", healthSrv.ReadinessHandler())
+	router.GET("/metrics", promMetrics.Handler())
+
 	// Initialize handlers
-	postHandler := delivery.NewPostHandler(postUC, commentUC, userUC)
-	commentHandler := delivery.NewCommentHandler(commentUC)
+	postHandler := delivery.NewPostHandler(postUC, commentUC, userUC, voteUC, viewUC)
+	commentHandler := delivery.NewCommentHandler(commentUC, voteUC)
 	authHandler := delivery.NewAuthHandler(authUC)
 	chatHandler := delivery.NewChatHandler(chatUC)
+	searchHandler := delivery.NewSearchHandler(searchUC)
+	categoryHandler := delivery.NewCategoryHandler(categoryUC, postUC, voteUC)
+	tagHandler := delivery.NewTagHandler(tagUC, postUC, voteUC)
+	revisionHandler := delivery.NewPostRevisionHandler(revisionUC)
+	voteHandler := delivery.NewVoteHandler(voteUC)
+	notificationHandler := delivery.NewNotificationHandler(notificationUC)
+	userHandler := delivery.NewUserHandler(mentionUC)
+	attachmentHandler := delivery.NewAttachmentHandler(attachmentUC)
+	moderationHandler := delivery.NewModerationHandler(moderationUC)
+	trashHandler := delivery.NewTrashHandler(trashUC)
+	feedHandler := delivery.NewFeedHandler(feedUC, cfg.Feeds.BaseURL)
 
 	// Setup routes
 
@@ -155,19 +262,123 @@ func main() {
 		}
 	}
 
-	// Posts routes
+	// Search routes
+	router.GET("/search", searchHandler.Search)
+
+	// Category routes
+	categories := router.Group("/categories")
+	categories.Use(delivery.OptionalAuthMiddleware(cfg))
+	{
+		categories.GET("", categoryHandler.ListCategories)
+		categories.GET("/:slug/posts", categoryHandler.GetCategoryPosts)
+	}
+
+	// Tag routes
+	tags := router.Group("/tags")
+	tags.Use(delivery.OptionalAuthMiddleware(cfg))
+	{
+		tags.GET("", tagHandler.SuggestTags)
+		tags.GET("/:name/posts", tagHandler.GetTagPosts)
+	}
+
+	// Feed routes
+	feeds := router.Group("/feeds")
+	{
+		feeds.GET("/posts.atom", feedHandler.SiteFeed)
+		feeds.GET("/posts.rss", feedHandler.SiteFeed)
+		feeds.GET("/categories/:slug/posts.atom", feedHandler.CategoryFeed)
+		feeds.GET("/categories/:slug/posts.rss", feedHandler.CategoryFeed)
+		feeds.GET("/tags/:name/posts.atom", feedHandler.TagFeed)
+		feeds.GET("/tags/:name/posts.rss", feedHandler.TagFeed)
+		feeds.GET("/users/:id/posts.atom", feedHandler.UserFeed)
+		feeds.GET("/users/:id/posts.rss", feedHandler.UserFeed)
+	}
+
+	// Admin routes
+	admin := router.Group("/admin")
+	admin.Use(delivery.AuthMiddleware(cfg))
+	{
+		admin.POST("/categories", categoryHandler.CreateCategory)
+		admin.PUT("/categories/:id", categoryHandler.UpdateCategory)
+		admin.DELETE("/categories/:id", categoryHandler.DeleteCategory)
+		admin.PUT("/tags/:name", tagHandler.RenameTag)
+	}
+
+	// User routes
+	users := router.Group("/users")
+	{
+		users.GET("/suggest", userHandler.SuggestUsers)
+
+		protected := users.Group("")
+		protected.Use(delivery.AuthMiddleware(cfg))
+		{
+			protected.GET("/blocks", userHandler.ListBlockedUsers)
+			protected.POST("/:id/block", userHandler.BlockUser)
+			protected.DELETE("/:id/block", userHandler.UnblockUser)
+		}
+	}
+
+	// Notification routes. Browsers open the socket with the token query parameter.
+	notifications := router.Group("/notifications")
+	notifications.Use(delivery.AuthMiddleware(cfg))
+	{
+		notifications.GET("", notificationHandler.ListNotifications)
+		notifications.GET("/unread-count", notificationHandler.UnreadCount)
+		notifications.GET("/ws", notificationHandler.HandleWebSocket)
+		notifications.POST("/read-all", notificationHandler.MarkAllRead)
+		notifications.POST("/:id/read", notificationHandler.MarkRead)
+	}
+
+	// Attachment routes. Files are served by signed links without a session.
+	router.GET("/attachments/:id/:variant", attachmentHandler.ServeAttachment)
+	uploads := router.Group("/uploads")
+	uploads.Use(delivery.AuthMiddleware(cfg), delivery.BanMiddleware(moderationUC))
+	{
+		uploads.POST("", attachmentHandler.Upload)
+	}
+
+	// Report and moderation routes. Banned users can still report content.
+	router.POST("/reports", delivery.AuthMiddleware(cfg), moderationHandler.CreateReport)
+	moderation := router.Group("/moderation")
+	moderation.Use(delivery.AuthMiddleware(cfg))
+	{
+		moderation.GET("/reports", moderationHandler.ListReportQueue)
+		moderation.POST("/reports/:target_type/:target_id/resolve", moderationHandler.ResolveReports)
+		moderation.GET("/log", moderationHandler.ListModerationLog)
+		moderation.PUT("/posts/:id/state", moderationHandler.SetPostState)
+		moderation.PUT("/posts/:id/pin", moderationHandler.SetPostPinned)
+		moderation.GET("/trash", trashHandler.ListTrash)
+		moderation.POST("/trash/:target_type/:target_id/restore", trashHandler.RestoreFromTrash)
+	}
+
+	// Reaction routes
+	router.GET("/reactions", voteHandler.ListReactions)
+
+	// Posts routes. Public reads include the caller's votes when a token is sent.
 	posts := router.Group("/posts")
+	posts.Use(delivery.OptionalAuthMiddleware(cfg))
 	{
 		posts.GET("", postHandler.GetAllPosts)
 		posts.GET("/:id", postHandler.GetPostByID)
+		posts.GET("/:id/revisions", revisionHandler.ListRevisions)
+		posts.GET("/:id/revisions/diff", revisionHandler.DiffRevisions)
+		posts.GET("/:id/revisions/:rev", revisionHandler.GetRevision)
 
 		// Protected routes
 		protected := posts.Group("")
-		protected.Use(delivery.AuthMiddleware(cfg))
+		protected.Use(delivery.AuthMiddleware(cfg), delivery.BanMiddleware(moderationUC))
 		{
 			protected.POST("", postHandler.CreatePost)
+			protected.GET("/drafts", postHandler.ListDrafts)
+			protected.PUT("/:id/publication", postHandler.SetPublication)
 			protected.DELETE("/:id", postHandler.DeletePost)
 			protected.PUT("/:id", postHandler.UpdatePost)
+			protected.POST("/:id/revisions/:rev/restore", revisionHandler.RestoreRevision)
+			protected.POST("/:id/vote", voteHandler.VotePost)
+			protected.POST("/:id/reactions", voteHandler.ReactToPost)
+			protected.GET("/:id/subscription", notificationHandler.GetSubscription)
+			protected.POST("/:id/subscription", notificationHandler.Subscribe)
+			protected.DELETE("/:id/subscription", notificationHandler.Unsubscribe)
 		}
 
 		// Comments routes
@@ -177,30 +388,87 @@ func main() {
 
 			// Protected comments routes
 			protectedComments := comments.Group("")
-			protectedComments.Use(delivery.AuthMiddleware(cfg))
+			protectedComments.Use(delivery.AuthMiddleware(cfg), delivery.BanMiddleware(moderationUC))
 			{
 				protectedComments.POST("", commentHandler.CreateComment)
+				protectedComments.PUT("/:comment_id", commentHandler.UpdateComment)
 				protectedComments.DELETE("/:comment_id", commentHandler.DeleteComment)
+				protectedComments.GET("/:comment_id/revisions", commentHandler.GetCommentRevisions)
+				protectedComments.POST("/:comment_id/vote", voteHandler.VoteComment)
+				protectedComments.POST("/:comment_id/reactions", voteHandler.ReactToComment)
 			}
 		}
 	}
 
-	// Start HTTP server in goroutine
-	go func() {
-		if err := router.Run(":" + cfg.Server.Port); err != nil {
-			log.Fatalf("failed to start HTTP server: %v", err)
-		}
-	}()
+	httpSrv := &http.Server{
+		Addr:              ":" + cfg.Server.Port,
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
 
-	// Graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	// Start background tasks
+	app := lifecycle.New(log, cfg.Server.ShutdownTimeout)
+	app.Go("health_watch", func(ctx context.Context) error {
+		healthSrv.Watch(ctx, cfg.Health.CheckInterval)
+		return nil
+	})
+	app.Go("post_rankings", func(ctx context.Context) error {
+		rankingUC.Run(ctx, cfg.Ranking.RefreshInterval)
+		return nil
+	})
+	app.Go("attachment_gc", func(ctx context.Context) error {
+		attachmentUC.Run(ctx, cfg.Uploads.GCInterval)
+		return nil
+	})
+	app.Go("post_publisher", func(ctx context.Context) error {
+		postUC.Run(ctx, cfg.Publishing.Interval)
+		return nil
+	})
+	app.Go("post_views", func(ctx context.Context) error {
+		viewUC.Run(ctx, cfg.Views.FlushInterval)
+		return nil
+	})
+	app.Go("trash_purge", func(ctx context.Context) error {
+		trashUC.Run(ctx, cfg.Trash.PurgeInterval)
+		return nil
+	})
+	app.Go("grpc_server", func(context.Context) error {
+		log.Infow("gRPC server started", "port", cfg.GRPC.Port)
+		return grpcSrv.Serve(grpcLis)
+	})
+	app.Go("http_server", func(context.Context) error {
+		log.Infow("HTTP server started", "port", cfg.Server.Port)
+		if err := httpSrv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
 
-	// Gracefully stop gRPC server
-	grpcSrv.GracefulStop()
+	// Teardown runs in reverse order: drain readiness, stop HTTP and gRPC,
+	// stop the background jobs, close chat and notification clients, release the
+	// auth connection, write the buffered post views and close the DB pool.
+	// Tracing is flushed last so that spans from the teardown are exported.
+	app.OnStop("tracing", shutdownTracing)
+	app.OnStop("postgres", func(context.Context) error { return repo.Close() })
+	app.OnStop("post_views", viewUC.Flush)
+	app.OnStop("auth_grpc_conn", func(context.Context) error { return authConn.Close() })
+	app.OnStop("chat_hub", chatUC.Shutdown)
+	app.OnStop("notification_hub", notificationHub.Shutdown)
+	app.OnStop("background_tasks", app.StopTasks)
+	app.OnStop("grpc_server", lifecycle.StopGRPC(grpcSrv))
+	app.OnStop("http_server", httpSrv.Shutdown)
+	app.OnStop("health", func(context.Context) error {
+		healthSrv.Shutdown()
+		return nil
+	})
 
-	// Cancel context
-	cancel()
+	// Graceful shutdown
+	sigCtx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
+	if err := app.Wait(sigCtx); err != nil {
+		log.Errorw("shutdown finished with errors", "error", err)
+		return
+	}
+	log.Info("forum service stopped")
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/repository"
+)
+
+const migrateUsage = `usage: forum-service migrate <command>
+
+commands:
+  up              apply all pending migrations
+  down [N]        roll back the last N migrations (default 1)
+  status          show the current schema version and migrations
+  force VERSION   set the schema version and clear the dirty flag`
+
+// runMigrate executes the migrate subcommand and returns an error
+// if the command is unknown or the migration fails
+func runMigrate(repo repository.MigrationManager, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command\n%s", migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		if err := repo.RunMigrations(); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "migrations applied")
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+		if err := repo.RollbackMigrations(steps); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "rolled back %d migration(s)\n", steps)
+	case "status":
+		status, err := repo.MigrationStatus()
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(out, status)
+	case "force":
+		if len(args) < 2 {
+			return fmt.Errorf("missing version\n%s", migrateUsage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if err := repo.ForceMigrationVersion(version); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "forced version %d\n", version)
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], migrateUsage)
+	}
+	return nil
+}
+
+func printMigrationStatus(out io.Writer, status *repository.MigrationStatus) {
+	if status.Applied {
+		fmt.Fprintf(out, "current version: %d (dirty: %v)\n", status.Version, status.Dirty)
+	} else {
+		fmt.Fprintln(out, "current version: none")
+	}
+
+	for _, v := range status.Available {
+		state := "pending"
+		if status.Applied && v <= status.Version {
+			state = "applied"
+		}
+		fmt.Fprintf(out, "  %03d  %s\n", v, state)
+	}
+}
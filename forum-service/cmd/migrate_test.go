@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMigrationManager struct {
+	mock.Mock
+}
+
+func (m *MockMigrationManager) RunMigrations() error {
+	return m.Called().Error(0)
+}
+
+func (m *MockMigrationManager) RollbackMigrations(steps int) error {
+	return m.Called(steps).Error(0)
+}
+
+func (m *MockMigrationManager) MigrationStatus() (*repository.MigrationStatus, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.MigrationStatus), args.Error(1)
+}
+
+func (m *MockMigrationManager) ForceMigrationVersion(version int) error {
+	return m.Called(version).Error(0)
+}
+
+func TestRunMigrate(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		mockSetup   func(*MockMigrationManager)
+		expectedOut string
+		expectedErr string
+	}{
+		{
+			name: "Up",
+			args: []string{"up"},
+			mockSetup: func(m *MockMigrationManager) {
+				m.On("RunMigrations").Return(nil)
+			},
+			expectedOut: "migrations applied\n",
+		},
+		{
+			name: "DownDefaultStep",
+			args: []string{"down"},
+			mockSetup: func(m *MockMigrationManager) {
+				m.On("RollbackMigrations", 1).Return(nil)
+			},
+			expectedOut: "rolled back 1 migration(s)\n",
+		},
+		{
+			name: "DownSteps",
+			args: []string{"down", "2"},
+			mockSetup: func(m *MockMigrationManager) {
+				m.On("RollbackMigrations", 2).Return(nil)
+			},
+			expectedOut: "rolled back 2 migration(s)\n",
+		},
+		{
+			name:        "DownInvalidSteps",
+			args:        []string{"down", "zero"},
+			mockSetup:   func(m *MockMigrationManager) {},
+			expectedErr: `invalid number of steps "zero"`,
+		},
+		{
+			name: "Status",
+			args: []string{"status"},
+			mockSetup: func(m *MockMigrationManager) {
+				m.On("MigrationStatus").Return(&repository.MigrationStatus{
+					Version:   1,
+					Applied:   true,
+					Available: []uint{1, 2},
+				}, nil)
+			},
+			expectedOut: "current version: 1 (dirty: false)\n  001  applied\n  002  pending\n",
+		},
+		{
+			name: "Force",
+			args: []string{"force", "2"},
+			mockSetup: func(m *MockMigrationManager) {
+				m.On("ForceMigrationVersion", 2).Return(nil)
+			},
+			expectedOut: "forced version 2\n",
+		},
+		{
+			name: "UpError",
+			args: []string{"up"},
+			mockSetup: func(m *MockMigrationManager) {
+				m.On("RunMigrations").Return(errors.New("dirty database"))
+			},
+			expectedErr: "dirty database",
+		},
+		{
+			name:        "UnknownCommand",
+			args:        []string{"sideways"},
+			mockSetup:   func(m *MockMigrationManager) {},
+			expectedErr: `unknown migrate command "sideways"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockMigrationManager)
+			tt.mockSetup(repo)
+
+			var out bytes.Buffer
+			err := runMigrate(repo, tt.args, &out)
+
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOut, out.String())
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
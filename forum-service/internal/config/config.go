@@ -2,6 +2,9 @@ package config
 
 import (
 	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/storage"
+	"github.com/lera-guryan2222/fooorum/platform/tracing"
 )
 
 // Добавляем явное объявление структуры
@@ -18,7 +21,8 @@ type PostgresConfig struct {
 type Config struct {
 	Postgres PostgresConfig // Теперь используем явный тип
 	Server   struct {
-		Port string
+		Port            string
+		ShutdownTimeout time.Duration
 	}
 	Auth struct {
 		AccessTokenDuration  time.Duration
@@ -38,6 +42,53 @@ type Config struct {
 	GRPC struct {
 		Port string `yaml:"port"`
 	} `yaml:"grpc"`
+	Health struct {
+		CheckTimeout  time.Duration `yaml:"check_timeout"`
+		CheckInterval time.Duration `yaml:"check_interval"`
+	} `yaml:"health"`
+	Tracing tracing.Config `yaml:"tracing"`
+	Ranking struct {
+		RefreshInterval time.Duration `yaml:"refresh_interval"`
+	} `yaml:"ranking"`
+	Uploads UploadsConfig `yaml:"uploads"`
+	// Publishing - как часто публикуются запланированные посты
+	Publishing struct {
+		Interval time.Duration `yaml:"interval"`
+	} `yaml:"publishing"`
+	// Trash - сколько удаленное хранится в корзине и как часто она очищается
+	Trash struct {
+		Retention     time.Duration `yaml:"retention"`
+		PurgeInterval time.Duration `yaml:"purge_interval"`
+	} `yaml:"trash"`
+	// Views - окно, в котором повторный просмотр не засчитывается, как часто
+	// просмотры пишутся в базу, сколько хранятся почасовые счетчики и сколько
+	// читателей помнится для отсева повторов
+	Views struct {
+		DedupWindow   time.Duration `yaml:"dedup_window"`
+		FlushInterval time.Duration `yaml:"flush_interval"`
+		Retention     time.Duration `yaml:"retention"`
+		MaxViewers    int           `yaml:"max_viewers"`
+	} `yaml:"views"`
+	// Feeds - публичный адрес форума для ссылок в RSS и Atom, название форума
+	// и число постов в ленте
+	Feeds struct {
+		BaseURL string `yaml:"base_url"`
+		Title   string `yaml:"title"`
+		Items   int    `yaml:"items"`
+	} `yaml:"feeds"`
+}
+
+// UploadsConfig - хранилище вложений и ограничения загрузок
+type UploadsConfig struct {
+	// Backend - local или s3
+	Backend    string           `yaml:"backend"`
+	Dir        string           `yaml:"dir"`
+	S3         storage.S3Config `yaml:"s3"`
+	MaxSize    int64            `yaml:"max_size"`
+	SigningKey string           `yaml:"signing_key"`
+	URLTTL     time.Duration    `yaml:"url_ttl"`
+	OrphanTTL  time.Duration    `yaml:"orphan_ttl"`
+	GCInterval time.Duration    `yaml:"gc_interval"`
 }
 
 func Load() *Config {
@@ -54,6 +105,7 @@ func Load() *Config {
 
 	// Server configuration
 	cfg.Server.Port = "8081"
+	cfg.Server.ShutdownTimeout = 15 * time.Second
 
 	// Auth configuration
 	cfg.Auth.AccessTokenDuration = 24 * time.Hour
@@ -75,6 +127,46 @@ func Load() *Config {
 	// GRPC configuration
 	cfg.GRPC.Port = "50052"
 
+	// Health configuration
+	cfg.Health.CheckTimeout = 2 * time.Second
+	cfg.Health.CheckInterval = 10 * time.Second
+
+	// Tracing configuration
+	cfg.Tracing.Exporter = tracing.ExporterNone
+	cfg.Tracing.Endpoint = "localhost:4317"
+	cfg.Tracing.SampleRatio = 1
+
+	// Ranking configuration
+	cfg.Ranking.RefreshInterval = 5 * time.Minute
+
+	// Uploads configuration
+	cfg.Uploads.Backend = "local"
+	cfg.Uploads.Dir = "uploads"
+	cfg.Uploads.S3.Region = "us-east-1"
+	cfg.Uploads.MaxSize = 10 << 20
+	cfg.Uploads.SigningKey = "your-upload-signing-secret"
+	cfg.Uploads.URLTTL = time.Hour
+	cfg.Uploads.OrphanTTL = 24 * time.Hour
+	cfg.Uploads.GCInterval = time.Hour
+
+	// Publishing configuration
+	cfg.Publishing.Interval = time.Minute
+
+	// Trash configuration
+	cfg.Trash.Retention = 30 * 24 * time.Hour
+	cfg.Trash.PurgeInterval = time.Hour
+
+	// Views configuration
+	cfg.Views.DedupWindow = 30 * time.Minute
+	cfg.Views.FlushInterval = 30 * time.Second
+	cfg.Views.Retention = 8 * 24 * time.Hour
+	cfg.Views.MaxViewers = 100000
+
+	// Feeds configuration
+	cfg.Feeds.BaseURL = "http://localhost:8081"
+	cfg.Feeds.Title = "Fooorum"
+	cfg.Feeds.Items = 20
+
 	cfg.Migrations.Enable = false
 	return cfg
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"strconv"
 
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
 	postProto "github.com/lera-guryan2222/fooorum/forum-service/internal/proto/post"
 	userProto "github.com/lera-guryan2222/fooorum/forum-service/internal/proto/user"
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
@@ -27,7 +28,8 @@ func NewPostServer(postUC usecase.PostUseCase, userConn *googlegrpc.ClientConn)
 }
 
 func (s *PostServer) GetPostWithAuthor(ctx context.Context, req *postProto.PostRequest) (*postProto.PostResponse, error) {
-	post, err := s.postUsecase.GetPostByID(ctx, int(req.GetPostId()))
+	// Запрос gRPC анонимный: черновики не отдаются
+	post, err := s.postUsecase.GetPostByID(ctx, int(req.GetPostId()), 0)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "post not found: %v", err)
 	}
@@ -49,5 +51,50 @@ func (s *PostServer) GetPostWithAuthor(ctx context.Context, req *postProto.PostR
 		Title:      post.Title,
 		Content:    post.Content,
 		AuthorName: usernameResp.GetUsername(),
+		CategoryId: int32(post.CategoryID),
+		Tags:       post.Tags,
 	}, nil
 }
+
+// ListPosts отдает страницу постов; имя автора берется из самого поста,
+// без отдельного вызова auth-service на каждый пост
+func (s *PostServer) ListPosts(ctx context.Context, req *postProto.ListPostsRequest) (*postProto.ListPostsResponse, error) {
+	params := entity.PostListParams{
+		Sort:   entity.PostSort(req.GetSort()),
+		Window: entity.TopWindow(req.GetWindow()),
+		Filter: entity.PostFilter{
+			CategoryID: int(req.GetCategoryId()),
+			Tag:        req.GetTag(),
+			Author:     req.GetAuthor(),
+		},
+		Cursor: req.GetCursor(),
+		Limit:  int(req.GetPageSize()),
+	}
+	if req.GetFrom() != nil {
+		params.Filter.From = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		params.Filter.To = req.GetTo().AsTime()
+	}
+
+	page, err := s.postUsecase.ListPosts(ctx, params)
+	if err != nil {
+		if usecase.IsPostListInputError(err) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to list posts: %v", err)
+	}
+
+	resp := &postProto.ListPostsResponse{NextCursor: page.NextCursor}
+	for _, post := range page.Posts {
+		resp.Posts = append(resp.Posts, &postProto.PostResponse{
+			Id:         int32(post.ID),
+			Title:      post.Title,
+			Content:    post.Content,
+			AuthorName: post.Author,
+			CategoryId: int32(post.CategoryID),
+			Tags:       post.Tags,
+		})
+	}
+	return resp, nil
+}
@@ -4,24 +4,28 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/delivery/grpcserver"
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
 	postProto "github.com/lera-guryan2222/fooorum/forum-service/internal/proto/post"
 	userProto "github.com/lera-guryan2222/fooorum/forum-service/internal/proto/user"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type MockPostUsecase struct {
 	mock.Mock
 }
 
-func (m *MockPostUsecase) GetPostByID(ctx context.Context, id int) (*entity.Post, error) {
-	args := m.Called(ctx, id)
+func (m *MockPostUsecase) GetPostByID(ctx context.Context, id, viewerID int) (*entity.Post, error) {
+	args := m.Called(ctx, id, viewerID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -33,12 +37,12 @@ func (m *MockPostUsecase) CreatePost(ctx context.Context, post *entity.Post) err
 	return args.Error(0)
 }
 
-func (m *MockPostUsecase) GetAllPosts(ctx context.Context) ([]*entity.Post, error) {
-	args := m.Called(ctx)
+func (m *MockPostUsecase) ListPosts(ctx context.Context, params entity.PostListParams) (*entity.PostPage, error) {
+	args := m.Called(ctx, params)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*entity.Post), args.Error(1)
+	return args.Get(0).(*entity.PostPage), args.Error(1)
 }
 
 func (m *MockPostUsecase) DeletePost(ctx context.Context, postID, userID int) error {
@@ -50,10 +54,26 @@ type MockUserClient struct {
 	mock.Mock
 }
 
-func (m *MockPostUsecase) UpdatePost(ctx context.Context, postID int, userID int, title, content string) error {
-	args := m.Called(ctx, postID, userID, title, content)
+func (m *MockPostUsecase) UpdatePost(ctx context.Context, postID int, userID int, update entity.PostUpdate) error {
+	args := m.Called(ctx, postID, userID, update)
 	return args.Error(0)
 }
+
+func (m *MockPostUsecase) ListDrafts(ctx context.Context, userID int, params entity.DraftListParams) (*entity.PostPage, error) {
+	args := m.Called(ctx, userID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PostPage), args.Error(1)
+}
+
+func (m *MockPostUsecase) SetPublication(ctx context.Context, postID, userID int, publication entity.PostPublication) (*entity.Post, error) {
+	args := m.Called(ctx, postID, userID, publication)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
 func (m *MockUserClient) GetUsername(ctx context.Context, in *userProto.UserRequest, opts ...grpc.CallOption) (*userProto.UserResponse, error) {
 	args := m.Called(ctx, in)
 	if args.Get(0) == nil {
@@ -76,7 +96,7 @@ func TestPostServer_GetPostWithAuthor(t *testing.T) {
 			name: "Success",
 			req:  &postProto.PostRequest{PostId: 1},
 			mockPostSetup: func(m *MockPostUsecase) {
-				m.On("GetPostByID", mock.Anything, 1).
+				m.On("GetPostByID", mock.Anything, 1, 0).
 					Return(&entity.Post{
 						ID:      1,
 						Title:   "Test Post",
@@ -99,7 +119,7 @@ func TestPostServer_GetPostWithAuthor(t *testing.T) {
 			name: "PostNotFound",
 			req:  &postProto.PostRequest{PostId: 2},
 			mockPostSetup: func(m *MockPostUsecase) {
-				m.On("GetPostByID", mock.Anything, 2).
+				m.On("GetPostByID", mock.Anything, 2, 0).
 					Return(nil, errors.New("post not found"))
 			},
 			mockUserSetup:  func(m *MockUserClient) {},
@@ -110,7 +130,7 @@ func TestPostServer_GetPostWithAuthor(t *testing.T) {
 			name: "InvalidAuthorIDFormat",
 			req:  &postProto.PostRequest{PostId: 3},
 			mockPostSetup: func(m *MockPostUsecase) {
-				m.On("GetPostByID", mock.Anything, 3).
+				m.On("GetPostByID", mock.Anything, 3, 0).
 					Return(&entity.Post{
 						ID:      3,
 						Title:   "Invalid Author",
@@ -126,7 +146,7 @@ func TestPostServer_GetPostWithAuthor(t *testing.T) {
 			name: "UserServiceError",
 			req:  &postProto.PostRequest{PostId: 4},
 			mockPostSetup: func(m *MockPostUsecase) {
-				m.On("GetPostByID", mock.Anything, 4).
+				m.On("GetPostByID", mock.Anything, 4, 0).
 					Return(&entity.Post{
 						ID:      4,
 						Title:   "Test Post",
@@ -173,3 +193,80 @@ func TestPostServer_GetPostWithAuthor(t *testing.T) {
 		})
 	}
 }
+
+func TestPostServer_ListPosts(t *testing.T) {
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		req          *postProto.ListPostsRequest
+		mockSetup    func(*MockPostUsecase)
+		expectedResp *postProto.ListPostsResponse
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success",
+			req: &postProto.ListPostsRequest{
+				Sort:       "oldest",
+				Author:     "alice",
+				CategoryId: 2,
+				Tag:        "go",
+				From:       timestamppb.New(from),
+				PageSize:   1,
+				Cursor:     "abc",
+			},
+			mockSetup: func(m *MockPostUsecase) {
+				m.On("ListPosts", mock.Anything, entity.PostListParams{
+					Sort:   entity.PostSortOldest,
+					Filter: entity.PostFilter{CategoryID: 2, Tag: "go", Author: "alice", From: from},
+					Cursor: "abc",
+					Limit:  1,
+				}).Return(&entity.PostPage{
+					Posts:      []*entity.Post{{ID: 1, Title: "Test Post", Content: "Test Content", Author: "alice", CategoryID: 2, Tags: []string{"go"}}},
+					NextCursor: "def",
+				}, nil)
+			},
+			expectedResp: &postProto.ListPostsResponse{
+				Posts: []*postProto.PostResponse{
+					{Id: 1, Title: "Test Post", Content: "Test Content", AuthorName: "alice", CategoryId: 2, Tags: []string{"go"}},
+				},
+				NextCursor: "def",
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "InvalidCursor",
+			req:  &postProto.ListPostsRequest{Cursor: "broken"},
+			mockSetup: func(m *MockPostUsecase) {
+				m.On("ListPosts", mock.Anything, entity.PostListParams{Cursor: "broken"}).
+					Return(nil, usecase.ErrInvalidCursor)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "UsecaseError",
+			req:  &postProto.ListPostsRequest{},
+			mockSetup: func(m *MockPostUsecase) {
+				m.On("ListPosts", mock.Anything, entity.PostListParams{}).
+					Return(nil, errors.New("database error"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postUsecase := new(MockPostUsecase)
+			tt.mockSetup(postUsecase)
+
+			server := grpcserver.NewPostServer(postUsecase, nil)
+			resp, err := server.ListPosts(context.Background(), tt.req)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				assert.True(t, proto.Equal(tt.expectedResp, resp))
+			}
+			postUsecase.AssertExpectations(t)
+		})
+	}
+}
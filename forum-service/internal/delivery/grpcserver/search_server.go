@@ -0,0 +1,60 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	searchProto "github.com/lera-guryan2222/fooorum/forum-service/internal/proto/search"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type SearchServer struct {
+	searchProto.UnimplementedSearchServiceServer
+	searchUsecase usecase.SearchUseCase
+}
+
+func NewSearchServer(searchUC usecase.SearchUseCase) *SearchServer {
+	return &SearchServer{searchUsecase: searchUC}
+}
+
+func (s *SearchServer) Search(ctx context.Context, req *searchProto.SearchRequest) (*searchProto.SearchResponse, error) {
+	params := entity.SearchParams{
+		Query:  req.GetQuery(),
+		Type:   entity.SearchType(req.GetType()),
+		Filter: entity.PostFilter{Author: req.GetAuthor()},
+		Cursor: req.GetCursor(),
+		Limit:  int(req.GetPageSize()),
+	}
+	if req.GetFrom() != nil {
+		params.Filter.From = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		params.Filter.To = req.GetTo().AsTime()
+	}
+
+	page, err := s.searchUsecase.Search(ctx, params)
+	if err != nil {
+		if usecase.IsSearchInputError(err) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to search: %v", err)
+	}
+
+	resp := &searchProto.SearchResponse{NextCursor: page.NextCursor}
+	for _, r := range page.Results {
+		resp.Results = append(resp.Results, &searchProto.SearchResult{
+			Type:       string(r.Type),
+			PostId:     int32(r.PostID),
+			CommentId:  int32(r.CommentID),
+			Title:      r.Title,
+			Snippet:    r.Snippet,
+			AuthorName: r.Author,
+			CreatedAt:  timestamppb.New(r.CreatedAt),
+			Rank:       float32(r.Rank),
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,121 @@
+package grpcserver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/delivery/grpcserver"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	searchProto "github.com/lera-guryan2222/fooorum/forum-service/internal/proto/search"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type MockSearchUsecase struct {
+	mock.Mock
+}
+
+func (m *MockSearchUsecase) Search(ctx context.Context, params entity.SearchParams) (*entity.SearchPage, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SearchPage), args.Error(1)
+}
+
+func TestSearchServer_Search(t *testing.T) {
+	created := time.Date(2025, 5, 2, 10, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		req          *searchProto.SearchRequest
+		mockSetup    func(*MockSearchUsecase)
+		expectedResp *searchProto.SearchResponse
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success",
+			req: &searchProto.SearchRequest{
+				Query:    "golang",
+				Type:     "comment",
+				Author:   "alice",
+				To:       timestamppb.New(to),
+				PageSize: 5,
+			},
+			mockSetup: func(m *MockSearchUsecase) {
+				m.On("Search", mock.Anything, entity.SearchParams{
+					Query:  "golang",
+					Type:   entity.SearchTypeComment,
+					Filter: entity.PostFilter{Author: "alice", To: to},
+					Limit:  5,
+				}).Return(&entity.SearchPage{
+					Results: []*entity.SearchResult{{
+						Type:      entity.SearchTypeComment,
+						PostID:    1,
+						CommentID: 3,
+						Title:     "Test Post",
+						Snippet:   "<mark>golang</mark>",
+						Author:    "alice",
+						CreatedAt: created,
+						Rank:      0.5,
+					}},
+					NextCursor: "next",
+				}, nil)
+			},
+			expectedResp: &searchProto.SearchResponse{
+				Results: []*searchProto.SearchResult{{
+					Type:       "comment",
+					PostId:     1,
+					CommentId:  3,
+					Title:      "Test Post",
+					Snippet:    "<mark>golang</mark>",
+					AuthorName: "alice",
+					CreatedAt:  timestamppb.New(created),
+					Rank:       0.5,
+				}},
+				NextCursor: "next",
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "InvalidArgument",
+			req:  &searchProto.SearchRequest{},
+			mockSetup: func(m *MockSearchUsecase) {
+				m.On("Search", mock.Anything, entity.SearchParams{}).Return(nil, usecase.ErrEmptySearchQuery)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "UsecaseError",
+			req:  &searchProto.SearchRequest{Query: "golang"},
+			mockSetup: func(m *MockSearchUsecase) {
+				m.On("Search", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searchUsecase := new(MockSearchUsecase)
+			tt.mockSetup(searchUsecase)
+
+			server := grpcserver.NewSearchServer(searchUsecase)
+			resp, err := server.Search(context.Background(), tt.req)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				assert.True(t, proto.Equal(tt.expectedResp, resp))
+			}
+			searchUsecase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package delivery
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+)
+
+// multipartOverhead - запас на заголовки multipart сверх размера самого файла
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	attachmentUC usecase.AttachmentUseCase
+}
+
+func NewAttachmentHandler(attachmentUC usecase.AttachmentUseCase) *AttachmentHandler {
+	return &AttachmentHandler{attachmentUC: attachmentUC}
+}
+
+// Upload godoc
+// @Summary Upload a file
+// @Description Upload an image (PNG, JPEG, GIF, WebP), PDF or plain text file as the multipart field file. The type is detected from the content. Images get a thumbnail. Pass the returned id in attachment_ids when creating a post, comment or chat message; uploads left unattached are deleted after a day. url and thumbnail_url are signed links that expire.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "File to upload"
+// @Success 201 {object} entity.Attachment
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 413 {object} docs.Error
+// @Failure 415 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /uploads [post]
+
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.attachmentUC.MaxUploadSize()+multipartOverhead)
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": usecase.ErrAttachmentTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentUC.Upload(c.Request.Context(), userID.(int), header.Filename, file)
+	if err != nil {
+		respondAttachmentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// ServeAttachment godoc
+// @Summary Download an attachment
+// @Description Serve the file or thumbnail of an attachment through a signed link from url or thumbnail_url. Images and thumbnails are shown inline, other files are downloaded.
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path int true "Attachment ID"
+// @Param variant path string true "File or thumbnail" Enums(file, thumbnail)
+// @Param expires query int true "Link expiry, Unix time"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file
+// @Failure 403 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 410 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /attachments/{id}/{variant} [get]
+
+func (h *AttachmentHandler) ServeAttachment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": usecase.ErrAttachmentNotFound.Error()})
+		return
+	}
+
+	variant := c.Param("variant")
+	expires := c.Query("expires")
+	attachment, blob, err := h.attachmentUC.Open(c.Request.Context(), id, variant, expires, c.Query("signature"))
+	if err != nil {
+		respondAttachmentError(c, err)
+		return
+	}
+	defer blob.Close()
+
+	contentType, disposition := attachment.ContentType, "attachment"
+	if variant == usecase.AttachmentVariantThumbnail {
+		contentType = usecase.ThumbnailContentType
+	}
+	if strings.HasPrefix(contentType, "image/") {
+		disposition = "inline"
+	}
+	if contentType == "text/plain" {
+		contentType = "text/plain; charset=utf-8"
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "default-src 'none'; sandbox")
+	// Ссылка неизменна до истечения срока, браузер может хранить ответ до этого момента
+	if unix, err := strconv.ParseInt(expires, 10, 64); err == nil {
+		if maxAge := time.Until(time.Unix(unix, 0)); maxAge > 0 {
+			c.Header("Cache-Control", "private, max-age="+strconv.Itoa(int(maxAge.Seconds())))
+		}
+	}
+	if variant == usecase.AttachmentVariantFile {
+		c.Header("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	}
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, blob); err != nil {
+		log.Printf("[ERROR] ServeAttachment %d: %v", id, err)
+	}
+}
+
+func respondAttachmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrEmptyUpload), errors.Is(err, usecase.ErrTooManyAttachments):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrAttachmentTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUnsupportedMediaType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidAttachmentLink):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrAttachmentLinkExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrAttachmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("[ERROR] Attachment: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process attachment"})
+	}
+}
@@ -0,0 +1,227 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAttachmentUseCase - мок для AttachmentUseCase
+type MockAttachmentUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentUseCase) AttachmentsFor(ctx context.Context, target entity.AttachmentTarget, ids []int) (map[int][]entity.Attachment, error) {
+	args := m.Called(ctx, target, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int][]entity.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentUseCase) Upload(ctx context.Context, userID int, filename string, r io.Reader) (*entity.Attachment, error) {
+	args := m.Called(ctx, userID, filename, r)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentUseCase) Open(ctx context.Context, id int, variant, expires, signature string) (*entity.Attachment, io.ReadCloser, error) {
+	args := m.Called(ctx, id, variant, expires, signature)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*entity.Attachment), args.Get(1).(io.ReadCloser), args.Error(2)
+}
+
+func (m *MockAttachmentUseCase) MaxUploadSize() int64 {
+	return m.Called().Get(0).(int64)
+}
+
+func (m *MockAttachmentUseCase) CollectOrphans(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAttachmentUseCase) Run(ctx context.Context, interval time.Duration) {
+	m.Called(ctx, interval)
+}
+
+func newUploadRequest(t *testing.T, field, filename string, content []byte) *http.Request {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile(field, filename)
+	assert.NoError(t, err)
+	part.Write(content)
+	w.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/uploads", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func setupAttachmentRouter(h *AttachmentHandler) *gin.Engine {
+	router := gin.New()
+	router.POST("/uploads", func(c *gin.Context) {
+		c.Set("user_id", 2)
+		h.Upload(c)
+	})
+	router.GET("/attachments/:id/:variant", h.ServeAttachment)
+	return router
+}
+
+func TestAttachmentHandler_Upload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		field    string
+		content  []byte
+		uploadFn func(*MockAttachmentUseCase)
+		status   int
+	}{
+		{
+			name:    "Success",
+			field:   "file",
+			content: []byte("hello"),
+			uploadFn: func(m *MockAttachmentUseCase) {
+				m.On("Upload", mock.Anything, 2, "notes.txt", mock.Anything).
+					Return(&entity.Attachment{ID: 3, Filename: "notes.txt", URL: "/attachments/3/file?expires=1&signature=s"}, nil)
+			},
+			status: http.StatusCreated,
+		},
+		{
+			name:    "MissingFile",
+			field:   "upload",
+			content: []byte("hello"),
+			status:  http.StatusBadRequest,
+		},
+		{
+			name:    "BodyTooLarge",
+			field:   "file",
+			content: bytes.Repeat([]byte("a"), 2*multipartOverhead),
+			status:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:    "TooLarge",
+			field:   "file",
+			content: []byte("hello"),
+			uploadFn: func(m *MockAttachmentUseCase) {
+				m.On("Upload", mock.Anything, 2, "notes.txt", mock.Anything).Return(nil, usecase.ErrAttachmentTooLarge)
+			},
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:    "UnsupportedType",
+			field:   "file",
+			content: []byte("<html></html>"),
+			uploadFn: func(m *MockAttachmentUseCase) {
+				m.On("Upload", mock.Anything, 2, "notes.txt", mock.Anything).Return(nil, usecase.ErrUnsupportedMediaType)
+			},
+			status: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockAttachmentUseCase)
+			uc.On("MaxUploadSize").Return(int64(10))
+			if tt.uploadFn != nil {
+				tt.uploadFn(uc)
+			}
+			w := httptest.NewRecorder()
+
+			setupAttachmentRouter(NewAttachmentHandler(uc)).ServeHTTP(w, newUploadRequest(t, tt.field, "notes.txt", tt.content))
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.uploadFn == nil {
+				uc.AssertNotCalled(t, "Upload")
+			}
+			uc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAttachmentHandler_ServeAttachment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	expires := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	t.Run("File", func(t *testing.T) {
+		uc := new(MockAttachmentUseCase)
+		uc.On("Open", mock.Anything, 3, "file", expires, "sig").Return(
+			&entity.Attachment{ID: 3, Filename: "отчет.pdf", ContentType: "application/pdf", Size: 4},
+			io.NopCloser(bytes.NewReader([]byte("%PDF"))), nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/attachments/3/file?expires="+expires+"&signature=sig", nil)
+
+		setupAttachmentRouter(NewAttachmentHandler(uc)).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "%PDF", w.Body.String())
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Equal(t, "attachment; filename*=utf-8''%D0%BE%D1%82%D1%87%D0%B5%D1%82.pdf", w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "4", w.Header().Get("Content-Length"))
+		assert.Contains(t, w.Header().Get("Cache-Control"), "private, max-age=")
+	})
+
+	t.Run("Thumbnail", func(t *testing.T) {
+		uc := new(MockAttachmentUseCase)
+		uc.On("Open", mock.Anything, 3, "thumbnail", expires, "sig").Return(
+			&entity.Attachment{ID: 3, Filename: "cat.jpg", ContentType: "image/jpeg", Size: 1000},
+			io.NopCloser(bytes.NewReader([]byte("png"))), nil)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/attachments/3/thumbnail?expires="+expires+"&signature=sig", nil)
+
+		setupAttachmentRouter(NewAttachmentHandler(uc)).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, "inline; filename=cat.jpg", w.Header().Get("Content-Disposition"))
+		assert.Empty(t, w.Header().Get("Content-Length"))
+	})
+
+	for name, tc := range map[string]struct {
+		err    error
+		status int
+	}{
+		"BadSignature": {usecase.ErrInvalidAttachmentLink, http.StatusForbidden},
+		"Expired":      {usecase.ErrAttachmentLinkExpired, http.StatusGone},
+		"NotFound":     {usecase.ErrAttachmentNotFound, http.StatusNotFound},
+	} {
+		t.Run(name, func(t *testing.T) {
+			uc := new(MockAttachmentUseCase)
+			uc.On("Open", mock.Anything, 3, "file", "1", "sig").Return(nil, nil, tc.err)
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/attachments/3/file?expires=1&signature=sig", nil)
+
+			setupAttachmentRouter(NewAttachmentHandler(uc)).ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+
+	t.Run("InvalidID", func(t *testing.T) {
+		uc := new(MockAttachmentUseCase)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/attachments/abc/file", nil)
+
+		setupAttachmentRouter(NewAttachmentHandler(uc)).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		uc.AssertNotCalled(t, "Open")
+	})
+}
@@ -0,0 +1,228 @@
+package delivery
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+)
+
+type CategoryHandler struct {
+	categoryUC usecase.CategoryUseCase
+	postUC     usecase.PostUseCase
+	voteUC     usecase.VoteUseCase
+}
+
+func NewCategoryHandler(categoryUC usecase.CategoryUseCase, postUC usecase.PostUseCase, voteUC usecase.VoteUseCase) *CategoryHandler {
+	return &CategoryHandler{
+		categoryUC: categoryUC,
+		postUC:     postUC,
+		voteUC:     voteUC,
+	}
+}
+
+// ListCategories godoc
+// @Summary List categories
+// @Description Category tree ordered by position. Each category carries the number of its own posts and the time of the latest post or comment.
+// @Tags categories
+// @Produce json
+// @Success 200 {array} entity.Category
+// @Failure 500 {object} docs.Error
+// @Router /categories [get]
+
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	categories, err := h.categoryUC.ListCategories(c.Request.Context())
+	if err != nil {
+		log.Printf("[ERROR] ListCategories: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list categories"})
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// GetCategoryPosts godoc
+// @Summary List posts in a category
+// @Description Retrieve a page of posts of one category. Accepts the same sorting, filtering and paging parameters as GET /posts.
+// @Tags categories
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Param sort query string false "Sort order" Enums(newest, oldest, most_commented, recently_active, hot, top, rising, most_viewed) default(newest)
+// @Param window query string false "Period for sort=top (posts created) and sort=most_viewed (views)" Enums(day, week, all) default(day)
+// @Param author query string false "Author username"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param cursor query string false "Opaque cursor from next_cursor"
+// @Success 200 {object} entity.PostPage
+// @Failure 400 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /categories/{slug}/posts [get]
+
+func (h *CategoryHandler) GetCategoryPosts(c *gin.Context) {
+	category, err := h.categoryUC.GetCategoryBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrCategoryNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	params, err := parsePostListParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	params.Filter.CategoryID = category.ID
+
+	page, err := h.postUC.ListPosts(c.Request.Context(), params)
+	if err != nil {
+		if usecase.IsPostListInputError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("[ERROR] GetCategoryPosts: Failed to get posts: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	annotatePosts(c, h.voteUC, page.Posts...)
+	c.JSON(http.StatusOK, page)
+}
+
+// CreateCategory godoc
+// @Summary Create category
+// @Description Create a category. Admin only.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category body entity.CategoryInput true "Category" SchemaExample({"slug":"go","name":"Go","description":"Everything about Go","position":1,"read_only":false})
+// @Success 201 {object} entity.Category
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /admin/categories [post]
+
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var input entity.CategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.categoryUC.CreateCategory(c.Request.Context(), userID.(int), input)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, category)
+}
+
+// UpdateCategory godoc
+// @Summary Update category
+// @Description Replace the fields of a category, including its parent. Admin only.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Param category body entity.CategoryInput true "Category"
+// @Success 200 {object} entity.Category
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /admin/categories/{id} [put]
+
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		return
+	}
+
+	var input entity.CategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.categoryUC.UpdateCategory(c.Request.Context(), userID.(int), id, input)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// DeleteCategory godoc
+// @Summary Delete category
+// @Description Delete an empty category. Posts and subcategories must be moved out first. Admin only.
+// @Tags categories
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 409 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /admin/categories/{id} [delete]
+
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		return
+	}
+
+	if err := h.categoryUC.DeleteCategory(c.Request.Context(), userID.(int), id); err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "category deleted successfully"})
+}
+
+func respondCategoryError(c *gin.Context, err error) {
+	switch {
+	case usecase.IsCategoryInputError(err):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrAdminRequired):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrCategoryNotEmpty):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("[ERROR] Category: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to change category"})
+	}
+}
@@ -0,0 +1,235 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCategoryUseCase - мок для CategoryUseCase
+type MockCategoryUseCase struct {
+	mock.Mock
+}
+
+func (m *MockCategoryUseCase) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) CreateCategory(ctx context.Context, userID int, input entity.CategoryInput) (*entity.Category, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) UpdateCategory(ctx context.Context, userID, id int, input entity.CategoryInput) (*entity.Category, error) {
+	args := m.Called(ctx, userID, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) DeleteCategory(ctx context.Context, userID, id int) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func TestCategoryHandler_ListCategories(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockCategoryUC := new(MockCategoryUseCase)
+	handler := NewCategoryHandler(mockCategoryUC, new(MockPostUseCase), passiveVoteUseCase())
+
+	mockCategoryUC.On("ListCategories", mock.Anything).Return([]*entity.Category{
+		{ID: 1, Slug: "general", Name: "General", PostCount: 3},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/categories", nil)
+
+	handler.ListCategories(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"post_count":3`)
+	mockCategoryUC.AssertExpectations(t)
+}
+
+func TestCategoryHandler_GetCategoryPosts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		url          string
+		mockSetup    func(*MockCategoryUseCase, *MockPostUseCase)
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			url:  "/categories/go/posts?sort=oldest&limit=5",
+			mockSetup: func(cuc *MockCategoryUseCase, puc *MockPostUseCase) {
+				cuc.On("GetCategoryBySlug", mock.Anything, "go").Return(&entity.Category{ID: 2, Slug: "go"}, nil)
+				puc.On("ListPosts", mock.Anything, entity.PostListParams{
+					Sort:   entity.PostSortOldest,
+					Filter: entity.PostFilter{CategoryID: 2},
+					Limit:  5,
+				}).Return(&entity.PostPage{Posts: []*entity.Post{{ID: 1, CategoryID: 2}}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "UnknownCategory",
+			url:  "/categories/go/posts",
+			mockSetup: func(cuc *MockCategoryUseCase, puc *MockPostUseCase) {
+				cuc.On("GetCategoryBySlug", mock.Anything, "go").Return(nil, usecase.ErrCategoryNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "InvalidSort",
+			url:  "/categories/go/posts?sort=random",
+			mockSetup: func(cuc *MockCategoryUseCase, puc *MockPostUseCase) {
+				cuc.On("GetCategoryBySlug", mock.Anything, "go").Return(&entity.Category{ID: 2, Slug: "go"}, nil)
+				puc.On("ListPosts", mock.Anything, mock.Anything).Return(nil, usecase.ErrInvalidSort)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCategoryUC := new(MockCategoryUseCase)
+			mockPostUC := new(MockPostUseCase)
+			tt.mockSetup(mockCategoryUC, mockPostUC)
+			handler := NewCategoryHandler(mockCategoryUC, mockPostUC, passiveVoteUseCase())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", tt.url, nil)
+			c.Params = gin.Params{gin.Param{Key: "slug", Value: "go"}}
+
+			handler.GetCategoryPosts(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockCategoryUC.AssertExpectations(t)
+			mockPostUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoryHandler_CreateCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         string
+		mockSetup    func(*MockCategoryUseCase)
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			body: `{"slug":"go","name":"Go","read_only":true}`,
+			mockSetup: func(uc *MockCategoryUseCase) {
+				uc.On("CreateCategory", mock.Anything, 1, entity.CategoryInput{Slug: "go", Name: "Go", ReadOnly: true}).
+					Return(&entity.Category{ID: 5, Slug: "go", Name: "Go", ReadOnly: true}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "Forbidden",
+			body: `{"slug":"go","name":"Go"}`,
+			mockSetup: func(uc *MockCategoryUseCase) {
+				uc.On("CreateCategory", mock.Anything, 1, mock.Anything).Return(nil, usecase.ErrAdminRequired)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "InvalidInput",
+			body: `{"slug":"Go Lang","name":"Go"}`,
+			mockSetup: func(uc *MockCategoryUseCase) {
+				uc.On("CreateCategory", mock.Anything, 1, mock.Anything).Return(nil, usecase.ErrInvalidSlug)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "InvalidJSON",
+			body:         `{`,
+			mockSetup:    func(uc *MockCategoryUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCategoryUC := new(MockCategoryUseCase)
+			tt.mockSetup(mockCategoryUC)
+			handler := NewCategoryHandler(mockCategoryUC, new(MockPostUseCase), passiveVoteUseCase())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/admin/categories", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", 1)
+
+			handler.CreateCategory(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockCategoryUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoryHandler_DeleteCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{name: "Success", expectedCode: http.StatusOK},
+		{name: "NotEmpty", err: usecase.ErrCategoryNotEmpty, expectedCode: http.StatusConflict},
+		{name: "NotFound", err: usecase.ErrCategoryNotFound, expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCategoryUC := new(MockCategoryUseCase)
+			mockCategoryUC.On("DeleteCategory", mock.Anything, 1, 7).Return(tt.err)
+			handler := NewCategoryHandler(mockCategoryUC, new(MockPostUseCase), passiveVoteUseCase())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("DELETE", "/admin/categories/7", nil)
+			c.Params = gin.Params{gin.Param{Key: "id", Value: "7"}}
+			c.Set("user_id", 1)
+
+			handler.DeleteCategory(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockCategoryUC.AssertExpectations(t)
+		})
+	}
+}
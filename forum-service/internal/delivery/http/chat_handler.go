@@ -2,6 +2,7 @@
 package delivery
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -52,10 +53,11 @@ func (h *ChatHandler) HandleWebSocket(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param Authorization header string true "Bearer <token>"
-// @Param message body object true "Message object" SchemaExample({"text":"Hello, world!"})
+// @Param message body object true "Message object" SchemaExample({"text":"Hello, world!","attachment_ids":[7]})
 // @Success 201 {object} entity.ChatMessage
-// @Failure 400 {object} docs.Error "Invalid request format"
+// @Failure 400 {object} docs.Error "Invalid request format or unavailable attachment"
 // @Failure 401 {object} docs.Error "Missing or invalid authentication token"
+// @Failure 403 {object} docs.Error "User is banned"
 // @Failure 500 {object} docs.Error "Server error"
 // @Router /chat/messages [post]
 func (h *ChatHandler) SendMessage(c *gin.Context) {
@@ -78,7 +80,8 @@ func (h *ChatHandler) SendMessage(c *gin.Context) {
 	}
 
 	var request struct {
-		Text string `json:"text" binding:"required"`
+		Text          string `json:"text" binding:"required"`
+		AttachmentIDs []int  `json:"attachment_ids"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -90,12 +93,27 @@ func (h *ChatHandler) SendMessage(c *gin.Context) {
 	}
 
 	message := &entity.ChatMessage{
-		UserID: userID.(int),
-		Author: username.(string),
-		Text:   request.Text,
+		UserID:        userID.(int),
+		Author:        username.(string),
+		Text:          request.Text,
+		AttachmentIDs: request.AttachmentIDs,
 	}
 
 	if err := h.chatUC.SendMessage(c.Request.Context(), message); err != nil {
+		if errors.Is(err, usecase.ErrAttachmentNotFound) || errors.Is(err, usecase.ErrTooManyAttachments) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "invalid_attachments",
+			})
+			return
+		}
+		if errors.Is(err, usecase.ErrUserBanned) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+				"code":  "user_banned",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to save message",
 			"details": err.Error(),
@@ -105,11 +123,12 @@ func (h *ChatHandler) SendMessage(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"id":         message.ID,
-		"user_id":    message.UserID,
-		"author":     message.Author,
-		"text":       message.Text,
-		"created_at": message.CreatedAt,
+		"id":          message.ID,
+		"user_id":     message.UserID,
+		"author":      message.Author,
+		"text":        message.Text,
+		"created_at":  message.CreatedAt,
+		"attachments": message.Attachments,
 	})
 }
 
@@ -133,6 +133,22 @@ func TestChatHandler_SendMessage(t *testing.T) {
 			requestBody:    `{"text": "Hello"}`,
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name: "banned user",
+			setupContext: func(c *gin.Context) {
+				c.Set("user_id", 123)
+				c.Set("username", "testuser")
+			},
+			setupMock: func() *MockChatUseCase {
+				return &MockChatUseCase{
+					SendMessageFunc: func(ctx context.Context, message *entity.ChatMessage) error {
+						return &usecase.UserBannedError{Ban: &entity.UserBan{UserID: 123}}
+					},
+				}
+			},
+			requestBody:    `{"text": "Hello"}`,
+			expectedStatus: http.StatusForbidden,
+		},
 	}
 
 	for _, tt := range tests {
@@ -2,6 +2,7 @@ package delivery
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -12,25 +13,32 @@ import (
 
 type CommentHandler struct {
 	commentUC usecase.CommentUseCaseInterface
+	voteUC    usecase.VoteUseCase
 }
 
-func NewCommentHandler(commentUC usecase.CommentUseCaseInterface) *CommentHandler {
-	return &CommentHandler{commentUC: commentUC}
+func NewCommentHandler(commentUC usecase.CommentUseCaseInterface, voteUC usecase.VoteUseCase) *CommentHandler {
+	return &CommentHandler{commentUC: commentUC, voteUC: voteUC}
+}
+
+type updateCommentRequest struct {
+	Content string `json:"content" binding:"required"`
 }
 
 // CreateComment godoc
 // @Summary Create a comment
-// @Description Create a new comment for a specific post. Requires Bearer token authentication.
+// @Description Create a new comment for a specific post, or a reply when parent_id is set. format is plain (default) or markdown; the response carries the source and its sanitized HTML in content_html. Requires Bearer token authentication.
 // @Tags comments
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param Authorization header string true "Bearer <token>"
 // @Param id path int true "Post ID"
-// @Param comment body entity.Comment true "Comment object" SchemaExample({"content":"This is a comment"})
+// @Param comment body entity.Comment true "Comment object" SchemaExample({"content":"This is a **comment**","format":"markdown","parent_id":12,"attachment_ids":[7]})
 // @Success 201 {object} entity.Comment
-// @Failure 400 {object} docs.Error "Invalid request format"
+// @Failure 400 {object} docs.Error "Invalid request format, unknown content format, unavailable attachment, unknown parent or reply nested too deeply"
 // @Failure 401 {object} docs.Error "Missing or invalid authentication token"
+// @Failure 403 {object} docs.Error "Post is locked or archived"
+// @Failure 404 {object} docs.Error "Post not found"
 // @Failure 500 {object} docs.Error "Server error"
 // @Router /posts/{id}/comments [post]
 
@@ -58,6 +66,20 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 	comment.UserID = userID.(int)
 
 	if err := h.commentUC.CreateComment(c.Request.Context(), &comment); err != nil {
+		if errors.Is(err, usecase.ErrParentCommentNotFound) || errors.Is(err, usecase.ErrCommentTooDeep) ||
+			errors.Is(err, usecase.ErrInvalidFormat) || errors.Is(err, usecase.ErrAttachmentNotFound) ||
+			errors.Is(err, usecase.ErrTooManyAttachments) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, usecase.ErrPostLocked) || errors.Is(err, usecase.ErrPostArchived) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, usecase.ErrPostNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -67,13 +89,17 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 
 // GetComments godoc
 // @Summary Get comments for a post
-// @Description Retrieve all comments for a specific post
+// @Description Retrieve all comments for a specific post as a flat chronological list, or with view=tree a page of top-level comments with nested replies. Pass next_cursor or a comment's replies_cursor as cursor to load more.
 // @Tags comments
 // @Accept json
 // @Produce json
 // @Param id path int true "Post ID"
-// @Success 200 {array} entity.Comment
+// @Param view query string false "Response shape" Enums(flat, tree) default(flat)
+// @Param limit query int false "Tree view: comments per page (max 100)" default(20)
+// @Param cursor query string false "Tree view: next_cursor or replies_cursor"
+// @Success 200 {array} entity.Comment "Flat view; view=tree returns entity.CommentTreePage"
 // @Failure 400 {object} docs.Error
+// @Failure 404 {object} docs.Error
 // @Failure 500 {object} docs.Error
 // @Router /posts/{id}/comments [get]
 
@@ -84,18 +110,168 @@ func (h *CommentHandler) GetComments(c *gin.Context) {
 		return
 	}
 
+	if c.Query("view") == "tree" {
+		h.getCommentTree(c, postID)
+		return
+	}
+
 	comments, err := h.commentUC.GetCommentsByPostID(c.Request.Context(), postID)
 	if err != nil {
+		if errors.Is(err, usecase.ErrPostNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	annotateComments(c, h.voteUC, commentPointers(comments))
 
 	c.JSON(http.StatusOK, comments)
 }
 
+func (h *CommentHandler) getCommentTree(c *gin.Context, postID int) {
+	params := entity.CommentTreeParams{Cursor: c.Query("cursor")}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		params.Limit = limit
+	}
+
+	page, err := h.commentUC.GetCommentTree(c.Request.Context(), postID, params)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, usecase.ErrPostNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	annotateComments(c, h.voteUC, page.Comments)
+
+	c.JSON(http.StatusOK, page)
+}
+
+// UpdateComment godoc
+// @Summary Edit comment
+// @Description Replace the content of a comment. Only the author or an admin can edit; the previous content is kept in the comment history. The comment keeps its format and content_html is re-rendered.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param comment_id path int true "Comment ID"
+// @Param comment body updateCommentRequest true "New content"
+// @Success 200 {object} entity.Comment
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/comments/{comment_id} [put]
+
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	postID, commentID, ok := parseCommentPath(c)
+	if !ok {
+		return
+	}
+
+	var req updateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.commentUC.UpdateComment(c.Request.Context(), postID, commentID, userID.(int), req.Content)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+	annotateComments(c, h.voteUC, []*entity.Comment{comment})
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// GetCommentRevisions godoc
+// @Summary Get comment edit history
+// @Description List the previous versions of a comment, oldest first. Available to moderators and the comment author.
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param comment_id path int true "Comment ID"
+// @Success 200 {array} entity.CommentRevision
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/comments/{comment_id}/revisions [get]
+
+func (h *CommentHandler) GetCommentRevisions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	postID, commentID, ok := parseCommentPath(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.commentUC.GetCommentRevisions(c.Request.Context(), postID, commentID, userID.(int))
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+func parseCommentPath(c *gin.Context) (postID, commentID int, ok bool) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return 0, 0, false
+	}
+	commentID, err = strconv.Atoi(c.Param("comment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment ID"})
+		return 0, 0, false
+	}
+	return postID, commentID, true
+}
+
+func respondCommentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrEmptyComment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrCommentNotFound), errors.Is(err, usecase.ErrPostNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrCommentEditForbidden), errors.Is(err, usecase.ErrCommentHistoryDenied),
+		errors.Is(err, usecase.ErrPostArchived):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 // DeleteComment godoc
 // @Summary Delete comment
-// @Description Delete a specific comment
+// @Description Move a comment to the trash. A comment with replies stays in the thread as a placeholder. Moderators can restore it until it is purged. Comments of an archived post can only be deleted by moderators.
 // @Tags comments
 // @Accept json
 // @Produce json
@@ -105,6 +281,7 @@ func (h *CommentHandler) GetComments(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} docs.Error
 // @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
 // @Failure 404 {object} docs.Error
 // @Failure 500 {object} docs.Error
 // @Router /posts/{id}/comments/{comment_id} [delete]
@@ -127,7 +304,11 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
 			return
 		}
-		if err.Error() == "unauthorized: you can only delete your own comments" {
+		if errors.Is(err, usecase.ErrCommentNotFound) || errors.Is(err, usecase.ErrPostNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "unauthorized: you can only delete your own comments" || errors.Is(err, usecase.ErrPostArchived) {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
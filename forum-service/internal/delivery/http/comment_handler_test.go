@@ -10,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -29,6 +30,30 @@ func (m *MockCommentUseCase) GetCommentsByPostID(ctx context.Context, postID int
 	return args.Get(0).([]entity.Comment), args.Error(1)
 }
 
+func (m *MockCommentUseCase) GetCommentTree(ctx context.Context, postID int, params entity.CommentTreeParams) (*entity.CommentTreePage, error) {
+	args := m.Called(ctx, postID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.CommentTreePage), args.Error(1)
+}
+
+func (m *MockCommentUseCase) UpdateComment(ctx context.Context, postID, commentID, userID int, content string) (*entity.Comment, error) {
+	args := m.Called(ctx, postID, commentID, userID, content)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Comment), args.Error(1)
+}
+
+func (m *MockCommentUseCase) GetCommentRevisions(ctx context.Context, postID, commentID, userID int) ([]entity.CommentRevision, error) {
+	args := m.Called(ctx, postID, commentID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.CommentRevision), args.Error(1)
+}
+
 func (m *MockCommentUseCase) DeleteComment(ctx context.Context, commentID, userID int) error {
 	args := m.Called(ctx, commentID, userID)
 	return args.Error(0)
@@ -38,7 +63,7 @@ func TestCreateComment(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockCommentUC := new(MockCommentUseCase)
-	handler := NewCommentHandler(mockCommentUC)
+	handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
 
 	// Create a test request with JSON body
 	requestBody := `{"content": "Test comment"}`
@@ -71,11 +96,45 @@ func TestCreateComment(t *testing.T) {
 	mockCommentUC.AssertExpectations(t)
 }
 
+func TestCreateCommentClosedPost(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{name: "Locked", err: &usecase.PostClosedError{PostID: 1, State: entity.PostLocked}, expectedCode: http.StatusForbidden},
+		{name: "Archived", err: &usecase.PostClosedError{PostID: 1, State: entity.PostArchived}, expectedCode: http.StatusForbidden},
+		{name: "PostNotFound", err: usecase.ErrPostNotFound, expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommentUC := new(MockCommentUseCase)
+			handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
+			mockCommentUC.On("CreateComment", mock.Anything, mock.AnythingOfType("*entity.Comment")).Return(tt.err)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/posts/1/comments", strings.NewReader(`{"content": "Test comment"}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", 1)
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			handler.CreateComment(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockCommentUC.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetComments(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockCommentUC := new(MockCommentUseCase)
-	handler := NewCommentHandler(mockCommentUC)
+	handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/posts/1/comments", nil)
@@ -101,7 +160,7 @@ func TestDeleteComment(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockCommentUC := new(MockCommentUseCase)
-	handler := NewCommentHandler(mockCommentUC)
+	handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/comments/1", nil)
@@ -123,7 +182,7 @@ func TestCreateCommentUnauthorized(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockCommentUC := new(MockCommentUseCase)
-	handler := NewCommentHandler(mockCommentUC)
+	handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("POST", "/posts/1/comments", strings.NewReader(`{"content": "Test"}`))
@@ -142,7 +201,7 @@ func TestCreateCommentInvalidPostID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockCommentUC := new(MockCommentUseCase)
-	handler := NewCommentHandler(mockCommentUC)
+	handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("POST", "/posts/invalid/comments", strings.NewReader(`{"content": "Test"}`))
@@ -162,7 +221,7 @@ func TestGetCommentsInvalidPostID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockCommentUC := new(MockCommentUseCase)
-	handler := NewCommentHandler(mockCommentUC)
+	handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/posts/invalid/comments", nil)
@@ -180,7 +239,7 @@ func TestDeleteCommentInvalidCommentID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockCommentUC := new(MockCommentUseCase)
-	handler := NewCommentHandler(mockCommentUC)
+	handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/comments/invalid", nil)
@@ -199,7 +258,7 @@ func TestDeleteCommentUnauthorized(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockCommentUC := new(MockCommentUseCase)
-	handler := NewCommentHandler(mockCommentUC)
+	handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/comments/1", nil)
@@ -217,7 +276,7 @@ func TestDeleteCommentNotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockCommentUC := new(MockCommentUseCase)
-	handler := NewCommentHandler(mockCommentUC)
+	handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/comments/1", nil)
@@ -234,3 +293,235 @@ func TestDeleteCommentNotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 	mockCommentUC.AssertExpectations(t)
 }
+
+func TestDeleteCommentArchivedPost(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockCommentUC := new(MockCommentUseCase)
+	handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/comments/1", nil)
+
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("user_id", 1)
+	c.Params = gin.Params{gin.Param{Key: "comment_id", Value: "1"}}
+
+	mockCommentUC.On("DeleteComment", mock.Anything, 1, 1).
+		Return(&usecase.PostClosedError{PostID: 5, State: entity.PostArchived})
+
+	handler.DeleteComment(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockCommentUC.AssertExpectations(t)
+}
+
+func TestCreateCommentReply(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockCommentUC := new(MockCommentUseCase)
+	handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
+
+	req, _ := http.NewRequest("POST", "/posts/1/comments", strings.NewReader(`{"content": "Reply", "parent_id": 5}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("user_id", 1)
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+	mockCommentUC.On("CreateComment", mock.Anything, mock.MatchedBy(func(cm *entity.Comment) bool {
+		return cm.ParentID != nil && *cm.ParentID == 5
+	})).Return(usecase.ErrCommentTooDeep)
+
+	handler.CreateComment(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockCommentUC.AssertExpectations(t)
+}
+
+func TestGetCommentsTree(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		query        string
+		mockSetup    func(*MockCommentUseCase)
+		expectedCode int
+	}{
+		{
+			name:  "Success",
+			query: "view=tree&limit=10&cursor=abc",
+			mockSetup: func(m *MockCommentUseCase) {
+				m.On("GetCommentTree", mock.Anything, 1, entity.CommentTreeParams{Cursor: "abc", Limit: 10}).
+					Return(&entity.CommentTreePage{Comments: []*entity.Comment{{ID: 1}}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "InvalidLimit",
+			query:        "view=tree&limit=-1",
+			mockSetup:    func(m *MockCommentUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "InvalidCursor",
+			query: "view=tree&cursor=bad",
+			mockSetup: func(m *MockCommentUseCase) {
+				m.On("GetCommentTree", mock.Anything, 1, entity.CommentTreeParams{Cursor: "bad"}).
+					Return(nil, usecase.ErrInvalidCursor)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "TreePostNotFound",
+			query: "view=tree",
+			mockSetup: func(m *MockCommentUseCase) {
+				m.On("GetCommentTree", mock.Anything, 1, entity.CommentTreeParams{}).
+					Return(nil, usecase.ErrPostNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:  "FlatPostNotFound",
+			query: "",
+			mockSetup: func(m *MockCommentUseCase) {
+				m.On("GetCommentsByPostID", mock.Anything, 1).
+					Return([]entity.Comment(nil), usecase.ErrPostNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommentUC := new(MockCommentUseCase)
+			handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
+			tt.mockSetup(mockCommentUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/posts/1/comments?"+tt.query, nil)
+			c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+			handler.GetComments(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockCommentUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateComment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         string
+		mockSetup    func(*MockCommentUseCase)
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			body: `{"content": "edited"}`,
+			mockSetup: func(m *MockCommentUseCase) {
+				m.On("UpdateComment", mock.Anything, 1, 2, 1, "edited").
+					Return(&entity.Comment{ID: 2, PostID: 1, Content: "edited"}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "MissingContent",
+			body:         `{}`,
+			mockSetup:    func(m *MockCommentUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Forbidden",
+			body: `{"content": "edited"}`,
+			mockSetup: func(m *MockCommentUseCase) {
+				m.On("UpdateComment", mock.Anything, 1, 2, 1, "edited").Return(nil, usecase.ErrCommentEditForbidden)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "PostArchived",
+			body: `{"content": "edited"}`,
+			mockSetup: func(m *MockCommentUseCase) {
+				m.On("UpdateComment", mock.Anything, 1, 2, 1, "edited").
+					Return(nil, &usecase.PostClosedError{PostID: 1, State: entity.PostArchived})
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "NotFound",
+			body: `{"content": "edited"}`,
+			mockSetup: func(m *MockCommentUseCase) {
+				m.On("UpdateComment", mock.Anything, 1, 2, 1, "edited").Return(nil, usecase.ErrCommentNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommentUC := new(MockCommentUseCase)
+			handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
+			tt.mockSetup(mockCommentUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("PUT", "/posts/1/comments/2", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", 1)
+			c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "comment_id", Value: "2"}}
+
+			handler.UpdateComment(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockCommentUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetCommentRevisions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockCommentUC := new(MockCommentUseCase)
+		handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
+		mockCommentUC.On("GetCommentRevisions", mock.Anything, 1, 2, 1).
+			Return([]entity.CommentRevision{{ID: 1, CommentID: 2, Content: "old"}}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/posts/1/comments/2/revisions", nil)
+		c.Set("user_id", 1)
+		c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "comment_id", Value: "2"}}
+
+		handler.GetCommentRevisions(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"content":"old"`)
+		mockCommentUC.AssertExpectations(t)
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		mockCommentUC := new(MockCommentUseCase)
+		handler := NewCommentHandler(mockCommentUC, passiveVoteUseCase())
+		mockCommentUC.On("GetCommentRevisions", mock.Anything, 1, 2, 1).Return(nil, usecase.ErrCommentHistoryDenied)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/posts/1/comments/2/revisions", nil)
+		c.Set("user_id", 1)
+		c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "comment_id", Value: "2"}}
+
+		handler.GetCommentRevisions(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockCommentUC.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,189 @@
+package delivery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/feed"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+)
+
+type FeedHandler struct {
+	feedUC  usecase.FeedUseCase
+	baseURL string
+}
+
+// NewFeedHandler - baseURL - публичный адрес форума, от него строятся ссылки в лентах
+func NewFeedHandler(feedUC usecase.FeedUseCase, baseURL string) *FeedHandler {
+	return &FeedHandler{
+		feedUC:  feedUC,
+		baseURL: baseURL,
+	}
+}
+
+// SiteFeed godoc
+// @Summary Feed of new posts
+// @Description Atom or RSS feed of the newest published posts of the forum. Supports conditional GET with If-None-Match and If-Modified-Since.
+// @Tags feeds
+// @Produce application/atom+xml
+// @Produce application/rss+xml
+// @Param If-None-Match header string false "ETag of a previously fetched feed"
+// @Param If-Modified-Since header string false "Last-Modified of a previously fetched feed"
+// @Success 200 {string} string "Feed document"
+// @Header 200 {string} ETag "Feed version"
+// @Header 200 {string} Last-Modified "Latest post update"
+// @Success 304 "Feed has not changed"
+// @Failure 500 {object} docs.Error
+// @Router /feeds/posts.atom [get]
+// @Router /feeds/posts.rss [get]
+
+func (h *FeedHandler) SiteFeed(c *gin.Context) {
+	f, err := h.feedUC.SiteFeed(c.Request.Context())
+	h.serveFeed(c, f, err)
+}
+
+// CategoryFeed godoc
+// @Summary Feed of new posts in a category
+// @Description Atom or RSS feed of the newest published posts in a category. Supports conditional GET with If-None-Match and If-Modified-Since.
+// @Tags feeds
+// @Produce application/atom+xml
+// @Produce application/rss+xml
+// @Param slug path string true "Category slug"
+// @Param If-None-Match header string false "ETag of a previously fetched feed"
+// @Param If-Modified-Since header string false "Last-Modified of a previously fetched feed"
+// @Success 200 {string} string "Feed document"
+// @Header 200 {string} ETag "Feed version"
+// @Header 200 {string} Last-Modified "Latest post update"
+// @Success 304 "Feed has not changed"
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /feeds/categories/{slug}/posts.atom [get]
+// @Router /feeds/categories/{slug}/posts.rss [get]
+
+func (h *FeedHandler) CategoryFeed(c *gin.Context) {
+	f, err := h.feedUC.CategoryFeed(c.Request.Context(), c.Param("slug"))
+	h.serveFeed(c, f, err)
+}
+
+// TagFeed godoc
+// @Summary Feed of new posts with a tag
+// @Description Atom or RSS feed of the newest published posts tagged with name. Supports conditional GET with If-None-Match and If-Modified-Since.
+// @Tags feeds
+// @Produce application/atom+xml
+// @Produce application/rss+xml
+// @Param name path string true "Tag name"
+// @Param If-None-Match header string false "ETag of a previously fetched feed"
+// @Param If-Modified-Since header string false "Last-Modified of a previously fetched feed"
+// @Success 200 {string} string "Feed document"
+// @Header 200 {string} ETag "Feed version"
+// @Header 200 {string} Last-Modified "Latest post update"
+// @Success 304 "Feed has not changed"
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /feeds/tags/{name}/posts.atom [get]
+// @Router /feeds/tags/{name}/posts.rss [get]
+
+func (h *FeedHandler) TagFeed(c *gin.Context) {
+	f, err := h.feedUC.TagFeed(c.Request.Context(), c.Param("name"))
+	h.serveFeed(c, f, err)
+}
+
+// UserFeed godoc
+// @Summary Feed of new posts by a user
+// @Description Atom or RSS feed of the newest published posts of a user. Supports conditional GET with If-None-Match and If-Modified-Since.
+// @Tags feeds
+// @Produce application/atom+xml
+// @Produce application/rss+xml
+// @Param id path int true "User ID"
+// @Param If-None-Match header string false "ETag of a previously fetched feed"
+// @Param If-Modified-Since header string false "Last-Modified of a previously fetched feed"
+// @Success 200 {string} string "Feed document"
+// @Header 200 {string} ETag "Feed version"
+// @Header 200 {string} Last-Modified "Latest post update"
+// @Success 304 "Feed has not changed"
+// @Failure 400 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /feeds/users/{id}/posts.atom [get]
+// @Router /feeds/users/{id}/posts.rss [get]
+
+func (h *FeedHandler) UserFeed(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	f, err := h.feedUC.UserFeed(c.Request.Context(), userID)
+	h.serveFeed(c, f, err)
+}
+
+// serveFeed отдает ленту в формате, указанном расширением пути. ETag строится по
+// содержимому ленты, Last-Modified - по последнему обновлению ее постов.
+func (h *FeedHandler) serveFeed(c *gin.Context, f *entity.Feed, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrCategoryNotFound), errors.Is(err, usecase.ErrTagNotFound),
+			errors.Is(err, usecase.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			log.Printf("[ERROR] Feed: Failed to build feed %s: %v", c.Request.URL.Path, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build feed"})
+		}
+		return
+	}
+
+	format := feed.Atom
+	if strings.HasSuffix(c.Request.URL.Path, ".rss") {
+		format = feed.RSS
+	}
+	body, err := feed.Render(format, f, h.baseURL, c.Request.URL.Path)
+	if err != nil {
+		log.Printf("[ERROR] Feed: Failed to render feed %s: %v", c.Request.URL.Path, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to render feed"})
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	if !f.Updated.IsZero() {
+		c.Header("Last-Modified", f.Updated.UTC().Format(http.TimeFormat))
+	}
+	if feedNotModified(c.Request, etag, f.Updated) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Data(http.StatusOK, format.ContentType(), body)
+}
+
+// feedNotModified сообщает, что у клиента актуальная лента. If-None-Match
+// проверяется первым; If-Modified-Since учитывается только без него.
+func feedNotModified(r *http.Request, etag string, updated time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if updated.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	// Last-Modified передается с точностью до секунды
+	return !updated.Truncate(time.Second).After(since)
+}
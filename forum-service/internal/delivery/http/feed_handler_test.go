@@ -0,0 +1,210 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockFeedUseCase - мок для FeedUseCase
+type MockFeedUseCase struct {
+	mock.Mock
+}
+
+func (m *MockFeedUseCase) SiteFeed(ctx context.Context) (*entity.Feed, error) {
+	return m.result(m.Called(ctx))
+}
+
+func (m *MockFeedUseCase) CategoryFeed(ctx context.Context, slug string) (*entity.Feed, error) {
+	return m.result(m.Called(ctx, slug))
+}
+
+func (m *MockFeedUseCase) TagFeed(ctx context.Context, name string) (*entity.Feed, error) {
+	return m.result(m.Called(ctx, name))
+}
+
+func (m *MockFeedUseCase) UserFeed(ctx context.Context, userID int) (*entity.Feed, error) {
+	return m.result(m.Called(ctx, userID))
+}
+
+func (m *MockFeedUseCase) result(args mock.Arguments) (*entity.Feed, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Feed), args.Error(1)
+}
+
+var feedUpdated = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func testSiteFeed() *entity.Feed {
+	return &entity.Feed{
+		Title:   "Forum",
+		Path:    "/posts",
+		Updated: feedUpdated,
+		Posts:   []*entity.Post{{ID: 1, Title: "Hello", Author: "alice", CreatedAt: feedUpdated, UpdatedAt: feedUpdated}},
+	}
+}
+
+func serveFeedRequest(h *FeedHandler, handle gin.HandlerFunc, path string, params gin.Params, header http.Header) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", path, nil)
+	for k, v := range header {
+		c.Request.Header[k] = v
+	}
+	c.Params = params
+	handle(c)
+	return w
+}
+
+func TestFeedHandler_SiteFeed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(MockFeedUseCase)
+	mockUC.On("SiteFeed", mock.Anything).Return(testSiteFeed(), nil)
+	handler := NewFeedHandler(mockUC, "https://forum.example")
+
+	w := serveFeedRequest(handler, handler.SiteFeed, "/feeds/posts.atom", nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "Sat, 01 Mar 2025 12:00:00 GMT", w.Header().Get("Last-Modified"))
+	assert.Contains(t, w.Body.String(), `<link rel="self" type="application/atom+xml" href="https://forum.example/feeds/posts.atom">`)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	rss := serveFeedRequest(handler, handler.SiteFeed, "/feeds/posts.rss", nil, nil)
+	require.Equal(t, http.StatusOK, rss.Code)
+	assert.Equal(t, "application/rss+xml; charset=utf-8", rss.Header().Get("Content-Type"))
+	assert.Contains(t, rss.Body.String(), `<rss version="2.0"`)
+	assert.NotEqual(t, etag, rss.Header().Get("ETag"))
+
+	tests := []struct {
+		name         string
+		header       http.Header
+		expectedCode int
+	}{
+		{"MatchingETag", http.Header{"If-None-Match": {`"other", ` + etag}}, http.StatusNotModified},
+		{"WeakETag", http.Header{"If-None-Match": {"W/" + etag}}, http.StatusNotModified},
+		{"StaleETag", http.Header{"If-None-Match": {`"other"`}}, http.StatusOK},
+		// If-None-Match важнее If-Modified-Since
+		{"StaleETagFreshDate", http.Header{"If-None-Match": {`"other"`}, "If-Modified-Since": {"Sat, 01 Mar 2025 12:00:00 GMT"}}, http.StatusOK},
+		{"NotModifiedSince", http.Header{"If-Modified-Since": {"Sat, 01 Mar 2025 12:00:00 GMT"}}, http.StatusNotModified},
+		{"ModifiedSince", http.Header{"If-Modified-Since": {"Sat, 01 Mar 2025 11:59:59 GMT"}}, http.StatusOK},
+		{"InvalidDate", http.Header{"If-Modified-Since": {"yesterday"}}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveFeedRequest(handler, handler.SiteFeed, "/feeds/posts.atom", nil, tt.header)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			if tt.expectedCode == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestFeedHandler_ScopedFeeds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		path         string
+		params       gin.Params
+		handle       func(h *FeedHandler) gin.HandlerFunc
+		mockSetup    func(m *MockFeedUseCase)
+		expectedCode int
+	}{
+		{
+			name:   "Category",
+			path:   "/feeds/categories/go/posts.rss",
+			params: gin.Params{{Key: "slug", Value: "go"}},
+			handle: func(h *FeedHandler) gin.HandlerFunc { return h.CategoryFeed },
+			mockSetup: func(m *MockFeedUseCase) {
+				m.On("CategoryFeed", mock.Anything, "go").Return(testSiteFeed(), nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "UnknownCategory",
+			path:   "/feeds/categories/nope/posts.atom",
+			params: gin.Params{{Key: "slug", Value: "nope"}},
+			handle: func(h *FeedHandler) gin.HandlerFunc { return h.CategoryFeed },
+			mockSetup: func(m *MockFeedUseCase) {
+				m.On("CategoryFeed", mock.Anything, "nope").Return(nil, usecase.ErrCategoryNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "UnknownTag",
+			path:   "/feeds/tags/rust/posts.atom",
+			params: gin.Params{{Key: "name", Value: "rust"}},
+			handle: func(h *FeedHandler) gin.HandlerFunc { return h.TagFeed },
+			mockSetup: func(m *MockFeedUseCase) {
+				m.On("TagFeed", mock.Anything, "rust").Return(nil, usecase.ErrTagNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "User",
+			path:   "/feeds/users/7/posts.atom",
+			params: gin.Params{{Key: "id", Value: "7"}},
+			handle: func(h *FeedHandler) gin.HandlerFunc { return h.UserFeed },
+			mockSetup: func(m *MockFeedUseCase) {
+				m.On("UserFeed", mock.Anything, 7).Return(&entity.Feed{Title: "alice", Path: "/users/7"}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "InvalidUserID",
+			path:         "/feeds/users/x/posts.atom",
+			params:       gin.Params{{Key: "id", Value: "x"}},
+			handle:       func(h *FeedHandler) gin.HandlerFunc { return h.UserFeed },
+			mockSetup:    func(m *MockFeedUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "UnknownUser",
+			path:   "/feeds/users/9/posts.atom",
+			params: gin.Params{{Key: "id", Value: "9"}},
+			handle: func(h *FeedHandler) gin.HandlerFunc { return h.UserFeed },
+			mockSetup: func(m *MockFeedUseCase) {
+				m.On("UserFeed", mock.Anything, 9).Return(nil, usecase.ErrUserNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockFeedUseCase)
+			tt.mockSetup(mockUC)
+			handler := NewFeedHandler(mockUC, "https://forum.example")
+
+			w := serveFeedRequest(handler, tt.handle(handler), tt.path, tt.params, nil)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFeedHandler_EmptyFeedHasNoLastModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(MockFeedUseCase)
+	mockUC.On("SiteFeed", mock.Anything).Return(&entity.Feed{Title: "Forum", Path: "/posts"}, nil)
+	handler := NewFeedHandler(mockUC, "https://forum.example")
+
+	w := serveFeedRequest(handler, handler.SiteFeed, "/feeds/posts.atom", nil, http.Header{"If-Modified-Since": {"Sat, 01 Mar 2025 12:00:00 GMT"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Last-Modified"))
+}
@@ -1,13 +1,16 @@
 package delivery
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/config"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
 )
 
 type authUseCase interface {
@@ -96,12 +99,7 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(cfg.Auth.SecretKey), nil
-		})
+		token, err := jwt.Parse(tokenString, hmacKeyFunc(cfg))
 
 		if err != nil {
 			abortWithAuthError(c, "Invalid token", "invalid_token", "details", err.Error())
@@ -125,6 +123,69 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 	}
 }
 
+// OptionalAuthMiddleware sets the same context keys as AuthMiddleware when the request
+// carries a valid token and lets anonymous requests through, so public endpoints can
+// personalize responses. An invalid token is treated as no token.
+func OptionalAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := extractToken(c)
+		if tokenString == "" {
+			c.Next()
+			return
+		}
+
+		token, err := jwt.Parse(tokenString, hmacKeyFunc(cfg))
+		if err == nil && token.Valid {
+			if claims, ok := token.Claims.(jwt.MapClaims); ok {
+				if userID, username, role, err := extractClaims(claims); err == nil {
+					c.Set("user_id", userID)
+					c.Set("username", username)
+					c.Set("user_role", role)
+				}
+			}
+		}
+		c.Next()
+	}
+}
+
+// BanMiddleware rejects requests that create or change content when the user set by
+// AuthMiddleware is banned. Reads and deletions pass through.
+func BanMiddleware(bans usecase.BanChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+		if !exists {
+			c.Next()
+			return
+		}
+
+		if err := bans.CheckNotBanned(c.Request.Context(), userID.(int)); err != nil {
+			if errors.Is(err, usecase.ErrUserBanned) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": "user_banned"})
+				return
+			}
+			log.Printf("[ERROR] BanMiddleware: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check ban"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func hmacKeyFunc(cfg *config.Config) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(cfg.Auth.SecretKey), nil
+	}
+}
+
 func extractClaims(claims jwt.MapClaims) (int, string, string, error) {
 	userID, ok := claims["user_id"].(float64)
 	if !ok {
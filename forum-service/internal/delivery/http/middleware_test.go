@@ -205,6 +205,61 @@ func TestAuthMiddleware_OptionsRequest(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestOptionalAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		Auth: struct {
+			AccessTokenDuration  time.Duration
+			RefreshTokenDuration time.Duration
+			SecretKey            string
+		}{
+			SecretKey: "test-secret-key",
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":  7,
+		"username": "viewer",
+		"exp":      time.Now().Add(time.Hour).Unix(),
+	})
+	tokenString, err := token.SignedString([]byte("test-secret-key"))
+	if err != nil {
+		t.Fatalf("Failed to create test token: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		header     string
+		wantUserID bool
+	}{
+		{name: "ValidToken", header: "Bearer " + tokenString, wantUserID: true},
+		{name: "NoToken"},
+		{name: "InvalidToken", header: "Bearer invalid.token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("Authorization", tt.header)
+			}
+
+			OptionalAuthMiddleware(cfg)(c)
+
+			// Анонимный запрос не отклоняется
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.False(t, c.IsAborted())
+			_, exists := c.Get("user_id")
+			assert.Equal(t, tt.wantUserID, exists)
+			if tt.wantUserID {
+				assert.Equal(t, 7, c.GetInt("user_id"))
+			}
+		})
+	}
+}
+
 func TestExtractClaims_Valid(t *testing.T) {
 	claims := jwt.MapClaims{
 		"user_id":  float64(1),
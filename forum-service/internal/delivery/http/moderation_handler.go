@@ -0,0 +1,321 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+)
+
+type ModerationHandler struct {
+	moderationUC usecase.ModerationUseCase
+}
+
+func NewModerationHandler(moderationUC usecase.ModerationUseCase) *ModerationHandler {
+	return &ModerationHandler{moderationUC: moderationUC}
+}
+
+// CreateReport godoc
+// @Summary Report content
+// @Description Report a post, comment or chat message to moderators. Reporting the same content again while the report is open updates its reason and note; the response is then 200 instead of 201.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param report body entity.ReportInput true "Report" SchemaExample({"target_type":"comment","target_id":42,"reason":"spam","note":"link farm"})
+// @Success 201 {object} entity.Report
+// @Success 200 {object} entity.Report "An open report was updated"
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error "Own content"
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /reports [post]
+
+func (h *ModerationHandler) CreateReport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var input entity.ReportInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	report, created, err := h.moderationUC.Report(c.Request.Context(), userID.(int), input)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, report)
+}
+
+// ListReportQueue godoc
+// @Summary List open reports
+// @Description Open reports grouped by reported content, oldest first. Requires the moderator role. Pass next_cursor back as cursor for the next page.
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param target_type query string false "Only reports on post, comment or chat_message"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} entity.ReportQueuePage
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /moderation/reports [get]
+
+func (h *ModerationHandler) ListReportQueue(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	params := entity.ReportQueueParams{
+		TargetType: entity.ReportTarget(c.Query("target_type")),
+		Cursor:     c.Query("cursor"),
+	}
+	limit, ok := moderationLimit(c)
+	if !ok {
+		return
+	}
+	params.Limit = limit
+
+	page, err := h.moderationUC.ListReportQueue(c.Request.Context(), userID.(int), params)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// ResolveReports godoc
+// @Summary Resolve reports on content
+// @Description Close all open reports on the content with an action: dismiss, hide, delete, warn or ban the author. Requires the moderator role. The decision is recorded in the moderation log.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param target_type path string true "post, comment or chat_message"
+// @Param target_id path int true "Content ID"
+// @Param resolution body entity.ModerationResolution true "Decision" SchemaExample({"action":"ban","note":"repeated spam","ban_days":7})
+// @Success 200 {object} entity.ModerationLogEntry
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /moderation/reports/{target_type}/{target_id}/resolve [post]
+
+func (h *ModerationHandler) ResolveReports(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	targetID, err := strconv.Atoi(c.Param("target_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid target ID"})
+		return
+	}
+
+	var resolution entity.ModerationResolution
+	if err := c.ShouldBindJSON(&resolution); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	entry, err := h.moderationUC.ResolveReports(c.Request.Context(), userID.(int),
+		entity.ReportTarget(c.Param("target_type")), targetID, resolution)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// ListModerationLog godoc
+// @Summary Moderation log
+// @Description Moderator decisions, newest first. Requires the moderator role. Pass next_cursor back as cursor for the next page.
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query int false "Only decisions on content of this user"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} entity.ModerationLogPage
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /moderation/log [get]
+
+func (h *ModerationHandler) ListModerationLog(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	params := entity.ModerationLogParams{Cursor: c.Query("cursor")}
+	if targetUser := c.Query("user_id"); targetUser != "" {
+		v, err := strconv.Atoi(targetUser)
+		if err != nil || v <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id parameter"})
+			return
+		}
+		params.TargetUserID = v
+	}
+	limit, ok := moderationLimit(c)
+	if !ok {
+		return
+	}
+	params.Limit = limit
+
+	page, err := h.moderationUC.ListModerationLog(c.Request.Context(), userID.(int), params)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// SetPostState godoc
+// @Summary Lock, archive or reopen a post
+// @Description Locked posts accept no new comments; archived posts and their comments are read-only. Requires the moderator role. A change is recorded in the moderation log.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param state body entity.PostStateInput true "New state" SchemaExample({"state":"locked","note":"cooling down"})
+// @Success 200 {object} entity.PostStatus
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /moderation/posts/{id}/state [put]
+
+func (h *ModerationHandler) SetPostState(c *gin.Context) {
+	userID, postID, ok := moderatedPost(c)
+	if !ok {
+		return
+	}
+
+	var input entity.PostStateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	status, err := h.moderationUC.SetPostState(c.Request.Context(), userID, postID, input)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// SetPostPinned godoc
+// @Summary Pin or unpin a post
+// @Description Pinned posts are listed before all others. Requires the moderator role. A change is recorded in the moderation log.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param pin body entity.PostPinInput true "Pinned flag" SchemaExample({"pinned":true,"note":"forum rules"})
+// @Success 200 {object} entity.PostStatus
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /moderation/posts/{id}/pin [put]
+
+func (h *ModerationHandler) SetPostPinned(c *gin.Context) {
+	userID, postID, ok := moderatedPost(c)
+	if !ok {
+		return
+	}
+
+	var input entity.PostPinInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	status, err := h.moderationUC.SetPostPinned(c.Request.Context(), userID, postID, input)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+func moderatedPost(c *gin.Context) (userID, postID int, ok bool) {
+	id, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return 0, 0, false
+	}
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return 0, 0, false
+	}
+	return id.(int), postID, true
+}
+
+func moderationLimit(c *gin.Context) (int, bool) {
+	limit := c.Query("limit")
+	if limit == "" {
+		return 0, true
+	}
+	v, err := strconv.Atoi(limit)
+	if err != nil || v < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+		return 0, false
+	}
+	return v, true
+}
+
+func respondModerationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidReportTarget),
+		errors.Is(err, usecase.ErrInvalidReportReason),
+		errors.Is(err, usecase.ErrModerationNoteTooLong),
+		errors.Is(err, usecase.ErrInvalidModerationAction),
+		errors.Is(err, usecase.ErrInvalidBanDuration),
+		errors.Is(err, usecase.ErrInvalidPostState),
+		errors.Is(err, usecase.ErrInvalidTrashTarget),
+		errors.Is(err, usecase.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrModeratorRequired),
+		errors.Is(err, usecase.ErrCannotReportOwn),
+		errors.Is(err, usecase.ErrCannotSanctionModerator):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrReportTargetNotFound),
+		errors.Is(err, usecase.ErrNoOpenReports),
+		errors.Is(err, usecase.ErrTrashItemNotFound),
+		errors.Is(err, usecase.ErrUserNotFound),
+		errors.Is(err, usecase.ErrPostNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,484 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockModerationUseCase - мок для ModerationUseCase
+type MockModerationUseCase struct {
+	mock.Mock
+}
+
+func (m *MockModerationUseCase) CheckNotBanned(ctx context.Context, userID int) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func (m *MockModerationUseCase) Report(ctx context.Context, reporterID int, input entity.ReportInput) (*entity.Report, bool, error) {
+	args := m.Called(ctx, reporterID, input)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*entity.Report), args.Bool(1), args.Error(2)
+}
+
+func (m *MockModerationUseCase) ListReportQueue(ctx context.Context, moderatorID int, params entity.ReportQueueParams) (*entity.ReportQueuePage, error) {
+	args := m.Called(ctx, moderatorID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ReportQueuePage), args.Error(1)
+}
+
+func (m *MockModerationUseCase) ResolveReports(ctx context.Context, moderatorID int, target entity.ReportTarget, targetID int, resolution entity.ModerationResolution) (*entity.ModerationLogEntry, error) {
+	args := m.Called(ctx, moderatorID, target, targetID, resolution)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ModerationLogEntry), args.Error(1)
+}
+
+func (m *MockModerationUseCase) ListModerationLog(ctx context.Context, moderatorID int, params entity.ModerationLogParams) (*entity.ModerationLogPage, error) {
+	args := m.Called(ctx, moderatorID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ModerationLogPage), args.Error(1)
+}
+
+func (m *MockModerationUseCase) SetPostState(ctx context.Context, moderatorID, postID int, input entity.PostStateInput) (*entity.PostStatus, error) {
+	args := m.Called(ctx, moderatorID, postID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PostStatus), args.Error(1)
+}
+
+func (m *MockModerationUseCase) SetPostPinned(ctx context.Context, moderatorID, postID int, input entity.PostPinInput) (*entity.PostStatus, error) {
+	args := m.Called(ctx, moderatorID, postID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PostStatus), args.Error(1)
+}
+
+func TestCreateReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	input := entity.ReportInput{TargetType: entity.ReportOnComment, TargetID: 4, Reason: entity.ReasonSpam}
+	body := `{"target_type":"comment","target_id":4,"reason":"spam"}`
+
+	tests := []struct {
+		name         string
+		body         string
+		mockSetup    func(m *MockModerationUseCase)
+		expectedCode int
+	}{
+		{
+			name: "Created",
+			body: body,
+			mockSetup: func(m *MockModerationUseCase) {
+				m.On("Report", mock.Anything, 1, input).Return(&entity.Report{ID: 3}, true, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "Coalesced",
+			body: body,
+			mockSetup: func(m *MockModerationUseCase) {
+				m.On("Report", mock.Anything, 1, input).Return(&entity.Report{ID: 3}, false, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "MissingReason",
+			body:         `{"target_type":"comment","target_id":4}`,
+			mockSetup:    func(m *MockModerationUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "OwnContent",
+			body: body,
+			mockSetup: func(m *MockModerationUseCase) {
+				m.On("Report", mock.Anything, 1, input).Return(nil, false, usecase.ErrCannotReportOwn)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "TargetNotFound",
+			body: body,
+			mockSetup: func(m *MockModerationUseCase) {
+				m.On("Report", mock.Anything, 1, input).Return(nil, false, usecase.ErrReportTargetNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockModerationUseCase)
+			handler := NewModerationHandler(mockUC)
+			tt.mockSetup(mockUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/reports", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", 1)
+
+			handler.CreateReport(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListReportQueue(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		query        string
+		mockSetup    func(m *MockModerationUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:  "Success",
+			query: "?target_type=post&limit=5&cursor=12",
+			mockSetup: func(m *MockModerationUseCase) {
+				m.On("ListReportQueue", mock.Anything, 1, entity.ReportQueueParams{TargetType: entity.ReportOnPost, Cursor: "12", Limit: 5}).
+					Return(&entity.ReportQueuePage{Groups: []*entity.ReportGroup{{TargetType: entity.ReportOnPost, TargetID: 7, ReportCount: 2}}}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"report_count":2`,
+		},
+		{
+			name:         "InvalidLimit",
+			query:        "?limit=-1",
+			mockSetup:    func(m *MockModerationUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "NotModerator",
+			mockSetup: func(m *MockModerationUseCase) {
+				m.On("ListReportQueue", mock.Anything, 1, entity.ReportQueueParams{}).Return(nil, usecase.ErrModeratorRequired)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockModerationUseCase)
+			handler := NewModerationHandler(mockUC)
+			tt.mockSetup(mockUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/moderation/reports"+tt.query, nil)
+			c.Set("user_id", 1)
+
+			handler.ListReportQueue(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResolveReports(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resolution := entity.ModerationResolution{Action: entity.ModerationBan, Note: "spam", BanDays: 7}
+	body := `{"action":"ban","note":"spam","ban_days":7}`
+
+	tests := []struct {
+		name         string
+		targetID     string
+		body         string
+		mockSetup    func(m *MockModerationUseCase)
+		expectedCode int
+	}{
+		{
+			name:     "Success",
+			targetID: "4",
+			body:     body,
+			mockSetup: func(m *MockModerationUseCase) {
+				m.On("ResolveReports", mock.Anything, 1, entity.ReportOnComment, 4, resolution).
+					Return(&entity.ModerationLogEntry{ID: 5, Action: entity.ModerationBan}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "InvalidTargetID",
+			targetID:     "abc",
+			body:         body,
+			mockSetup:    func(m *MockModerationUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "MissingAction",
+			targetID:     "4",
+			body:         `{"note":"x"}`,
+			mockSetup:    func(m *MockModerationUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:     "NoOpenReports",
+			targetID: "4",
+			body:     body,
+			mockSetup: func(m *MockModerationUseCase) {
+				m.On("ResolveReports", mock.Anything, 1, entity.ReportOnComment, 4, resolution).Return(nil, usecase.ErrNoOpenReports)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:     "SanctionModerator",
+			targetID: "4",
+			body:     body,
+			mockSetup: func(m *MockModerationUseCase) {
+				m.On("ResolveReports", mock.Anything, 1, entity.ReportOnComment, 4, resolution).Return(nil, usecase.ErrCannotSanctionModerator)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockModerationUseCase)
+			handler := NewModerationHandler(mockUC)
+			tt.mockSetup(mockUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/moderation/reports/comment/"+tt.targetID+"/resolve", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "target_type", Value: "comment"}, {Key: "target_id", Value: tt.targetID}}
+			c.Set("user_id", 1)
+
+			handler.ResolveReports(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListModerationLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUC := new(MockModerationUseCase)
+		handler := NewModerationHandler(mockUC)
+		mockUC.On("ListModerationLog", mock.Anything, 1, entity.ModerationLogParams{TargetUserID: 3, Cursor: "9"}).
+			Return(&entity.ModerationLogPage{Entries: []*entity.ModerationLogEntry{{ID: 8, Action: entity.ModerationWarn}}}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/moderation/log?user_id=3&cursor=9", nil)
+		c.Set("user_id", 1)
+
+		handler.ListModerationLog(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"action":"warn"`)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("InvalidUserID", func(t *testing.T) {
+		handler := NewModerationHandler(new(MockModerationUseCase))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/moderation/log?user_id=x", nil)
+		c.Set("user_id", 1)
+
+		handler.ListModerationLog(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestSetPostState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	input := entity.PostStateInput{State: entity.PostLocked, Note: "flame war"}
+	body := `{"state":"locked","note":"flame war"}`
+
+	tests := []struct {
+		name         string
+		postID       string
+		body         string
+		mockSetup    func(m *MockModerationUseCase)
+		expectedCode int
+	}{
+		{
+			name:   "Success",
+			postID: "4",
+			body:   body,
+			mockSetup: func(m *MockModerationUseCase) {
+				m.On("SetPostState", mock.Anything, 1, 4, input).Return(&entity.PostStatus{PostID: 4, State: entity.PostLocked}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "InvalidPostID",
+			postID:       "abc",
+			body:         body,
+			mockSetup:    func(m *MockModerationUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "MissingState",
+			postID:       "4",
+			body:         `{"note":"x"}`,
+			mockSetup:    func(m *MockModerationUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "InvalidState",
+			postID: "4",
+			body:   `{"state":"closed"}`,
+			mockSetup: func(m *MockModerationUseCase) {
+				m.On("SetPostState", mock.Anything, 1, 4, entity.PostStateInput{State: "closed"}).Return(nil, usecase.ErrInvalidPostState)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "PostNotFound",
+			postID: "4",
+			body:   body,
+			mockSetup: func(m *MockModerationUseCase) {
+				m.On("SetPostState", mock.Anything, 1, 4, input).Return(nil, usecase.ErrPostNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "NotModerator",
+			postID: "4",
+			body:   body,
+			mockSetup: func(m *MockModerationUseCase) {
+				m.On("SetPostState", mock.Anything, 1, 4, input).Return(nil, usecase.ErrModeratorRequired)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockModerationUseCase)
+			handler := NewModerationHandler(mockUC)
+			tt.mockSetup(mockUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("PUT", "/moderation/posts/"+tt.postID+"/state", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.postID}}
+			c.Set("user_id", 1)
+
+			handler.SetPostState(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetPostPinned(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUC := new(MockModerationUseCase)
+		handler := NewModerationHandler(mockUC)
+		pinned := true
+		mockUC.On("SetPostPinned", mock.Anything, 1, 4, entity.PostPinInput{Pinned: &pinned}).
+			Return(&entity.PostStatus{PostID: 4, State: entity.PostOpen, Pinned: true}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("PUT", "/moderation/posts/4/pin", strings.NewReader(`{"pinned":true}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: "4"}}
+		c.Set("user_id", 1)
+
+		handler.SetPostPinned(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"pinned":true`)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("MissingFlag", func(t *testing.T) {
+		handler := NewModerationHandler(new(MockModerationUseCase))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("PUT", "/moderation/posts/4/pin", strings.NewReader(`{}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: "4"}}
+		c.Set("user_id", 1)
+
+		handler.SetPostPinned(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestBanMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		method       string
+		auth         bool
+		banErr       error
+		checked      bool
+		expectedCode int
+		aborted      bool
+	}{
+		{name: "NotBanned", method: "POST", auth: true, checked: true, expectedCode: http.StatusOK},
+		{name: "Banned", method: "PUT", auth: true, checked: true, banErr: &usecase.UserBannedError{Ban: &entity.UserBan{UserID: 1}}, expectedCode: http.StatusForbidden, aborted: true},
+		{name: "CheckFailed", method: "POST", auth: true, checked: true, banErr: errors.New("database error"), expectedCode: http.StatusInternalServerError, aborted: true},
+		{name: "ReadPassesThrough", method: "GET", auth: true, expectedCode: http.StatusOK},
+		{name: "DeletePassesThrough", method: "DELETE", auth: true, expectedCode: http.StatusOK},
+		{name: "Anonymous", method: "POST", expectedCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockModerationUseCase)
+			if tt.checked {
+				mockUC.On("CheckNotBanned", mock.Anything, 1).Return(tt.banErr)
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(tt.method, "/", nil)
+			if tt.auth {
+				c.Set("user_id", 1)
+			}
+
+			BanMiddleware(mockUC)(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.aborted, c.IsAborted())
+			if tt.expectedCode == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), `"code":"user_banned"`)
+			}
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
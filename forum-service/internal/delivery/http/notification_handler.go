@@ -0,0 +1,296 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+)
+
+type NotificationHandler struct {
+	notificationUC usecase.NotificationUseCase
+}
+
+func NewNotificationHandler(notificationUC usecase.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{notificationUC: notificationUC}
+}
+
+type subscriptionResponse struct {
+	Subscribed bool `json:"subscribed"`
+}
+
+type unreadCountResponse struct {
+	UnreadCount int `json:"unread_count"`
+}
+
+type markAllReadResponse struct {
+	Marked int `json:"marked"`
+}
+
+// ListNotifications godoc
+// @Summary List notifications
+// @Description Notifications of the current user, newest first, with the total unread count. Pass next_cursor back as cursor for the next page.
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Only unread notifications"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} entity.NotificationPage
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /notifications [get]
+
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	params := entity.NotificationListParams{Cursor: c.Query("cursor")}
+	if unread := c.Query("unread"); unread != "" {
+		v, err := strconv.ParseBool(unread)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid unread parameter"})
+			return
+		}
+		params.UnreadOnly = v
+	}
+	if limit := c.Query("limit"); limit != "" {
+		v, err := strconv.Atoi(limit)
+		if err != nil || v < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+			return
+		}
+		params.Limit = v
+	}
+
+	page, err := h.notificationUC.ListNotifications(c.Request.Context(), userID.(int), params)
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// UnreadCount godoc
+// @Summary Count unread notifications
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} unreadCountResponse
+// @Failure 401 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /notifications/unread-count [get]
+
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	count, err := h.notificationUC.UnreadCount(c.Request.Context(), userID.(int))
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, unreadCountResponse{UnreadCount: count})
+}
+
+// MarkRead godoc
+// @Summary Mark a notification as read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Notification ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /notifications/{id}/read [post]
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification ID"})
+		return
+	}
+
+	if err := h.notificationUC.MarkRead(c.Request.Context(), userID.(int), id); err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "notification marked as read"})
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications as read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} markAllReadResponse
+// @Failure 401 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /notifications/read-all [post]
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	marked, err := h.notificationUC.MarkAllRead(c.Request.Context(), userID.(int))
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, markAllReadResponse{Marked: marked})
+}
+
+// HandleWebSocket godoc
+// @Summary Live notifications
+// @Description WebSocket that pushes each new notification of the current user as a JSON entity.Notification. Browsers pass the token in the token query parameter.
+// @Tags notifications
+// @Security BearerAuth
+// @Param token query string false "Access token"
+// @Success 101 "Switching protocols to WebSocket"
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Router /notifications/ws [get]
+
+func (h *NotificationHandler) HandleWebSocket(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to upgrade connection"})
+		return
+	}
+	h.notificationUC.HandleWebSocket(userID.(int), conn)
+}
+
+// GetSubscription godoc
+// @Summary Check the post subscription
+// @Description Whether the current user is notified about new comments on the post.
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Success 200 {object} subscriptionResponse
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/subscription [get]
+
+func (h *NotificationHandler) GetSubscription(c *gin.Context) {
+	userID, postID, ok := subscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	subscribed, err := h.notificationUC.IsSubscribed(c.Request.Context(), userID, postID)
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, subscriptionResponse{Subscribed: subscribed})
+}
+
+// Subscribe godoc
+// @Summary Subscribe to a post
+// @Description Notify the current user about new comments on the post. Authors are subscribed to their posts automatically.
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Success 200 {object} subscriptionResponse
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/subscription [post]
+
+func (h *NotificationHandler) Subscribe(c *gin.Context) {
+	userID, postID, ok := subscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.notificationUC.Subscribe(c.Request.Context(), userID, postID); err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, subscriptionResponse{Subscribed: true})
+}
+
+// Unsubscribe godoc
+// @Summary Unsubscribe from a post
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Success 200 {object} subscriptionResponse
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/subscription [delete]
+
+func (h *NotificationHandler) Unsubscribe(c *gin.Context) {
+	userID, postID, ok := subscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.notificationUC.Unsubscribe(c.Request.Context(), userID, postID); err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, subscriptionResponse{Subscribed: false})
+}
+
+// subscriptionParams извлекает пользователя и id поста; при ошибке ответ уже отправлен
+func subscriptionParams(c *gin.Context) (userID, postID int, ok bool) {
+	id, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return 0, 0, false
+	}
+
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return 0, 0, false
+	}
+	return id.(int), postID, true
+}
+
+func respondNotificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidNotificationCursor):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrNotificationNotFound), errors.Is(err, usecase.ErrPostNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
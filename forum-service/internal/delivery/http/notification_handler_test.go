@@ -0,0 +1,225 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotificationUseCase - мок для NotificationUseCase
+type MockNotificationUseCase struct {
+	mock.Mock
+}
+
+func (m *MockNotificationUseCase) CommentCreated(ctx context.Context, comment *entity.Comment) {
+	m.Called(ctx, comment)
+}
+
+func (m *MockNotificationUseCase) Subscribe(ctx context.Context, userID, postID int) error {
+	return m.Called(ctx, userID, postID).Error(0)
+}
+
+func (m *MockNotificationUseCase) Unsubscribe(ctx context.Context, userID, postID int) error {
+	return m.Called(ctx, userID, postID).Error(0)
+}
+
+func (m *MockNotificationUseCase) IsSubscribed(ctx context.Context, userID, postID int) (bool, error) {
+	args := m.Called(ctx, userID, postID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationUseCase) ListNotifications(ctx context.Context, userID int, params entity.NotificationListParams) (*entity.NotificationPage, error) {
+	args := m.Called(ctx, userID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.NotificationPage), args.Error(1)
+}
+
+func (m *MockNotificationUseCase) MarkRead(ctx context.Context, userID, notificationID int) error {
+	return m.Called(ctx, userID, notificationID).Error(0)
+}
+
+func (m *MockNotificationUseCase) MarkAllRead(ctx context.Context, userID int) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationUseCase) UnreadCount(ctx context.Context, userID int) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationUseCase) HandleWebSocket(userID int, conn usecase.WebSocketConnection) {
+	m.Called(userID, conn)
+}
+
+func TestListNotifications(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		query        string
+		auth         bool
+		mockSetup    func(m *MockNotificationUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:  "Success",
+			query: "?unread=true&limit=10&cursor=15",
+			auth:  true,
+			mockSetup: func(m *MockNotificationUseCase) {
+				m.On("ListNotifications", mock.Anything, 1, entity.NotificationListParams{UnreadOnly: true, Cursor: "15", Limit: 10}).
+					Return(&entity.NotificationPage{
+						Notifications: []*entity.Notification{{ID: 14, UserID: 1, Type: entity.NotificationReply}},
+						UnreadCount:   3,
+					}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"unread_count":3`,
+		},
+		{
+			name:         "Unauthorized",
+			mockSetup:    func(m *MockNotificationUseCase) {},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "InvalidUnread",
+			query:        "?unread=maybe",
+			auth:         true,
+			mockSetup:    func(m *MockNotificationUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "InvalidCursor",
+			query: "?cursor=abc",
+			auth:  true,
+			mockSetup: func(m *MockNotificationUseCase) {
+				m.On("ListNotifications", mock.Anything, 1, entity.NotificationListParams{Cursor: "abc"}).
+					Return(nil, usecase.ErrInvalidNotificationCursor)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockNotificationUseCase)
+			handler := NewNotificationHandler(mockUC)
+			tt.mockSetup(mockUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/notifications"+tt.query, nil)
+			if tt.auth {
+				c.Set("user_id", 1)
+			}
+
+			handler.ListNotifications(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMarkNotificationRead(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		id           string
+		mockSetup    func(m *MockNotificationUseCase)
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			id:   "5",
+			mockSetup: func(m *MockNotificationUseCase) {
+				m.On("MarkRead", mock.Anything, 1, 5).Return(nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "NotFound",
+			id:   "6",
+			mockSetup: func(m *MockNotificationUseCase) {
+				m.On("MarkRead", mock.Anything, 1, 6).Return(usecase.ErrNotificationNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "InvalidID",
+			id:           "abc",
+			mockSetup:    func(m *MockNotificationUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockNotificationUseCase)
+			handler := NewNotificationHandler(mockUC)
+			tt.mockSetup(mockUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/notifications/"+tt.id+"/read", nil)
+			c.Set("user_id", 1)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			handler.MarkRead(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUC := new(MockNotificationUseCase)
+		handler := NewNotificationHandler(mockUC)
+		mockUC.On("Subscribe", mock.Anything, 1, 3).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/posts/3/subscription", nil)
+		c.Set("user_id", 1)
+		c.Params = gin.Params{{Key: "id", Value: "3"}}
+
+		handler.Subscribe(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"subscribed":true}`, w.Body.String())
+	})
+
+	t.Run("PostNotFound", func(t *testing.T) {
+		mockUC := new(MockNotificationUseCase)
+		handler := NewNotificationHandler(mockUC)
+		mockUC.On("Unsubscribe", mock.Anything, 1, 3).Return(usecase.ErrPostNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("DELETE", "/posts/3/subscription", nil)
+		c.Set("user_id", 1)
+		c.Params = gin.Params{{Key: "id", Value: "3"}}
+
+		handler.Unsubscribe(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
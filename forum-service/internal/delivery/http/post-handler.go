@@ -1,10 +1,16 @@
 package delivery
 
 import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
@@ -15,32 +21,39 @@ type PostHandler struct {
 	postUC    usecase.PostUseCase
 	commentUC usecase.CommentUseCaseInterface
 	userUC    usecase.UserUseCaseInterface
+	voteUC    usecase.VoteUseCase
+	viewUC    usecase.ViewUseCase
 }
 
 func NewPostHandler(
 	postUC usecase.PostUseCase,
 	commentUC usecase.CommentUseCaseInterface,
 	userUC usecase.UserUseCaseInterface,
+	voteUC usecase.VoteUseCase,
+	viewUC usecase.ViewUseCase,
 ) *PostHandler {
 	return &PostHandler{
 		postUC:    postUC,
 		commentUC: commentUC,
 		userUC:    userUC,
+		voteUC:    voteUC,
+		viewUC:    viewUC,
 	}
 }
 
 // CreatePost godoc
 // @Summary Create a new post
-// @Description Create a new forum post. Requires Bearer token authentication.
+// @Description Create a new forum post. format is plain (default) or markdown; the response carries the source and its sanitized HTML in content_html. status is published (default), draft, or scheduled with a future publish_at; unpublished posts are seen only by their author. Requires Bearer token authentication.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param Authorization header string true "Bearer <token>"
-// @Param post body entity.Post true "Post object" SchemaExample({"title":"My Post","content":"Post content"})
+// @Param post body entity.Post true "Post object" SchemaExample({"title":"My Post","content":"Post **content**","format":"markdown","category_id":1,"tags":["go","concurrency"],"attachment_ids":[7],"status":"scheduled","publish_at":"2030-01-01T09:00:00Z"})
 // @Success 201 {object} entity.Post
-// @Failure 400 {object} docs.Error "Invalid request format"
+// @Failure 400 {object} docs.Error "Invalid request format, unknown category, unavailable attachment or invalid status"
 // @Failure 401 {object} docs.Error "Missing or invalid authentication token"
+// @Failure 403 {object} docs.Error "Category is read-only"
 // @Failure 500 {object} docs.Error "Server error"
 // @Router /posts [post]
 
@@ -86,7 +99,18 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 	post.Author = user.Username
 
 	if err := h.postUC.CreatePost(c.Request.Context(), &post); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, usecase.ErrCategoryRequired), errors.Is(err, usecase.ErrCategoryNotFound),
+			errors.Is(err, usecase.ErrInvalidTag), errors.Is(err, usecase.ErrTooManyTags),
+			errors.Is(err, usecase.ErrInvalidFormat), errors.Is(err, usecase.ErrAttachmentNotFound),
+			errors.Is(err, usecase.ErrTooManyAttachments), errors.Is(err, usecase.ErrInvalidPublishStatus),
+			errors.Is(err, usecase.ErrInvalidPublishAt):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrCategoryReadOnly):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -95,7 +119,7 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 
 // GetPostByID godoc
 // @Summary Get post by ID
-// @Description Retrieve a specific post by its ID
+// @Description Retrieve a specific post by its ID. Drafts and scheduled posts are found only by their author. A view of a published post is counted once per user, or per anonymous client, within the deduplication window; view_count is updated in batches.
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -113,11 +137,15 @@ func (h *PostHandler) GetPostByID(c *gin.Context) {
 		return
 	}
 
-	post, err := h.postUC.GetPostByID(c.Request.Context(), id)
+	post, err := h.postUC.GetPostByID(c.Request.Context(), id, viewerID(c))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	c.Header("ETag", postETag(post.Version))
+	if !post.Unpublished() {
+		h.viewUC.RecordView(post.ID, viewerKey(c))
+	}
 
 	// Получаем имя автора
 	user, err := h.userUC.GetUserByID(c.Request.Context(), post.UserID)
@@ -125,10 +153,14 @@ func (h *PostHandler) GetPostByID(c *gin.Context) {
 		post.Author = user.Username
 	}
 
-	comments, err := h.commentUC.GetCommentsByPostID(c.Request.Context(), id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	// У черновика, который видит только автор, комментариев нет
+	comments := []entity.Comment{}
+	if !post.Unpublished() {
+		comments, err = h.commentUC.GetCommentsByPostID(c.Request.Context(), id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	// Получаем имена авторов комментариев
@@ -138,6 +170,8 @@ func (h *PostHandler) GetPostByID(c *gin.Context) {
 			comments[i].Author = user.Username
 		}
 	}
+	annotatePosts(c, h.voteUC, post)
+	annotateComments(c, h.voteUC, commentPointers(comments))
 
 	response := gin.H{
 		"post":     post,
@@ -148,13 +182,22 @@ func (h *PostHandler) GetPostByID(c *gin.Context) {
 }
 
 // GetAllPosts godoc
-// @Summary Get all posts
-// @Description Retrieve a list of all forum posts
+// @Summary List posts
+// @Description Retrieve a page of forum posts. Pass next_cursor from the previous page as cursor to get the next one.
 // @Tags posts
 // @Accept json
 // @Produce json
+// @Param sort query string false "Sort order" Enums(newest, oldest, most_commented, recently_active, hot, top, rising, most_viewed) default(newest)
+// @Param window query string false "Period for sort=top (posts created) and sort=most_viewed (views)" Enums(day, week, all) default(day)
+// @Param author query string false "Author username"
+// @Param tag query string false "Tag name"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param cursor query string false "Opaque cursor from next_cursor"
 // @Param includeComments query boolean false "Include comments in response"
-// @Success 200 {array} entity.Post
+// @Success 200 {object} entity.PostPage
+// @Failure 400 {object} docs.Error
 // @Failure 500 {object} docs.Error
 // @Router /posts [get]
 
@@ -162,18 +205,23 @@ func (h *PostHandler) GetAllPosts(c *gin.Context) {
 	log.Printf("[DEBUG] GetAllPosts: Handler started")
 	includeComments := c.Query("includeComments") == "true"
 
-	posts, err := h.postUC.GetAllPosts(c.Request.Context())
+	params, err := parsePostListParams(c)
 	if err != nil {
-		log.Printf("[ERROR] GetAllPosts: Failed to get posts: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if posts == nil {
-		log.Printf("[DEBUG] GetAllPosts: No posts found, returning empty array")
-		c.JSON(http.StatusOK, []entity.Post{})
+	page, err := h.postUC.ListPosts(c.Request.Context(), params)
+	if err != nil {
+		if usecase.IsPostListInputError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("[ERROR] GetAllPosts: Failed to get posts: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	posts := page.Posts
 
 	log.Printf("[DEBUG] GetAllPosts: Retrieved %d posts", len(posts))
 
@@ -188,6 +236,10 @@ func (h *PostHandler) GetAllPosts(c *gin.Context) {
 
 		if includeComments {
 			comments, err := h.commentUC.GetCommentsByPostID(c.Request.Context(), posts[i].ID)
+			// Пост могли скрыть уже после выборки страницы
+			if errors.Is(err, usecase.ErrPostNotFound) {
+				comments, err = []entity.Comment{}, nil
+			}
 			if err != nil {
 				log.Printf("[ERROR] GetAllPosts: Failed to get comments for post %d: %v", posts[i].ID, err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -204,17 +256,57 @@ func (h *PostHandler) GetAllPosts(c *gin.Context) {
 				comments[j].Author = user.Username
 			}
 
+			annotateComments(c, h.voteUC, commentPointers(comments))
 			posts[i].Comments = comments
 		}
 	}
+	annotatePosts(c, h.voteUC, posts...)
 
 	log.Printf("[DEBUG] GetAllPosts: Sending response with %d posts", len(posts))
-	c.JSON(http.StatusOK, posts)
+	c.JSON(http.StatusOK, page)
+}
+
+// parsePostListParams reads sort, filters and paging from the query string.
+func parsePostListParams(c *gin.Context) (entity.PostListParams, error) {
+	params := entity.PostListParams{
+		Sort:   entity.PostSort(c.Query("sort")),
+		Window: entity.TopWindow(c.Query("window")),
+		Cursor: c.Query("cursor"),
+		Filter: entity.PostFilter{Author: c.Query("author"), Tag: c.Query("tag")},
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return params, errors.New("invalid limit")
+		}
+		params.Limit = limit
+	}
+
+	var err error
+	if params.Filter.From, err = parseDateParam(c.Query("from")); err != nil {
+		return params, fmt.Errorf("invalid from: %w", err)
+	}
+	if params.Filter.To, err = parseDateParam(c.Query("to")); err != nil {
+		return params, fmt.Errorf("invalid to: %w", err)
+	}
+	return params, nil
+}
+
+// parseDateParam accepts RFC3339 timestamps and plain dates; an empty value means no bound.
+func parseDateParam(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, v)
 }
 
 // DeletePost godoc
 // @Summary Delete post
-// @Description Delete a specific post
+// @Description Move a post, with its comments, to the trash. Moderators can restore it until it is purged. An archived post can only be deleted by moderators.
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -223,6 +315,8 @@ func (h *PostHandler) GetAllPosts(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} docs.Error
 // @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 404 {object} docs.Error
 // @Failure 500 {object} docs.Error
 // @Router /posts/{id} [delete]
 
@@ -240,6 +334,14 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 	}
 
 	if err := h.postUC.DeletePost(c.Request.Context(), postID, userID.(int)); err != nil {
+		if errors.Is(err, usecase.ErrPostNotFound) || errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": usecase.ErrPostNotFound.Error()})
+			return
+		}
+		if errors.Is(err, usecase.ErrPostDeleteForbidden) || errors.Is(err, usecase.ErrPostArchived) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -249,18 +351,22 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 
 // UpdatePost godoc
 // @Summary Update post
-// @Description Update a specific post. Only the owner or admin can update.
+// @Description Update a specific post. Only the owner or admin can update. Tags are replaced when the tags field is present and kept otherwise, and so is the format. A changed title or content is saved as a new revision with the optional edit summary. If-Match must carry the ETag returned by GET /posts/{id}; if the post has changed since, the update is rejected with 412 and the current version.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Post ID"
-// @Param post body entity.Post true "Post object"
+// @Param If-Match header string true "ETag of the edited post version"
+// @Param post body entity.PostUpdate true "Post update"
 // @Success 200 {object} entity.Post
+// @Header 200 {string} ETag "New post version"
 // @Failure 400 {object} docs.Error
 // @Failure 401 {object} docs.Error
-// @Failure 403 {object} docs.Error
+// @Failure 403 {object} docs.Error "Not the author, or the post is archived"
 // @Failure 404 {object} docs.Error
+// @Failure 412 {object} versionConflictResponse "Post was modified; body carries the current version"
+// @Failure 428 {object} docs.Error "Missing If-Match"
 // @Failure 500 {object} docs.Error
 // @Router /posts/{id} [put]
 func (h *PostHandler) UpdatePost(c *gin.Context) {
@@ -276,15 +382,37 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	var req entity.Post
+	version, ok := postIfMatch(c)
+	if !ok {
+		return
+	}
+
+	var req entity.PostUpdate
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.Version = version
 
-	err = h.postUC.UpdatePost(c.Request.Context(), postID, userID.(int), req.Title, req.Content)
+	err = h.postUC.UpdatePost(c.Request.Context(), postID, userID.(int), req)
 	if err != nil {
-		if err.Error() == "unauthorized: you can only update your own posts" {
+		if respondVersionConflict(c, err) {
+			return
+		}
+		if errors.Is(err, usecase.ErrPostVersionRequired) {
+			c.JSON(http.StatusPreconditionRequired, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, usecase.ErrPostNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, usecase.ErrInvalidTag) || errors.Is(err, usecase.ErrTooManyTags) ||
+			errors.Is(err, usecase.ErrEditSummaryTooLong) || errors.Is(err, usecase.ErrInvalidFormat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, usecase.ErrPostEditForbidden) || errors.Is(err, usecase.ErrPostArchived) {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
@@ -292,10 +420,179 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	updatedPost, err := h.postUC.GetPostByID(c.Request.Context(), postID)
+	updatedPost, err := h.postUC.GetPostByID(c.Request.Context(), postID, userID.(int))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	c.Header("ETag", postETag(updatedPost.Version))
+	annotatePosts(c, h.voteUC, updatedPost)
 	c.JSON(http.StatusOK, updatedPost)
 }
+
+// ListDrafts godoc
+// @Summary List own drafts
+// @Description Drafts and scheduled posts of the current user, newest first. Pass next_cursor back as cursor for the next page.
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Success 200 {object} entity.PostPage
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /posts/drafts [get]
+
+func (h *PostHandler) ListDrafts(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	params := entity.DraftListParams{Cursor: c.Query("cursor")}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		params.Limit = limit
+	}
+
+	page, err := h.postUC.ListDrafts(c.Request.Context(), userID.(int), params)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// SetPublication godoc
+// @Summary Publish or schedule a draft
+// @Description Publish an own draft or scheduled post now (status published), schedule it for a future publish_at (status scheduled) or move a scheduled post back to drafts (status draft). A post published now is listed as new; mentioned users are notified on publication.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param publication body entity.PostPublication true "New status" SchemaExample({"status":"scheduled","publish_at":"2030-01-01T09:00:00Z"})
+// @Success 200 {object} entity.Post
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error "Not the author"
+// @Failure 404 {object} docs.Error
+// @Failure 409 {object} docs.Error "Post is already published"
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/publication [put]
+
+func (h *PostHandler) SetPublication(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var publication entity.PostPublication
+	if err := c.ShouldBindJSON(&publication); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	post, err := h.postUC.SetPublication(c.Request.Context(), postID, userID.(int), publication)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidPublishStatus), errors.Is(err, usecase.ErrInvalidPublishAt):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrPostEditForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrPostNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrPostAlreadyPublished):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, post)
+}
+
+// viewerKey - читатель, повторные просмотры которого не засчитываются: пользователь
+// или, без входа, отпечаток клиента по IP и User-Agent
+func viewerKey(c *gin.Context) string {
+	if id := viewerID(c); id != 0 {
+		return "user:" + strconv.Itoa(id)
+	}
+	sum := sha256.Sum256([]byte(c.ClientIP() + "\x00" + c.Request.UserAgent()))
+	return "anon:" + hex.EncodeToString(sum[:16])
+}
+
+// versionConflictResponse - тело ответа 412: текущая версия поста и сам пост,
+// чтобы клиент мог перенести правку на актуальный текст
+type versionConflictResponse struct {
+	Error          string       `json:"error"`
+	CurrentVersion int          `json:"current_version"`
+	Post           *entity.Post `json:"post"`
+}
+
+// respondVersionConflict отвечает 412 с текущей версией поста, если err - конфликт версий
+func respondVersionConflict(c *gin.Context, err error) bool {
+	var conflict *usecase.PostVersionConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	c.Header("ETag", postETag(conflict.Current.Version))
+	c.JSON(http.StatusPreconditionFailed, versionConflictResponse{
+		Error:          err.Error(),
+		CurrentVersion: conflict.Current.Version,
+		Post:           conflict.Current,
+	})
+	return true
+}
+
+// postIfMatch читает версию поста из If-Match. Без заголовка отвечает 428,
+// при неверном формате - 400.
+func postIfMatch(c *gin.Context) (int, bool) {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": usecase.ErrPostVersionRequired.Error()})
+		return 0, false
+	}
+	version, err := parsePostETag(ifMatch)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid If-Match: expected the post ETag"})
+		return 0, false
+	}
+	return version, true
+}
+
+// postETag - ETag поста, строится по его версии
+func postETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// parsePostETag извлекает версию из If-Match. Слабая форма W/"N" тоже принимается,
+// "*" и списки ETag - нет: правка должна указывать конкретную версию.
+func parsePostETag(value string) (int, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, errors.New("malformed ETag")
+	}
+	version, err := strconv.Atoi(value[1 : len(value)-1])
+	if err != nil || version <= 0 {
+		return 0, errors.New("malformed ETag")
+	}
+	return version, nil
+}
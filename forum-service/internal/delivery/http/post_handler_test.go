@@ -2,10 +2,13 @@ package delivery
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
@@ -24,25 +27,44 @@ func (m *MockPostUseCase) CreatePost(ctx context.Context, post *entity.Post) err
 	return args.Error(0)
 }
 
-func (m *MockPostUseCase) GetPostByID(ctx context.Context, id int) (*entity.Post, error) {
-	args := m.Called(ctx, id)
+func (m *MockPostUseCase) GetPostByID(ctx context.Context, id, viewerID int) (*entity.Post, error) {
+	args := m.Called(ctx, id, viewerID)
 	return args.Get(0).(*entity.Post), args.Error(1)
 }
 
-func (m *MockPostUseCase) GetAllPosts(ctx context.Context) ([]*entity.Post, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]*entity.Post), args.Error(1)
+func (m *MockPostUseCase) ListPosts(ctx context.Context, params entity.PostListParams) (*entity.PostPage, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PostPage), args.Error(1)
 }
 
 func (m *MockPostUseCase) DeletePost(ctx context.Context, postID, userID int) error {
 	args := m.Called(ctx, postID, userID)
 	return args.Error(0)
 }
-func (m *MockPostUseCase) UpdatePost(ctx context.Context, postID, userID int, title, content string) error {
-	args := m.Called(ctx, postID, userID, title, content)
+func (m *MockPostUseCase) UpdatePost(ctx context.Context, postID, userID int, update entity.PostUpdate) error {
+	args := m.Called(ctx, postID, userID, update)
 	return args.Error(0)
 }
 
+func (m *MockPostUseCase) ListDrafts(ctx context.Context, userID int, params entity.DraftListParams) (*entity.PostPage, error) {
+	args := m.Called(ctx, userID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PostPage), args.Error(1)
+}
+
+func (m *MockPostUseCase) SetPublication(ctx context.Context, postID, userID int, publication entity.PostPublication) (*entity.Post, error) {
+	args := m.Called(ctx, postID, userID, publication)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
 // MockUserUseCase
 type MockUserUseCase struct {
 	mock.Mock
@@ -65,12 +87,36 @@ func (m *MockUserUseCase) GetUsersByIDs(ctx context.Context, ids []int) (map[int
 
 var _ usecase.PostUseCase = (*MockPostUseCase)(nil)
 
+// MockViewUseCase - мок для ViewUseCase
+type MockViewUseCase struct {
+	mock.Mock
+}
+
+func (m *MockViewUseCase) RecordView(postID int, viewer string) {
+	m.Called(postID, viewer)
+}
+
+func (m *MockViewUseCase) Flush(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *MockViewUseCase) Run(ctx context.Context, interval time.Duration) {
+	m.Called(ctx, interval)
+}
+
+// passiveViewUseCase принимает любые просмотры
+func passiveViewUseCase() *MockViewUseCase {
+	m := new(MockViewUseCase)
+	m.On("RecordView", mock.Anything, mock.Anything).Return().Maybe()
+	return m
+}
+
 func TestNewPostHandler(t *testing.T) {
 	mockPostUC := new(MockPostUseCase)
 	mockCommentUC := new(MockCommentUseCase)
 	mockUserUC := new(MockUserUseCase)
 
-	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC)
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
 	assert.NotNil(t, handler)
 }
 
@@ -100,7 +146,7 @@ func TestPostHandler_CreatePost_Success(t *testing.T) {
 	c.Request = httptest.NewRequest("POST", "/posts", strings.NewReader(postJSON))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC)
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
 	handler.CreatePost(c)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
@@ -119,7 +165,7 @@ func TestPostHandler_CreatePost_Unauthorized(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request = httptest.NewRequest("POST", "/posts", nil)
 
-	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC)
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
 	handler.CreatePost(c)
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -138,7 +184,7 @@ func TestPostHandler_CreatePost_BadRequest(t *testing.T) {
 	c.Request = httptest.NewRequest("POST", "/posts", nil)
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC)
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
 	handler.CreatePost(c)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -155,7 +201,7 @@ func TestPostHandler_GetPostByID_Success(t *testing.T) {
 	testUser := &entity.User{ID: 1, Username: "testuser"}
 	testComments := []entity.Comment{{ID: 1, PostID: 1, UserID: 1}}
 
-	mockPostUC.On("GetPostByID", mock.Anything, 1).Return(testPost, nil)
+	mockPostUC.On("GetPostByID", mock.Anything, 1, 0).Return(testPost, nil)
 	mockUserUC.On("GetUserByID", mock.Anything, 1).Return(testUser, nil).Twice()
 	mockCommentUC.On("GetCommentsByPostID", mock.Anything, 1).Return(testComments, nil)
 
@@ -164,7 +210,7 @@ func TestPostHandler_GetPostByID_Success(t *testing.T) {
 	c.Request = httptest.NewRequest("GET", "/posts/1", nil)
 	c.Params = gin.Params{{Key: "id", Value: "1"}}
 
-	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC)
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
 	handler.GetPostByID(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
@@ -187,7 +233,7 @@ func TestPostHandler_GetPostByID_InvalidID(t *testing.T) {
 	c.Request = httptest.NewRequest("GET", "/posts/invalid", nil)
 	c.Params = gin.Params{{Key: "id", Value: "invalid"}}
 
-	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC)
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
 	handler.GetPostByID(c)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -200,14 +246,14 @@ func TestPostHandler_GetPostByID_NotFound(t *testing.T) {
 	mockCommentUC := new(MockCommentUseCase)
 	mockUserUC := new(MockUserUseCase)
 
-	mockPostUC.On("GetPostByID", mock.Anything, 1).Return((*entity.Post)(nil), assert.AnError)
+	mockPostUC.On("GetPostByID", mock.Anything, 1, 0).Return((*entity.Post)(nil), assert.AnError)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = httptest.NewRequest("GET", "/posts/1", nil)
 	c.Params = gin.Params{{Key: "id", Value: "1"}}
 
-	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC)
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
 	handler.GetPostByID(c)
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
@@ -224,7 +270,8 @@ func TestPostHandler_GetAllPosts_Success(t *testing.T) {
 	testUser1 := &entity.User{ID: 1, Username: "user1"}
 	testUser2 := &entity.User{ID: 2, Username: "user2"}
 
-	mockPostUC.On("GetAllPosts", mock.Anything).Return(testPosts, nil)
+	mockPostUC.On("ListPosts", mock.Anything, entity.PostListParams{}).
+		Return(&entity.PostPage{Posts: testPosts, NextCursor: "next"}, nil)
 	mockUserUC.On("GetUserByID", mock.Anything, 1).Return(testUser1, nil)
 	mockUserUC.On("GetUserByID", mock.Anything, 2).Return(testUser2, nil)
 
@@ -232,14 +279,96 @@ func TestPostHandler_GetAllPosts_Success(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request = httptest.NewRequest("GET", "/posts", nil)
 
-	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC)
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
 	handler.GetAllPosts(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"next_cursor":"next"`)
 	mockPostUC.AssertExpectations(t)
 	mockUserUC.AssertExpectations(t)
 }
 
+func TestPostHandler_GetAllPosts_Params(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		query      string
+		params     *entity.PostListParams
+		ucErr      error
+		wantStatus int
+	}{
+		{
+			name:  "SortFiltersAndCursor",
+			query: "sort=most_commented&author=alice&from=2025-05-01&to=2025-06-01T00:00:00Z&limit=10&cursor=abc",
+			params: &entity.PostListParams{
+				Sort: entity.PostSortMostCommented,
+				Filter: entity.PostFilter{
+					Author: "alice",
+					From:   time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+					To:     time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+				},
+				Cursor: "abc",
+				Limit:  10,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "InvalidLimit",
+			query:      "limit=-1",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "InvalidDate",
+			query:      "from=yesterday",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "TopWithWindow",
+			query:      "sort=top&window=week",
+			params:     &entity.PostListParams{Sort: entity.PostSortTop, Window: entity.TopWindowWeek},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "InvalidWindow",
+			query:      "sort=hot&window=week",
+			params:     &entity.PostListParams{Sort: entity.PostSortHot, Window: entity.TopWindowWeek},
+			ucErr:      usecase.ErrInvalidWindow,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "InvalidCursor",
+			query:      "cursor=broken",
+			params:     &entity.PostListParams{Cursor: "broken"},
+			ucErr:      usecase.ErrInvalidCursor,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPostUC := new(MockPostUseCase)
+			if tt.params != nil {
+				if tt.ucErr != nil {
+					mockPostUC.On("ListPosts", mock.Anything, *tt.params).Return(nil, tt.ucErr)
+				} else {
+					mockPostUC.On("ListPosts", mock.Anything, *tt.params).Return(&entity.PostPage{Posts: []*entity.Post{}}, nil)
+				}
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/posts?"+tt.query, nil)
+
+			handler := NewPostHandler(mockPostUC, new(MockCommentUseCase), new(MockUserUseCase), passiveVoteUseCase(), passiveViewUseCase())
+			handler.GetAllPosts(c)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockPostUC.AssertExpectations(t)
+		})
+	}
+}
+
 func TestPostHandler_GetAllPosts_WithComments(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -253,7 +382,7 @@ func TestPostHandler_GetAllPosts_WithComments(t *testing.T) {
 	testComments1 := []entity.Comment{{ID: 1, PostID: 1, UserID: 1}}
 	testComments2 := []entity.Comment{}
 
-	mockPostUC.On("GetAllPosts", mock.Anything).Return(testPosts, nil)
+	mockPostUC.On("ListPosts", mock.Anything, entity.PostListParams{}).Return(&entity.PostPage{Posts: testPosts}, nil)
 	mockUserUC.On("GetUserByID", mock.Anything, 1).Return(testUser1, nil).Twice()
 	mockUserUC.On("GetUserByID", mock.Anything, 2).Return(testUser2, nil)
 	mockCommentUC.On("GetCommentsByPostID", mock.Anything, 1).Return(testComments1, nil)
@@ -263,7 +392,7 @@ func TestPostHandler_GetAllPosts_WithComments(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request = httptest.NewRequest("GET", "/posts?includeComments=true", nil)
 
-	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC)
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
 	handler.GetAllPosts(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
@@ -287,7 +416,7 @@ func TestPostHandler_DeletePost_Success(t *testing.T) {
 	c.Request = httptest.NewRequest("DELETE", "/posts/1", nil)
 	c.Params = gin.Params{{Key: "id", Value: "1"}}
 
-	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC)
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
 	handler.DeletePost(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
@@ -306,7 +435,7 @@ func TestPostHandler_DeletePost_Unauthorized(t *testing.T) {
 	c.Request = httptest.NewRequest("DELETE", "/posts/1", nil)
 	c.Params = gin.Params{{Key: "id", Value: "1"}}
 
-	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC)
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
 	handler.DeletePost(c)
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -325,7 +454,7 @@ func TestPostHandler_DeletePost_InvalidID(t *testing.T) {
 	c.Request = httptest.NewRequest("DELETE", "/posts/invalid", nil)
 	c.Params = gin.Params{{Key: "id", Value: "invalid"}}
 
-	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC)
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
 	handler.DeletePost(c)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -346,9 +475,388 @@ func TestPostHandler_DeletePost_InternalError(t *testing.T) {
 	c.Request = httptest.NewRequest("DELETE", "/posts/1", nil)
 	c.Params = gin.Params{{Key: "id", Value: "1"}}
 
-	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC)
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
 	handler.DeletePost(c)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	mockPostUC.AssertExpectations(t)
 }
+
+func TestPostHandler_DeletePost_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"NotFound", usecase.ErrPostNotFound, http.StatusNotFound},
+		{"NoRows", fmt.Errorf("failed to delete post: %w", sql.ErrNoRows), http.StatusNotFound},
+		{"NotOwner", usecase.ErrPostDeleteForbidden, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPostUC := new(MockPostUseCase)
+			mockPostUC.On("DeletePost", mock.Anything, 1, 2).Return(tt.err)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("user_id", 2)
+			c.Request = httptest.NewRequest("DELETE", "/posts/1", nil)
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			handler := NewPostHandler(mockPostUC, new(MockCommentUseCase), new(MockUserUseCase), passiveVoteUseCase(), passiveViewUseCase())
+			handler.DeletePost(c)
+
+			assert.Equal(t, tt.expected, w.Code)
+			mockPostUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostHandler_DeletePost_Archived(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockPostUC := new(MockPostUseCase)
+	mockCommentUC := new(MockCommentUseCase)
+	mockUserUC := new(MockUserUseCase)
+
+	mockPostUC.On("DeletePost", mock.Anything, 1, 1).
+		Return(&usecase.PostClosedError{PostID: 1, State: entity.PostArchived})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", 1)
+	c.Request = httptest.NewRequest("DELETE", "/posts/1", nil)
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
+	handler.DeletePost(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockPostUC.AssertExpectations(t)
+}
+
+func TestPostHandler_GetPostByID_ETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockPostUC := new(MockPostUseCase)
+	mockCommentUC := new(MockCommentUseCase)
+	mockUserUC := new(MockUserUseCase)
+
+	mockPostUC.On("GetPostByID", mock.Anything, 1, 0).Return(&entity.Post{ID: 1, UserID: 1, Version: 4}, nil)
+	mockUserUC.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1}, nil)
+	mockCommentUC.On("GetCommentsByPostID", mock.Anything, 1).Return([]entity.Comment{}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/posts/1", nil)
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), passiveViewUseCase())
+	handler.GetPostByID(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+}
+
+func TestPostHandler_UpdatePost(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	update := entity.PostUpdate{Title: "T", Content: "C", Version: 3}
+
+	tests := []struct {
+		name         string
+		ifMatch      string
+		mockSetup    func(*MockPostUseCase)
+		expectedCode int
+		expectedETag string
+		expectedBody string
+	}{
+		{
+			name:    "Success",
+			ifMatch: `"3"`,
+			mockSetup: func(m *MockPostUseCase) {
+				m.On("UpdatePost", mock.Anything, 1, 1, update).Return(nil)
+				m.On("GetPostByID", mock.Anything, 1, 1).Return(&entity.Post{ID: 1, Version: 4}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedETag: `"4"`,
+		},
+		{
+			name:    "WeakETag",
+			ifMatch: `W/"3"`,
+			mockSetup: func(m *MockPostUseCase) {
+				m.On("UpdatePost", mock.Anything, 1, 1, update).Return(nil)
+				m.On("GetPostByID", mock.Anything, 1, 1).Return(&entity.Post{ID: 1, Version: 4}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedETag: `"4"`,
+		},
+		{
+			name:         "MissingIfMatch",
+			mockSetup:    func(m *MockPostUseCase) {},
+			expectedCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:         "MalformedIfMatch",
+			ifMatch:      "*",
+			mockSetup:    func(m *MockPostUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:    "Conflict",
+			ifMatch: `"3"`,
+			mockSetup: func(m *MockPostUseCase) {
+				m.On("UpdatePost", mock.Anything, 1, 1, update).
+					Return(&usecase.PostVersionConflictError{Current: &entity.Post{ID: 1, Version: 5}})
+			},
+			expectedCode: http.StatusPreconditionFailed,
+			expectedETag: `"5"`,
+			expectedBody: `"current_version":5`,
+		},
+		{
+			name:    "Forbidden",
+			ifMatch: `"3"`,
+			mockSetup: func(m *MockPostUseCase) {
+				m.On("UpdatePost", mock.Anything, 1, 1, update).Return(usecase.ErrPostEditForbidden)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:    "InvalidFormat",
+			ifMatch: `"3"`,
+			mockSetup: func(m *MockPostUseCase) {
+				m.On("UpdatePost", mock.Anything, 1, 1, update).Return(usecase.ErrInvalidFormat)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPostUC := new(MockPostUseCase)
+			tt.mockSetup(mockPostUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("user_id", 1)
+			c.Request = httptest.NewRequest("PUT", "/posts/1", strings.NewReader(`{"title":"T","content":"C"}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			handler := NewPostHandler(mockPostUC, new(MockCommentUseCase), new(MockUserUseCase), passiveVoteUseCase(), passiveViewUseCase())
+			handler.UpdatePost(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedETag, w.Header().Get("ETag"))
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			mockPostUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostHandler_ListDrafts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		query        string
+		mockSetup    func(*MockPostUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:  "Success",
+			query: "?limit=5&cursor=9",
+			mockSetup: func(m *MockPostUseCase) {
+				m.On("ListDrafts", mock.Anything, 1, entity.DraftListParams{Cursor: "9", Limit: 5}).
+					Return(&entity.PostPage{Posts: []*entity.Post{{ID: 4, Status: entity.PostDraft}}}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"status":"draft"`,
+		},
+		{
+			name:         "InvalidLimit",
+			query:        "?limit=0",
+			mockSetup:    func(m *MockPostUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "InvalidCursor",
+			query: "?cursor=x",
+			mockSetup: func(m *MockPostUseCase) {
+				m.On("ListDrafts", mock.Anything, 1, entity.DraftListParams{Cursor: "x"}).Return(nil, usecase.ErrInvalidCursor)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPostUC := new(MockPostUseCase)
+			tt.mockSetup(mockPostUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("user_id", 1)
+			c.Request = httptest.NewRequest("GET", "/posts/drafts"+tt.query, nil)
+
+			handler := NewPostHandler(mockPostUC, new(MockCommentUseCase), new(MockUserUseCase), passiveVoteUseCase(), passiveViewUseCase())
+			handler.ListDrafts(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			mockPostUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostHandler_SetPublication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	publish := entity.PostPublication{Status: entity.PostPublished}
+
+	tests := []struct {
+		name         string
+		body         string
+		mockSetup    func(*MockPostUseCase)
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			body: `{"status":"published"}`,
+			mockSetup: func(m *MockPostUseCase) {
+				m.On("SetPublication", mock.Anything, 1, 1, publish).Return(&entity.Post{ID: 1, Status: entity.PostPublished}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "MissingStatus",
+			body:         `{}`,
+			mockSetup:    func(m *MockPostUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "InvalidPublishAt",
+			body: `{"status":"scheduled"}`,
+			mockSetup: func(m *MockPostUseCase) {
+				m.On("SetPublication", mock.Anything, 1, 1, entity.PostPublication{Status: entity.PostScheduled}).Return(nil, usecase.ErrInvalidPublishAt)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Forbidden",
+			body: `{"status":"published"}`,
+			mockSetup: func(m *MockPostUseCase) {
+				m.On("SetPublication", mock.Anything, 1, 1, publish).Return(nil, usecase.ErrPostEditForbidden)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "NotFound",
+			body: `{"status":"published"}`,
+			mockSetup: func(m *MockPostUseCase) {
+				m.On("SetPublication", mock.Anything, 1, 1, publish).Return(nil, usecase.ErrPostNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "AlreadyPublished",
+			body: `{"status":"published"}`,
+			mockSetup: func(m *MockPostUseCase) {
+				m.On("SetPublication", mock.Anything, 1, 1, publish).Return(nil, usecase.ErrPostAlreadyPublished)
+			},
+			expectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPostUC := new(MockPostUseCase)
+			tt.mockSetup(mockPostUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("user_id", 1)
+			c.Request = httptest.NewRequest("PUT", "/posts/1/publication", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			handler := NewPostHandler(mockPostUC, new(MockCommentUseCase), new(MockUserUseCase), passiveVoteUseCase(), passiveViewUseCase())
+			handler.SetPublication(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockPostUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostHandler_GetPostByID_RecordsView(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	anonymous := func(ip, agent string) string {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/posts/1", nil)
+		c.Request.RemoteAddr = ip + ":1234"
+		c.Request.Header.Set("User-Agent", agent)
+		return viewerKey(c)
+	}
+	// Отпечаток анонимного читателя зависит от IP и User-Agent
+	assert.Equal(t, anonymous("10.0.0.1", "reader"), anonymous("10.0.0.1", "reader"))
+	assert.NotEqual(t, anonymous("10.0.0.1", "reader"), anonymous("10.0.0.2", "reader"))
+	assert.NotEqual(t, anonymous("10.0.0.1", "reader"), anonymous("10.0.0.1", "bot"))
+
+	tests := []struct {
+		name   string
+		userID int
+		post   *entity.Post
+		viewer string
+	}{
+		{"User", 5, &entity.Post{ID: 1, UserID: 1, Status: entity.PostPublished}, "user:5"},
+		{"Anonymous", 0, &entity.Post{ID: 1, UserID: 1, Status: entity.PostPublished}, anonymous("192.0.2.1", "")},
+		{"DraftNotCounted", 1, &entity.Post{ID: 1, UserID: 1, Status: entity.PostDraft}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPostUC := new(MockPostUseCase)
+			mockCommentUC := new(MockCommentUseCase)
+			mockUserUC := new(MockUserUseCase)
+			mockViewUC := new(MockViewUseCase)
+			mockPostUC.On("GetPostByID", mock.Anything, 1, tt.userID).Return(tt.post, nil)
+			mockUserUC.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Username: "author"}, nil)
+			mockCommentUC.On("GetCommentsByPostID", mock.Anything, 1).Return([]entity.Comment{}, nil)
+			if tt.viewer != "" {
+				mockViewUC.On("RecordView", 1, tt.viewer).Return().Once()
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/posts/1", nil)
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+			if tt.userID != 0 {
+				c.Set("user_id", tt.userID)
+			}
+
+			handler := NewPostHandler(mockPostUC, mockCommentUC, mockUserUC, passiveVoteUseCase(), mockViewUC)
+			handler.GetPostByID(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			mockViewUC.AssertExpectations(t)
+			if tt.viewer == "" {
+				mockViewUC.AssertNotCalled(t, "RecordView", mock.Anything, mock.Anything)
+				mockCommentUC.AssertNotCalled(t, "GetCommentsByPostID", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
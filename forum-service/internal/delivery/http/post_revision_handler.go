@@ -0,0 +1,192 @@
+package delivery
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+)
+
+type PostRevisionHandler struct {
+	revisionUC usecase.PostRevisionUseCase
+}
+
+func NewPostRevisionHandler(revisionUC usecase.PostRevisionUseCase) *PostRevisionHandler {
+	return &PostRevisionHandler{revisionUC: revisionUC}
+}
+
+// ListRevisions godoc
+// @Summary List post revisions
+// @Description Edit history of a post, newest first. Content is omitted; fetch a single revision to read it.
+// @Tags revisions
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {array} entity.PostRevision
+// @Failure 400 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/revisions [get]
+
+func (h *PostRevisionHandler) ListRevisions(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return
+	}
+
+	revisions, err := h.revisionUC.ListRevisions(c.Request.Context(), postID)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, revisions)
+}
+
+// GetRevision godoc
+// @Summary Get a post revision
+// @Description Title and content of a post as of revision rev.
+// @Tags revisions
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} entity.PostRevision
+// @Failure 400 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/revisions/{rev} [get]
+
+func (h *PostRevisionHandler) GetRevision(c *gin.Context) {
+	postID, rev, ok := parseRevisionPath(c)
+	if !ok {
+		return
+	}
+
+	revision, err := h.revisionUC.GetRevision(c.Request.Context(), postID, rev)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, revision)
+}
+
+// DiffRevisions godoc
+// @Summary Compare post revisions
+// @Description Difference between revisions from and to: a line-based unified diff, or word-level segments of the title and content.
+// @Tags revisions
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param from query int true "Base revision"
+// @Param to query int true "Compared revision"
+// @Param format query string false "Diff format" Enums(unified, word) default(unified)
+// @Success 200 {object} entity.PostDiff
+// @Failure 400 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/revisions/diff [get]
+
+func (h *PostRevisionHandler) DiffRevisions(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from revision"})
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to revision"})
+		return
+	}
+
+	diff, err := h.revisionUC.DiffRevisions(c.Request.Context(), postID, from, to, entity.DiffFormat(c.Query("format")))
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, diff)
+}
+
+// RestoreRevision godoc
+// @Summary Restore a post revision
+// @Description Bring back the title and content of revision rev. The restore is recorded as a new revision; tags are kept. Only the owner or admin can restore. If-Match must carry the ETag returned by GET /posts/{id}; if the post has changed since, the restore is rejected with 412 and the current version.
+// @Tags revisions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param rev path int true "Revision number"
+// @Param If-Match header string true "ETag of the post version being replaced"
+// @Success 200 {object} entity.Post
+// @Header 200 {string} ETag "New post version"
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 412 {object} versionConflictResponse "Post was modified; body carries the current version"
+// @Failure 428 {object} docs.Error "Missing If-Match"
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/revisions/{rev}/restore [post]
+
+func (h *PostRevisionHandler) RestoreRevision(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	postID, rev, ok := parseRevisionPath(c)
+	if !ok {
+		return
+	}
+	version, ok := postIfMatch(c)
+	if !ok {
+		return
+	}
+
+	post, err := h.revisionUC.RestoreRevision(c.Request.Context(), postID, rev, userID.(int), version)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+	c.Header("ETag", postETag(post.Version))
+	c.JSON(http.StatusOK, post)
+}
+
+func parseRevisionPath(c *gin.Context) (postID, rev int, ok bool) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return 0, 0, false
+	}
+	rev, err = strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision"})
+		return 0, 0, false
+	}
+	return postID, rev, true
+}
+
+func respondRevisionError(c *gin.Context, err error) {
+	if respondVersionConflict(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, usecase.ErrPostVersionRequired):
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidDiffFormat):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrPostEditForbidden), errors.Is(err, usecase.ErrPostArchived):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrPostNotFound), errors.Is(err, usecase.ErrRevisionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("[ERROR] Post revisions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,244 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPostRevisionUseCase - мок для PostRevisionUseCase
+type MockPostRevisionUseCase struct {
+	mock.Mock
+}
+
+func (m *MockPostRevisionUseCase) ListRevisions(ctx context.Context, postID int) ([]entity.PostRevision, error) {
+	args := m.Called(ctx, postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.PostRevision), args.Error(1)
+}
+
+func (m *MockPostRevisionUseCase) GetRevision(ctx context.Context, postID, rev int) (*entity.PostRevision, error) {
+	args := m.Called(ctx, postID, rev)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PostRevision), args.Error(1)
+}
+
+func (m *MockPostRevisionUseCase) DiffRevisions(ctx context.Context, postID, from, to int, format entity.DiffFormat) (*entity.PostDiff, error) {
+	args := m.Called(ctx, postID, from, to, format)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PostDiff), args.Error(1)
+}
+
+func (m *MockPostRevisionUseCase) RestoreRevision(ctx context.Context, postID, rev, userID, version int) (*entity.Post, error) {
+	args := m.Called(ctx, postID, rev, userID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func TestPostRevisionHandler_ListRevisions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		mockSetup    func(*MockPostRevisionUseCase)
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			mockSetup: func(m *MockPostRevisionUseCase) {
+				m.On("ListRevisions", mock.Anything, 1).Return([]entity.PostRevision{{Rev: 1}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "PostNotFound",
+			mockSetup: func(m *MockPostRevisionUseCase) {
+				m.On("ListRevisions", mock.Anything, 1).Return(nil, usecase.ErrPostNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockPostRevisionUseCase)
+			tt.mockSetup(mockUC)
+			handler := NewPostRevisionHandler(mockUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/posts/1/revisions", nil)
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			handler.ListRevisions(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostRevisionHandler_DiffRevisions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		query        string
+		mockSetup    func(*MockPostRevisionUseCase)
+		expectedCode int
+	}{
+		{
+			name:  "Success",
+			query: "from=1&to=2&format=word",
+			mockSetup: func(m *MockPostRevisionUseCase) {
+				m.On("DiffRevisions", mock.Anything, 1, 1, 2, entity.DiffFormatWord).
+					Return(&entity.PostDiff{PostID: 1, From: 1, To: 2, Format: entity.DiffFormatWord}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "MissingFrom",
+			query:        "to=2",
+			mockSetup:    func(m *MockPostRevisionUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "InvalidFormat",
+			query: "from=1&to=2&format=html",
+			mockSetup: func(m *MockPostRevisionUseCase) {
+				m.On("DiffRevisions", mock.Anything, 1, 1, 2, entity.DiffFormat("html")).
+					Return(nil, usecase.ErrInvalidDiffFormat)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "RevisionNotFound",
+			query: "from=1&to=5",
+			mockSetup: func(m *MockPostRevisionUseCase) {
+				m.On("DiffRevisions", mock.Anything, 1, 1, 5, entity.DiffFormat("")).
+					Return(nil, usecase.ErrRevisionNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockPostRevisionUseCase)
+			tt.mockSetup(mockUC)
+			handler := NewPostRevisionHandler(mockUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/posts/1/revisions/diff?"+tt.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			handler.DiffRevisions(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostRevisionHandler_RestoreRevision(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		authorized   bool
+		ifMatch      string
+		mockSetup    func(*MockPostRevisionUseCase)
+		expectedCode int
+		expectedETag string
+	}{
+		{
+			name:       "Success",
+			authorized: true,
+			ifMatch:    `"3"`,
+			mockSetup: func(m *MockPostRevisionUseCase) {
+				m.On("RestoreRevision", mock.Anything, 1, 2, 7, 3).Return(&entity.Post{ID: 1, Version: 4}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedETag: `"4"`,
+		},
+		{
+			name:       "Forbidden",
+			authorized: true,
+			ifMatch:    `"3"`,
+			mockSetup: func(m *MockPostRevisionUseCase) {
+				m.On("RestoreRevision", mock.Anything, 1, 2, 7, 3).Return(nil, usecase.ErrPostEditForbidden)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:       "StaleVersion",
+			authorized: true,
+			ifMatch:    `"3"`,
+			mockSetup: func(m *MockPostRevisionUseCase) {
+				m.On("RestoreRevision", mock.Anything, 1, 2, 7, 3).
+					Return(nil, &usecase.PostVersionConflictError{Current: &entity.Post{ID: 1, Version: 5}})
+			},
+			expectedCode: http.StatusPreconditionFailed,
+			expectedETag: `"5"`,
+		},
+		{
+			name:         "MissingIfMatch",
+			authorized:   true,
+			mockSetup:    func(m *MockPostRevisionUseCase) {},
+			expectedCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:         "InvalidIfMatch",
+			authorized:   true,
+			ifMatch:      "*",
+			mockSetup:    func(m *MockPostRevisionUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Unauthorized",
+			mockSetup:    func(m *MockPostRevisionUseCase) {},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockPostRevisionUseCase)
+			tt.mockSetup(mockUC)
+			handler := NewPostRevisionHandler(mockUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/posts/1/revisions/2/restore", nil)
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+			c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "rev", Value: "2"}}
+			if tt.authorized {
+				c.Set("user_id", 7)
+			}
+
+			handler.RestoreRevision(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedETag, w.Header().Get("ETag"))
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
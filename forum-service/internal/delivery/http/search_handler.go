@@ -0,0 +1,79 @@
+package delivery
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+)
+
+type SearchHandler struct {
+	searchUC usecase.SearchUseCase
+}
+
+func NewSearchHandler(searchUC usecase.SearchUseCase) *SearchHandler {
+	return &SearchHandler{searchUC: searchUC}
+}
+
+// Search godoc
+// @Summary Search posts and comments
+// @Description Full-text search ranked by relevance. q supports web search syntax: "exact phrase", OR, -exclude. Snippets are HTML-escaped with matches wrapped in <mark>.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query"
+// @Param type query string false "Result type" Enums(all, post, comment) default(all)
+// @Param author query string false "Author username"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Page size (max 50)" default(20)
+// @Param cursor query string false "Opaque cursor from next_cursor"
+// @Success 200 {object} entity.SearchPage
+// @Failure 400 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /search [get]
+
+func (h *SearchHandler) Search(c *gin.Context) {
+	params := entity.SearchParams{
+		Query:  c.Query("q"),
+		Type:   entity.SearchType(c.Query("type")),
+		Filter: entity.PostFilter{Author: c.Query("author")},
+		Cursor: c.Query("cursor"),
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		params.Limit = limit
+	}
+
+	var err error
+	if params.Filter.From, err = parseDateParam(c.Query("from")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid from: %v", err)})
+		return
+	}
+	if params.Filter.To, err = parseDateParam(c.Query("to")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid to: %v", err)})
+		return
+	}
+
+	page, err := h.searchUC.Search(c.Request.Context(), params)
+	if err != nil {
+		if usecase.IsSearchInputError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("[ERROR] Search: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "search failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,103 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSearchUseCase - мок для SearchUseCase
+type MockSearchUseCase struct {
+	mock.Mock
+}
+
+func (m *MockSearchUseCase) Search(ctx context.Context, params entity.SearchParams) (*entity.SearchPage, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SearchPage), args.Error(1)
+}
+
+func TestSearchHandler_Search(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		url          string
+		mockSetup    func(*MockSearchUseCase)
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			url:  "/search?q=golang&type=post&author=alice&from=2025-05-01&limit=10&cursor=abc",
+			mockSetup: func(uc *MockSearchUseCase) {
+				uc.On("Search", mock.Anything, entity.SearchParams{
+					Query: "golang",
+					Type:  entity.SearchTypePost,
+					Filter: entity.PostFilter{
+						Author: "alice",
+						From:   time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+					},
+					Cursor: "abc",
+					Limit:  10,
+				}).Return(&entity.SearchPage{Results: []*entity.SearchResult{{PostID: 1}}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "InvalidLimit",
+			url:          "/search?q=golang&limit=abc",
+			mockSetup:    func(uc *MockSearchUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "InvalidDate",
+			url:          "/search?q=golang&to=yesterday",
+			mockSetup:    func(uc *MockSearchUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "InvalidInput",
+			url:  "/search",
+			mockSetup: func(uc *MockSearchUseCase) {
+				uc.On("Search", mock.Anything, mock.Anything).Return(nil, usecase.ErrEmptySearchQuery)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "InternalError",
+			url:  "/search?q=golang",
+			mockSetup: func(uc *MockSearchUseCase) {
+				uc.On("Search", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSearchUC := new(MockSearchUseCase)
+			tt.mockSetup(mockSearchUC)
+			handler := NewSearchHandler(mockSearchUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", tt.url, nil)
+
+			handler.Search(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockSearchUC.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package delivery
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+)
+
+type TagHandler struct {
+	tagUC  usecase.TagUseCase
+	postUC usecase.PostUseCase
+	voteUC usecase.VoteUseCase
+}
+
+func NewTagHandler(tagUC usecase.TagUseCase, postUC usecase.PostUseCase, voteUC usecase.VoteUseCase) *TagHandler {
+	return &TagHandler{
+		tagUC:  tagUC,
+		postUC: postUC,
+		voteUC: voteUC,
+	}
+}
+
+type renameTagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// SuggestTags godoc
+// @Summary Autocomplete tags
+// @Description Tags starting with prefix, most used first. Without prefix returns the most used tags.
+// @Tags tags
+// @Produce json
+// @Param prefix query string false "Tag prefix"
+// @Param limit query int false "Number of tags (max 50)" default(10)
+// @Success 200 {array} entity.Tag
+// @Failure 400 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /tags [get]
+
+func (h *TagHandler) SuggestTags(c *gin.Context) {
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	tags, err := h.tagUC.SuggestTags(c.Request.Context(), c.Query("prefix"), limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidTag) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("[ERROR] SuggestTags: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list tags"})
+		return
+	}
+	c.JSON(http.StatusOK, tags)
+}
+
+// GetTagPosts godoc
+// @Summary List posts with a tag
+// @Description Retrieve a page of posts tagged with name. Accepts the same sorting, filtering and paging parameters as GET /posts.
+// @Tags tags
+// @Produce json
+// @Param name path string true "Tag name"
+// @Param sort query string false "Sort order" Enums(newest, oldest, most_commented, recently_active, hot, top, rising, most_viewed) default(newest)
+// @Param window query string false "Period for sort=top (posts created) and sort=most_viewed (views)" Enums(day, week, all) default(day)
+// @Param author query string false "Author username"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param cursor query string false "Opaque cursor from next_cursor"
+// @Success 200 {object} entity.PostPage
+// @Failure 400 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /tags/{name}/posts [get]
+
+func (h *TagHandler) GetTagPosts(c *gin.Context) {
+	tag, err := h.tagUC.GetTag(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrTagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	params, err := parsePostListParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	params.Filter.Tag = tag.Name
+
+	page, err := h.postUC.ListPosts(c.Request.Context(), params)
+	if err != nil {
+		if usecase.IsPostListInputError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("[ERROR] GetTagPosts: Failed to get posts: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	annotatePosts(c, h.voteUC, page.Posts...)
+	c.JSON(http.StatusOK, page)
+}
+
+// RenameTag godoc
+// @Summary Rename or merge a tag
+// @Description Rename a tag. If a tag with the new name already exists, the old tag is merged into it. Admin only.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Current tag name"
+// @Param tag body renameTagRequest true "New name" SchemaExample({"name":"golang"})
+// @Success 200 {object} entity.Tag
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /admin/tags/{name} [put]
+
+func (h *TagHandler) RenameTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req renameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.tagUC.RenameTag(c.Request.Context(), userID.(int), c.Param("name"), req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidTag):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrAdminRequired):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrTagNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			log.Printf("[ERROR] RenameTag: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, tag)
+}
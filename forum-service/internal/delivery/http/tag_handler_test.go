@@ -0,0 +1,205 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTagUseCase - мок для TagUseCase
+type MockTagUseCase struct {
+	mock.Mock
+}
+
+func (m *MockTagUseCase) SuggestTags(ctx context.Context, prefix string, limit int) ([]*entity.Tag, error) {
+	args := m.Called(ctx, prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Tag), args.Error(1)
+}
+
+func (m *MockTagUseCase) GetTag(ctx context.Context, name string) (*entity.Tag, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Tag), args.Error(1)
+}
+
+func (m *MockTagUseCase) RenameTag(ctx context.Context, userID int, from, to string) (*entity.Tag, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Tag), args.Error(1)
+}
+
+func TestTagHandler_SuggestTags(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		url          string
+		mockSetup    func(*MockTagUseCase)
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			url:  "/tags?prefix=go&limit=5",
+			mockSetup: func(uc *MockTagUseCase) {
+				uc.On("SuggestTags", mock.Anything, "go", 5).Return([]*entity.Tag{{ID: 1, Name: "go", PostCount: 4}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "InvalidLimit",
+			url:          "/tags?limit=-1",
+			mockSetup:    func(uc *MockTagUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "InvalidPrefix",
+			url:  "/tags?prefix=%25",
+			mockSetup: func(uc *MockTagUseCase) {
+				uc.On("SuggestTags", mock.Anything, "%", 0).Return(nil, usecase.ErrInvalidTag)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTagUC := new(MockTagUseCase)
+			tt.mockSetup(mockTagUC)
+			handler := NewTagHandler(mockTagUC, new(MockPostUseCase), passiveVoteUseCase())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", tt.url, nil)
+
+			handler.SuggestTags(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockTagUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTagHandler_GetTagPosts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		mockSetup    func(*MockTagUseCase, *MockPostUseCase)
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			mockSetup: func(tuc *MockTagUseCase, puc *MockPostUseCase) {
+				tuc.On("GetTag", mock.Anything, "Go").Return(&entity.Tag{ID: 1, Name: "go"}, nil)
+				puc.On("ListPosts", mock.Anything, entity.PostListParams{
+					Filter: entity.PostFilter{Tag: "go"},
+				}).Return(&entity.PostPage{Posts: []*entity.Post{{ID: 1, Tags: []string{"go"}}}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "UnknownTag",
+			mockSetup: func(tuc *MockTagUseCase, puc *MockPostUseCase) {
+				tuc.On("GetTag", mock.Anything, "Go").Return(nil, usecase.ErrTagNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTagUC := new(MockTagUseCase)
+			mockPostUC := new(MockPostUseCase)
+			tt.mockSetup(mockTagUC, mockPostUC)
+			handler := NewTagHandler(mockTagUC, mockPostUC, passiveVoteUseCase())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/tags/Go/posts", nil)
+			c.Params = gin.Params{gin.Param{Key: "name", Value: "Go"}}
+
+			handler.GetTagPosts(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockTagUC.AssertExpectations(t)
+			mockPostUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTagHandler_RenameTag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         string
+		mockSetup    func(*MockTagUseCase)
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			body: `{"name":"go"}`,
+			mockSetup: func(uc *MockTagUseCase) {
+				uc.On("RenameTag", mock.Anything, 1, "golang", "go").Return(&entity.Tag{ID: 2, Name: "go", PostCount: 7}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "MissingName",
+			body:         `{}`,
+			mockSetup:    func(uc *MockTagUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Forbidden",
+			body: `{"name":"go"}`,
+			mockSetup: func(uc *MockTagUseCase) {
+				uc.On("RenameTag", mock.Anything, 1, "golang", "go").Return(nil, usecase.ErrAdminRequired)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "NotFound",
+			body: `{"name":"go"}`,
+			mockSetup: func(uc *MockTagUseCase) {
+				uc.On("RenameTag", mock.Anything, 1, "golang", "go").Return(nil, usecase.ErrTagNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTagUC := new(MockTagUseCase)
+			tt.mockSetup(mockTagUC)
+			handler := NewTagHandler(mockTagUC, new(MockPostUseCase), passiveVoteUseCase())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("PUT", "/admin/tags/golang", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{gin.Param{Key: "name", Value: "golang"}}
+			c.Set("user_id", 1)
+
+			handler.RenameTag(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockTagUC.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package delivery
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+)
+
+type TrashHandler struct {
+	trashUC usecase.TrashUseCase
+}
+
+func NewTrashHandler(trashUC usecase.TrashUseCase) *TrashHandler {
+	return &TrashHandler{trashUC: trashUC}
+}
+
+// ListTrash godoc
+// @Summary List trash
+// @Description Deleted posts and comments, most recently deleted first, with the time each is purged for good. Comments of a deleted post are restored with the post and are not listed. Requires the moderator role. Pass next_cursor back as cursor for the next page.
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param target_type query string false "Only post or comment"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} entity.TrashPage
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /moderation/trash [get]
+
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	params := entity.TrashParams{
+		TargetType: entity.ReportTarget(c.Query("target_type")),
+		Cursor:     c.Query("cursor"),
+	}
+	limit, ok := moderationLimit(c)
+	if !ok {
+		return
+	}
+	params.Limit = limit
+
+	page, err := h.trashUC.ListTrash(c.Request.Context(), userID.(int), params)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// RestoreFromTrash godoc
+// @Summary Restore from trash
+// @Description Bring a deleted post, with its comments, or a deleted comment back. A comment of a deleted post cannot be restored on its own. Requires the moderator role. The restore is recorded in the moderation log.
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param target_type path string true "post or comment"
+// @Param target_id path int true "Content ID"
+// @Success 200 {object} entity.ModerationLogEntry
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 403 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /moderation/trash/{target_type}/{target_id}/restore [post]
+
+func (h *TrashHandler) RestoreFromTrash(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	targetID, err := strconv.Atoi(c.Param("target_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid target ID"})
+		return
+	}
+
+	entry, err := h.trashUC.Restore(c.Request.Context(), userID.(int),
+		entity.ReportTarget(c.Param("target_type")), targetID)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
@@ -0,0 +1,172 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTrashUseCase - мок для TrashUseCase
+type MockTrashUseCase struct {
+	mock.Mock
+}
+
+func (m *MockTrashUseCase) ListTrash(ctx context.Context, moderatorID int, params entity.TrashParams) (*entity.TrashPage, error) {
+	args := m.Called(ctx, moderatorID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TrashPage), args.Error(1)
+}
+
+func (m *MockTrashUseCase) Restore(ctx context.Context, moderatorID int, target entity.ReportTarget, targetID int) (*entity.ModerationLogEntry, error) {
+	args := m.Called(ctx, moderatorID, target, targetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ModerationLogEntry), args.Error(1)
+}
+
+func (m *MockTrashUseCase) PurgeExpired(ctx context.Context) (entity.PurgeResult, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(entity.PurgeResult), args.Error(1)
+}
+
+func (m *MockTrashUseCase) Run(ctx context.Context, interval time.Duration) {
+	m.Called(ctx, interval)
+}
+
+func TestListTrash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		query        string
+		mockSetup    func(m *MockTrashUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:  "Success",
+			query: "?target_type=comment&limit=5&cursor=abc",
+			mockSetup: func(m *MockTrashUseCase) {
+				m.On("ListTrash", mock.Anything, 1, entity.TrashParams{TargetType: entity.ReportOnComment, Cursor: "abc", Limit: 5}).
+					Return(&entity.TrashPage{Items: []*entity.TrashItem{{TargetType: entity.ReportOnComment, TargetID: 7, DeletedBy: "mod"}}}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"deleted_by":"mod"`,
+		},
+		{
+			name:         "InvalidLimit",
+			query:        "?limit=x",
+			mockSetup:    func(m *MockTrashUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "InvalidTarget",
+			query: "?target_type=chat_message",
+			mockSetup: func(m *MockTrashUseCase) {
+				m.On("ListTrash", mock.Anything, 1, entity.TrashParams{TargetType: entity.ReportOnChatMessage}).Return(nil, usecase.ErrInvalidTrashTarget)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "NotModerator",
+			mockSetup: func(m *MockTrashUseCase) {
+				m.On("ListTrash", mock.Anything, 1, entity.TrashParams{}).Return(nil, usecase.ErrModeratorRequired)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockTrashUseCase)
+			handler := NewTrashHandler(mockUC)
+			tt.mockSetup(mockUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/moderation/trash"+tt.query, nil)
+			c.Set("user_id", 1)
+
+			handler.ListTrash(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestoreFromTrash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		targetID     string
+		mockSetup    func(m *MockTrashUseCase)
+		expectedCode int
+	}{
+		{
+			name:     "Success",
+			targetID: "4",
+			mockSetup: func(m *MockTrashUseCase) {
+				m.On("Restore", mock.Anything, 1, entity.ReportOnPost, 4).
+					Return(&entity.ModerationLogEntry{ID: 3, Action: entity.ModerationRestore}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "InvalidTargetID",
+			targetID:     "abc",
+			mockSetup:    func(m *MockTrashUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:     "NotInTrash",
+			targetID: "4",
+			mockSetup: func(m *MockTrashUseCase) {
+				m.On("Restore", mock.Anything, 1, entity.ReportOnPost, 4).Return(nil, usecase.ErrTrashItemNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:     "NotModerator",
+			targetID: "4",
+			mockSetup: func(m *MockTrashUseCase) {
+				m.On("Restore", mock.Anything, 1, entity.ReportOnPost, 4).Return(nil, usecase.ErrModeratorRequired)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockTrashUseCase)
+			handler := NewTrashHandler(mockUC)
+			tt.mockSetup(mockUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/moderation/trash/post/"+tt.targetID+"/restore", nil)
+			c.Params = gin.Params{{Key: "target_type", Value: "post"}, {Key: "target_id", Value: tt.targetID}}
+			c.Set("user_id", 1)
+
+			handler.RestoreFromTrash(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package delivery
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+)
+
+type UserHandler struct {
+	mentionUC usecase.MentionUseCase
+}
+
+func NewUserHandler(mentionUC usecase.MentionUseCase) *UserHandler {
+	return &UserHandler{mentionUC: mentionUC}
+}
+
+// SuggestUsers godoc
+// @Summary Autocomplete usernames
+// @Description Users whose name starts with prefix, ignoring case, for @mention autocomplete. A leading @ is ignored.
+// @Tags users
+// @Produce json
+// @Param prefix query string true "Username prefix"
+// @Param limit query int false "Number of users (max 25)" default(10)
+// @Success 200 {array} entity.UserSummary
+// @Failure 400 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /users/suggest [get]
+
+func (h *UserHandler) SuggestUsers(c *gin.Context) {
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	users, err := h.mentionUC.SuggestUsers(c.Request.Context(), c.Query("prefix"), limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrEmptyUserPrefix) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("[ERROR] SuggestUsers: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list users"})
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// ListBlockedUsers godoc
+// @Summary List blocked users
+// @Description Users blocked by the current user. Their @mentions of the current user are ignored.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} entity.UserSummary
+// @Failure 401 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /users/blocks [get]
+
+func (h *UserHandler) ListBlockedUsers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	users, err := h.mentionUC.ListBlockedUsers(c.Request.Context(), userID.(int))
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// BlockUser godoc
+// @Summary Block a user
+// @Description Ignore @mentions of the current user by this user. Blocking twice is not an error.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /users/{id}/block [post]
+
+func (h *UserHandler) BlockUser(c *gin.Context) {
+	userID, blockedID, ok := blockParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.mentionUC.BlockUser(c.Request.Context(), userID, blockedID); err != nil {
+		respondUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "user blocked"})
+}
+
+// UnblockUser godoc
+// @Summary Unblock a user
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /users/{id}/block [delete]
+
+func (h *UserHandler) UnblockUser(c *gin.Context) {
+	userID, blockedID, ok := blockParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.mentionUC.UnblockUser(c.Request.Context(), userID, blockedID); err != nil {
+		respondUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "user unblocked"})
+}
+
+// blockParams извлекает текущего пользователя и id блокируемого; при ошибке ответ уже отправлен
+func blockParams(c *gin.Context) (userID, blockedID int, ok bool) {
+	id, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return 0, 0, false
+	}
+
+	blockedID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return 0, 0, false
+	}
+	return id.(int), blockedID, true
+}
+
+func respondUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrCannotBlockSelf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
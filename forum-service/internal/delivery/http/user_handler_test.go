@@ -0,0 +1,171 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMentionUseCase - мок для MentionUseCase
+type MockMentionUseCase struct {
+	mock.Mock
+}
+
+func (m *MockMentionUseCase) TrackMentions(ctx context.Context, source entity.MentionSource, authorID int, text string) {
+	m.Called(ctx, source, authorID, text)
+}
+
+func (m *MockMentionUseCase) SuggestUsers(ctx context.Context, prefix string, limit int) ([]entity.UserSummary, error) {
+	args := m.Called(ctx, prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserSummary), args.Error(1)
+}
+
+func (m *MockMentionUseCase) BlockUser(ctx context.Context, userID, blockedUserID int) error {
+	return m.Called(ctx, userID, blockedUserID).Error(0)
+}
+
+func (m *MockMentionUseCase) UnblockUser(ctx context.Context, userID, blockedUserID int) error {
+	return m.Called(ctx, userID, blockedUserID).Error(0)
+}
+
+func (m *MockMentionUseCase) ListBlockedUsers(ctx context.Context, userID int) ([]entity.UserSummary, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserSummary), args.Error(1)
+}
+
+func TestSuggestUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		query        string
+		mockSetup    func(m *MockMentionUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:  "Success",
+			query: "?prefix=al&limit=5",
+			mockSetup: func(m *MockMentionUseCase) {
+				m.On("SuggestUsers", mock.Anything, "al", 5).
+					Return([]entity.UserSummary{{ID: 1, Username: "alice"}}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"id":1,"username":"alice"}]`,
+		},
+		{
+			name:  "EmptyPrefix",
+			query: "",
+			mockSetup: func(m *MockMentionUseCase) {
+				m.On("SuggestUsers", mock.Anything, "", 0).Return(nil, usecase.ErrEmptyUserPrefix)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "InvalidLimit",
+			query:        "?prefix=al&limit=-1",
+			mockSetup:    func(m *MockMentionUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockMentionUseCase)
+			handler := NewUserHandler(mockUC)
+			tt.mockSetup(mockUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/users/suggest"+tt.query, nil)
+
+			handler.SuggestUsers(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBlockUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		id           string
+		auth         bool
+		mockSetup    func(m *MockMentionUseCase)
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			id:   "3",
+			auth: true,
+			mockSetup: func(m *MockMentionUseCase) {
+				m.On("BlockUser", mock.Anything, 1, 3).Return(nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Unauthorized",
+			id:           "3",
+			mockSetup:    func(m *MockMentionUseCase) {},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "Self",
+			id:   "1",
+			auth: true,
+			mockSetup: func(m *MockMentionUseCase) {
+				m.On("BlockUser", mock.Anything, 1, 1).Return(usecase.ErrCannotBlockSelf)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "UnknownUser",
+			id:   "9",
+			auth: true,
+			mockSetup: func(m *MockMentionUseCase) {
+				m.On("BlockUser", mock.Anything, 1, 9).Return(usecase.ErrUserNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockMentionUseCase)
+			handler := NewUserHandler(mockUC)
+			tt.mockSetup(mockUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/users/"+tt.id+"/block", nil)
+			if tt.auth {
+				c.Set("user_id", 1)
+			}
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			handler.BlockUser(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package delivery
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+)
+
+type VoteHandler struct {
+	voteUC usecase.VoteUseCase
+}
+
+func NewVoteHandler(voteUC usecase.VoteUseCase) *VoteHandler {
+	return &VoteHandler{voteUC: voteUC}
+}
+
+type voteRequest struct {
+	Value int `json:"value" binding:"required"`
+}
+
+type reactionRequest struct {
+	Emoji string `json:"emoji" binding:"required"`
+}
+
+// VotePost godoc
+// @Summary Vote on a post
+// @Description Upvote (1) or downvote (-1) a post. Repeating the same vote withdraws it, the opposite value flips it.
+// @Tags votes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param vote body voteRequest true "Vote" SchemaExample({"value":1})
+// @Success 200 {object} entity.VoteState
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/vote [post]
+
+func (h *VoteHandler) VotePost(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return
+	}
+	h.vote(c, entity.VoteTarget{Kind: entity.TargetPost, ID: postID})
+}
+
+// VoteComment godoc
+// @Summary Vote on a comment
+// @Description Upvote (1) or downvote (-1) a comment. Repeating the same vote withdraws it, the opposite value flips it.
+// @Tags votes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param comment_id path int true "Comment ID"
+// @Param vote body voteRequest true "Vote" SchemaExample({"value":-1})
+// @Success 200 {object} entity.VoteState
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/comments/{comment_id}/vote [post]
+
+func (h *VoteHandler) VoteComment(c *gin.Context) {
+	postID, commentID, ok := parseCommentPath(c)
+	if !ok {
+		return
+	}
+	h.vote(c, entity.VoteTarget{Kind: entity.TargetComment, ID: commentID, PostID: postID})
+}
+
+func (h *VoteHandler) vote(c *gin.Context, target entity.VoteTarget) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req voteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	state, err := h.voteUC.Vote(c.Request.Context(), userID.(int), target, req.Value)
+	if err != nil {
+		respondVoteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
+// ReactToPost godoc
+// @Summary Toggle a reaction on a post
+// @Description Add an emoji reaction to a post, or remove it if the user already left it. See GET /reactions for the allowed set.
+// @Tags votes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param reaction body reactionRequest true "Reaction" SchemaExample({"emoji":"🎉"})
+// @Success 200 {object} entity.VoteState
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/reactions [post]
+
+func (h *VoteHandler) ReactToPost(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return
+	}
+	h.react(c, entity.VoteTarget{Kind: entity.TargetPost, ID: postID})
+}
+
+// ReactToComment godoc
+// @Summary Toggle a reaction on a comment
+// @Description Add an emoji reaction to a comment, or remove it if the user already left it. See GET /reactions for the allowed set.
+// @Tags votes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param comment_id path int true "Comment ID"
+// @Param reaction body reactionRequest true "Reaction" SchemaExample({"emoji":"👍"})
+// @Success 200 {object} entity.VoteState
+// @Failure 400 {object} docs.Error
+// @Failure 401 {object} docs.Error
+// @Failure 404 {object} docs.Error
+// @Failure 500 {object} docs.Error
+// @Router /posts/{id}/comments/{comment_id}/reactions [post]
+
+func (h *VoteHandler) ReactToComment(c *gin.Context) {
+	postID, commentID, ok := parseCommentPath(c)
+	if !ok {
+		return
+	}
+	h.react(c, entity.VoteTarget{Kind: entity.TargetComment, ID: commentID, PostID: postID})
+}
+
+func (h *VoteHandler) react(c *gin.Context, target entity.VoteTarget) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req reactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	state, err := h.voteUC.ToggleReaction(c.Request.Context(), userID.(int), target, req.Emoji)
+	if err != nil {
+		respondVoteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
+// ListReactions godoc
+// @Summary List allowed reactions
+// @Description Emoji that can be used as reactions on posts and comments.
+// @Tags votes
+// @Produce json
+// @Success 200 {array} string
+// @Router /reactions [get]
+
+func (h *VoteHandler) ListReactions(c *gin.Context) {
+	c.JSON(http.StatusOK, h.voteUC.AllowedReactions())
+}
+
+func respondVoteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidVote), errors.Is(err, usecase.ErrInvalidReaction):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrVoteTargetNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// viewerID - id пользователя, если запрос авторизован (см. OptionalAuthMiddleware), иначе 0
+func viewerID(c *gin.Context) int {
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(int); ok {
+			return id
+		}
+	}
+	return 0
+}
+
+// annotatePosts добавляет к постам голос и реакции текущего пользователя. Ошибка
+// не мешает отдать ответ: без персональных полей он остается корректным.
+func annotatePosts(c *gin.Context, voteUC usecase.VoteUseCase, posts ...*entity.Post) {
+	viewer := viewerID(c)
+	if viewer == 0 {
+		return
+	}
+	if err := voteUC.AnnotatePosts(c.Request.Context(), viewer, posts...); err != nil {
+		log.Printf("[WARN] Failed to load viewer votes for posts: %v", err)
+	}
+}
+
+// annotateComments - то же для комментариев и их ответов
+func annotateComments(c *gin.Context, voteUC usecase.VoteUseCase, comments []*entity.Comment) {
+	viewer := viewerID(c)
+	if viewer == 0 {
+		return
+	}
+	if err := voteUC.AnnotateComments(c.Request.Context(), viewer, comments); err != nil {
+		log.Printf("[WARN] Failed to load viewer votes for comments: %v", err)
+	}
+}
+
+// commentPointers - указатели на элементы среза, чтобы заполнить их на месте
+func commentPointers(comments []entity.Comment) []*entity.Comment {
+	ptrs := make([]*entity.Comment, len(comments))
+	for i := range comments {
+		ptrs[i] = &comments[i]
+	}
+	return ptrs
+}
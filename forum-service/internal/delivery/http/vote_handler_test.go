@@ -0,0 +1,231 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockVoteUseCase - мок для VoteUseCase
+type MockVoteUseCase struct {
+	mock.Mock
+}
+
+func (m *MockVoteUseCase) Vote(ctx context.Context, userID int, target entity.VoteTarget, value int) (*entity.VoteState, error) {
+	args := m.Called(ctx, userID, target, value)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.VoteState), args.Error(1)
+}
+
+func (m *MockVoteUseCase) ToggleReaction(ctx context.Context, userID int, target entity.VoteTarget, emoji string) (*entity.VoteState, error) {
+	args := m.Called(ctx, userID, target, emoji)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.VoteState), args.Error(1)
+}
+
+func (m *MockVoteUseCase) AllowedReactions() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func (m *MockVoteUseCase) AnnotatePosts(ctx context.Context, viewerID int, posts ...*entity.Post) error {
+	args := m.Called(ctx, viewerID, posts)
+	return args.Error(0)
+}
+
+func (m *MockVoteUseCase) AnnotateComments(ctx context.Context, viewerID int, comments []*entity.Comment) error {
+	args := m.Called(ctx, viewerID, comments)
+	return args.Error(0)
+}
+
+var _ usecase.VoteUseCase = (*MockVoteUseCase)(nil)
+
+// passiveVoteUseCase - мок для тестов, которым не важны голоса: аннотация ничего не меняет
+func passiveVoteUseCase() *MockVoteUseCase {
+	m := new(MockVoteUseCase)
+	m.On("AnnotatePosts", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	m.On("AnnotateComments", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+func TestVotePost(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	target := entity.VoteTarget{Kind: entity.TargetPost, ID: 1}
+
+	tests := []struct {
+		name         string
+		body         string
+		auth         bool
+		mockSetup    func(m *MockVoteUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "Success",
+			body: `{"value": 1}`,
+			auth: true,
+			mockSetup: func(m *MockVoteUseCase) {
+				m.On("Vote", mock.Anything, 1, target, 1).
+					Return(&entity.VoteState{Score: 5, Reactions: map[string]int{}, MyVote: 1}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"score":5`,
+		},
+		{
+			name:         "Unauthorized",
+			body:         `{"value": 1}`,
+			mockSetup:    func(m *MockVoteUseCase) {},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "MissingValue",
+			body:         `{}`,
+			auth:         true,
+			mockSetup:    func(m *MockVoteUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "InvalidValue",
+			body: `{"value": 3}`,
+			auth: true,
+			mockSetup: func(m *MockVoteUseCase) {
+				m.On("Vote", mock.Anything, 1, target, 3).Return(nil, usecase.ErrInvalidVote)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "NotFound",
+			body: `{"value": -1}`,
+			auth: true,
+			mockSetup: func(m *MockVoteUseCase) {
+				m.On("Vote", mock.Anything, 1, target, -1).Return(nil, usecase.ErrVoteTargetNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVoteUC := new(MockVoteUseCase)
+			handler := NewVoteHandler(mockVoteUC)
+			tt.mockSetup(mockVoteUC)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/posts/1/vote", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			if tt.auth {
+				c.Set("user_id", 1)
+			}
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			handler.VotePost(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			mockVoteUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReactToComment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	target := entity.VoteTarget{Kind: entity.TargetComment, ID: 2, PostID: 1}
+
+	t.Run("Success", func(t *testing.T) {
+		mockVoteUC := new(MockVoteUseCase)
+		handler := NewVoteHandler(mockVoteUC)
+		mockVoteUC.On("ToggleReaction", mock.Anything, 1, target, "🎉").
+			Return(&entity.VoteState{Reactions: map[string]int{"🎉": 1}, MyReactions: []string{"🎉"}}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/posts/1/comments/2/reactions", strings.NewReader(`{"emoji": "🎉"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("user_id", 1)
+		c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "comment_id", Value: "2"}}
+
+		handler.ReactToComment(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"my_reactions":["🎉"]`)
+		mockVoteUC.AssertExpectations(t)
+	})
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		mockVoteUC := new(MockVoteUseCase)
+		handler := NewVoteHandler(mockVoteUC)
+		mockVoteUC.On("ToggleReaction", mock.Anything, 1, target, "🍕").Return(nil, usecase.ErrInvalidReaction)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/posts/1/comments/2/reactions", strings.NewReader(`{"emoji": "🍕"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("user_id", 1)
+		c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "comment_id", Value: "2"}}
+
+		handler.ReactToComment(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockVoteUC.AssertExpectations(t)
+	})
+}
+
+func TestListReactions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockVoteUC := new(MockVoteUseCase)
+	handler := NewVoteHandler(mockVoteUC)
+	mockVoteUC.On("AllowedReactions").Return([]string{"👍", "👀"})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/reactions", nil)
+
+	handler.ListReactions(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `["👍","👀"]`, w.Body.String())
+}
+
+func TestGetCommentsAnnotatesViewer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockCommentUC := new(MockCommentUseCase)
+	mockVoteUC := new(MockVoteUseCase)
+	handler := NewCommentHandler(mockCommentUC, mockVoteUC)
+	mockCommentUC.On("GetCommentsByPostID", mock.Anything, 1).
+		Return([]entity.Comment{{ID: 2, PostID: 1, Content: "hi", Score: 3}}, nil)
+	mockVoteUC.On("AnnotateComments", mock.Anything, 7, mock.Anything).
+		Run(func(args mock.Arguments) {
+			args.Get(2).([]*entity.Comment)[0].MyVote = -1
+		}).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/posts/1/comments", nil)
+	c.Set("user_id", 7)
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	handler.GetComments(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"score":3`)
+	assert.Contains(t, w.Body.String(), `"my_vote":-1`)
+	mockVoteUC.AssertExpectations(t)
+}
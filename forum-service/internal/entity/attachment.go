@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// AttachmentTarget - к чему прикреплен файл
+type AttachmentTarget string
+
+const (
+	AttachedToPost    AttachmentTarget = "post"
+	AttachedToComment AttachmentTarget = "comment"
+	AttachedToChat    AttachmentTarget = "chat"
+)
+
+// Attachment - загруженный файл. Пока он ни к чему не прикреплен, он считается
+// брошенным и через некоторое время удаляется. URL и ThumbnailURL - подписанные
+// ссылки с ограниченным сроком действия.
+type Attachment struct {
+	ID            int       `json:"id"`
+	UserID        int       `json:"user_id"`
+	Filename      string    `json:"filename"`
+	ContentType   string    `json:"content_type"`
+	Size          int64     `json:"size"`
+	Width         int       `json:"width,omitempty"`
+	Height        int       `json:"height,omitempty"`
+	Key           string    `json:"-"`
+	ThumbnailKey  string    `json:"-"`
+	PostID        *int      `json:"post_id,omitempty"`
+	CommentID     *int      `json:"comment_id,omitempty"`
+	ChatMessageID *int      `json:"chat_message_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	URL           string    `json:"url"`
+	ThumbnailURL  string    `json:"thumbnail_url,omitempty"`
+}
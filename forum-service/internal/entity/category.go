@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// Category - раздел форума. Разделы образуют дерево через ParentID,
+// внутри одного родителя упорядочены по Position.
+type Category struct {
+	ID          int    `json:"id" db:"id"`
+	Slug        string `json:"slug" db:"slug"`
+	Name        string `json:"name" db:"name"`
+	Description string `json:"description" db:"description"`
+	Position    int    `json:"position" db:"position"`
+	ParentID    *int   `json:"parent_id,omitempty" db:"parent_id"`
+	// ReadOnly разрешает создавать посты только модераторам и администраторам
+	ReadOnly       bool        `json:"read_only" db:"read_only"`
+	PostCount      int         `json:"post_count" db:"-"`
+	LastActivityAt *time.Time  `json:"last_activity_at,omitempty" db:"-"`
+	Children       []*Category `json:"children,omitempty" db:"-"`
+}
+
+// CategoryInput - поля раздела, которые задает администратор
+type CategoryInput struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Position    int    `json:"position"`
+	ParentID    *int   `json:"parent_id"`
+	ReadOnly    bool   `json:"read_only"`
+}
@@ -8,4 +8,7 @@ type ChatMessage struct {
 	Author    string    `json:"author"`
 	Text      string    `json:"text"`
 	CreatedAt time.Time `json:"created_at"`
+	// AttachmentIDs - загрузки, прикрепляемые к сообщению; Attachments - прикрепленные файлы
+	AttachmentIDs []int        `json:"attachment_ids,omitempty"`
+	Attachments   []Attachment `json:"attachments,omitempty"`
 }
@@ -2,11 +2,79 @@ package entity
 
 import "time"
 
+// DeletedCommentContent заменяет текст удаленного комментария, у которого остались ответы
+const DeletedCommentContent = "[deleted]"
+
+// HiddenCommentContent заменяет текст комментария, скрытого модератором
+const HiddenCommentContent = "[hidden by moderator]"
+
 type Comment struct {
 	ID        int       `json:"id" db:"id"`
 	Content   string    `json:"content" db:"content"`
 	PostID    int       `json:"post_id" db:"post_id"` // Должно быть int
+	ParentID  *int      `json:"parent_id,omitempty" db:"parent_id"`
+	Depth     int       `json:"depth" db:"depth"`
 	UserID    int       `json:"user_id" db:"user_id"`
 	Author    string    `json:"author" db:"-"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	// Format - разметка Content, ContentHTML - отрисованный и очищенный HTML
+	Format      ContentFormat `json:"format" db:"format"`
+	ContentHTML string        `json:"content_html" db:"content_html"`
+	// AttachmentIDs - загрузки, прикрепляемые при создании; Attachments - прикрепленные файлы
+	AttachmentIDs []int        `json:"attachment_ids,omitempty" db:"-"`
+	Attachments   []Attachment `json:"attachments,omitempty" db:"-"`
+	// EditedAt - время последней правки, nil если комментарий не редактировался
+	EditedAt *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	Deleted  bool       `json:"deleted,omitempty" db:"-"`
+	Hidden   bool       `json:"hidden,omitempty" db:"-"`
+	// Score и Reactions - итоги голосов и реакций, MyVote и MyReactions - запросившего
+	Score       int            `json:"score" db:"score"`
+	Reactions   map[string]int `json:"reactions" db:"reactions"`
+	MyVote      int            `json:"my_vote" db:"-"`
+	MyReactions []string       `json:"my_reactions,omitempty" db:"-"`
+	// ReplyCount - число прямых ответов; Replies может содержать только часть из них,
+	// остальные загружаются по RepliesCursor
+	ReplyCount    int        `json:"reply_count" db:"-"`
+	Replies       []*Comment `json:"replies,omitempty" db:"-"`
+	RepliesCursor string     `json:"replies_cursor,omitempty" db:"-"`
+}
+
+// CommentRevision - прежний текст комментария, замененный правкой EditedBy в EditedAt
+type CommentRevision struct {
+	ID        int       `json:"id" db:"id"`
+	CommentID int       `json:"comment_id" db:"comment_id"`
+	Content   string    `json:"content" db:"content"`
+	EditedBy  *int      `json:"edited_by,omitempty" db:"edited_by"`
+	Editor    string    `json:"editor,omitempty" db:"-"`
+	EditedAt  time.Time `json:"edited_at" db:"edited_at"`
+}
+
+// CommentCursor указывает, чьи ответы загружать (ParentID, 0 - верхний уровень)
+// и после какого комментария продолжить (Time, ID; пустые - с начала)
+type CommentCursor struct {
+	ParentID int       `json:"p,omitempty"`
+	Time     time.Time `json:"t,omitempty"`
+	ID       int       `json:"id,omitempty"`
+}
+
+// CommentTreeParams - параметры запроса дерева комментариев
+type CommentTreeParams struct {
+	Cursor string
+	Limit  int
+}
+
+// CommentTreeQuery - запрос к репозиторию: Limit комментариев уровня Cursor.ParentID
+// и до Depth уровней ответов под ними, не больше Replies ответов на комментарий
+type CommentTreeQuery struct {
+	PostID  int
+	Cursor  CommentCursor
+	Limit   int
+	Depth   int
+	Replies int
+}
+
+// CommentTreePage - страница дерева комментариев
+type CommentTreePage struct {
+	Comments   []*Comment `json:"comments"`
+	NextCursor string     `json:"next_cursor,omitempty"`
 }
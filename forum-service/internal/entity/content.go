@@ -0,0 +1,16 @@
+package entity
+
+// ContentFormat - разметка текста поста или комментария
+type ContentFormat string
+
+const (
+	// FormatPlain - обычный текст: HTML экранируется, переводы строк сохраняются
+	FormatPlain ContentFormat = "plain"
+	// FormatMarkdown - CommonMark с расширениями GFM
+	FormatMarkdown ContentFormat = "markdown"
+)
+
+// Valid сообщает, поддерживается ли формат
+func (f ContentFormat) Valid() bool {
+	return f == FormatPlain || f == FormatMarkdown
+}
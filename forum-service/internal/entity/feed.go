@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// Feed - последние посты форума, раздела, метки или автора для RSS и Atom.
+// Path - путь страницы, которую описывает лента, относительно адреса форума;
+// Updated - самое позднее время обновления постов ленты (нулевое у пустой ленты).
+type Feed struct {
+	Title       string
+	Description string
+	Path        string
+	Updated     time.Time
+	Posts       []*Post
+}
@@ -0,0 +1,24 @@
+package entity
+
+// MentionSourceKind - где упомянут пользователь
+type MentionSourceKind string
+
+const (
+	MentionInPost    MentionSourceKind = "post"
+	MentionInComment MentionSourceKind = "comment"
+	MentionInChat    MentionSourceKind = "chat"
+)
+
+// MentionSource - текст с упоминаниями: пост, комментарий или сообщение чата.
+// Для комментария PostID - пост, к которому он оставлен.
+type MentionSource struct {
+	Kind   MentionSourceKind
+	ID     int
+	PostID int
+}
+
+// UserSummary - пользователь в подсказках и списках
+type UserSummary struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
@@ -0,0 +1,227 @@
+package entity
+
+import "time"
+
+// ReportTarget - вид содержимого, на которое жалуются
+type ReportTarget string
+
+const (
+	ReportOnPost        ReportTarget = "post"
+	ReportOnComment     ReportTarget = "comment"
+	ReportOnChatMessage ReportTarget = "chat_message"
+)
+
+// Valid сообщает, поддерживается ли вид содержимого
+func (t ReportTarget) Valid() bool {
+	switch t {
+	case ReportOnPost, ReportOnComment, ReportOnChatMessage:
+		return true
+	}
+	return false
+}
+
+// ReportReason - причина жалобы
+type ReportReason string
+
+const (
+	ReasonSpam           ReportReason = "spam"
+	ReasonHarassment     ReportReason = "harassment"
+	ReasonHateSpeech     ReportReason = "hate_speech"
+	ReasonViolence       ReportReason = "violence"
+	ReasonSexualContent  ReportReason = "sexual_content"
+	ReasonMisinformation ReportReason = "misinformation"
+	ReasonOffTopic       ReportReason = "off_topic"
+	ReasonOther          ReportReason = "other"
+)
+
+// Valid сообщает, входит ли причина в список допустимых
+func (r ReportReason) Valid() bool {
+	switch r {
+	case ReasonSpam, ReasonHarassment, ReasonHateSpeech, ReasonViolence,
+		ReasonSexualContent, ReasonMisinformation, ReasonOffTopic, ReasonOther:
+		return true
+	}
+	return false
+}
+
+// ReportStatus - состояние жалобы: открытая ждет модератора
+type ReportStatus string
+
+const (
+	ReportOpen     ReportStatus = "open"
+	ReportResolved ReportStatus = "resolved"
+)
+
+// Report - жалоба ReporterID на содержимое TargetType/TargetID автора TargetUserID.
+// Повторная жалоба того же пользователя на то же содержимое заменяет причину и
+// примечание открытой жалобы, а не создает новую.
+type Report struct {
+	ID           int          `json:"id" db:"id"`
+	ReporterID   int          `json:"reporter_id" db:"reporter_id"`
+	Reporter     string       `json:"reporter,omitempty" db:"-"`
+	TargetType   ReportTarget `json:"target_type" db:"target_type"`
+	TargetID     int          `json:"target_id" db:"target_id"`
+	TargetUserID *int         `json:"target_user_id,omitempty" db:"target_user_id"`
+	Reason       ReportReason `json:"reason" db:"reason"`
+	Note         string       `json:"note,omitempty" db:"note"`
+	Status       ReportStatus `json:"status" db:"status"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// ReportInput - жалоба, отправленная пользователем
+type ReportInput struct {
+	TargetType ReportTarget `json:"target_type" binding:"required"`
+	TargetID   int          `json:"target_id" binding:"required"`
+	Reason     ReportReason `json:"reason" binding:"required"`
+	Note       string       `json:"note"`
+}
+
+// ReportedContent - содержимое, на которое жалуются, в текущем виде.
+// Excerpt обрезан; Title - заголовок поста или поста, к которому относится комментарий.
+type ReportedContent struct {
+	AuthorID *int   `json:"author_id,omitempty"`
+	Author   string `json:"author,omitempty"`
+	PostID   *int   `json:"post_id,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Excerpt  string `json:"excerpt"`
+	Hidden   bool   `json:"hidden"`
+}
+
+// ReportGroup - открытые жалобы на одно содержимое. Content пусто, если содержимое
+// уже удалено; Reasons - число жалоб по причинам.
+type ReportGroup struct {
+	TargetType      ReportTarget         `json:"target_type"`
+	TargetID        int                  `json:"target_id"`
+	TargetUserID    *int                 `json:"target_user_id,omitempty"`
+	Content         *ReportedContent     `json:"content,omitempty"`
+	ReportCount     int                  `json:"report_count"`
+	Reasons         map[ReportReason]int `json:"reasons"`
+	FirstReportedAt time.Time            `json:"first_reported_at"`
+	LastReportedAt  time.Time            `json:"last_reported_at"`
+	Reports         []Report             `json:"reports"`
+	// FirstReportID - ключ постраничной выдачи очереди
+	FirstReportID int `json:"-"`
+}
+
+// ReportQueueParams - параметры запроса страницы очереди модерации
+type ReportQueueParams struct {
+	TargetType ReportTarget
+	Cursor     string
+	Limit      int
+}
+
+// ReportQueueQuery - запрос к репозиторию: группы, первая жалоба которых новее AfterID
+type ReportQueueQuery struct {
+	TargetType ReportTarget
+	AfterID    int
+	Limit      int
+}
+
+// ReportQueuePage - страница очереди: группы жалоб, давние первыми
+type ReportQueuePage struct {
+	Groups     []*ReportGroup `json:"groups"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// ModerationAction - решение модератора по жалобам
+type ModerationAction string
+
+const (
+	// ModerationDismiss - жалобы необоснованны, содержимое не меняется
+	ModerationDismiss ModerationAction = "dismiss"
+	// ModerationHide - содержимое скрывается от пользователей
+	ModerationHide ModerationAction = "hide"
+	// ModerationDelete - содержимое удаляется
+	ModerationDelete ModerationAction = "delete"
+	// ModerationWarn - автор получает предупреждение
+	ModerationWarn ModerationAction = "warn"
+	// ModerationBan - автор лишается возможности писать на форуме
+	ModerationBan ModerationAction = "ban"
+
+	// Изменения закрепления и состояния поста, не связанные с жалобами
+	ModerationPin       ModerationAction = "pin"
+	ModerationUnpin     ModerationAction = "unpin"
+	ModerationLock      ModerationAction = "lock"
+	ModerationUnlock    ModerationAction = "unlock"
+	ModerationArchive   ModerationAction = "archive"
+	ModerationUnarchive ModerationAction = "unarchive"
+
+	// ModerationRestore - удаленный пост или комментарий возвращен из корзины
+	ModerationRestore ModerationAction = "restore"
+)
+
+// Valid сообщает, можно ли этим решением закрыть жалобы
+func (a ModerationAction) Valid() bool {
+	switch a {
+	case ModerationDismiss, ModerationHide, ModerationDelete, ModerationWarn, ModerationBan:
+		return true
+	}
+	return false
+}
+
+// ModerationResolution - решение по открытым жалобам на содержимое.
+// BanDays задает срок блокировки для ban, 0 - бессрочно.
+type ModerationResolution struct {
+	Action  ModerationAction `json:"action" binding:"required"`
+	Note    string           `json:"note"`
+	BanDays int              `json:"ban_days"`
+}
+
+// ModerationLogEntry - запись журнала модерации: кто, когда и как решил жалобы
+// на содержимое или изменил пост. ReportCount - сколько жалоб закрыто решением.
+type ModerationLogEntry struct {
+	ID           int              `json:"id" db:"id"`
+	ModeratorID  *int             `json:"moderator_id,omitempty" db:"moderator_id"`
+	Moderator    string           `json:"moderator,omitempty" db:"-"`
+	Action       ModerationAction `json:"action" db:"action"`
+	TargetType   ReportTarget     `json:"target_type" db:"target_type"`
+	TargetID     int              `json:"target_id" db:"target_id"`
+	TargetUserID *int             `json:"target_user_id,omitempty" db:"target_user_id"`
+	TargetUser   string           `json:"target_user,omitempty" db:"-"`
+	Note         string           `json:"note,omitempty" db:"note"`
+	ReportCount  int              `json:"report_count" db:"report_count"`
+	BanExpiresAt *time.Time       `json:"ban_expires_at,omitempty" db:"ban_expires_at"`
+	CreatedAt    time.Time        `json:"created_at" db:"created_at"`
+}
+
+// ModerationLogParams - параметры запроса страницы журнала модерации
+type ModerationLogParams struct {
+	TargetUserID int
+	Cursor       string
+	Limit        int
+}
+
+// ModerationLogQuery - запрос к репозиторию: записи с id меньше BeforeID (0 - с начала)
+type ModerationLogQuery struct {
+	TargetUserID int
+	BeforeID     int
+	Limit        int
+}
+
+// ModerationLogPage - страница журнала модерации, новые записи первыми
+type ModerationLogPage struct {
+	Entries    []*ModerationLogEntry `json:"entries"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+// PostStateInput - новое состояние поста от модератора
+type PostStateInput struct {
+	State PostState `json:"state" binding:"required"`
+	Note  string    `json:"note"`
+}
+
+// PostPinInput - закрепление или открепление поста модератором
+type PostPinInput struct {
+	Pinned *bool  `json:"pinned" binding:"required"`
+	Note   string `json:"note"`
+}
+
+// UserBan - блокировка пользователя. ExpiresAt пусто у бессрочной блокировки.
+type UserBan struct {
+	UserID    int        `json:"user_id" db:"user_id"`
+	BannedBy  *int       `json:"banned_by,omitempty" db:"banned_by"`
+	Reason    string     `json:"reason,omitempty" db:"reason"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+}
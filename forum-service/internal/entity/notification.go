@@ -0,0 +1,58 @@
+package entity
+
+import "time"
+
+// NotificationType - событие, о котором сообщает уведомление
+type NotificationType string
+
+const (
+	// NotificationComment - новый комментарий в посте, на который подписан пользователь
+	NotificationComment NotificationType = "comment"
+	// NotificationReply - ответ на комментарий пользователя
+	NotificationReply NotificationType = "reply"
+	// NotificationMention - пользователя упомянули через @username
+	NotificationMention NotificationType = "mention"
+	// NotificationWarning - модератор вынес пользователю предупреждение
+	NotificationWarning NotificationType = "warning"
+	// NotificationBan - модератор заблокировал пользователя
+	NotificationBan NotificationType = "ban"
+)
+
+// Notification - уведомление пользователя UserID о действии ActorID.
+// ReadAt пусто, пока уведомление не прочитано.
+type Notification struct {
+	ID        int              `json:"id" db:"id"`
+	UserID    int              `json:"user_id" db:"user_id"`
+	Type      NotificationType `json:"type" db:"type"`
+	ActorID   *int             `json:"actor_id,omitempty" db:"actor_id"`
+	Actor     string           `json:"actor,omitempty" db:"-"`
+	PostID    *int             `json:"post_id,omitempty" db:"post_id"`
+	PostTitle string           `json:"post_title,omitempty" db:"-"`
+	CommentID *int             `json:"comment_id,omitempty" db:"comment_id"`
+	ReadAt    *time.Time       `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	// Note - пояснение модератора к предупреждению или блокировке
+	Note string `json:"note,omitempty" db:"note"`
+}
+
+// NotificationListParams - параметры запроса страницы уведомлений
+type NotificationListParams struct {
+	UnreadOnly bool
+	Cursor     string
+	Limit      int
+}
+
+// NotificationQuery - запрос к репозиторию: уведомления с id меньше BeforeID (0 - с начала)
+type NotificationQuery struct {
+	UserID     int
+	UnreadOnly bool
+	BeforeID   int
+	Limit      int
+}
+
+// NotificationPage - страница уведомлений, новые первыми
+type NotificationPage struct {
+	Notifications []*Notification `json:"notifications"`
+	NextCursor    string          `json:"next_cursor,omitempty"`
+	UnreadCount   int             `json:"unread_count"`
+}
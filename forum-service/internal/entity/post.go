@@ -4,14 +4,280 @@ import "time"
 
 // internal/entity/post.go
 type Post struct {
-	ID        int       `json:"id" db:"id"`
-	Title     string    `json:"title" db:"title"`
-	Content   string    `json:"content" db:"content"`
-	UserID    int       `json:"user_id" db:"user_id"`
-	Author    string    `json:"author" db:"-"` // db:"-" означает, что это поле не маппится напрямую
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	Comments  []Comment `json:"comments,omitempty" db:"-"`
+	ID           int       `json:"id" db:"id"`
+	Title        string    `json:"title" db:"title"`
+	Content      string    `json:"content" db:"content"`
+	UserID       int       `json:"user_id" db:"user_id"`
+	CategoryID   int       `json:"category_id" db:"category_id"`
+	Version      int       `json:"version" db:"version"` // растет с каждой правкой, см. If-Match
+	Tags         []string  `json:"tags" db:"-"`
+	Author       string    `json:"author" db:"-"` // db:"-" означает, что это поле не маппится напрямую
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"` // время публикации или последней правки
+	CommentCount int       `json:"comment_count" db:"-"`
+	ViewCount    int       `json:"view_count" db:"view_count"` // просмотры без повторных от того же читателя
+	// Format - разметка Content, ContentHTML - отрисованный и очищенный HTML
+	Format      ContentFormat `json:"format" db:"format"`
+	ContentHTML string        `json:"content_html" db:"content_html"`
+	// AttachmentIDs - загрузки, прикрепляемые при создании; Attachments - прикрепленные файлы
+	AttachmentIDs []int        `json:"attachment_ids,omitempty" db:"-"`
+	Attachments   []Attachment `json:"attachments,omitempty" db:"-"`
+	// Rank - значение рейтинга для сортировок hot, top и rising, для most_viewed -
+	// число просмотров за период
+	Rank float64 `json:"rank,omitempty" db:"-"`
+	// Score - сумма голосов, Reactions - число реакций по эмодзи;
+	// MyVote и MyReactions - голос и реакции запросившего пользователя
+	Score          int            `json:"score" db:"score"`
+	Reactions      map[string]int `json:"reactions" db:"reactions"`
+	MyVote         int            `json:"my_vote" db:"-"`
+	MyReactions    []string       `json:"my_reactions,omitempty" db:"-"`
+	LastActivityAt time.Time      `json:"last_activity_at" db:"-"`
+	Comments       []Comment      `json:"comments,omitempty" db:"-"`
+	// State - открыто ли обсуждение; Pinned - пост закреплен над остальными в списках
+	State  PostState `json:"state" db:"state"`
+	Pinned bool      `json:"pinned" db:"pinned"`
+	// Status - черновик, запланированный или опубликованный пост; пустое значение
+	// при создании - опубликованный. PublishAt - время публикации запланированного.
+	Status    PublishStatus `json:"status" db:"status"`
+	PublishAt *time.Time    `json:"publish_at,omitempty" db:"publish_at"`
 }
+
+// Unpublished сообщает, что пост - черновик или ждет публикации и виден только автору
+func (p *Post) Unpublished() bool {
+	return p.Status == PostDraft || p.Status == PostScheduled
+}
+
+// PublishStatus - опубликован ли пост
+type PublishStatus string
+
+const (
+	PostPublished PublishStatus = "published"
+	// PostDraft - черновик, автор опубликует его сам
+	PostDraft PublishStatus = "draft"
+	// PostScheduled - пост будет опубликован в PublishAt
+	PostScheduled PublishStatus = "scheduled"
+)
+
+// Valid сообщает, поддерживается ли статус
+func (s PublishStatus) Valid() bool {
+	return s == PostPublished || s == PostDraft || s == PostScheduled
+}
+
+// PostPublication - новый статус неопубликованного поста: published публикует
+// сразу, scheduled - в PublishAt, draft возвращает запланированный пост в черновики
+type PostPublication struct {
+	Status    PublishStatus `json:"status" binding:"required"`
+	PublishAt *time.Time    `json:"publish_at,omitempty"`
+}
+
+// DraftListParams - параметры запроса страницы своих черновиков и запланированных постов
+type DraftListParams struct {
+	Cursor string
+	Limit  int
+}
+
+// DraftQuery - запрос к репозиторию: неопубликованные посты UserID с id меньше BeforeID
+type DraftQuery struct {
+	UserID   int
+	BeforeID int
+	Limit    int
+}
+
+// PostState - состояние обсуждения поста, которое задает модератор
+type PostState string
+
+const (
+	PostOpen PostState = "open"
+	// PostLocked - новые комментарии не принимаются
+	PostLocked PostState = "locked"
+	// PostArchived - пост и комментарии доступны только для чтения
+	PostArchived PostState = "archived"
+)
+
+// Valid сообщает, поддерживается ли состояние
+func (s PostState) Valid() bool {
+	return s == PostOpen || s == PostLocked || s == PostArchived
+}
+
+// PostStatus - закрепление и состояние поста
+type PostStatus struct {
+	PostID int       `json:"post_id"`
+	UserID int       `json:"-"`
+	State  PostState `json:"state"`
+	Pinned bool      `json:"pinned"`
+}
+
+// PostStatusUpdate - изменение поста модератором: новое State либо Pinned
+type PostStatusUpdate struct {
+	State  PostState
+	Pinned *bool
+}
+
+// Action возвращает действие для журнала модерации при изменении current;
+// пустое, если изменение ничего не меняет
+func (u PostStatusUpdate) Action(current PostStatus) ModerationAction {
+	if u.Pinned != nil && *u.Pinned != current.Pinned {
+		if *u.Pinned {
+			return ModerationPin
+		}
+		return ModerationUnpin
+	}
+	if u.State == "" || u.State == current.State {
+		return ""
+	}
+	switch {
+	case u.State == PostLocked:
+		return ModerationLock
+	case u.State == PostArchived:
+		return ModerationArchive
+	case current.State == PostArchived:
+		return ModerationUnarchive
+	}
+	return ModerationUnlock
+}
+
+// PostSort задает порядок выдачи списка постов
+type PostSort string
+
+const (
+	PostSortNewest         PostSort = "newest"
+	PostSortOldest         PostSort = "oldest"
+	PostSortMostCommented  PostSort = "most_commented"
+	PostSortRecentlyActive PostSort = "recently_active"
+	// Рейтинговые сортировки используют значения, заранее посчитанные фоновой задачей
+	PostSortHot    PostSort = "hot"
+	PostSortTop    PostSort = "top"
+	PostSortRising PostSort = "rising"
+	// PostSortMostViewed - по числу просмотров за период window
+	PostSortMostViewed PostSort = "most_viewed"
+)
+
+// Valid сообщает, поддерживается ли порядок сортировки
+func (s PostSort) Valid() bool {
+	switch s {
+	case PostSortNewest, PostSortOldest, PostSortMostCommented, PostSortRecentlyActive,
+		PostSortHot, PostSortTop, PostSortRising, PostSortMostViewed:
+		return true
+	}
+	return false
+}
+
+// Ranked сообщает, упорядочена ли выдача по рейтингу или числу просмотров
+func (s PostSort) Ranked() bool {
+	return s == PostSortHot || s == PostSortTop || s == PostSortRising || s == PostSortMostViewed
+}
+
+// Windowed сообщает, принимает ли сортировка период window
+func (s PostSort) Windowed() bool {
+	return s == PostSortTop || s == PostSortMostViewed
+}
+
+// TopWindow - период для сортировок top и most_viewed: для top учитываются посты,
+// созданные за этот период, для most_viewed - просмотры за него
+type TopWindow string
+
+const (
+	TopWindowDay  TopWindow = "day"
+	TopWindowWeek TopWindow = "week"
+	TopWindowAll  TopWindow = "all"
+)
+
+// Duration возвращает длину периода; 0 для all и неизвестных значений
+func (w TopWindow) Duration() time.Duration {
+	switch w {
+	case TopWindowDay:
+		return 24 * time.Hour
+	case TopWindowWeek:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// Valid сообщает, поддерживается ли период
+func (w TopWindow) Valid() bool {
+	return w == TopWindowDay || w == TopWindowWeek || w == TopWindowAll
+}
+
+// PostActivity - активность поста, по которой фоновая задача считает рейтинги.
+// Recent* - голоса и комментарии за последнее окно rising.
+type PostActivity struct {
+	PostID         int
+	CreatedAt      time.Time
+	Score          int
+	Comments       int
+	RecentScore    int
+	RecentComments int
+}
+
+// PostViews - число новых просмотров поста за час, начинающийся в Hour
+type PostViews struct {
+	PostID int
+	Hour   time.Time
+	Views  int
+}
+
+// PostRanking - посчитанные значения рейтингов поста
+type PostRanking struct {
+	PostID int
+	Hot    float64
+	Top    float64
+	Rising float64
+}
+
+// PostFilter ограничивает список постов разделом, меткой, автором и интервалом дат создания [From, To)
+type PostFilter struct {
+	CategoryID int
+	Tag        string
+	Author     string
+	From       time.Time
+	To         time.Time
+}
+
+// PostListParams - параметры запроса страницы постов.
+// Cursor - непрозрачная строка из NextCursor предыдущей страницы.
+type PostListParams struct {
+	Sort   PostSort
+	Window TopWindow // только для sort=top
+	Filter PostFilter
+	Cursor string
+	Limit  int
+}
+
+// PostCursor - позиция в выдаче, после которой начинается следующая страница.
+// Для сортировок по времени используется Time, для most_commented - Count,
+// для рейтинговых - Rank. Pinned - позиция среди закрепленных постов, которые идут первыми.
+type PostCursor struct {
+	Sort   PostSort  `json:"s"`
+	Window TopWindow `json:"w,omitempty"`
+	Time   time.Time `json:"t,omitempty"`
+	Count  int       `json:"c,omitempty"`
+	Rank   float64   `json:"r,omitempty"`
+	Pinned bool      `json:"p,omitempty"`
+	ID     int       `json:"id"`
+}
+
+// PostQuery - запрос к репозиторию с уже разобранным курсором
+type PostQuery struct {
+	Sort   PostSort
+	Window TopWindow
+	Filter PostFilter
+	After  *PostCursor
+	Limit  int
+}
+
+// PostPage - страница постов и курсор следующей страницы (пустой на последней)
+type PostPage struct {
+	Posts      []*Post `json:"posts"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// Роли пользователей
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
 type User struct {
 	ID           int    `json:"id"`
 	Username     string `json:"username"`
@@ -20,6 +286,11 @@ type User struct {
 	Role         string `json:"role"`
 }
 
+// IsModerator сообщает, может ли пользователь модерировать форум
+func (u *User) IsModerator() bool {
+	return u.Role == RoleModerator || u.Role == RoleAdmin
+}
+
 type AuthResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
@@ -0,0 +1,69 @@
+package entity
+
+import "time"
+
+// PostRevision - сохраненная версия заголовка и текста поста. Rev нумерует версии
+// поста с 1; последняя версия совпадает с текущим постом.
+type PostRevision struct {
+	ID        int       `json:"id" db:"id"`
+	PostID    int       `json:"post_id" db:"post_id"`
+	Rev       int       `json:"rev" db:"rev"`
+	Title     string    `json:"title" db:"title"`
+	Content   string    `json:"content,omitempty" db:"content"` // в списке версий не заполняется
+	EditorID  *int      `json:"editor_id,omitempty" db:"editor_id"`
+	Editor    string    `json:"editor,omitempty" db:"-"`
+	Summary   string    `json:"summary,omitempty" db:"summary"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// PostUpdate - правка поста. Метки заменяются, только если Tags не nil;
+// Summary - необязательное описание правки для истории. Version - версия поста,
+// на которой основана правка (из If-Match); 0 - без проверки.
+type PostUpdate struct {
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"`
+	Summary string   `json:"summary"`
+	Version int      `json:"-"`
+	// Format - новая разметка, пустое значение оставляет текущую;
+	// ContentHTML заполняется сервисом перед сохранением
+	Format      ContentFormat `json:"format"`
+	ContentHTML string        `json:"-"`
+}
+
+// DiffFormat задает вид сравнения версий
+type DiffFormat string
+
+const (
+	// DiffFormatUnified - построчный unified diff
+	DiffFormatUnified DiffFormat = "unified"
+	// DiffFormatWord - пословное сравнение в виде фрагментов
+	DiffFormatWord DiffFormat = "word"
+)
+
+// DiffOp - тип фрагмента пословного сравнения
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffSegment - фрагмент пословного сравнения
+type DiffSegment struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// PostDiff - сравнение версий From и To поста. Для формата unified заполняется Unified,
+// для word - Title и Content.
+type PostDiff struct {
+	PostID  int           `json:"post_id"`
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Format  DiffFormat    `json:"format"`
+	Unified string        `json:"unified,omitempty"`
+	Title   []DiffSegment `json:"title,omitempty"`
+	Content []DiffSegment `json:"content,omitempty"`
+}
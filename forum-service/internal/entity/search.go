@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+// SearchType ограничивает поиск постами или комментариями
+type SearchType string
+
+const (
+	SearchTypeAll     SearchType = "all"
+	SearchTypePost    SearchType = "post"
+	SearchTypeComment SearchType = "comment"
+)
+
+// Valid сообщает, поддерживается ли тип результатов
+func (t SearchType) Valid() bool {
+	switch t {
+	case SearchTypeAll, SearchTypePost, SearchTypeComment:
+		return true
+	}
+	return false
+}
+
+// SearchParams - параметры поискового запроса.
+// Query понимает синтаксис websearch_to_tsquery: "фраза", OR, -исключение.
+type SearchParams struct {
+	Query  string
+	Type   SearchType
+	Filter PostFilter
+	Cursor string
+	Limit  int
+}
+
+// SearchQuery - запрос к репозиторию с разобранным курсором
+type SearchQuery struct {
+	Query  string
+	Type   SearchType
+	Filter PostFilter
+	Offset int
+	Limit  int
+}
+
+// SearchResult - найденный пост или комментарий.
+// Snippet экранирован для HTML, совпадения обернуты в <mark>.
+type SearchResult struct {
+	Type      SearchType `json:"type"`
+	PostID    int        `json:"post_id"`
+	CommentID int        `json:"comment_id,omitempty"`
+	Title     string     `json:"title"`
+	Snippet   string     `json:"snippet"`
+	UserID    int        `json:"user_id"`
+	Author    string     `json:"author"`
+	CreatedAt time.Time  `json:"created_at"`
+	Rank      float64    `json:"rank"`
+}
+
+// SearchPage - страница результатов и курсор следующей страницы
+type SearchPage struct {
+	Results    []*SearchResult `json:"results"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,8 @@
+package entity
+
+// Tag - метка поста. PostCount - число постов с этой меткой.
+type Tag struct {
+	ID        int    `json:"id" db:"id"`
+	Name      string `json:"name" db:"name"`
+	PostCount int    `json:"post_count" db:"-"`
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// TrashItem - удаленный пост или комментарий, который еще можно восстановить.
+// Content.Title - заголовок поста или поста, к которому относится комментарий;
+// PurgeAt - когда фоновая задача удалит его окончательно.
+type TrashItem struct {
+	TargetType  ReportTarget    `json:"target_type"`
+	TargetID    int             `json:"target_id"`
+	Content     ReportedContent `json:"content"`
+	DeletedAt   time.Time       `json:"deleted_at"`
+	DeletedByID *int            `json:"deleted_by_id,omitempty"`
+	DeletedBy   string          `json:"deleted_by,omitempty"`
+	PurgeAt     time.Time       `json:"purge_at"`
+}
+
+// TrashParams - параметры запроса страницы корзины. Пустой TargetType - посты и
+// комментарии вместе.
+type TrashParams struct {
+	TargetType ReportTarget
+	Cursor     string
+	Limit      int
+}
+
+// TrashCursor - позиция в корзине: время удаления, тип и id последнего элемента
+type TrashCursor struct {
+	DeletedAt  time.Time    `json:"t"`
+	TargetType ReportTarget `json:"k"`
+	TargetID   int          `json:"id"`
+}
+
+// TrashQuery - запрос к репозиторию с уже разобранным курсором
+type TrashQuery struct {
+	TargetType ReportTarget
+	After      *TrashCursor
+	Limit      int
+}
+
+// TrashPage - страница корзины, недавно удаленное первым
+type TrashPage struct {
+	Items      []*TrashItem `json:"items"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+// PurgeResult - сколько постов и комментариев удалено из корзины окончательно.
+// Комментарии, ушедшие вместе с постом или родительским комментарием, не считаются.
+type PurgeResult struct {
+	Posts    int
+	Comments int
+}
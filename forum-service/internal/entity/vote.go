@@ -0,0 +1,24 @@
+package entity
+
+// TargetKind - тип объекта, за который голосуют или на который реагируют
+type TargetKind string
+
+const (
+	TargetPost    TargetKind = "post"
+	TargetComment TargetKind = "comment"
+)
+
+// VoteTarget - пост или комментарий. Для комментария PostID - пост, к которому он относится.
+type VoteTarget struct {
+	Kind   TargetKind
+	ID     int
+	PostID int
+}
+
+// VoteState - итоги голосов и реакций объекта после изменения и вклад в них пользователя
+type VoteState struct {
+	Score       int            `json:"score"`
+	Reactions   map[string]int `json:"reactions"`
+	MyVote      int            `json:"my_vote"`
+	MyReactions []string       `json:"my_reactions"`
+}
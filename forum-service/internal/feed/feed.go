@@ -0,0 +1,223 @@
+// Package feed renders post feeds as Atom 1.0 and RSS 2.0 documents. All text
+// goes through encoding/xml, which escapes markup and replaces characters that
+// are not allowed in XML, so titles and post bodies cannot break the document.
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+// Format is the syndication format of a rendered feed.
+type Format string
+
+const (
+	Atom Format = "atom"
+	RSS  Format = "rss"
+)
+
+// ContentType returns the media type a feed of format f is served with.
+func (f Format) ContentType() string {
+	if f == RSS {
+		return "application/rss+xml; charset=utf-8"
+	}
+	return "application/atom+xml; charset=utf-8"
+}
+
+// Render writes f in format. Links are absolute: baseURL is the public address of
+// the forum and selfPath the path the feed itself is served at.
+func Render(format Format, f *entity.Feed, baseURL, selfPath string) ([]byte, error) {
+	baseURL = strings.TrimRight(baseURL, "/")
+	var doc interface{}
+	if format == RSS {
+		doc = rssDocument(f, baseURL, selfPath)
+	} else {
+		doc = atomDocument(f, baseURL, selfPath)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// PostPath is the path a feed entry links to.
+func PostPath(postID int) string {
+	return "/posts/" + strconv.Itoa(postID)
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     atomPerson     `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomText       `xml:"content"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func atomDocument(f *entity.Feed, baseURL, selfPath string) *atomFeed {
+	doc := &atomFeed{
+		Title:    f.Title,
+		Subtitle: f.Description,
+		ID:       baseURL + selfPath,
+		Updated:  atomTime(f.Updated),
+		Links: []atomLink{
+			{Rel: "self", Type: Atom.mediaType(), Href: baseURL + selfPath},
+			{Rel: "alternate", Href: baseURL + f.Path},
+		},
+	}
+	for _, post := range f.Posts {
+		link := baseURL + PostPath(post.ID)
+		entry := atomEntry{
+			Title:     post.Title,
+			ID:        link,
+			Link:      atomLink{Rel: "alternate", Href: link},
+			Published: atomTime(post.CreatedAt),
+			Updated:   atomTime(postUpdated(post)),
+			Author:    atomPerson{Name: post.Author},
+			Content:   atomText{Type: "html", Body: postHTML(post)},
+		}
+		for _, tag := range post.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return doc
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Creator     string   `xml:"dc:creator"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func rssDocument(f *entity.Feed, baseURL, selfPath string) *rssFeed {
+	doc := &rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        baseURL + f.Path,
+			Description: f.Description,
+			Self:        atomLink{Rel: "self", Type: RSS.mediaType(), Href: baseURL + selfPath},
+		},
+	}
+	if doc.Channel.Description == "" {
+		doc.Channel.Description = f.Title
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+	for _, post := range f.Posts {
+		link := baseURL + PostPath(post.ID)
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       post.Title,
+			Link:        link,
+			GUID:        rssGUID{IsPermaLink: true, Value: link},
+			PubDate:     post.CreatedAt.UTC().Format(time.RFC1123Z),
+			Creator:     post.Author,
+			Categories:  post.Tags,
+			Description: postHTML(post),
+		})
+	}
+	return doc
+}
+
+// mediaType is ContentType without parameters, as used in link elements.
+func (f Format) mediaType() string {
+	t, _, _ := strings.Cut(f.ContentType(), ";")
+	return t
+}
+
+// postHTML returns the rendered body of a post. Posts saved before rendering was
+// introduced have no HTML, so their source is escaped instead.
+func postHTML(post *entity.Post) string {
+	if post.ContentHTML != "" {
+		return post.ContentHTML
+	}
+	return "<p>" + html.EscapeString(post.Content) + "</p>"
+}
+
+func postUpdated(post *entity.Post) time.Time {
+	if post.UpdatedAt.After(post.CreatedAt) {
+		return post.UpdatedAt
+	}
+	return post.CreatedAt
+}
+
+// atomTime formats t as an RFC 3339 date; an empty feed is dated at the epoch.
+func atomTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
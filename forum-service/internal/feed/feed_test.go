@@ -0,0 +1,101 @@
+package feed
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFeed() *entity.Feed {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*3600))
+	return &entity.Feed{
+		Title:   "Q&A <forum>",
+		Path:    "/posts",
+		Updated: created.Add(time.Hour),
+		Posts: []*entity.Post{
+			{
+				ID:          7,
+				Title:       "</title><script>\x01",
+				Author:      "alice",
+				Tags:        []string{"go", "c++"},
+				ContentHTML: "<p>a &amp; b</p>",
+				CreatedAt:   created,
+				UpdatedAt:   created.Add(time.Hour),
+			},
+			{ID: 5, Title: "plain", Author: "bob", Content: "1 < 2", CreatedAt: created.Add(-time.Hour)},
+		},
+	}
+}
+
+func TestRender_Atom(t *testing.T) {
+	body, err := Render(Atom, testFeed(), "https://forum.example/", "/feeds/posts.atom")
+	require.NoError(t, err)
+
+	var doc atomFeed
+	require.NoError(t, xml.Unmarshal(body, &doc))
+	assert.Equal(t, "Q&A <forum>", doc.Title)
+	assert.Equal(t, "https://forum.example/feeds/posts.atom", doc.ID)
+	assert.Equal(t, "2025-03-01T10:00:00Z", doc.Updated)
+	assert.Equal(t, "https://forum.example/posts", doc.Links[1].Href)
+
+	require.Len(t, doc.Entries, 2)
+	entry := doc.Entries[0]
+	// Недопустимый в XML символ заменяется, разметка в заголовке экранируется
+	assert.Equal(t, "</title><script>�", entry.Title)
+	assert.Equal(t, "https://forum.example/posts/7", entry.ID)
+	assert.Equal(t, "2025-03-01T09:00:00Z", entry.Published)
+	assert.Equal(t, "2025-03-01T10:00:00Z", entry.Updated)
+	assert.Equal(t, "alice", entry.Author.Name)
+	assert.Equal(t, []atomCategory{{Term: "go"}, {Term: "c++"}}, entry.Categories)
+	assert.Equal(t, atomText{Type: "html", Body: "<p>a &amp; b</p>"}, entry.Content)
+
+	// Пост без отрисованного HTML: текст экранируется, updated не раньше published
+	assert.Equal(t, "<p>1 &lt; 2</p>", doc.Entries[1].Content.Body)
+	assert.Equal(t, doc.Entries[1].Published, doc.Entries[1].Updated)
+}
+
+func TestRender_RSS(t *testing.T) {
+	body, err := Render(RSS, testFeed(), "https://forum.example", "/feeds/posts.rss")
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `<atom:link rel="self" type="application/rss+xml" href="https://forum.example/feeds/posts.rss"></atom:link>`)
+
+	var doc rssFeed
+	require.NoError(t, xml.Unmarshal(body, &doc))
+	assert.Equal(t, "2.0", doc.Version)
+	assert.Equal(t, "Q&A <forum>", doc.Channel.Title)
+	// Без описания используется заголовок
+	assert.Equal(t, "Q&A <forum>", doc.Channel.Description)
+	assert.Equal(t, "Sat, 01 Mar 2025 10:00:00 +0000", doc.Channel.LastBuildDate)
+
+	require.Len(t, doc.Channel.Items, 2)
+	item := doc.Channel.Items[0]
+	assert.Equal(t, "https://forum.example/posts/7", item.Link)
+	assert.Equal(t, rssGUID{IsPermaLink: true, Value: "https://forum.example/posts/7"}, item.GUID)
+	assert.Equal(t, "Sat, 01 Mar 2025 09:00:00 +0000", item.PubDate)
+	assert.Equal(t, []string{"go", "c++"}, item.Categories)
+	assert.Equal(t, "<p>a &amp; b</p>", item.Description)
+}
+
+func TestRender_EmptyFeed(t *testing.T) {
+	f := &entity.Feed{Title: "Empty", Path: "/posts"}
+
+	body, err := Render(Atom, f, "https://forum.example", "/feeds/posts.atom")
+	require.NoError(t, err)
+	var atom atomFeed
+	require.NoError(t, xml.Unmarshal(body, &atom))
+	assert.Equal(t, "1970-01-01T00:00:00Z", atom.Updated)
+	assert.Empty(t, atom.Entries)
+
+	body, err = Render(RSS, f, "https://forum.example", "/feeds/posts.rss")
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "lastBuildDate")
+}
+
+func TestFormat_ContentType(t *testing.T) {
+	assert.Equal(t, "application/atom+xml; charset=utf-8", Atom.ContentType())
+	assert.Equal(t, "application/rss+xml; charset=utf-8", RSS.ContentType())
+}
@@ -0,0 +1,71 @@
+// Package markup renders post and comment sources to HTML that is safe to embed
+// in a page: CommonMark with GitHub extensions and highlighted code blocks,
+// passed through an allow-list sanitizer.
+package markup
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+)
+
+// classPattern limits class attributes to the plain identifiers emitted by the
+// highlighter and the GFM renderer.
+var classPattern = regexp.MustCompile(`^[a-zA-Z0-9 _-]+$`)
+
+// Raw HTML in the source is dropped by goldmark (html.WithUnsafe is not set);
+// the sanitizer is a second line of defence against anything the renderer emits.
+var (
+	md = goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			highlighting.NewHighlighting(
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+			),
+		),
+	)
+	policy = newPolicy()
+)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(classPattern).OnElements("pre", "code", "span")
+	// GFM task lists render as disabled checkboxes
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// Render returns sanitized HTML for source written in format. An empty format is
+// treated as plain text.
+func Render(format entity.ContentFormat, source string) (string, error) {
+	switch format {
+	case entity.FormatPlain, "":
+		return RenderPlain(source), nil
+	case entity.FormatMarkdown:
+		var buf bytes.Buffer
+		if err := md.Convert([]byte(source), &buf); err != nil {
+			return "", fmt.Errorf("render markdown: %w", err)
+		}
+		return policy.Sanitize(buf.String()), nil
+	}
+	return "", fmt.Errorf("unknown content format %q", format)
+}
+
+// RenderPlain escapes source and keeps its line breaks. Migration 015 backfills
+// existing rows with the same transformation in SQL.
+func RenderPlain(source string) string {
+	escaped := html.EscapeString(source)
+	return "<p>" + strings.ReplaceAll(escaped, "\n", "<br>") + "</p>"
+}
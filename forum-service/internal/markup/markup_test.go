@@ -0,0 +1,79 @@
+package markup
+
+import (
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_Markdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Table",
+			source:   "| a | b |\n|---|---|\n| 1 | 2 |",
+			contains: []string{"<table>", "<th>a</th>", "<td>2</td>"},
+		},
+		{
+			name:     "TaskList",
+			source:   "- [x] done\n- [ ] todo",
+			contains: []string{`<input checked="" disabled="" type="checkbox"> done`, `<input disabled="" type="checkbox"> todo`},
+		},
+		{
+			name:     "HighlightedCode",
+			source:   "```go\nfunc main() {}\n```",
+			contains: []string{`<pre class="chroma">`, `<span class="kd">func</span>`},
+			excludes: []string{"style="},
+		},
+		{
+			name:     "Strikethrough",
+			source:   "~~old~~",
+			contains: []string{"<del>old</del>"},
+		},
+		{
+			name:     "RawHTMLDropped",
+			source:   `<script>alert(1)</script><img src=x onerror=alert(1)>`,
+			excludes: []string{"<script", "onerror", "<img"},
+		},
+		{
+			name:     "UnsafeLinkDropped",
+			source:   "[x](javascript:alert(1)) [y](https://example.com)",
+			contains: []string{`<a href="https://example.com" rel="nofollow noopener" target="_blank">y</a>`},
+			excludes: []string{"javascript:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Render(entity.FormatMarkdown, tt.source)
+			require.NoError(t, err)
+			for _, s := range tt.contains {
+				assert.Contains(t, out, s)
+			}
+			for _, s := range tt.excludes {
+				assert.NotContains(t, out, s)
+			}
+		})
+	}
+}
+
+func TestRender_Plain(t *testing.T) {
+	out, err := Render(entity.FormatPlain, "a < b & \"c\"\n**not bold**")
+	require.NoError(t, err)
+	assert.Equal(t, "<p>a &lt; b &amp; &#34;c&#34;<br>**not bold**</p>", out)
+
+	out, err = Render("", "x")
+	require.NoError(t, err)
+	assert.Equal(t, "<p>x</p>", out)
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	_, err := Render("html", "<b>x</b>")
+	assert.Error(t, err)
+}
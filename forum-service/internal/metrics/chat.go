@@ -0,0 +1,55 @@
+// Package metrics exposes the chat WebSocket hub metrics of forum-service. The
+// HTTP, gRPC and database metrics shared by the services live in platform/metrics.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ChatMetrics receives events from the chat WebSocket hub.
+type ChatMetrics struct {
+	dropped   prometheus.Counter
+	broadcast prometheus.Histogram
+}
+
+// NewChatMetrics registers the hub collectors with reg. connected is sampled on
+// every scrape to report the number of open WebSocket connections.
+func NewChatMetrics(reg prometheus.Registerer, namespace string, connected func() int) *ChatMetrics {
+	cm := &ChatMetrics{
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "chat",
+			Name:      "dropped_slow_clients_total",
+			Help:      "WebSocket clients disconnected because their send buffer was full.",
+		}),
+		broadcast: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "chat",
+			Name:      "broadcast_duration_seconds",
+			Help:      "Time spent fanning a chat message out to all connected clients.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+		}),
+	}
+
+	reg.MustRegister(
+		cm.dropped,
+		cm.broadcast,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "chat",
+			Name:      "connected_clients",
+			Help:      "Currently connected WebSocket chat clients.",
+		}, func() float64 { return float64(connected()) }),
+	)
+	return cm
+}
+
+func (cm *ChatMetrics) SlowClientDropped() {
+	cm.dropped.Inc()
+}
+
+func (cm *ChatMetrics) ObserveBroadcast(d time.Duration) {
+	cm.broadcast.Observe(d.Seconds())
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func scrape(t *testing.T, reg *prometheus.Registry) string {
+	w := httptest.NewRecorder()
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestChatMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	cm := NewChatMetrics(reg, "forum", func() int { return 3 })
+
+	cm.SlowClientDropped()
+	cm.ObserveBroadcast(time.Millisecond)
+
+	body := scrape(t, reg)
+	assert.Contains(t, body, "forum_chat_connected_clients 3")
+	assert.Contains(t, body, "forum_chat_dropped_slow_clients_total 1")
+	assert.Contains(t, body, "forum_chat_broadcast_duration_seconds_count 1")
+}
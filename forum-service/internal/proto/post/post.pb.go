@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
 // 	protoc-gen-go v1.36.6
-// 	protoc        v6.30.2
+// 	protoc        (unknown)
 // source: post.proto
 
 package post
@@ -10,6 +10,7 @@ import (
 	_ "github.com/lera-guryan2222/fooorum/forum-service/internal/proto/user"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -72,6 +73,8 @@ type PostResponse struct {
 	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
 	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
 	AuthorName    string                 `protobuf:"bytes,4,opt,name=author_name,json=authorName,proto3" json:"author_name,omitempty"` // Будем заполнять через gRPC вызов
+	CategoryId    int32                  `protobuf:"varint,5,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Tags          []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -134,23 +137,217 @@ func (x *PostResponse) GetAuthorName() string {
 	return ""
 }
 
+func (x *PostResponse) GetCategoryId() int32 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *PostResponse) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// Пустые поля означают значения по умолчанию: sort=newest, page_size=20, без фильтров
+type ListPostsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sort          string                 `protobuf:"bytes,1,opt,name=sort,proto3" json:"sort,omitempty"`     // newest | oldest | most_commented | recently_active | hot | top | rising
+	Author        string                 `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"` // username автора
+	From          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	PageSize      int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Cursor        string                 `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`                            // next_cursor из предыдущего ответа
+	CategoryId    int32                  `protobuf:"varint,7,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"` // 0 - все разделы
+	Tag           string                 `protobuf:"bytes,8,opt,name=tag,proto3" json:"tag,omitempty"`                                  // имя метки
+	Window        string                 `protobuf:"bytes,9,opt,name=window,proto3" json:"window,omitempty"`                            // day | week | all, только для sort=top
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPostsRequest) Reset() {
+	*x = ListPostsRequest{}
+	mi := &file_post_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPostsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPostsRequest) ProtoMessage() {}
+
+func (x *ListPostsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_post_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPostsRequest.ProtoReflect.Descriptor instead.
+func (*ListPostsRequest) Descriptor() ([]byte, []int) {
+	return file_post_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListPostsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListPostsRequest) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *ListPostsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListPostsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListPostsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListPostsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListPostsRequest) GetCategoryId() int32 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *ListPostsRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ListPostsRequest) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+type ListPostsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Posts         []*PostResponse        `protobuf:"bytes,1,rep,name=posts,proto3" json:"posts,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // пустой на последней странице
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPostsResponse) Reset() {
+	*x = ListPostsResponse{}
+	mi := &file_post_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPostsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPostsResponse) ProtoMessage() {}
+
+func (x *ListPostsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_post_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPostsResponse.ProtoReflect.Descriptor instead.
+func (*ListPostsResponse) Descriptor() ([]byte, []int) {
+	return file_post_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListPostsResponse) GetPosts() []*PostResponse {
+	if x != nil {
+		return x.Posts
+	}
+	return nil
+}
+
+func (x *ListPostsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 var File_post_proto protoreflect.FileDescriptor
 
 const file_post_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
 	"post.proto\x12\x04post\x1a\n" +
-	"user.proto\"&\n" +
+	"user.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"&\n" +
 	"\vPostRequest\x12\x17\n" +
-	"\apost_id\x18\x01 \x01(\x05R\x06postId\"o\n" +
+	"\apost_id\x18\x01 \x01(\x05R\x06postId\"\xa4\x01\n" +
 	"\fPostResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
 	"\acontent\x18\x03 \x01(\tR\acontent\x12\x1f\n" +
 	"\vauthor_name\x18\x04 \x01(\tR\n" +
-	"authorName2I\n" +
+	"authorName\x12\x1f\n" +
+	"\vcategory_id\x18\x05 \x01(\x05R\n" +
+	"categoryId\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\"\x9a\x02\n" +
+	"\x10ListPostsRequest\x12\x12\n" +
+	"\x04sort\x18\x01 \x01(\tR\x04sort\x12\x16\n" +
+	"\x06author\x18\x02 \x01(\tR\x06author\x12.\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x16\n" +
+	"\x06cursor\x18\x06 \x01(\tR\x06cursor\x12\x1f\n" +
+	"\vcategory_id\x18\a \x01(\x05R\n" +
+	"categoryId\x12\x10\n" +
+	"\x03tag\x18\b \x01(\tR\x03tag\x12\x16\n" +
+	"\x06window\x18\t \x01(\tR\x06window\"^\n" +
+	"\x11ListPostsResponse\x12(\n" +
+	"\x05posts\x18\x01 \x03(\v2\x12.post.PostResponseR\x05posts\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor2\x87\x01\n" +
 	"\vPostService\x12:\n" +
-	"\x11GetPostWithAuthor\x12\x11.post.PostRequest\x1a\x12.post.PostResponseB:Z8github.com/perfect1337/forum-service/internal/proto/postb\x06proto3"
+	"\x11GetPostWithAuthor\x12\x11.post.PostRequest\x1a\x12.post.PostResponse\x12<\n" +
+	"\tListPosts\x12\x16.post.ListPostsRequest\x1a\x17.post.ListPostsResponseBFZDgithub.com/lera-guryan2222/fooorum/forum-service/internal/proto/postb\x06proto3"
 
 var (
 	file_post_proto_rawDescOnce sync.Once
@@ -164,19 +361,27 @@ func file_post_proto_rawDescGZIP() []byte {
 	return file_post_proto_rawDescData
 }
 
-var file_post_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_post_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_post_proto_goTypes = []any{
-	(*PostRequest)(nil),  // 0: post.PostRequest
-	(*PostResponse)(nil), // 1: post.PostResponse
+	(*PostRequest)(nil),           // 0: post.PostRequest
+	(*PostResponse)(nil),          // 1: post.PostResponse
+	(*ListPostsRequest)(nil),      // 2: post.ListPostsRequest
+	(*ListPostsResponse)(nil),     // 3: post.ListPostsResponse
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
 }
 var file_post_proto_depIdxs = []int32{
-	0, // 0: post.PostService.GetPostWithAuthor:input_type -> post.PostRequest
-	1, // 1: post.PostService.GetPostWithAuthor:output_type -> post.PostResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	4, // 0: post.ListPostsRequest.from:type_name -> google.protobuf.Timestamp
+	4, // 1: post.ListPostsRequest.to:type_name -> google.protobuf.Timestamp
+	1, // 2: post.ListPostsResponse.posts:type_name -> post.PostResponse
+	0, // 3: post.PostService.GetPostWithAuthor:input_type -> post.PostRequest
+	2, // 4: post.PostService.ListPosts:input_type -> post.ListPostsRequest
+	1, // 5: post.PostService.GetPostWithAuthor:output_type -> post.PostResponse
+	3, // 6: post.PostService.ListPosts:output_type -> post.ListPostsResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_post_proto_init() }
@@ -190,7 +395,7 @@ func file_post_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_post_proto_rawDesc), len(file_post_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lib/pq"
+)
+
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, attachment *entity.Attachment) error
+	GetAttachment(ctx context.Context, id int) (*entity.Attachment, error)
+	ListAttachments(ctx context.Context, target entity.AttachmentTarget, ids []int) ([]entity.Attachment, error)
+	ListOrphanedAttachments(ctx context.Context, before time.Time, limit int) ([]entity.Attachment, error)
+	DeleteOrphanedAttachment(ctx context.Context, id int) error
+}
+
+const attachmentColumns = `id, user_id, filename, content_type, size, COALESCE(width, 0), COALESCE(height, 0),
+        blob_key, COALESCE(thumbnail_key, ''), post_id, comment_id, chat_message_id, created_at`
+
+// orphaned matches attachments that belong to nothing
+const orphaned = `post_id IS NULL AND comment_id IS NULL AND chat_message_id IS NULL`
+
+func attachmentColumn(target entity.AttachmentTarget) (string, error) {
+	switch target {
+	case entity.AttachedToPost:
+		return "post_id", nil
+	case entity.AttachedToComment:
+		return "comment_id", nil
+	case entity.AttachedToChat:
+		return "chat_message_id", nil
+	}
+	return "", fmt.Errorf("unknown attachment target %q", target)
+}
+
+func (p *Postgres) CreateAttachment(ctx context.Context, a *entity.Attachment) error {
+	var thumbnailKey sql.NullString
+	if a.ThumbnailKey != "" {
+		thumbnailKey = sql.NullString{String: a.ThumbnailKey, Valid: true}
+	}
+	var width, height sql.NullInt64
+	if a.Width > 0 {
+		width = sql.NullInt64{Int64: int64(a.Width), Valid: true}
+		height = sql.NullInt64{Int64: int64(a.Height), Valid: true}
+	}
+	err := p.db.QueryRowContext(ctx, `
+        INSERT INTO attachments (user_id, blob_key, thumbnail_key, filename, content_type, size, width, height)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id, created_at
+    `, a.UserID, a.Key, thumbnailKey, a.Filename, a.ContentType, a.Size, width, height).Scan(&a.ID, &a.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) GetAttachment(ctx context.Context, id int) (*entity.Attachment, error) {
+	row := p.db.QueryRowContext(ctx, `SELECT `+attachmentColumns+` FROM attachments WHERE id = $1`, id)
+	attachment, err := scanAttachment(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// ListAttachments returns the attachments of the given posts, comments or chat
+// messages in upload order.
+func (p *Postgres) ListAttachments(ctx context.Context, target entity.AttachmentTarget, ids []int) ([]entity.Attachment, error) {
+	column, err := attachmentColumn(target)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := p.db.QueryContext(ctx, fmt.Sprintf(`
+        SELECT %s FROM attachments WHERE %s = ANY($1) ORDER BY id
+    `, attachmentColumns, column), pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	return scanAttachments(rows)
+}
+
+// ListOrphanedAttachments returns up to limit attachments created before before
+// that belong to nothing, oldest first.
+func (p *Postgres) ListOrphanedAttachments(ctx context.Context, before time.Time, limit int) ([]entity.Attachment, error) {
+	rows, err := p.db.QueryContext(ctx, `
+        SELECT `+attachmentColumns+` FROM attachments
+        WHERE `+orphaned+` AND created_at < $1
+        ORDER BY created_at
+        LIMIT $2
+    `, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orphaned attachments: %w", err)
+	}
+	return scanAttachments(rows)
+}
+
+// DeleteOrphanedAttachment deletes the attachment unless it has been attached in
+// the meantime, in which case a wrapped sql.ErrNoRows is returned.
+func (p *Postgres) DeleteOrphanedAttachment(ctx context.Context, id int) error {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM attachments WHERE id = $1 AND `+orphaned, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	return requireAffected(res, "orphaned attachment")
+}
+
+// linkAttachments attaches the uploads ids of userID to the row id of target.
+// Uploads that do not exist, belong to someone else or are already attached make
+// it fail with a wrapped sql.ErrNoRows, so the caller's transaction rolls back.
+func linkAttachments(ctx context.Context, tx *sql.Tx, target entity.AttachmentTarget, id, userID int, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	column, err := attachmentColumn(target)
+	if err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, fmt.Sprintf(`
+        UPDATE attachments SET %s = $1
+        WHERE id = ANY($2) AND user_id = $3 AND %s
+    `, column, orphaned), id, pq.Array(ids), userID)
+	if err != nil {
+		return fmt.Errorf("failed to link attachments: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if int(n) != len(ids) {
+		return fmt.Errorf("attachments not found: %w", sql.ErrNoRows)
+	}
+	return nil
+}
+
+func scanAttachment(row scanner) (*entity.Attachment, error) {
+	var a entity.Attachment
+	var postID, commentID, chatMessageID sql.NullInt64
+	if err := row.Scan(
+		&a.ID,
+		&a.UserID,
+		&a.Filename,
+		&a.ContentType,
+		&a.Size,
+		&a.Width,
+		&a.Height,
+		&a.Key,
+		&a.ThumbnailKey,
+		&postID,
+		&commentID,
+		&chatMessageID,
+		&a.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	a.PostID = nullIntPtr(postID)
+	a.CommentID = nullIntPtr(commentID)
+	a.ChatMessageID = nullIntPtr(chatMessageID)
+	return &a, nil
+}
+
+func scanAttachments(rows *sql.Rows) ([]entity.Attachment, error) {
+	defer rows.Close()
+	var attachments []entity.Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, *a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return attachments, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresAttachments(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Устанавливаем тестовые данные
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+	_, err = repo.db.ExecContext(ctx, `
+		INSERT INTO users (id, username, email, password_hash, role)
+		VALUES (2, 'second', 'second@example.com', 'hashedpassword', 'user')
+	`)
+	require.NoError(t, err)
+
+	upload := func(userID int, key string) *entity.Attachment {
+		a := &entity.Attachment{UserID: userID, Filename: key, ContentType: "text/plain", Size: 4, Key: key}
+		require.NoError(t, repo.CreateAttachment(ctx, a))
+		return a
+	}
+	image := &entity.Attachment{UserID: 1, Filename: "cat.png", ContentType: "image/png", Size: 100,
+		Width: 640, Height: 480, Key: "aa/cat.png", ThumbnailKey: "aa/cat_thumb.png"}
+	require.NoError(t, repo.CreateAttachment(ctx, image))
+	text := upload(1, "bb/notes.txt")
+	foreign := upload(2, "cc/foreign.txt")
+
+	got, err := repo.GetAttachment(ctx, image.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 640, got.Width)
+	assert.Equal(t, "aa/cat_thumb.png", got.ThumbnailKey)
+	assert.Nil(t, got.PostID)
+
+	// Чужая загрузка откатывает создание поста целиком
+	post := &entity.Post{Title: "Pics", Content: "Content", UserID: 1, CategoryID: 1, AttachmentIDs: []int{image.ID, foreign.ID}}
+	err = repo.CreatePost(ctx, post)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	got, err = repo.GetAttachment(ctx, image.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.PostID)
+
+	post.AttachmentIDs = []int{image.ID, text.ID}
+	require.NoError(t, repo.CreatePost(ctx, post))
+	attachments, err := repo.ListAttachments(ctx, entity.AttachedToPost, []int{post.ID})
+	require.NoError(t, err)
+	require.Len(t, attachments, 2)
+	assert.Equal(t, image.ID, attachments[0].ID)
+	assert.Equal(t, post.ID, *attachments[1].PostID)
+
+	// Уже прикрепленную загрузку нельзя прикрепить повторно
+	comment := &entity.Comment{Content: "again", PostID: post.ID, UserID: 1, AttachmentIDs: []int{text.ID}}
+	assert.ErrorIs(t, repo.CreateComment(ctx, comment), sql.ErrNoRows)
+
+	message := &entity.ChatMessage{UserID: 2, Author: "second", Text: "file", AttachmentIDs: []int{foreign.ID}}
+	require.NoError(t, repo.SaveChatMessage(ctx, message))
+	attachments, err = repo.ListAttachments(ctx, entity.AttachedToChat, []int{message.ID})
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+
+	// Непривязанные загрузки собираются только после срока
+	stale := upload(1, "dd/stale.txt")
+	orphans, err := repo.ListOrphanedAttachments(ctx, time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, orphans)
+	orphans, err = repo.ListOrphanedAttachments(ctx, time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, orphans, 1)
+	assert.Equal(t, stale.ID, orphans[0].ID)
+
+	assert.ErrorIs(t, repo.DeleteOrphanedAttachment(ctx, image.ID), sql.ErrNoRows)
+	require.NoError(t, repo.DeleteOrphanedAttachment(ctx, stale.ID))
+	_, err = repo.GetAttachment(ctx, stale.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Пост в корзине держит вложения, окончательное удаление освобождает их для сборщика
+	require.NoError(t, repo.DeletePost(ctx, post.ID, post.UserID))
+	orphans, err = repo.ListOrphanedAttachments(ctx, time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, orphans)
+	_, err = repo.PurgeDeleted(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	orphans, err = repo.ListOrphanedAttachments(ctx, time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.Len(t, orphans, 2)
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+type CategoryRepository interface {
+	CreateCategory(ctx context.Context, category *entity.Category) error
+	GetCategoryByID(ctx context.Context, id int) (*entity.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error)
+	ListCategories(ctx context.Context) ([]*entity.Category, error)
+	UpdateCategory(ctx context.Context, category *entity.Category) error
+	DeleteCategory(ctx context.Context, id int) error
+	CategoryHasPosts(ctx context.Context, id int) (bool, error)
+}
+
+func (p *Postgres) CreateCategory(ctx context.Context, category *entity.Category) error {
+	query := `
+        INSERT INTO categories (slug, name, description, position, parent_id, read_only)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id
+    `
+	err := p.db.QueryRowContext(ctx, query,
+		category.Slug,
+		category.Name,
+		category.Description,
+		category.Position,
+		category.ParentID,
+		category.ReadOnly,
+	).Scan(&category.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+	return nil
+}
+
+const categoryColumns = `id, slug, name, description, position, parent_id, read_only`
+
+func (p *Postgres) GetCategoryByID(ctx context.Context, id int) (*entity.Category, error) {
+	row := p.db.QueryRowContext(ctx, `SELECT `+categoryColumns+` FROM categories WHERE id = $1`, id)
+	return scanCategory(row)
+}
+
+func (p *Postgres) GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	row := p.db.QueryRowContext(ctx, `SELECT `+categoryColumns+` FROM categories WHERE slug = $1`, slug)
+	return scanCategory(row)
+}
+
+func scanCategory(row *sql.Row) (*entity.Category, error) {
+	var category entity.Category
+	var parentID sql.NullInt64
+	err := row.Scan(
+		&category.ID,
+		&category.Slug,
+		&category.Name,
+		&category.Description,
+		&category.Position,
+		&parentID,
+		&category.ReadOnly,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		category.ParentID = &id
+	}
+	return &category, nil
+}
+
+// ListCategories returns every category with the number of its own posts and the time
+// of the latest post or comment in it, ordered by position within each parent.
+func (p *Postgres) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	query := `
+        SELECT cat.id, cat.slug, cat.name, cat.description, cat.position, cat.parent_id, cat.read_only,
+               COALESCE(stats.post_count, 0), stats.last_activity_at
+        FROM categories cat
+        LEFT JOIN (
+            SELECT p.category_id,
+                   COUNT(*) AS post_count,
+                   MAX(GREATEST(p.created_at, COALESCE(c.last_comment_at, p.created_at))) AS last_activity_at
+            FROM posts p
+            LEFT JOIN (
+                SELECT post_id, MAX(created_at) AS last_comment_at
+                FROM comments
+                WHERE deleted_at IS NULL
+                GROUP BY post_id
+            ) c ON c.post_id = p.id
+            WHERE p.hidden_at IS NULL AND p.deleted_at IS NULL AND p.status = 'published'
+            GROUP BY p.category_id
+        ) stats ON stats.category_id = cat.id
+        ORDER BY cat.position, cat.id
+    `
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*entity.Category
+	for rows.Next() {
+		var category entity.Category
+		var parentID sql.NullInt64
+		var lastActivity sql.NullTime
+		if err := rows.Scan(
+			&category.ID,
+			&category.Slug,
+			&category.Name,
+			&category.Description,
+			&category.Position,
+			&parentID,
+			&category.ReadOnly,
+			&category.PostCount,
+			&lastActivity,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		if parentID.Valid {
+			id := int(parentID.Int64)
+			category.ParentID = &id
+		}
+		if lastActivity.Valid {
+			category.LastActivityAt = &lastActivity.Time
+		}
+		categories = append(categories, &category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return categories, nil
+}
+
+func (p *Postgres) UpdateCategory(ctx context.Context, category *entity.Category) error {
+	query := `
+        UPDATE categories
+        SET slug = $1, name = $2, description = $3, position = $4, parent_id = $5, read_only = $6
+        WHERE id = $7
+    `
+	res, err := p.db.ExecContext(ctx, query,
+		category.Slug,
+		category.Name,
+		category.Description,
+		category.Position,
+		category.ParentID,
+		category.ReadOnly,
+		category.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+	return requireAffected(res, "category")
+}
+
+func (p *Postgres) DeleteCategory(ctx context.Context, id int) error {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	return requireAffected(res, "category")
+}
+
+// CategoryHasPosts reports whether any post refers to the category, drafts, hidden
+// posts and posts in the trash included: each of them blocks deleting it.
+func (p *Postgres) CategoryHasPosts(ctx context.Context, id int) (bool, error) {
+	var exists bool
+	err := p.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM posts WHERE category_id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check category posts: %w", err)
+	}
+	return exists, nil
+}
+
+// requireAffected reports sql.ErrNoRows when a statement did not touch any row.
+func requireAffected(res sql.Result, what string) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%s not found: %w", what, sql.ErrNoRows)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresCategories(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Устанавливаем тестовые данные: пост 1 попадает в раздел general
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+
+	parentID := 1
+	child := &entity.Category{Slug: "go", Name: "Go", Position: 1, ParentID: &parentID, ReadOnly: true}
+	require.NoError(t, repo.CreateCategory(ctx, child))
+	assert.NotZero(t, child.ID)
+
+	got, err := repo.GetCategoryBySlug(ctx, "go")
+	require.NoError(t, err)
+	assert.Equal(t, child, got)
+
+	categories, err := repo.ListCategories(ctx)
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	assert.Equal(t, "general", categories[0].Slug)
+	assert.Equal(t, 1, categories[0].PostCount)
+	assert.NotNil(t, categories[0].LastActivityAt)
+	assert.Equal(t, 0, categories[1].PostCount)
+	assert.Nil(t, categories[1].LastActivityAt)
+
+	post := &entity.Post{Title: "Go post", Content: "Content", UserID: 1, CategoryID: child.ID}
+	require.NoError(t, repo.CreatePost(ctx, post))
+
+	posts, err := repo.ListPosts(ctx, entity.PostQuery{
+		Sort:   entity.PostSortNewest,
+		Filter: entity.PostFilter{CategoryID: child.ID},
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, post.ID, posts[0].ID)
+
+	child.Name = "Golang"
+	require.NoError(t, repo.UpdateCategory(ctx, child))
+
+	// Пост в корзине не виден в разделе, но удалять раздел все равно нельзя
+	require.NoError(t, repo.DeletePost(ctx, post.ID, 1))
+	hasPosts, err := repo.CategoryHasPosts(ctx, child.ID)
+	require.NoError(t, err)
+	assert.True(t, hasPosts)
+
+	news := &entity.Category{Slug: "news", Name: "News", Position: 2}
+	require.NoError(t, repo.CreateCategory(ctx, news))
+	hasPosts, err = repo.CategoryHasPosts(ctx, news.ID)
+	require.NoError(t, err)
+	assert.False(t, hasPosts)
+	require.NoError(t, repo.DeleteCategory(ctx, news.ID))
+
+	assert.ErrorIs(t, repo.DeleteCategory(ctx, 999), sql.ErrNoRows)
+	_, err = repo.GetCategoryByID(ctx, 999)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
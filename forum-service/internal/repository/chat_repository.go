@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -36,6 +37,7 @@ func (p *Postgres) GetChatMessages(ctx context.Context, limit int) ([]entity.Cha
 	query := `
         SELECT id, user_id, author, text, created_at 
         FROM chat_messages 
+        WHERE hidden_at IS NULL
         ORDER BY created_at DESC 
         LIMIT $1
     `
@@ -57,24 +59,29 @@ func (p *Postgres) GetChatMessages(ctx context.Context, limit int) ([]entity.Cha
 
 	return messages, nil
 }
+
+// SaveChatMessage saves the message and links message.AttachmentIDs to it. A
+// missing or unavailable attachment fails with a wrapped sql.ErrNoRows.
 func (p *Postgres) SaveChatMessage(ctx context.Context, message *entity.ChatMessage) error {
-	query := `
+	return p.withTx(ctx, func(tx *sql.Tx) error {
+		query := `
         INSERT INTO chat_messages (user_id, author, text, created_at)
         VALUES ($1, $2, $3, NOW())
         RETURNING id, created_at
     `
 
-	err := p.db.QueryRowContext(
-		ctx,
-		query,
-		message.UserID,
-		message.Author,
-		message.Text,
-	).Scan(&message.ID, &message.CreatedAt)
+		err := tx.QueryRowContext(
+			ctx,
+			query,
+			message.UserID,
+			message.Author,
+			message.Text,
+		).Scan(&message.ID, &message.CreatedAt)
 
-	if err != nil {
-		return fmt.Errorf("database error: %w", err)
-	}
+		if err != nil {
+			return fmt.Errorf("database error: %w", err)
+		}
 
-	return nil
+		return linkAttachments(ctx, tx, entity.AttachedToChat, message.ID, message.UserID, message.AttachmentIDs)
+	})
 }
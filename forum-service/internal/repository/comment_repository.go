@@ -4,37 +4,141 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/markup"
 )
 
 type CommentRepository interface {
 	CreateComment(ctx context.Context, comment *entity.Comment) error
+	GetCommentByID(ctx context.Context, id int) (*entity.Comment, error)
 	GetCommentsByPostID(ctx context.Context, postID int) ([]entity.Comment, error)
+	ListCommentTree(ctx context.Context, q entity.CommentTreeQuery) ([]*entity.Comment, error)
+	UpdateComment(ctx context.Context, comment *entity.Comment, editorID int) error
+	GetCommentRevisions(ctx context.Context, commentID int) ([]entity.CommentRevision, error)
 	DeleteComment(ctx context.Context, commentID int, userID int) error
 }
 
+// CreateComment saves the comment and links comment.AttachmentIDs to it. A missing
+// or unavailable attachment fails with a wrapped sql.ErrNoRows.
 func (p *Postgres) CreateComment(ctx context.Context, comment *entity.Comment) error {
-	query := `INSERT INTO comments (content, post_id, user_id) 
-				VALUES ($1, $2, $3)
+	return p.withTx(ctx, func(tx *sql.Tx) error {
+		query := `INSERT INTO comments (content, format, content_html, post_id, user_id, parent_id, depth) 
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
 				RETURNING id, created_at`
-	return p.db.QueryRowContext(ctx, query,
-		comment.Content, comment.PostID, comment.UserID).
-		Scan(&comment.ID, &comment.CreatedAt)
+		err := tx.QueryRowContext(ctx, query,
+			comment.Content, comment.Format, comment.ContentHTML, comment.PostID, comment.UserID, comment.ParentID, comment.Depth).
+			Scan(&comment.ID, &comment.CreatedAt)
+		if err != nil {
+			return err
+		}
+		return linkAttachments(ctx, tx, entity.AttachedToComment, comment.ID, comment.UserID, comment.AttachmentIDs)
+	})
 }
 
-func (p *Postgres) GetCommentsByPostID(ctx context.Context, postID int) ([]entity.Comment, error) {
-	query := `
-			SELECT 
+// shownComment is the condition for a comment aliased as c to show up in its thread:
+// it is live, or deleted but kept as a placeholder for its replies.
+const shownComment = `(c.deleted_at IS NULL OR c.placeholder)`
+
+// shownPost is the condition for the comments of a post aliased as p to be shown: the
+// post is published and neither hidden by a moderator nor in the trash.
+const shownPost = `p.status = 'published' AND p.hidden_at IS NULL AND p.deleted_at IS NULL`
+
+// commentColumns selects a comment aliased as c with its author (users aliased as u)
+// and the number of direct replies shown in the thread.
+const commentColumns = `
 				c.id, 
 				c.content, 
+				c.format,
+				c.content_html,
 				c.post_id, 
+				c.parent_id,
+				c.depth,
 				c.user_id, 
-				u.username AS author,
-				c.created_at
+				COALESCE(u.username, '') AS author,
+				c.created_at,
+				c.edited_at,
+				c.score,
+				c.reactions,
+				c.deleted_at IS NOT NULL AS deleted,
+				c.hidden_at IS NOT NULL AS hidden,
+				(SELECT COUNT(*) FROM comments r WHERE r.parent_id = c.id AND (r.deleted_at IS NULL OR r.placeholder)) AS reply_count`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanComment reads commentColumns. Deleted comments come back as placeholders
+// without content and author, hidden ones without content.
+func scanComment(row scanner) (*entity.Comment, error) {
+	var comment entity.Comment
+	var parentID sql.NullInt64
+	var editedAt sql.NullTime
+	if err := row.Scan(
+		&comment.ID,
+		&comment.Content,
+		&comment.Format,
+		&comment.ContentHTML,
+		&comment.PostID,
+		&parentID,
+		&comment.Depth,
+		&comment.UserID,
+		&comment.Author,
+		&comment.CreatedAt,
+		&editedAt,
+		&comment.Score,
+		(*reactionCounts)(&comment.Reactions),
+		&comment.Deleted,
+		&comment.Hidden,
+		&comment.ReplyCount,
+	); err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		comment.ParentID = &id
+	}
+	if editedAt.Valid {
+		comment.EditedAt = &editedAt.Time
+	}
+	if comment.Deleted {
+		comment.Content = entity.DeletedCommentContent
+		comment.Format = entity.FormatPlain
+		comment.ContentHTML = markup.RenderPlain(entity.DeletedCommentContent)
+		comment.Author = ""
+		comment.UserID = 0
+		comment.EditedAt = nil
+	} else if comment.Hidden {
+		comment.Content = entity.HiddenCommentContent
+		comment.Format = entity.FormatPlain
+		comment.ContentHTML = markup.RenderPlain(entity.HiddenCommentContent)
+	}
+	return &comment, nil
+}
+
+// GetCommentByID returns a comment, deleted ones with replies as placeholders.
+// Other deleted comments and comments of a post that is not shown are not found.
+func (p *Postgres) GetCommentByID(ctx context.Context, id int) (*entity.Comment, error) {
+	query := `SELECT ` + commentColumns + `
 			FROM comments c
-			JOIN users u ON c.user_id = u.id
-			WHERE c.post_id = $1
+			JOIN posts p ON p.id = c.post_id AND ` + shownPost + `
+			LEFT JOIN users u ON c.user_id = u.id
+			WHERE c.id = $1 AND ` + shownComment
+	comment, err := scanComment(p.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment by ID: %w", err)
+	}
+	return comment, nil
+}
+
+func (p *Postgres) GetCommentsByPostID(ctx context.Context, postID int) ([]entity.Comment, error) {
+	query := `
+			SELECT ` + commentColumns + `
+			FROM comments c
+			JOIN posts p ON p.id = c.post_id AND ` + shownPost + `
+			LEFT JOIN users u ON c.user_id = u.id
+			WHERE c.post_id = $1 AND ` + shownComment + `
 			ORDER BY c.created_at
 		`
 	rows, err := p.db.QueryContext(ctx, query, postID)
@@ -45,15 +149,78 @@ func (p *Postgres) GetCommentsByPostID(ctx context.Context, postID int) ([]entit
 
 	var comments []entity.Comment
 	for rows.Next() {
-		var comment entity.Comment
-		if err := rows.Scan(
-			&comment.ID,
-			&comment.Content,
-			&comment.PostID,
-			&comment.UserID,
-			&comment.Author,
-			&comment.CreatedAt,
-		); err != nil {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, *comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return comments, nil
+}
+
+// ListCommentTree returns up to q.Limit comments under q.Cursor.ParentID (top level when 0)
+// after the cursor position, and their replies q.Depth levels down, at most q.Replies per
+// comment. Rows are flat and ordered by (created_at, id); the caller assembles the tree.
+// A deleted post has no comments.
+func (p *Postgres) ListCommentTree(ctx context.Context, q entity.CommentTreeQuery) ([]*entity.Comment, error) {
+	args := []interface{}{q.PostID}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	level := "c.parent_id IS NULL"
+	if q.Cursor.ParentID != 0 {
+		level = "c.parent_id = " + arg(q.Cursor.ParentID)
+	}
+	after := ""
+	if q.Cursor.ID != 0 {
+		after = fmt.Sprintf("AND (c.created_at, c.id) > (%s, %s)", arg(q.Cursor.Time), arg(q.Cursor.ID))
+	}
+
+	// The lateral subquery caps the replies loaded per comment, so a single huge
+	// thread cannot blow up the page.
+	query := fmt.Sprintf(`
+        WITH RECURSIVE thread AS (
+            SELECT top.id, 0 AS level
+            FROM (
+                SELECT c.id FROM comments c
+                JOIN posts p ON p.id = c.post_id AND `+shownPost+`
+                WHERE c.post_id = $1 AND %s AND %s %s
+                ORDER BY c.created_at, c.id
+                LIMIT %s
+            ) top
+            UNION ALL
+            SELECT r.id, t.level + 1
+            FROM thread t
+            CROSS JOIN LATERAL (
+                SELECT c.id FROM comments c
+                WHERE c.parent_id = t.id AND %s
+                ORDER BY c.created_at, c.id
+                LIMIT %s
+            ) r
+            WHERE t.level < %s
+        )
+        SELECT %s
+        FROM thread t
+        JOIN comments c ON c.id = t.id
+        LEFT JOIN users u ON c.user_id = u.id
+        ORDER BY c.created_at, c.id
+    `, shownComment, level, after, arg(q.Limit), shownComment, arg(q.Replies), arg(q.Depth), commentColumns)
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comment tree for post %d: %w", q.PostID, err)
+	}
+	defer rows.Close()
+
+	var comments []*entity.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan comment: %w", err)
 		}
 		comments = append(comments, comment)
@@ -64,6 +231,74 @@ func (p *Postgres) GetCommentsByPostID(ctx context.Context, postID int) ([]entit
 	return comments, nil
 }
 
+// UpdateComment replaces the content of a live, visible comment with comment.Content, its
+// rendering with comment.ContentHTML and sets comment.EditedAt. The previous content is kept in comment_revisions.
+func (p *Postgres) UpdateComment(ctx context.Context, comment *entity.Comment, editorID int) error {
+	return p.withTx(ctx, func(tx *sql.Tx) error {
+		var previous string
+		err := tx.QueryRowContext(ctx, `
+            SELECT content FROM comments
+            WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL
+            FOR UPDATE
+        `, comment.ID).Scan(&previous)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO comment_revisions (comment_id, content, edited_by, edited_at)
+            VALUES ($1, $2, $3, NOW())
+        `, comment.ID, previous, editorID)
+		if err != nil {
+			return fmt.Errorf("failed to save comment revision: %w", err)
+		}
+
+		var editedAt time.Time
+		err = tx.QueryRowContext(ctx, `
+            UPDATE comments SET content = $2, content_html = $3, edited_at = NOW()
+            WHERE id = $1
+            RETURNING edited_at
+        `, comment.ID, comment.Content, comment.ContentHTML).Scan(&editedAt)
+		if err != nil {
+			return fmt.Errorf("failed to update comment: %w", err)
+		}
+		comment.EditedAt = &editedAt
+		return nil
+	})
+}
+
+// GetCommentRevisions returns the previous versions of a comment, oldest first.
+func (p *Postgres) GetCommentRevisions(ctx context.Context, commentID int) ([]entity.CommentRevision, error) {
+	rows, err := p.db.QueryContext(ctx, `
+        SELECT r.id, r.comment_id, r.content, r.edited_by, COALESCE(u.username, ''), r.edited_at
+        FROM comment_revisions r
+        LEFT JOIN users u ON r.edited_by = u.id
+        WHERE r.comment_id = $1
+        ORDER BY r.edited_at, r.id
+    `, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revisions for comment %d: %w", commentID, err)
+	}
+	defer rows.Close()
+
+	revisions := []entity.CommentRevision{}
+	for rows.Next() {
+		var rev entity.CommentRevision
+		var editedBy sql.NullInt64
+		if err := rows.Scan(&rev.ID, &rev.CommentID, &rev.Content, &editedBy, &rev.Editor, &rev.EditedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan comment revision: %w", err)
+		}
+		rev.EditedBy = nullIntPtr(editedBy)
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return revisions, nil
+}
+
+// DeleteComment moves a comment to the trash. A comment with replies stays in its
+// thread as a placeholder; placeholders left without replies are taken out of it.
 func (p *Postgres) DeleteComment(ctx context.Context, commentID int, userID int) error {
 	// First check if user is admin
 	var userRole string
@@ -72,31 +307,97 @@ func (p *Postgres) DeleteComment(ctx context.Context, commentID int, userID int)
 		return fmt.Errorf("failed to check user role: %w", err)
 	}
 
-	var query string
-	var args []interface{}
-	if userRole == "admin" {
-		// Admin can delete any comment
-		query = `DELETE FROM comments WHERE id = $1`
-		args = []interface{}{commentID}
-	} else {
-		// Regular users can only delete their own comments
-		query = `DELETE FROM comments WHERE id = $1 AND user_id = $2`
-		args = []interface{}{commentID, userID}
+	return p.withTx(ctx, func(tx *sql.Tx) error {
+		return deleteComment(ctx, tx, commentID, &userID, func(ownerID int) bool {
+			// Admin can delete any comment, regular users can only delete their own comments
+			return userRole == entity.RoleAdmin || ownerID == userID
+		})
+	})
+}
+
+// deleteComment moves the live comment commentID to the trash within tx as
+// DeleteComment does, recording deletedBy. When allowed is set and rejects the
+// comment's owner, sql.ErrNoRows is returned as for a missing comment.
+func deleteComment(ctx context.Context, tx *sql.Tx, commentID int, deletedBy *int, allowed func(ownerID int) bool) error {
+	// Locking the row also blocks concurrent replies, which need a key share lock on it.
+	var ownerID int
+	var parentID sql.NullInt64
+	var hasReplies bool
+	err := tx.QueryRowContext(ctx, `
+        SELECT user_id, parent_id,
+               EXISTS (SELECT 1 FROM comments r WHERE r.parent_id = c.id AND (r.deleted_at IS NULL OR r.placeholder))
+        FROM comments c
+        WHERE id = $1 AND deleted_at IS NULL
+        FOR UPDATE
+    `, commentID).Scan(&ownerID, &parentID, &hasReplies)
+	if err != nil {
+		return err
+	}
+	if allowed != nil && !allowed(ownerID) {
+		return sql.ErrNoRows
 	}
 
-	result, err := p.db.ExecContext(ctx, query, args...)
+	// Content and attachments are kept for a restore until the comment is purged
+	_, err = tx.ExecContext(ctx, `
+        UPDATE comments SET deleted_at = NOW(), deleted_by = $2, placeholder = $3 WHERE id = $1
+    `, commentID, deletedBy, hasReplies)
 	if err != nil {
 		return fmt.Errorf("failed to delete comment: %w", err)
 	}
+	if hasReplies {
+		return nil
+	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to check rows affected: %w", err)
+	// Walk up and take out placeholders that no longer have replies to show
+	for parentID.Valid {
+		var next sql.NullInt64
+		err := tx.QueryRowContext(ctx, `
+            UPDATE comments c SET placeholder = FALSE
+            WHERE id = $1 AND placeholder
+              AND NOT EXISTS (SELECT 1 FROM comments r WHERE r.parent_id = c.id AND (r.deleted_at IS NULL OR r.placeholder))
+            RETURNING parent_id
+        `, parentID.Int64).Scan(&next)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to clean up deleted comments: %w", err)
+		}
+		parentID = next
 	}
+	return nil
+}
 
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
+// restoreComment brings the comment commentID of a live post back from the trash
+// within tx and returns its author. Deleted ancestors become placeholders again so
+// that the comment shows up in its thread. A comment not in the trash is a wrapped
+// sql.ErrNoRows.
+func restoreComment(ctx context.Context, tx *sql.Tx, commentID int) (sql.NullInt64, error) {
+	var userID, parentID sql.NullInt64
+	err := tx.QueryRowContext(ctx, `
+        UPDATE comments c SET deleted_at = NULL, deleted_by = NULL, placeholder = FALSE
+        FROM posts p
+        WHERE c.id = $1 AND c.deleted_at IS NOT NULL AND p.id = c.post_id AND p.deleted_at IS NULL
+        RETURNING c.user_id, c.parent_id
+    `, commentID).Scan(&userID, &parentID)
+	if err != nil {
+		return userID, fmt.Errorf("failed to restore comment %d: %w", commentID, err)
 	}
 
-	return nil
+	for parentID.Valid {
+		var next sql.NullInt64
+		err := tx.QueryRowContext(ctx, `
+            UPDATE comments SET placeholder = TRUE
+            WHERE id = $1 AND deleted_at IS NOT NULL AND NOT placeholder
+            RETURNING parent_id
+        `, parentID.Int64).Scan(&next)
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			return userID, fmt.Errorf("failed to restore comment placeholders: %w", err)
+		}
+		parentID = next
+	}
+	return userID, nil
 }
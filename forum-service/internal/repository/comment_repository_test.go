@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockCommentRepository is a mock implementation of CommentRepository
@@ -53,15 +55,20 @@ func setupTestData(ctx context.Context, repo *Postgres) error {
 
 	// Создаем тестовый пост
 	_, err = repo.db.ExecContext(ctx, `
-		INSERT INTO posts (id, title, content, user_id)
-		VALUES (1, 'Test Post', 'Test Content', 1)
+		INSERT INTO posts (id, title, content, user_id, category_id)
+		VALUES (1, 'Test Post', 'Test Content', 1, 1)
 		RETURNING id
 	`)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// Следующие пользователи и посты получают идентификаторы после тестовых
+	_, err = repo.db.ExecContext(ctx, `
+		SELECT setval('users_id_seq', (SELECT MAX(id) FROM users));
+		SELECT setval('posts_id_seq', (SELECT MAX(id) FROM posts));
+	`)
+	return err
 }
 
 func TestPostgresCreateComment(t *testing.T) {
@@ -115,6 +122,24 @@ func TestPostgresGetCommentsByPostID(t *testing.T) {
 	comments, err := repo.GetCommentsByPostID(ctx, 1)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, comments)
+
+	// Комментарии скрытого поста и черновика не показываются
+	for _, update := range []string{
+		`UPDATE posts SET hidden_at = NOW() WHERE id = 1`,
+		`UPDATE posts SET hidden_at = NULL, status = 'draft' WHERE id = 1`,
+	} {
+		_, err = repo.db.ExecContext(ctx, update)
+		require.NoError(t, err)
+
+		comments, err = repo.GetCommentsByPostID(ctx, 1)
+		require.NoError(t, err)
+		assert.Empty(t, comments)
+		tree, err := repo.ListCommentTree(ctx, entity.CommentTreeQuery{PostID: 1, Limit: 10, Depth: 2, Replies: 5})
+		require.NoError(t, err)
+		assert.Empty(t, tree)
+		_, err = repo.GetCommentByID(ctx, 1)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	}
 }
 
 func TestPostgresDeleteComment(t *testing.T) {
@@ -144,3 +169,86 @@ func TestPostgresDeleteComment(t *testing.T) {
 	err = repo.DeleteComment(ctx, 1, 1)
 	assert.NoError(t, err)
 }
+
+func TestPostgresCommentTree(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Устанавливаем тестовые данные
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+
+	root := &entity.Comment{Content: "root", PostID: 1, UserID: 1}
+	require.NoError(t, repo.CreateComment(ctx, root))
+	reply := &entity.Comment{Content: "reply", PostID: 1, UserID: 1, ParentID: &root.ID, Depth: 1}
+	require.NoError(t, repo.CreateComment(ctx, reply))
+	nested := &entity.Comment{Content: "nested", PostID: 1, UserID: 1, ParentID: &reply.ID, Depth: 2}
+	require.NoError(t, repo.CreateComment(ctx, nested))
+
+	// Глубина 2 отдает корень и первый уровень ответов
+	comments, err := repo.ListCommentTree(ctx, entity.CommentTreeQuery{PostID: 1, Limit: 10, Depth: 2, Replies: 5})
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, root.ID, comments[0].ID)
+	assert.Equal(t, 1, comments[0].ReplyCount)
+	assert.Equal(t, reply.ID, comments[1].ID)
+	assert.Equal(t, 1, comments[1].ReplyCount)
+
+	// Комментарий с ответами становится заглушкой, ветка сохраняется
+	require.NoError(t, repo.DeleteComment(ctx, reply.ID, 1))
+	deleted, err := repo.GetCommentByID(ctx, reply.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted.Deleted)
+	assert.Equal(t, entity.DeletedCommentContent, deleted.Content)
+
+	// Удаление последнего ответа убирает и опустевшую заглушку
+	require.NoError(t, repo.DeleteComment(ctx, nested.ID, 1))
+	_, err = repo.GetCommentByID(ctx, reply.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repo.GetCommentByID(ctx, root.ID)
+	assert.NoError(t, err)
+}
+
+func TestPostgresUpdateComment(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Устанавливаем тестовые данные
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+
+	comment := &entity.Comment{Content: "first", PostID: 1, UserID: 1}
+	require.NoError(t, repo.CreateComment(ctx, comment))
+
+	comment.Content = "second"
+	require.NoError(t, repo.UpdateComment(ctx, comment, 1))
+	require.NotNil(t, comment.EditedAt)
+
+	stored, err := repo.GetCommentByID(ctx, comment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "second", stored.Content)
+	assert.NotNil(t, stored.EditedAt)
+
+	revisions, err := repo.GetCommentRevisions(ctx, comment.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, "first", revisions[0].Content)
+	assert.Equal(t, "testuser", revisions[0].Editor)
+
+	// Удаленный комментарий не редактируется
+	_, err = repo.db.ExecContext(ctx, `UPDATE comments SET deleted_at = NOW() WHERE id = $1`, comment.ID)
+	require.NoError(t, err)
+	assert.ErrorIs(t, repo.UpdateComment(ctx, comment, 1), sql.ErrNoRows)
+}
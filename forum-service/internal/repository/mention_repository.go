@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lib/pq"
+)
+
+type MentionRepository interface {
+	SyncMentions(ctx context.Context, source entity.MentionSource, authorID int, usernames []string) ([]*entity.Notification, error)
+	SuggestUsers(ctx context.Context, prefix string, limit int) ([]entity.UserSummary, error)
+	BlockUser(ctx context.Context, userID, blockedUserID int) error
+	UnblockUser(ctx context.Context, userID, blockedUserID int) error
+	ListBlockedUsers(ctx context.Context, userID int) ([]entity.UserSummary, error)
+}
+
+func mentionColumn(kind entity.MentionSourceKind) (string, error) {
+	switch kind {
+	case entity.MentionInPost:
+		return "post_id", nil
+	case entity.MentionInComment:
+		return "comment_id", nil
+	case entity.MentionInChat:
+		return "chat_message_id", nil
+	}
+	return "", fmt.Errorf("unknown mention source %q", kind)
+}
+
+// SyncMentions makes the stored mentions of source match usernames. Unknown names,
+// the author and users who blocked the author are skipped. Mentions that are gone
+// lose their unread notification; new ones get a notification, which is returned.
+func (p *Postgres) SyncMentions(ctx context.Context, source entity.MentionSource, authorID int, usernames []string) ([]*entity.Notification, error) {
+	column, err := mentionColumn(source.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var notifications []*entity.Notification
+	err = p.withTx(ctx, func(tx *sql.Tx) error {
+		var userIDs []int64
+		err := tx.QueryRowContext(ctx, `
+            SELECT COALESCE(array_agg(u.id), '{}')
+            FROM users u
+            WHERE u.username = ANY($1) AND u.id <> $2
+              AND NOT EXISTS (
+                  SELECT 1 FROM user_blocks b WHERE b.user_id = u.id AND b.blocked_user_id = $2
+              )
+        `, pq.Array(usernames), authorID).Scan(pq.Array(&userIDs))
+		if err != nil {
+			return fmt.Errorf("failed to resolve mentions: %w", err)
+		}
+
+		var removed []int64
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+            DELETE FROM mentions WHERE %s = $1 AND user_id <> ALL($2) RETURNING user_id
+        `, column), source.ID, pq.Array(userIDs))
+		if err != nil {
+			return fmt.Errorf("failed to delete mentions: %w", err)
+		}
+		if removed, err = scanInt64s(rows); err != nil {
+			return err
+		}
+		if len(removed) > 0 && source.Kind != entity.MentionInChat {
+			query := `DELETE FROM notifications
+                      WHERE type = $1 AND post_id = $2 AND comment_id IS NULL AND user_id = ANY($3) AND read_at IS NULL`
+			args := []interface{}{entity.NotificationMention, source.ID, pq.Array(removed)}
+			if source.Kind == entity.MentionInComment {
+				query = `DELETE FROM notifications
+                         WHERE type = $1 AND comment_id = $2 AND user_id = ANY($3) AND read_at IS NULL`
+			}
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return fmt.Errorf("failed to delete mention notifications: %w", err)
+			}
+		}
+
+		rows, err = tx.QueryContext(ctx, fmt.Sprintf(`
+            INSERT INTO mentions (user_id, author_id, %s)
+            SELECT unnest($1::int[]), $2, $3
+            ON CONFLICT DO NOTHING
+            RETURNING user_id
+        `, column), pq.Array(userIDs), authorID, source.ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert mentions: %w", err)
+		}
+		added, err := scanInt64s(rows)
+		if err != nil || len(added) == 0 {
+			return err
+		}
+
+		var postID, commentID sql.NullInt64
+		switch source.Kind {
+		case entity.MentionInPost:
+			postID = sql.NullInt64{Int64: int64(source.ID), Valid: true}
+		case entity.MentionInComment:
+			postID = sql.NullInt64{Int64: int64(source.PostID), Valid: true}
+			commentID = sql.NullInt64{Int64: int64(source.ID), Valid: true}
+		}
+		rows, err = tx.QueryContext(ctx, `
+            WITH n AS (
+                INSERT INTO notifications (user_id, type, actor_id, post_id, comment_id)
+                SELECT unnest($1::int[]), $2, $3, $4, $5
+                RETURNING *
+            )
+            SELECT `+notificationColumns+`
+            FROM n`+notificationJoins+`
+            ORDER BY n.id
+        `, pq.Array(added), entity.NotificationMention, authorID, postID, commentID)
+		if err != nil {
+			return fmt.Errorf("failed to create mention notifications: %w", err)
+		}
+		notifications, err = scanNotifications(rows)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func scanInt64s(rows *sql.Rows) ([]int64, error) {
+	defer rows.Close()
+	var values []int64
+	for rows.Next() {
+		var v int64
+		if err := rows.Scan(&v); err != nil {
+			return nil, fmt.Errorf("failed to scan id: %w", err)
+		}
+		values = append(values, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return values, nil
+}
+
+// SuggestUsers returns users whose name starts with prefix, ignoring case; shorter
+// names come first so that an exact match leads.
+func (p *Postgres) SuggestUsers(ctx context.Context, prefix string, limit int) ([]entity.UserSummary, error) {
+	rows, err := p.db.QueryContext(ctx, `
+        SELECT id, username
+        FROM users
+        WHERE username ILIKE $1 || '%'
+        ORDER BY length(username), username
+        LIMIT $2
+    `, likeEscaper.Replace(prefix), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	return scanUserSummaries(rows)
+}
+
+func (p *Postgres) BlockUser(ctx context.Context, userID, blockedUserID int) error {
+	_, err := p.db.ExecContext(ctx,
+		`INSERT INTO user_blocks (user_id, blocked_user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		userID, blockedUserID)
+	if err != nil {
+		return fmt.Errorf("failed to block user: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) UnblockUser(ctx context.Context, userID, blockedUserID int) error {
+	_, err := p.db.ExecContext(ctx,
+		`DELETE FROM user_blocks WHERE user_id = $1 AND blocked_user_id = $2`, userID, blockedUserID)
+	if err != nil {
+		return fmt.Errorf("failed to unblock user: %w", err)
+	}
+	return nil
+}
+
+// ListBlockedUsers returns the users blocked by userID, most recently blocked first.
+func (p *Postgres) ListBlockedUsers(ctx context.Context, userID int) ([]entity.UserSummary, error) {
+	rows, err := p.db.QueryContext(ctx, `
+        SELECT u.id, u.username
+        FROM user_blocks b
+        JOIN users u ON u.id = b.blocked_user_id
+        WHERE b.user_id = $1
+        ORDER BY b.created_at DESC, u.id
+    `, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocked users: %w", err)
+	}
+	return scanUserSummaries(rows)
+}
+
+func scanUserSummaries(rows *sql.Rows) ([]entity.UserSummary, error) {
+	defer rows.Close()
+	users := []entity.UserSummary{}
+	for rows.Next() {
+		var u entity.UserSummary
+		if err := rows.Scan(&u.ID, &u.Username); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return users, nil
+}
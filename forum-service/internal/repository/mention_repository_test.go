@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresMentions(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Устанавливаем тестовые данные
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+	_, err = repo.db.ExecContext(ctx, `
+		INSERT INTO users (id, username, email, password_hash, role)
+		VALUES (2, 'alice', 'alice@example.com', 'hashedpassword', 'user'),
+		       (3, 'Alfred', 'alfred@example.com', 'hashedpassword', 'user'),
+		       (4, 'bob', 'bob@example.com', 'hashedpassword', 'user')
+	`)
+	require.NoError(t, err)
+
+	// Подсказки без учета регистра, короткие имена первыми
+	users, err := repo.SuggestUsers(ctx, "al", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.UserSummary{{ID: 2, Username: "alice"}, {ID: 3, Username: "Alfred"}}, users)
+
+	// Боб заблокировал автора поста: его упоминание игнорируется, как и неизвестное имя
+	require.NoError(t, repo.BlockUser(ctx, 4, 1))
+	require.NoError(t, repo.BlockUser(ctx, 4, 1))
+	blocked, err := repo.ListBlockedUsers(ctx, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.UserSummary{{ID: 1, Username: "testuser"}}, blocked)
+
+	post := entity.MentionSource{Kind: entity.MentionInPost, ID: 1}
+	created, err := repo.SyncMentions(ctx, post, 1, []string{"alice", "bob", "nobody", "testuser"})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, 2, created[0].UserID)
+	assert.Equal(t, entity.NotificationMention, created[0].Type)
+	assert.Equal(t, "testuser", created[0].Actor)
+	assert.Equal(t, 1, *created[0].PostID)
+
+	// Повторное сохранение не уведомляет снова
+	created, err = repo.SyncMentions(ctx, post, 1, []string{"alice"})
+	require.NoError(t, err)
+	assert.Empty(t, created)
+
+	// Правка заменяет alice на Alfred: непрочитанное уведомление alice удаляется
+	created, err = repo.SyncMentions(ctx, post, 1, []string{"Alfred"})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, 3, created[0].UserID)
+	count, err := repo.CountUnreadNotifications(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// После разблокировки упоминание Боба в комментарии учитывается
+	require.NoError(t, repo.UnblockUser(ctx, 4, 1))
+	comment := &entity.Comment{Content: "@bob", PostID: 1, UserID: 1}
+	require.NoError(t, repo.CreateComment(ctx, comment))
+	created, err = repo.SyncMentions(ctx,
+		entity.MentionSource{Kind: entity.MentionInComment, ID: comment.ID, PostID: 1}, 1, []string{"bob"})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, comment.ID, *created[0].CommentID)
+
+	var mentions int
+	require.NoError(t, repo.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM mentions`).Scan(&mentions))
+	assert.Equal(t, 2, mentions)
+}
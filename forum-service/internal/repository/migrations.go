@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/lera-guryan2222/fooorum/forum-service/migrations"
+)
+
+// migrationsTable is where golang-migrate keeps the forum-service schema version.
+// It differs from the auth-service table because both services share one database.
+const migrationsTable = "forum_schema_migrations"
+
+// MigrationManager manages the forum-service schema migrations
+type MigrationManager interface {
+	RunMigrations() error
+	RollbackMigrations(steps int) error
+	MigrationStatus() (*MigrationStatus, error)
+	ForceMigrationVersion(version int) error
+}
+
+// MigrationStatus describes the current state of the database schema
+type MigrationStatus struct {
+	Version   uint
+	Dirty     bool
+	Applied   bool
+	Available []uint
+}
+
+// newMigrator creates a migrate instance on top of a dedicated pool connection
+// so that m.Close() does not close the shared *sql.DB
+func (p *Postgres) newMigrator(ctx context.Context) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iofs: %w", err)
+	}
+
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire db connection: %w", err)
+	}
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{
+		MigrationsTable: migrationsTable,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create postgres driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		driver.Close()
+		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
+	}
+
+	return m, nil
+}
+
+// RunMigrations applies all pending migrations
+func (p *Postgres) RunMigrations() error {
+	m, err := p.newMigrator(context.Background())
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	version, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("failed to get migration version: %w", err)
+	}
+
+	log.Printf("Migrations applied. Current version: %d, dirty: %v", version, dirty)
+	return nil
+}
+
+// RollbackMigrations rolls back the given number of most recent migrations
+func (p *Postgres) RollbackMigrations(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+
+	m, err := p.newMigrator(context.Background())
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to roll back migrations: %w", err)
+	}
+	return nil
+}
+
+// MigrationStatus returns the current schema version and the embedded migrations
+func (p *Postgres) MigrationStatus() (*MigrationStatus, error) {
+	available, err := availableMigrations(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := p.newMigrator(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer m.Close()
+
+	status := &MigrationStatus{Available: available}
+	version, dirty, err := m.Version()
+	switch {
+	case errors.Is(err, migrate.ErrNilVersion):
+		return status, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get migration version: %w", err)
+	}
+
+	status.Version = version
+	status.Dirty = dirty
+	status.Applied = true
+	return status, nil
+}
+
+// ForceMigrationVersion sets the schema version without running migrations
+// and clears the dirty flag. Used for manual recovery after a failed migration.
+func (p *Postgres) ForceMigrationVersion(version int) error {
+	m, err := p.newMigrator(context.Background())
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Force(version); err != nil {
+		return fmt.Errorf("failed to force migration version %d: %w", version, err)
+	}
+	return nil
+}
+
+// availableMigrations returns the sorted versions of the embedded up migrations
+func availableMigrations(fsys fs.FS) ([]uint, error) {
+	files, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	versions := make([]uint, 0, len(files))
+	for _, name := range files {
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, uint(v))
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailableMigrations(t *testing.T) {
+	versions, err := availableMigrations(migrations.FS)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22}, versions)
+}
+
+func TestPostgresMigrationsUpDown(t *testing.T) {
+	repo, err := setupTestDB()
+	require.NoError(t, err, "Failed to setup test database")
+
+	available, err := availableMigrations(migrations.FS)
+	require.NoError(t, err)
+	latest := available[len(available)-1]
+
+	status, err := repo.MigrationStatus()
+	require.NoError(t, err)
+	assert.True(t, status.Applied)
+	assert.False(t, status.Dirty)
+	assert.Equal(t, latest, status.Version)
+
+	// Откат всех миграций оставляет только таблицу users auth-service
+	require.NoError(t, repo.RollbackMigrations(len(available)))
+	status, err = repo.MigrationStatus()
+	require.NoError(t, err)
+	assert.False(t, status.Applied)
+
+	var tables []string
+	rows, err := repo.db.Query(`
+		SELECT table_name FROM information_schema.tables
+		WHERE table_schema = 'public' AND table_name <> $1
+		ORDER BY table_name
+	`, migrationsTable)
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		tables = append(tables, name)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"users"}, tables)
+
+	// Повторное применение восстанавливает схему
+	require.NoError(t, repo.RunMigrations())
+	status, err = repo.MigrationStatus()
+	require.NoError(t, err)
+	assert.Equal(t, latest, status.Version)
+	assert.False(t, status.Dirty)
+}
@@ -0,0 +1,507 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lib/pq"
+)
+
+type ModerationRepository interface {
+	GetReportTargetAuthor(ctx context.Context, target entity.ReportTarget, id int) (*int, error)
+	CreateReport(ctx context.Context, report *entity.Report) (bool, error)
+	GetReportGroup(ctx context.Context, target entity.ReportTarget, id int) (*entity.ReportGroup, error)
+	ListReportGroups(ctx context.Context, q entity.ReportQueueQuery) ([]*entity.ReportGroup, error)
+	ResolveReports(ctx context.Context, entry *entity.ModerationLogEntry) ([]*entity.Notification, error)
+	ListModerationLog(ctx context.Context, q entity.ModerationLogQuery) ([]*entity.ModerationLogEntry, error)
+	GetActiveBan(ctx context.Context, userID int) (*entity.UserBan, error)
+	SetPostStatus(ctx context.Context, update entity.PostStatusUpdate, entry *entity.ModerationLogEntry) (*entity.PostStatus, error)
+}
+
+// reportTable returns the table of a report target and the condition its rows must
+// meet to count as existing: posts and comments in the trash and unpublished posts do not.
+func reportTable(target entity.ReportTarget) (table, live string, err error) {
+	switch target {
+	case entity.ReportOnPost:
+		return "posts", "deleted_at IS NULL AND status = 'published'", nil
+	case entity.ReportOnComment:
+		return "comments", "deleted_at IS NULL", nil
+	case entity.ReportOnChatMessage:
+		return "chat_messages", "TRUE", nil
+	}
+	return "", "", fmt.Errorf("unknown report target %q", target)
+}
+
+// GetReportTargetAuthor returns the author of the reported content, nil when the
+// author is unknown. Missing content is a wrapped sql.ErrNoRows.
+func (p *Postgres) GetReportTargetAuthor(ctx context.Context, target entity.ReportTarget, id int) (*int, error) {
+	table, live, err := reportTable(target)
+	if err != nil {
+		return nil, err
+	}
+	var authorID sql.NullInt64
+	err = p.db.QueryRowContext(ctx, fmt.Sprintf(`SELECT user_id FROM %s WHERE id = $1 AND %s`, table, live), id).
+		Scan(&authorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reported %s: %w", target, err)
+	}
+	return nullIntPtr(authorID), nil
+}
+
+// CreateReport files report, or updates the reason and note of the reporter's open
+// report on the same target. It reports whether a new report was created.
+func (p *Postgres) CreateReport(ctx context.Context, report *entity.Report) (bool, error) {
+	var targetUserID sql.NullInt64
+	if report.TargetUserID != nil {
+		targetUserID = sql.NullInt64{Int64: int64(*report.TargetUserID), Valid: true}
+	}
+	var created bool
+	err := p.db.QueryRowContext(ctx, `
+        INSERT INTO reports (reporter_id, target_type, target_id, target_user_id, reason, note)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (reporter_id, target_type, target_id) WHERE status = 'open'
+        DO UPDATE SET reason = EXCLUDED.reason, note = EXCLUDED.note, updated_at = NOW()
+        RETURNING id, status, created_at, updated_at, xmax = 0
+    `, report.ReporterID, report.TargetType, report.TargetID, targetUserID, report.Reason, report.Note).
+		Scan(&report.ID, &report.Status, &report.CreatedAt, &report.UpdatedAt, &created)
+	if err != nil {
+		return false, fmt.Errorf("failed to create report: %w", err)
+	}
+	return created, nil
+}
+
+// GetReportGroup returns the open reports on one target. Without open reports a
+// wrapped sql.ErrNoRows is returned.
+func (p *Postgres) GetReportGroup(ctx context.Context, target entity.ReportTarget, id int) (*entity.ReportGroup, error) {
+	groups, err := p.queryReportGroups(ctx, []string{"target_type = $1", "target_id = $2"}, []interface{}{target, id}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no open reports on %s %d: %w", target, id, sql.ErrNoRows)
+	}
+	return groups[0], nil
+}
+
+// ListReportGroups returns open reports grouped by target, the group with the
+// oldest report first, starting after the group whose first report is q.AfterID.
+func (p *Postgres) ListReportGroups(ctx context.Context, q entity.ReportQueueQuery) ([]*entity.ReportGroup, error) {
+	var conds []string
+	var args []interface{}
+	if q.TargetType != "" {
+		args = append(args, q.TargetType)
+		conds = append(conds, fmt.Sprintf("target_type = $%d", len(args)))
+	}
+	return p.queryReportGroups(ctx, conds, append(args, q.AfterID), q.Limit)
+}
+
+// queryReportGroups groups the open reports matching conds. The argument after
+// those of conds is the first report id to start after, when there is one.
+func (p *Postgres) queryReportGroups(ctx context.Context, conds []string, args []interface{}, limit int) ([]*entity.ReportGroup, error) {
+	having := ""
+	if len(args) > len(conds) {
+		having = fmt.Sprintf("HAVING MIN(id) > $%d", len(args))
+	}
+	args = append(args, limit)
+	where := append([]string{"status = 'open'"}, conds...)
+
+	rows, err := p.db.QueryContext(ctx, fmt.Sprintf(`
+        WITH g AS (
+            SELECT target_type, target_id, MIN(id) AS first_id, COUNT(*) AS report_count,
+                   MIN(created_at) AS first_at, MAX(updated_at) AS last_at,
+                   (ARRAY_AGG(target_user_id ORDER BY id))[1] AS target_user_id
+            FROM reports
+            WHERE %s
+            GROUP BY target_type, target_id
+            %s
+            ORDER BY first_id
+            LIMIT $%d
+        )
+        SELECT g.target_type, g.target_id, g.first_id, g.report_count, g.first_at, g.last_at, g.target_user_id,
+               COALESCE(p.id, c.id, m.id) IS NOT NULL,
+               COALESCE(p.user_id, c.user_id, m.user_id), COALESCE(u.username, m.author, ''),
+               COALESCE(p.id, c.post_id), COALESCE(p.title, cp.title, ''),
+               COALESCE(p.content, c.content, m.text, ''),
+               COALESCE(p.hidden_at, c.hidden_at, m.hidden_at) IS NOT NULL
+        FROM g
+        LEFT JOIN posts p ON g.target_type = 'post' AND p.id = g.target_id AND p.deleted_at IS NULL
+        LEFT JOIN comments c ON g.target_type = 'comment' AND c.id = g.target_id AND c.deleted_at IS NULL
+        LEFT JOIN chat_messages m ON g.target_type = 'chat_message' AND m.id = g.target_id
+        LEFT JOIN posts cp ON cp.id = c.post_id
+        LEFT JOIN users u ON u.id = COALESCE(p.user_id, c.user_id, m.user_id)
+        ORDER BY g.first_id
+    `, strings.Join(where, " AND "), having, len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []*entity.ReportGroup
+	for rows.Next() {
+		var g entity.ReportGroup
+		var content entity.ReportedContent
+		var targetUserID, authorID, postID sql.NullInt64
+		var found bool
+		if err := rows.Scan(
+			&g.TargetType, &g.TargetID, &g.FirstReportID, &g.ReportCount, &g.FirstReportedAt, &g.LastReportedAt, &targetUserID,
+			&found, &authorID, &content.Author, &postID, &content.Title, &content.Excerpt, &content.Hidden,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan report group: %w", err)
+		}
+		g.TargetUserID = nullIntPtr(targetUserID)
+		if found {
+			content.AuthorID = nullIntPtr(authorID)
+			content.PostID = nullIntPtr(postID)
+			g.Content = &content
+		}
+		g.Reasons = make(map[entity.ReportReason]int)
+		groups = append(groups, &g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	if len(groups) == 0 {
+		return groups, nil
+	}
+	if err := p.loadGroupReports(ctx, groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// loadGroupReports fills the open reports and reason counts of groups.
+func (p *Postgres) loadGroupReports(ctx context.Context, groups []*entity.ReportGroup) error {
+	types := make([]string, len(groups))
+	ids := make([]int, len(groups))
+	byTarget := make(map[string]*entity.ReportGroup, len(groups))
+	for i, g := range groups {
+		types[i], ids[i] = string(g.TargetType), g.TargetID
+		byTarget[fmt.Sprintf("%s/%d", g.TargetType, g.TargetID)] = g
+	}
+
+	rows, err := p.db.QueryContext(ctx, `
+        SELECT r.id, r.reporter_id, COALESCE(u.username, ''), r.target_type, r.target_id, r.target_user_id,
+               r.reason, r.note, r.status, r.created_at, r.updated_at
+        FROM reports r
+        LEFT JOIN users u ON u.id = r.reporter_id
+        WHERE r.status = 'open'
+          AND (r.target_type, r.target_id) IN (SELECT * FROM UNNEST($1::text[], $2::int[]))
+        ORDER BY r.id
+    `, pq.Array(types), pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to list reports: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r entity.Report
+		var targetUserID sql.NullInt64
+		if err := rows.Scan(
+			&r.ID, &r.ReporterID, &r.Reporter, &r.TargetType, &r.TargetID, &targetUserID,
+			&r.Reason, &r.Note, &r.Status, &r.CreatedAt, &r.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to scan report: %w", err)
+		}
+		r.TargetUserID = nullIntPtr(targetUserID)
+		if g, ok := byTarget[fmt.Sprintf("%s/%d", r.TargetType, r.TargetID)]; ok {
+			g.Reports = append(g.Reports, r)
+			g.Reasons[r.Reason]++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows error: %w", err)
+	}
+	return nil
+}
+
+// moderationLogColumns selects a log entry aliased as l with the names of the
+// moderator (mu) and the sanctioned user (tu).
+const moderationLogColumns = `
+        l.id, l.moderator_id, COALESCE(mu.username, ''), l.action, l.target_type, l.target_id,
+        l.target_user_id, COALESCE(tu.username, ''), l.note, l.report_count, l.ban_expires_at, l.created_at`
+
+const moderationLogJoins = `
+        LEFT JOIN users mu ON mu.id = l.moderator_id
+        LEFT JOIN users tu ON tu.id = l.target_user_id`
+
+func scanModerationLogEntry(row scanner, e *entity.ModerationLogEntry) error {
+	var moderatorID, targetUserID sql.NullInt64
+	var banExpiresAt sql.NullTime
+	if err := row.Scan(
+		&e.ID, &moderatorID, &e.Moderator, &e.Action, &e.TargetType, &e.TargetID,
+		&targetUserID, &e.TargetUser, &e.Note, &e.ReportCount, &banExpiresAt, &e.CreatedAt,
+	); err != nil {
+		return err
+	}
+	e.ModeratorID = nullIntPtr(moderatorID)
+	e.TargetUserID = nullIntPtr(targetUserID)
+	e.BanExpiresAt = nil
+	if banExpiresAt.Valid {
+		e.BanExpiresAt = &banExpiresAt.Time
+	}
+	return nil
+}
+
+// ResolveReports applies entry.Action to the reported target, closes its open
+// reports and records entry in the moderation log, all in one transaction. The
+// warning or ban notifications it creates are returned for delivery. Without open
+// reports, or when the content to hide or delete is gone, a wrapped sql.ErrNoRows
+// is returned and nothing changes.
+func (p *Postgres) ResolveReports(ctx context.Context, entry *entity.ModerationLogEntry) ([]*entity.Notification, error) {
+	table, live, err := reportTable(entry.TargetType)
+	if err != nil {
+		return nil, err
+	}
+
+	var notifications []*entity.Notification
+	err = p.withTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `
+            SELECT id FROM reports
+            WHERE status = 'open' AND target_type = $1 AND target_id = $2
+            ORDER BY id
+            FOR UPDATE
+        `, entry.TargetType, entry.TargetID)
+		if err != nil {
+			return fmt.Errorf("failed to lock reports: %w", err)
+		}
+		reportIDs, err := scanInt64s(rows)
+		if err != nil {
+			return err
+		}
+		if len(reportIDs) == 0 {
+			return fmt.Errorf("no open reports on %s %d: %w", entry.TargetType, entry.TargetID, sql.ErrNoRows)
+		}
+
+		switch entry.Action {
+		case entity.ModerationHide:
+			res, err := tx.ExecContext(ctx, fmt.Sprintf(`
+                UPDATE %s SET hidden_at = COALESCE(hidden_at, NOW()) WHERE id = $1 AND %s
+            `, table, live), entry.TargetID)
+			if err != nil {
+				return fmt.Errorf("failed to hide %s: %w", entry.TargetType, err)
+			}
+			if err := requireAffected(res, string(entry.TargetType)); err != nil {
+				return err
+			}
+		case entity.ModerationDelete:
+			// Posts and comments go to the trash, chat messages are deleted for good
+			if entry.TargetType == entity.ReportOnComment {
+				if err := deleteComment(ctx, tx, entry.TargetID, entry.ModeratorID, nil); err != nil {
+					return err
+				}
+				break
+			}
+			if entry.TargetType == entity.ReportOnPost {
+				if err := deletePost(ctx, tx, entry.TargetID, entry.ModeratorID); err != nil {
+					return err
+				}
+				break
+			}
+			res, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, table), entry.TargetID)
+			if err != nil {
+				return fmt.Errorf("failed to delete %s: %w", entry.TargetType, err)
+			}
+			if err := requireAffected(res, string(entry.TargetType)); err != nil {
+				return err
+			}
+		case entity.ModerationBan:
+			_, err := tx.ExecContext(ctx, `
+                INSERT INTO user_bans (user_id, banned_by, reason, expires_at)
+                VALUES ($1, $2, $3, $4)
+                ON CONFLICT (user_id) DO UPDATE
+                SET banned_by = EXCLUDED.banned_by, reason = EXCLUDED.reason,
+                    created_at = NOW(), expires_at = EXCLUDED.expires_at
+            `, entry.TargetUserID, entry.ModeratorID, entry.Note, entry.BanExpiresAt)
+			if err != nil {
+				return fmt.Errorf("failed to ban user: %w", err)
+			}
+		}
+
+		if entry.Action == entity.ModerationWarn || entry.Action == entity.ModerationBan {
+			n, err := createSanctionNotification(ctx, tx, entry)
+			if err != nil {
+				return err
+			}
+			notifications = append(notifications, n)
+		}
+
+		entry.ReportCount = len(reportIDs)
+		if err := insertModerationLog(ctx, tx, entry); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            UPDATE reports SET status = 'resolved', resolution_id = $1, updated_at = NOW()
+            WHERE id = ANY($2)
+        `, entry.ID, pq.Array(reportIDs))
+		if err != nil {
+			return fmt.Errorf("failed to resolve reports: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// insertModerationLog records entry and fills it back with its id, timestamps and
+// user names.
+func insertModerationLog(ctx context.Context, tx *sql.Tx, entry *entity.ModerationLogEntry) error {
+	err := scanModerationLogEntry(tx.QueryRowContext(ctx, `
+        WITH l AS (
+            INSERT INTO moderation_log (moderator_id, action, target_type, target_id, target_user_id, note, report_count, ban_expires_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+            RETURNING *
+        )
+        SELECT `+moderationLogColumns+`
+        FROM l`+moderationLogJoins,
+		entry.ModeratorID, entry.Action, entry.TargetType, entry.TargetID, entry.TargetUserID,
+		entry.Note, entry.ReportCount, entry.BanExpiresAt,
+	), entry)
+	if err != nil {
+		return fmt.Errorf("failed to record moderation: %w", err)
+	}
+	return nil
+}
+
+// SetPostStatus applies update to the post entry.TargetID and records the change
+// in the moderation log as entry, in one transaction. The action and the author of
+// entry are filled from the change. An update that changes nothing writes nothing
+// and leaves entry.ID zero. A missing, hidden or deleted post is a wrapped sql.ErrNoRows.
+func (p *Postgres) SetPostStatus(ctx context.Context, update entity.PostStatusUpdate, entry *entity.ModerationLogEntry) (*entity.PostStatus, error) {
+	status := &entity.PostStatus{PostID: entry.TargetID}
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var userID sql.NullInt64
+		err := tx.QueryRowContext(ctx, `
+            SELECT user_id, state, pinned FROM posts WHERE id = $1 AND hidden_at IS NULL AND deleted_at IS NULL FOR UPDATE
+        `, entry.TargetID).Scan(&userID, &status.State, &status.Pinned)
+		if err != nil {
+			return fmt.Errorf("failed to lock post: %w", err)
+		}
+		status.UserID = int(userID.Int64)
+
+		entry.Action = update.Action(*status)
+		if entry.Action == "" {
+			return nil
+		}
+		if update.State != "" {
+			status.State = update.State
+		}
+		if update.Pinned != nil {
+			status.Pinned = *update.Pinned
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE posts SET state = $1, pinned = $2 WHERE id = $3`,
+			status.State, status.Pinned, entry.TargetID)
+		if err != nil {
+			return fmt.Errorf("failed to update post status: %w", err)
+		}
+
+		entry.TargetType = entity.ReportOnPost
+		entry.TargetUserID = nullIntPtr(userID)
+		return insertModerationLog(ctx, tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// createSanctionNotification tells the target user about a warning or ban,
+// linking the reported post or comment while it still exists.
+func createSanctionNotification(ctx context.Context, tx *sql.Tx, entry *entity.ModerationLogEntry) (*entity.Notification, error) {
+	notificationType := entity.NotificationWarning
+	if entry.Action == entity.ModerationBan {
+		notificationType = entity.NotificationBan
+	}
+	var postID, commentID interface{}
+	switch entry.TargetType {
+	case entity.ReportOnPost:
+		postID = entry.TargetID
+	case entity.ReportOnComment:
+		commentID = entry.TargetID
+	}
+
+	row := tx.QueryRowContext(ctx, `
+        WITH n AS (
+            INSERT INTO notifications (user_id, type, actor_id, post_id, comment_id, note)
+            VALUES ($1, $2, $3,
+                    COALESCE((SELECT id FROM posts WHERE id = $4), (SELECT post_id FROM comments WHERE id = $5)),
+                    (SELECT id FROM comments WHERE id = $5), $6)
+            RETURNING *
+        )
+        SELECT `+notificationColumns+`
+        FROM n`+notificationJoins,
+		entry.TargetUserID, notificationType, entry.ModeratorID, postID, commentID, entry.Note)
+	n, err := scanNotification(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to notify user: %w", err)
+	}
+	return n, nil
+}
+
+// ListModerationLog returns log entries newest first, starting below q.BeforeID
+// when it is set.
+func (p *Postgres) ListModerationLog(ctx context.Context, q entity.ModerationLogQuery) ([]*entity.ModerationLogEntry, error) {
+	var args []interface{}
+	filters := []string{"TRUE"}
+	if q.TargetUserID > 0 {
+		args = append(args, q.TargetUserID)
+		filters = append(filters, fmt.Sprintf("l.target_user_id = $%d", len(args)))
+	}
+	if q.BeforeID > 0 {
+		args = append(args, q.BeforeID)
+		filters = append(filters, fmt.Sprintf("l.id < $%d", len(args)))
+	}
+	args = append(args, q.Limit)
+
+	rows, err := p.db.QueryContext(ctx, fmt.Sprintf(`
+        SELECT %s
+        FROM moderation_log l%s
+        WHERE %s
+        ORDER BY l.id DESC
+        LIMIT $%d
+    `, moderationLogColumns, moderationLogJoins, strings.Join(filters, " AND "), len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query moderation log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*entity.ModerationLogEntry
+	for rows.Next() {
+		var e entity.ModerationLogEntry
+		if err := scanModerationLogEntry(rows, &e); err != nil {
+			return nil, fmt.Errorf("failed to scan moderation log entry: %w", err)
+		}
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return entries, nil
+}
+
+// GetActiveBan returns the user's ban unless it has expired. An unbanned user is
+// a wrapped sql.ErrNoRows.
+func (p *Postgres) GetActiveBan(ctx context.Context, userID int) (*entity.UserBan, error) {
+	var ban entity.UserBan
+	var bannedBy sql.NullInt64
+	var expiresAt sql.NullTime
+	err := p.db.QueryRowContext(ctx, `
+        SELECT user_id, banned_by, reason, created_at, expires_at
+        FROM user_bans
+        WHERE user_id = $1 AND (expires_at IS NULL OR expires_at > $2)
+    `, userID, time.Now()).Scan(&ban.UserID, &bannedBy, &ban.Reason, &ban.CreatedAt, &expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ban: %w", err)
+	}
+	ban.BannedBy = nullIntPtr(bannedBy)
+	if expiresAt.Valid {
+		ban.ExpiresAt = &expiresAt.Time
+	}
+	return &ban, nil
+}
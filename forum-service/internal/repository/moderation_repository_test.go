@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresModeration(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Устанавливаем тестовые данные
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+	_, err = repo.db.ExecContext(ctx, `
+		INSERT INTO users (id, username, email, password_hash, role)
+		VALUES (2, 'alice', 'alice@example.com', 'hashedpassword', 'user'),
+		       (3, 'bob', 'bob@example.com', 'hashedpassword', 'user'),
+		       (9, 'mod', 'mod@example.com', 'hashedpassword', 'moderator')
+	`)
+	require.NoError(t, err)
+	postAuthorID, moderatorID := 1, 9
+	comment := &entity.Comment{Content: "buy cheap pills", PostID: 1, UserID: 3}
+	require.NoError(t, repo.CreateComment(ctx, comment))
+
+	author, err := repo.GetReportTargetAuthor(ctx, entity.ReportOnComment, comment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, *author)
+	_, err = repo.GetReportTargetAuthor(ctx, entity.ReportOnPost, 404)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Повторная жалоба того же пользователя обновляет открытую
+	report := &entity.Report{ReporterID: 2, TargetType: entity.ReportOnComment, TargetID: comment.ID, TargetUserID: author, Reason: entity.ReasonOffTopic}
+	created, err := repo.CreateReport(ctx, report)
+	require.NoError(t, err)
+	assert.True(t, created)
+	again := &entity.Report{ReporterID: 2, TargetType: entity.ReportOnComment, TargetID: comment.ID, TargetUserID: author, Reason: entity.ReasonSpam, Note: "ads"}
+	created, err = repo.CreateReport(ctx, again)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, report.ID, again.ID)
+
+	postReport := &entity.Report{ReporterID: 3, TargetType: entity.ReportOnPost, TargetID: 1, TargetUserID: &postAuthorID, Reason: entity.ReasonOther}
+	_, err = repo.CreateReport(ctx, postReport)
+	require.NoError(t, err)
+	_, err = repo.CreateReport(ctx, &entity.Report{ReporterID: 1, TargetType: entity.ReportOnComment, TargetID: comment.ID, TargetUserID: author, Reason: entity.ReasonSpam})
+	require.NoError(t, err)
+
+	// Очередь: группа комментария раньше, в ней две жалобы на спам
+	groups, err := repo.ListReportGroups(ctx, entity.ReportQueueQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, entity.ReportOnComment, groups[0].TargetType)
+	assert.Equal(t, 2, groups[0].ReportCount)
+	assert.Equal(t, map[entity.ReportReason]int{entity.ReasonSpam: 2}, groups[0].Reasons)
+	require.NotNil(t, groups[0].Content)
+	assert.Equal(t, "bob", groups[0].Content.Author)
+	assert.Equal(t, "Test Post", groups[0].Content.Title)
+	assert.Equal(t, "buy cheap pills", groups[0].Content.Excerpt)
+	assert.Len(t, groups[0].Reports, 2)
+
+	next, err := repo.ListReportGroups(ctx, entity.ReportQueueQuery{AfterID: groups[0].FirstReportID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, next, 1)
+	assert.Equal(t, entity.ReportOnPost, next[0].TargetType)
+
+	// Блокировка автора закрывает обе жалобы на комментарий и уведомляет его
+	expires := time.Now().Add(24 * time.Hour)
+	entry := &entity.ModerationLogEntry{
+		ModeratorID: &moderatorID, Action: entity.ModerationBan, TargetType: entity.ReportOnComment,
+		TargetID: comment.ID, TargetUserID: author, Note: "spam", BanExpiresAt: &expires,
+	}
+	notifications, err := repo.ResolveReports(ctx, entry)
+	require.NoError(t, err)
+	assert.Equal(t, 2, entry.ReportCount)
+	assert.Equal(t, "mod", entry.Moderator)
+	assert.Equal(t, "bob", entry.TargetUser)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, entity.NotificationBan, notifications[0].Type)
+	assert.Equal(t, comment.ID, *notifications[0].CommentID)
+	assert.Equal(t, "spam", notifications[0].Note)
+
+	ban, err := repo.GetActiveBan(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 9, *ban.BannedBy)
+	_, err = repo.GetActiveBan(ctx, 2)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = repo.GetReportGroup(ctx, entity.ReportOnComment, comment.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repo.ResolveReports(ctx, &entity.ModerationLogEntry{ModeratorID: &moderatorID, Action: entity.ModerationDismiss, TargetType: entity.ReportOnComment, TargetID: comment.ID})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Скрытый пост пропадает из выдачи, жалобы на него закрыты
+	hide := &entity.ModerationLogEntry{ModeratorID: &moderatorID, Action: entity.ModerationHide, TargetType: entity.ReportOnPost, TargetID: 1, TargetUserID: &postAuthorID}
+	_, err = repo.ResolveReports(ctx, hide)
+	require.NoError(t, err)
+	_, err = repo.GetPostByID(ctx, 1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	groups, err = repo.ListReportGroups(ctx, entity.ReportQueueQuery{Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+
+	// Журнал: новые записи первыми, фильтр по пользователю
+	log, err := repo.ListModerationLog(ctx, entity.ModerationLogQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, log, 2)
+	assert.Equal(t, entity.ModerationHide, log[0].Action)
+	log, err = repo.ListModerationLog(ctx, entity.ModerationLogQuery{TargetUserID: 3, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, log, 1)
+	assert.Equal(t, entity.ModerationBan, log[0].Action)
+	require.NotNil(t, log[0].BanExpiresAt)
+	log, err = repo.ListModerationLog(ctx, entity.ModerationLogQuery{BeforeID: hide.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, log, 1)
+}
+
+func TestPostgresPostStatus(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+	_, err = repo.db.ExecContext(ctx, `
+		INSERT INTO users (id, username, email, password_hash, role)
+		VALUES (9, 'mod', 'mod@example.com', 'hashedpassword', 'moderator')
+	`)
+	require.NoError(t, err)
+	var newerID int
+	err = repo.db.QueryRowContext(ctx, `
+		INSERT INTO posts (title, content, user_id, category_id, created_at)
+		VALUES ('Newer Post', 'Content', 1, 1, NOW() + INTERVAL '1 hour')
+		RETURNING id
+	`).Scan(&newerID)
+	require.NoError(t, err)
+	moderatorID := 9
+
+	status, err := repo.GetPostStatus(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PostOpen, status.State)
+	assert.False(t, status.Pinned)
+
+	// Закрепленный пост идет первым, курсор после него ведет к остальным
+	pinned := true
+	pin := &entity.ModerationLogEntry{ModeratorID: &moderatorID, TargetID: 1, Note: "rules"}
+	status, err = repo.SetPostStatus(ctx, entity.PostStatusUpdate{Pinned: &pinned}, pin)
+	require.NoError(t, err)
+	assert.True(t, status.Pinned)
+	assert.Equal(t, entity.ModerationPin, pin.Action)
+	assert.NotZero(t, pin.ID)
+
+	posts, err := repo.ListPosts(ctx, entity.PostQuery{Sort: entity.PostSortNewest, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, 1, posts[0].ID)
+	assert.True(t, posts[0].Pinned)
+	assert.Equal(t, newerID, posts[1].ID)
+
+	posts, err = repo.ListPosts(ctx, entity.PostQuery{
+		Sort:  entity.PostSortNewest,
+		After: &entity.PostCursor{Sort: entity.PostSortNewest, Time: posts[0].CreatedAt, Pinned: true, ID: 1},
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, newerID, posts[0].ID)
+
+	// Архивирование записывается в журнал с автором поста
+	archive := &entity.ModerationLogEntry{ModeratorID: &moderatorID, TargetID: 1}
+	status, err = repo.SetPostStatus(ctx, entity.PostStatusUpdate{State: entity.PostArchived}, archive)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PostArchived, status.State)
+	assert.Equal(t, entity.ModerationArchive, archive.Action)
+	require.NotNil(t, archive.TargetUserID)
+	assert.Equal(t, 1, *archive.TargetUserID)
+
+	post, err := repo.GetPostByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PostArchived, post.State)
+	assert.True(t, post.Pinned)
+
+	// Повторное архивирование ничего не меняет и не пишется в журнал
+	again := &entity.ModerationLogEntry{ModeratorID: &moderatorID, TargetID: 1}
+	_, err = repo.SetPostStatus(ctx, entity.PostStatusUpdate{State: entity.PostArchived}, again)
+	require.NoError(t, err)
+	assert.Zero(t, again.ID)
+
+	log, err := repo.ListModerationLog(ctx, entity.ModerationLogQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, log, 2)
+	assert.Equal(t, entity.ModerationArchive, log[0].Action)
+	assert.Equal(t, entity.ModerationPin, log[1].Action)
+
+	_, err = repo.SetPostStatus(ctx, entity.PostStatusUpdate{State: entity.PostLocked}, &entity.ModerationLogEntry{ModeratorID: &moderatorID, TargetID: 404})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
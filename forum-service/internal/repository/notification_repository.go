@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+type NotificationRepository interface {
+	Subscribe(ctx context.Context, userID, postID int) error
+	Unsubscribe(ctx context.Context, userID, postID int) error
+	IsSubscribed(ctx context.Context, userID, postID int) (bool, error)
+	CreateCommentNotifications(ctx context.Context, comment *entity.Comment) ([]*entity.Notification, error)
+	ListNotifications(ctx context.Context, q entity.NotificationQuery) ([]*entity.Notification, error)
+	MarkNotificationRead(ctx context.Context, userID, notificationID int) error
+	MarkAllNotificationsRead(ctx context.Context, userID int) (int, error)
+	CountUnreadNotifications(ctx context.Context, userID int) (int, error)
+}
+
+// notificationColumns selects a notification aliased as n with the actor's name and
+// the post title, empty once the post is deleted.
+const notificationColumns = `
+        n.id, n.user_id, n.type, n.actor_id, COALESCE(a.username, ''),
+        n.post_id, COALESCE(p.title, ''), n.comment_id, n.read_at, n.created_at, n.note`
+
+const notificationJoins = `
+        LEFT JOIN users a ON a.id = n.actor_id
+        LEFT JOIN posts p ON p.id = n.post_id AND p.deleted_at IS NULL`
+
+func scanNotification(row scanner) (*entity.Notification, error) {
+	var n entity.Notification
+	var actorID, postID, commentID sql.NullInt64
+	var readAt sql.NullTime
+	if err := row.Scan(
+		&n.ID, &n.UserID, &n.Type, &actorID, &n.Actor,
+		&postID, &n.PostTitle, &commentID, &readAt, &n.CreatedAt, &n.Note,
+	); err != nil {
+		return nil, err
+	}
+	n.ActorID = nullIntPtr(actorID)
+	n.PostID = nullIntPtr(postID)
+	n.CommentID = nullIntPtr(commentID)
+	if readAt.Valid {
+		n.ReadAt = &readAt.Time
+	}
+	return &n, nil
+}
+
+func scanNotifications(rows *sql.Rows) ([]*entity.Notification, error) {
+	defer rows.Close()
+	var notifications []*entity.Notification
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return notifications, nil
+}
+
+func (p *Postgres) Subscribe(ctx context.Context, userID, postID int) error {
+	_, err := p.db.ExecContext(ctx,
+		`INSERT INTO subscriptions (user_id, post_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		userID, postID)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) Unsubscribe(ctx context.Context, userID, postID int) error {
+	_, err := p.db.ExecContext(ctx,
+		`DELETE FROM subscriptions WHERE user_id = $1 AND post_id = $2`, userID, postID)
+	if err != nil {
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) IsSubscribed(ctx context.Context, userID, postID int) (bool, error) {
+	var subscribed bool
+	err := p.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM subscriptions WHERE user_id = $1 AND post_id = $2)`,
+		userID, postID).Scan(&subscribed)
+	if err != nil {
+		return false, fmt.Errorf("failed to check subscription: %w", err)
+	}
+	return subscribed, nil
+}
+
+// CreateCommentNotifications notifies about a new comment: the author of the parent
+// comment gets a reply notification, the post's other subscribers a comment
+// notification. The comment's own author is never notified.
+func (p *Postgres) CreateCommentNotifications(ctx context.Context, comment *entity.Comment) ([]*entity.Notification, error) {
+	var parentID sql.NullInt64
+	if comment.ParentID != nil {
+		parentID = sql.NullInt64{Int64: int64(*comment.ParentID), Valid: true}
+	}
+	rows, err := p.db.QueryContext(ctx, `
+        WITH parent AS (
+            SELECT user_id FROM comments
+            WHERE id = $4::int AND deleted_at IS NULL AND user_id IS NOT NULL
+        ), recipients AS (
+            SELECT user_id, 'reply' AS type FROM parent
+            UNION
+            SELECT s.user_id, 'comment' FROM subscriptions s
+            WHERE s.post_id = $2 AND NOT EXISTS (SELECT 1 FROM parent WHERE parent.user_id = s.user_id)
+        ), n AS (
+            INSERT INTO notifications (user_id, type, actor_id, post_id, comment_id)
+            SELECT user_id, type, $1, $2, $3 FROM recipients WHERE user_id <> $1
+            RETURNING *
+        )
+        SELECT `+notificationColumns+`
+        FROM n`+notificationJoins+`
+        ORDER BY n.id
+    `, comment.UserID, comment.PostID, comment.ID, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notifications for comment %d: %w", comment.ID, err)
+	}
+	return scanNotifications(rows)
+}
+
+// ListNotifications returns notifications of q.UserID newest first, starting below
+// q.BeforeID when it is set.
+func (p *Postgres) ListNotifications(ctx context.Context, q entity.NotificationQuery) ([]*entity.Notification, error) {
+	args := []interface{}{q.UserID}
+	filters := []string{"n.user_id = $1"}
+	if q.UnreadOnly {
+		filters = append(filters, "n.read_at IS NULL")
+	}
+	if q.BeforeID > 0 {
+		args = append(args, q.BeforeID)
+		filters = append(filters, fmt.Sprintf("n.id < $%d", len(args)))
+	}
+	args = append(args, q.Limit)
+
+	rows, err := p.db.QueryContext(ctx, fmt.Sprintf(`
+        SELECT %s
+        FROM notifications n%s
+        WHERE %s
+        ORDER BY n.id DESC
+        LIMIT $%d
+    `, notificationColumns, notificationJoins, strings.Join(filters, " AND "), len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	return scanNotifications(rows)
+}
+
+// MarkNotificationRead marks one notification of the user as read. Marking an already
+// read notification is not an error; a notification of another user is not found.
+func (p *Postgres) MarkNotificationRead(ctx context.Context, userID, notificationID int) error {
+	res, err := p.db.ExecContext(ctx, `
+        UPDATE notifications SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+        WHERE id = $1 AND user_id = $2
+    `, notificationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	return requireAffected(res, "notification")
+}
+
+// MarkAllNotificationsRead marks every unread notification of the user as read and
+// returns how many there were.
+func (p *Postgres) MarkAllNotificationsRead(ctx context.Context, userID int) (int, error) {
+	res, err := p.db.ExecContext(ctx,
+		`UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND read_at IS NULL`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return int(n), nil
+}
+
+func (p *Postgres) CountUnreadNotifications(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := p.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresNotifications(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Устанавливаем тестовые данные
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+	_, err = repo.db.ExecContext(ctx, `
+		INSERT INTO users (id, username, email, password_hash, role)
+		VALUES (2, 'second', 'second@example.com', 'hashedpassword', 'user'),
+		       (3, 'third', 'third@example.com', 'hashedpassword', 'user')
+	`)
+	require.NoError(t, err)
+
+	// Автор поста и третий пользователь подписаны, повторная подписка не ошибка
+	require.NoError(t, repo.Subscribe(ctx, 1, 1))
+	require.NoError(t, repo.Subscribe(ctx, 3, 1))
+	require.NoError(t, repo.Subscribe(ctx, 3, 1))
+	subscribed, err := repo.IsSubscribed(ctx, 3, 1)
+	require.NoError(t, err)
+	assert.True(t, subscribed)
+	require.NoError(t, repo.Unsubscribe(ctx, 2, 1))
+
+	// Комментарий второго пользователя: оба подписчика получают "comment"
+	comment := &entity.Comment{Content: "first", PostID: 1, UserID: 2}
+	require.NoError(t, repo.CreateComment(ctx, comment))
+	created, err := repo.CreateCommentNotifications(ctx, comment)
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+	for _, n := range created {
+		assert.Equal(t, entity.NotificationComment, n.Type)
+		assert.Equal(t, "second", n.Actor)
+		assert.Equal(t, "Test Post", n.PostTitle)
+		assert.Nil(t, n.ReadAt)
+	}
+
+	// Ответ автора поста: второй получает "reply", третий "comment", сам автор ничего
+	reply := &entity.Comment{Content: "reply", PostID: 1, UserID: 1, ParentID: &comment.ID, Depth: 1}
+	require.NoError(t, repo.CreateComment(ctx, reply))
+	created, err = repo.CreateCommentNotifications(ctx, reply)
+	require.NoError(t, err)
+	types := map[int]entity.NotificationType{}
+	for _, n := range created {
+		types[n.UserID] = n.Type
+	}
+	assert.Equal(t, map[int]entity.NotificationType{2: entity.NotificationReply, 3: entity.NotificationComment}, types)
+
+	// Список третьего пользователя: новые первыми, постранично
+	page, err := repo.ListNotifications(ctx, entity.NotificationQuery{UserID: 3, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, reply.ID, *page[0].CommentID)
+	older, err := repo.ListNotifications(ctx, entity.NotificationQuery{UserID: 3, BeforeID: page[0].ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, older, 1)
+	assert.Equal(t, comment.ID, *older[0].CommentID)
+
+	// Прочтение: чужое уведомление не найдено
+	require.NoError(t, repo.MarkNotificationRead(ctx, 3, page[0].ID))
+	assert.ErrorIs(t, repo.MarkNotificationRead(ctx, 2, page[0].ID), sql.ErrNoRows)
+	unread, err := repo.ListNotifications(ctx, entity.NotificationQuery{UserID: 3, UnreadOnly: true, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, unread, 1)
+
+	count, err := repo.CountUnreadNotifications(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	marked, err := repo.MarkAllNotificationsRead(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, marked)
+	count, err = repo.CountUnreadNotifications(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
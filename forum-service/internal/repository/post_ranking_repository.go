@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lib/pq"
+)
+
+type PostRankingRepository interface {
+	ListPostActivity(ctx context.Context, recentSince time.Time) ([]entity.PostActivity, error)
+	SavePostRankings(ctx context.Context, rankings []entity.PostRanking) error
+}
+
+// ListPostActivity returns the vote score and live comment count of every published
+// post, plus the share of both that arrived at or after recentSince.
+func (p *Postgres) ListPostActivity(ctx context.Context, recentSince time.Time) ([]entity.PostActivity, error) {
+	rows, err := p.db.QueryContext(ctx, `
+        SELECT
+            p.id,
+            p.created_at,
+            COALESCE(v.score, 0),
+            COALESCE(c.comments, 0),
+            COALESCE(v.recent_score, 0),
+            COALESCE(c.recent_comments, 0)
+        FROM posts p
+        LEFT JOIN (
+            SELECT post_id,
+                   SUM(value) AS score,
+                   SUM(value) FILTER (WHERE created_at >= $1) AS recent_score
+            FROM votes
+            WHERE post_id IS NOT NULL
+            GROUP BY post_id
+        ) v ON v.post_id = p.id
+        LEFT JOIN (
+            SELECT post_id,
+                   COUNT(*) AS comments,
+                   COUNT(*) FILTER (WHERE created_at >= $1) AS recent_comments
+            FROM comments
+            WHERE deleted_at IS NULL
+            GROUP BY post_id
+        ) c ON c.post_id = p.id
+        WHERE p.deleted_at IS NULL AND p.status = 'published'
+    `, recentSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query post activity: %w", err)
+	}
+	defer rows.Close()
+
+	var activity []entity.PostActivity
+	for rows.Next() {
+		var a entity.PostActivity
+		if err := rows.Scan(&a.PostID, &a.CreatedAt, &a.Score, &a.Comments, &a.RecentScore, &a.RecentComments); err != nil {
+			return nil, fmt.Errorf("failed to scan post activity: %w", err)
+		}
+		activity = append(activity, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return activity, nil
+}
+
+// SavePostRankings upserts the rankings in one statement. Rankings of posts deleted
+// since their activity was read are skipped.
+func (p *Postgres) SavePostRankings(ctx context.Context, rankings []entity.PostRanking) error {
+	if len(rankings) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(rankings))
+	hot := make([]float64, len(rankings))
+	top := make([]float64, len(rankings))
+	rising := make([]float64, len(rankings))
+	for i, r := range rankings {
+		ids[i] = int64(r.PostID)
+		hot[i] = r.Hot
+		top[i] = r.Top
+		rising[i] = r.Rising
+	}
+
+	_, err := p.db.ExecContext(ctx, `
+        INSERT INTO post_rankings (post_id, hot, top, rising, computed_at)
+        SELECT u.post_id, u.hot, u.top, u.rising, CURRENT_TIMESTAMP
+        FROM unnest($1::int[], $2::double precision[], $3::double precision[], $4::double precision[])
+            AS u(post_id, hot, top, rising)
+        WHERE EXISTS (SELECT 1 FROM posts WHERE id = u.post_id)
+        ON CONFLICT (post_id) DO UPDATE
+        SET hot = EXCLUDED.hot, top = EXCLUDED.top, rising = EXCLUDED.rising, computed_at = EXCLUDED.computed_at
+    `, pq.Array(ids), pq.Array(hot), pq.Array(top), pq.Array(rising))
+	if err != nil {
+		return fmt.Errorf("failed to save post rankings: %w", err)
+	}
+	return nil
+}
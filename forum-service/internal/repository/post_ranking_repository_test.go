@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresPostRankings(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Устанавливаем тестовые данные
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+
+	older := &entity.Post{Title: "Older", Content: "c", UserID: 1, CategoryID: 1}
+	require.NoError(t, repo.CreatePost(ctx, older))
+	_, err = repo.db.ExecContext(ctx, `UPDATE posts SET created_at = NOW() - INTERVAL '3 days' WHERE id = $1`, older.ID)
+	require.NoError(t, err)
+	_, err = repo.Vote(ctx, 1, entity.VoteTarget{Kind: entity.TargetPost, ID: older.ID}, 1)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateComment(ctx, &entity.Comment{Content: "c", PostID: 1, UserID: 1}))
+
+	activity, err := repo.ListPostActivity(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	byID := map[int]entity.PostActivity{}
+	for _, a := range activity {
+		byID[a.PostID] = a
+	}
+	assert.Equal(t, 1, byID[older.ID].Score)
+	assert.Equal(t, 1, byID[older.ID].RecentScore)
+	assert.Equal(t, 1, byID[1].Comments)
+
+	require.NoError(t, repo.SavePostRankings(ctx, []entity.PostRanking{
+		{PostID: 1, Hot: 2, Top: 0, Rising: 0.5},
+		{PostID: older.ID, Hot: 1, Top: 1, Rising: 0.1},
+		{PostID: 999, Hot: 9}, // удаленный пост пропускается
+	}))
+
+	posts, err := repo.ListPosts(ctx, entity.PostQuery{Sort: entity.PostSortHot, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, 1, posts[0].ID)
+	assert.Equal(t, 2.0, posts[0].Rank)
+
+	// Постраничная выдача по рейтингу
+	posts, err = repo.ListPosts(ctx, entity.PostQuery{
+		Sort:  entity.PostSortHot,
+		After: &entity.PostCursor{Sort: entity.PostSortHot, Rank: 2, ID: 1},
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, older.ID, posts[0].ID)
+
+	// top за день не видит пост трехдневной давности
+	posts, err = repo.ListPosts(ctx, entity.PostQuery{Sort: entity.PostSortTop, Window: entity.TopWindowDay, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, 1, posts[0].ID)
+	posts, err = repo.ListPosts(ctx, entity.PostQuery{Sort: entity.PostSortTop, Window: entity.TopWindowAll, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, older.ID, posts[0].ID)
+}
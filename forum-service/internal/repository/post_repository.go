@@ -5,18 +5,25 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/config"
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
-	_ "github.com/lib/pq"
+	"github.com/lera-guryan2222/fooorum/platform/tracing"
+	"github.com/lib/pq"
 )
 
 type PostRepository interface {
 	CreatePost(ctx context.Context, post *entity.Post) error
-	GetAllPosts(ctx context.Context) ([]*entity.Post, error)
+	ListPosts(ctx context.Context, q entity.PostQuery) ([]*entity.Post, error)
 	GetPostByID(ctx context.Context, id int) (*entity.Post, error)
-	DeletePost(ctx context.Context, id int) error
-	UpdatePost(ctx context.Context, postID int, title, content string) error
+	GetPostStatus(ctx context.Context, id int) (*entity.PostStatus, error)
+	DeletePost(ctx context.Context, id, deletedBy int) error
+	UpdatePost(ctx context.Context, postID, editorID int, update entity.PostUpdate) error
+	ListDrafts(ctx context.Context, q entity.DraftQuery) ([]*entity.Post, error)
+	SetPostPublication(ctx context.Context, postID int, publication entity.PostPublication) error
+	PublishDuePosts(ctx context.Context, now time.Time, limit int) ([]*entity.Post, error)
 }
 
 type Postgres struct {
@@ -31,7 +38,7 @@ func NewPostgres(cfg *config.Config) (*Postgres, error) {
 		cfg.Postgres.Password, cfg.Postgres.DBName, cfg.Postgres.SSLMode,
 	)
 
-	db, err := sql.Open("postgres", connStr)
+	db, err := tracing.OpenDB("postgres", connStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open db: %w", err)
 	}
@@ -43,28 +50,235 @@ func NewPostgres(cfg *config.Config) (*Postgres, error) {
 	return &Postgres{db: db, cfg: cfg}, nil
 }
 
+// PingContext checks that the database is reachable.
+func (p *Postgres) PingContext(ctx context.Context) error {
+	return p.db.PingContext(ctx)
+}
+
+// DB exposes the underlying pool for instrumentation.
+func (p *Postgres) DB() *sql.DB {
+	return p.db
+}
+
+// Close closes the connection pool.
+func (p *Postgres) Close() error {
+	return p.db.Close()
+}
+
+// withTx runs fn in a transaction, committing if it returns nil and rolling back otherwise.
+func (p *Postgres) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// CreatePost saves a post with its status, published unless set: a draft or a
+// scheduled post stays unpublished.
 func (p *Postgres) CreatePost(ctx context.Context, post *entity.Post) error {
-	query := `INSERT INTO posts (title, content, user_id) VALUES ($1, $2, $3) 
-              RETURNING id, created_at`
-	return p.db.QueryRowContext(ctx, query, post.Title, post.Content, post.UserID).
-		Scan(&post.ID, &post.CreatedAt)
+	if post.Status == "" {
+		post.Status = entity.PostPublished
+	}
+	return p.withTx(ctx, func(tx *sql.Tx) error {
+		query := `INSERT INTO posts (title, content, format, content_html, user_id, category_id, status, publish_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
+              RETURNING id, version, created_at, updated_at`
+		err := tx.QueryRowContext(ctx, query, post.Title, post.Content, post.Format, post.ContentHTML, post.UserID, post.CategoryID, post.Status, post.PublishAt).
+			Scan(&post.ID, &post.Version, &post.CreatedAt, &post.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create post: %w", err)
+		}
+		if err := insertPostRevision(ctx, tx, post.ID, post.UserID, post.Title, post.Content, ""); err != nil {
+			return err
+		}
+		// Authors follow replies to their own posts
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO subscriptions (user_id, post_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			post.UserID, post.ID); err != nil {
+			return fmt.Errorf("failed to subscribe author: %w", err)
+		}
+		if err := linkAttachments(ctx, tx, entity.AttachedToPost, post.ID, post.UserID, post.AttachmentIDs); err != nil {
+			return err
+		}
+		return setPostTags(ctx, tx, post.ID, post.Tags)
+	})
 }
 
-func (p *Postgres) GetAllPosts(ctx context.Context) ([]*entity.Post, error) {
-	log.Printf("[DEBUG] Repository: Starting GetAllPosts query")
-	query := `
-            SELECT 
-            p.id, 
-            p.title, 
-            p.content, 
-            p.user_id, 
-            u.username AS author,
-            p.created_at
+// setPostTags replaces the tags of a post, creating tags that do not exist yet.
+func setPostTags(ctx context.Context, tx *sql.Tx, postID int, tags []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM post_tags WHERE post_id = $1`, postID); err != nil {
+		return fmt.Errorf("failed to clear post tags: %w", err)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO tags (name) SELECT unnest($1::text[])
+        ON CONFLICT (name) DO NOTHING
+    `, pq.Array(tags))
+	if err != nil {
+		return fmt.Errorf("failed to create tags: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO post_tags (post_id, tag_id)
+        SELECT $1, id FROM tags WHERE name = ANY($2)
+    `, postID, pq.Array(tags))
+	if err != nil {
+		return fmt.Errorf("failed to tag post: %w", err)
+	}
+	return nil
+}
+
+// postTagsColumn selects the sorted tag names of the post aliased as p.
+const postTagsColumn = `ARRAY(
+                    SELECT t.name FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+                    WHERE pt.post_id = p.id ORDER BY t.name
+                )`
+
+// postSortKeys maps a sort mode to its keyset column in the listing query: a posts
+// column (p), a comment aggregate (a) or the rank (k)
+// and its direction. Ties are broken by id in the same direction.
+var postSortKeys = map[entity.PostSort]struct {
+	column string
+	desc   bool
+}{
+	entity.PostSortNewest:         {"p.created_at", true},
+	entity.PostSortOldest:         {"p.created_at", false},
+	entity.PostSortMostCommented:  {"a.comment_count", true},
+	entity.PostSortRecentlyActive: {"a.last_activity_at", true},
+	entity.PostSortHot:            {"k.rank", true},
+	entity.PostSortTop:            {"k.rank", true},
+	entity.PostSortRising:         {"k.rank", true},
+	entity.PostSortMostViewed:     {"k.rank", true},
+}
+
+// postRankColumns maps ranked sorts to their precomputed post_rankings column.
+var postRankColumns = map[entity.PostSort]string{
+	entity.PostSortHot:    "r.hot",
+	entity.PostSortTop:    "r.top",
+	entity.PostSortRising: "r.rising",
+}
+
+func (p *Postgres) ListPosts(ctx context.Context, q entity.PostQuery) ([]*entity.Post, error) {
+	key, ok := postSortKeys[q.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort %q", q.Sort)
+	}
+
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	// Posts hidden by a moderator, in the trash or not yet published are left out of every listing
+	filters := []string{"p.hidden_at IS NULL", "p.deleted_at IS NULL", "p.status = 'published'"}
+	if q.Filter.CategoryID != 0 {
+		filters = append(filters, "p.category_id = "+arg(q.Filter.CategoryID))
+	}
+	if q.Filter.Tag != "" {
+		filters = append(filters, `EXISTS (
+                SELECT 1 FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+                WHERE pt.post_id = p.id AND t.name = `+arg(q.Filter.Tag)+`
+            )`)
+	}
+	if q.Filter.Author != "" {
+		filters = append(filters, "u.username = "+arg(q.Filter.Author))
+	}
+	if !q.Filter.From.IsZero() {
+		filters = append(filters, "p.created_at >= "+arg(q.Filter.From))
+	}
+	if !q.Filter.To.IsZero() {
+		filters = append(filters, "p.created_at < "+arg(q.Filter.To))
+	}
+	if window := q.Window.Duration(); q.Sort == entity.PostSortTop && window > 0 {
+		filters = append(filters, fmt.Sprintf("p.created_at >= NOW() - make_interval(secs => %s)", arg(window.Seconds())))
+	}
+	rank := "0"
+	if column, ok := postRankColumns[q.Sort]; ok {
+		rank = "COALESCE(" + column + ", 0)"
+	}
+	if q.Sort == entity.PostSortMostViewed {
+		rank = "p.view_count"
+		if window := q.Window.Duration(); window > 0 {
+			rank = fmt.Sprintf(`(SELECT COALESCE(SUM(v.views), 0) FROM post_views v
+                WHERE v.post_id = p.id AND v.hour >= date_trunc('hour', NOW() - make_interval(secs => %s)))`, arg(window.Seconds()))
+		}
+	}
+
+	dir, op := "ASC", ">"
+	if key.desc {
+		dir, op = "DESC", "<"
+	}
+	// Pinned posts come first in every order; a cursor among them continues with
+	// the rest of the pinned posts and then with all the others. The keyset is a
+	// plain filter, so a page sorted by date reads only the posts it returns.
+	if q.After != nil {
+		var value interface{} = q.After.Time
+		if q.Sort == entity.PostSortMostCommented {
+			value = q.After.Count
+		}
+		if q.Sort.Ranked() {
+			value = q.After.Rank
+		}
+		after := fmt.Sprintf("(%s, p.id) %s (%s, %s)", key.column, op, arg(value), arg(q.After.ID))
+		if q.After.Pinned {
+			filters = append(filters, fmt.Sprintf("(NOT p.pinned OR %s)", after))
+		} else {
+			filters = append(filters, "NOT p.pinned AND "+after)
+		}
+	}
+	where := "WHERE " + strings.Join(filters, " AND ")
+
+	// Comment counts come from a correlated subquery per post rather than from
+	// grouping all comments, so only the posts that pass the filters are counted
+	query := fmt.Sprintf(`
+        SELECT
+            p.id,
+            p.title,
+            p.content,
+            p.format,
+            p.content_html,
+            p.user_id,
+            p.category_id,
+            p.version,
+            p.score,
+            p.reactions,
+            %s AS tags,
+            COALESCE(u.username, '') AS author,
+            p.created_at,
+            p.updated_at,
+            p.view_count,
+            a.comment_count,
+            a.last_activity_at,
+            k.rank,
+            p.state,
+            p.pinned
         FROM posts p
         LEFT JOIN users u ON p.user_id = u.id
-        ORDER BY p.created_at DESC
-    `
-	rows, err := p.db.QueryContext(ctx, query)
+        LEFT JOIN post_rankings r ON r.post_id = p.id
+        CROSS JOIN LATERAL (
+            SELECT COUNT(*) AS comment_count,
+                   GREATEST(p.created_at, COALESCE(MAX(c.created_at), p.created_at)) AS last_activity_at
+            FROM comments c
+            WHERE c.post_id = p.id AND c.deleted_at IS NULL
+        ) a
+        CROSS JOIN LATERAL (SELECT %s::double precision AS rank) k
+        %s
+        ORDER BY p.pinned DESC, %s %s, p.id %s
+        LIMIT %s
+    `, postTagsColumn, rank, where, key.column, dir, dir, arg(q.Limit))
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		log.Printf("[ERROR] Repository: Failed to query posts: %v", err)
 		return nil, fmt.Errorf("failed to query posts: %w", err)
@@ -78,9 +292,23 @@ func (p *Postgres) GetAllPosts(ctx context.Context) ([]*entity.Post, error) {
 			&post.ID,
 			&post.Title,
 			&post.Content,
+			&post.Format,
+			&post.ContentHTML,
 			&post.UserID,
+			&post.CategoryID,
+			&post.Version,
+			&post.Score,
+			(*reactionCounts)(&post.Reactions),
+			pq.Array(&post.Tags),
 			&post.Author,
 			&post.CreatedAt,
+			&post.UpdatedAt,
+			&post.ViewCount,
+			&post.CommentCount,
+			&post.LastActivityAt,
+			&post.Rank,
+			&post.State,
+			&post.Pinned,
 		); err != nil {
 			log.Printf("[ERROR] Repository: Failed to scan post: %v", err)
 			return nil, fmt.Errorf("failed to scan post: %w", err)
@@ -92,41 +320,221 @@ func (p *Postgres) GetAllPosts(ctx context.Context) ([]*entity.Post, error) {
 		return nil, fmt.Errorf("rows error: %w", err)
 	}
 
-	log.Printf("[DEBUG] Repository: Successfully retrieved %d posts", len(posts))
 	return posts, nil
 }
 
+// postColumns selects a post aliased as p joined with its author aliased as u.
+const postColumns = `p.id, p.title, p.content, p.format, p.content_html, p.user_id, p.category_id, p.version, p.score, p.reactions,
+               ` + postTagsColumn + `, u.username, p.created_at, p.updated_at, p.view_count, p.state, p.pinned, p.status, p.publish_at`
+
+func scanPost(row scanner) (*entity.Post, error) {
+	var post entity.Post
+	var publishAt sql.NullTime
+	if err := row.Scan(
+		&post.ID,
+		&post.Title,
+		&post.Content,
+		&post.Format,
+		&post.ContentHTML,
+		&post.UserID,
+		&post.CategoryID,
+		&post.Version,
+		&post.Score,
+		(*reactionCounts)(&post.Reactions),
+		pq.Array(&post.Tags),
+		&post.Author,
+		&post.CreatedAt,
+		&post.UpdatedAt,
+		&post.ViewCount,
+		&post.State,
+		&post.Pinned,
+		&post.Status,
+		&publishAt,
+	); err != nil {
+		return nil, err
+	}
+	if publishAt.Valid {
+		post.PublishAt = &publishAt.Time
+	}
+	return &post, nil
+}
+
+// GetPostByID returns a post, drafts and scheduled posts included: the caller
+// decides who may see them. Hidden and deleted posts are not found.
 func (p *Postgres) GetPostByID(ctx context.Context, id int) (*entity.Post, error) {
 	query := `
-        SELECT p.id, p.title, p.content, p.user_id, u.username, p.created_at
+        SELECT ` + postColumns + `
         FROM posts p
         JOIN users u ON p.user_id = u.id
-        WHERE p.id = $1
+        WHERE p.id = $1 AND p.hidden_at IS NULL AND p.deleted_at IS NULL
     `
-	var post entity.Post
-	err := p.db.QueryRowContext(ctx, query, id).
-		Scan(
-			&post.ID,
-			&post.Title,
-			&post.Content,
-			&post.UserID,
-			&post.Author,
-			&post.CreatedAt,
-		)
+	post, err := scanPost(p.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get post by ID: %w", err)
 	}
-	return &post, nil
+	return post, nil
 }
 
-func (p *Postgres) DeletePost(ctx context.Context, id int) error {
-	query := `DELETE FROM posts WHERE id = $1`
-	_, err := p.db.ExecContext(ctx, query, id)
-	return err
+// GetPostStatus returns the state and pinned flag of a post. A missing, hidden,
+// deleted or unpublished post is a wrapped sql.ErrNoRows.
+func (p *Postgres) GetPostStatus(ctx context.Context, id int) (*entity.PostStatus, error) {
+	status := entity.PostStatus{PostID: id}
+	var userID sql.NullInt64
+	err := p.db.QueryRowContext(ctx, `
+        SELECT user_id, state, pinned FROM posts
+        WHERE id = $1 AND hidden_at IS NULL AND deleted_at IS NULL AND status = 'published'
+    `, id).Scan(&userID, &status.State, &status.Pinned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post status: %w", err)
+	}
+	status.UserID = int(userID.Int64)
+	return &status, nil
 }
 
-func (p *Postgres) UpdatePost(ctx context.Context, postID int, title, content string) error {
-	query := `UPDATE posts SET title = $1, content = $2 WHERE id = $3`
-	_, err := p.db.ExecContext(ctx, query, title, content, postID)
-	return err
+// DeletePost moves a post to the trash together with its comments, which come back
+// with it on restore. A post already in the trash is a wrapped sql.ErrNoRows.
+func (p *Postgres) DeletePost(ctx context.Context, id, deletedBy int) error {
+	return deletePost(ctx, p.db, id, &deletedBy)
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func deletePost(ctx context.Context, db execer, id int, deletedBy *int) error {
+	res, err := db.ExecContext(ctx, `
+        UPDATE posts SET deleted_at = NOW(), deleted_by = $2 WHERE id = $1 AND deleted_at IS NULL
+    `, id, deletedBy)
+	if err != nil {
+		return fmt.Errorf("failed to delete post: %w", err)
+	}
+	return requireAffected(res, "post")
+}
+
+// UpdatePost updates the title, content and its rendering, bumps the version and records a revision
+// by editorID when the title or content changes; tags are replaced only when not nil.
+// A non-zero update.Version must match the stored version, otherwise nothing is
+// written and a wrapped sql.ErrNoRows is returned, as for a missing post.
+func (p *Postgres) UpdatePost(ctx context.Context, postID, editorID int, update entity.PostUpdate) error {
+	return p.withTx(ctx, func(tx *sql.Tx) error {
+		var title, content string
+		var version int
+		err := tx.QueryRowContext(ctx, `SELECT title, content, version FROM posts WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, postID).
+			Scan(&title, &content, &version)
+		if err != nil {
+			return err
+		}
+		if update.Version != 0 && update.Version != version {
+			return fmt.Errorf("post %d is at version %d, not %d: %w", postID, version, update.Version, sql.ErrNoRows)
+		}
+
+		query := `UPDATE posts SET title = $1, content = $2, format = $3, content_html = $4, version = version + 1,
+                  updated_at = NOW() WHERE id = $5`
+		_, err = tx.ExecContext(ctx, query, update.Title, update.Content, update.Format, update.ContentHTML, postID)
+		if err != nil {
+			return err
+		}
+		if title != update.Title || content != update.Content {
+			err := insertPostRevision(ctx, tx, postID, editorID, update.Title, update.Content, update.Summary)
+			if err != nil {
+				return err
+			}
+		}
+		if update.Tags == nil {
+			return nil
+		}
+		return setPostTags(ctx, tx, postID, update.Tags)
+	})
+}
+
+// ListDrafts returns the drafts and scheduled posts of q.UserID, newest first.
+func (p *Postgres) ListDrafts(ctx context.Context, q entity.DraftQuery) ([]*entity.Post, error) {
+	args := []interface{}{q.UserID, q.Limit}
+	keyset := ""
+	if q.BeforeID > 0 {
+		args = append(args, q.BeforeID)
+		keyset = "AND p.id < $3"
+	}
+	rows, err := p.db.QueryContext(ctx, `
+        SELECT `+postColumns+`
+        FROM posts p
+        JOIN users u ON p.user_id = u.id
+        WHERE p.user_id = $1 AND p.status <> 'published' AND p.hidden_at IS NULL AND p.deleted_at IS NULL `+keyset+`
+        ORDER BY p.id DESC
+        LIMIT $2
+    `, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list drafts: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan draft: %w", err)
+		}
+		posts = append(posts, post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return posts, nil
+}
+
+// SetPostPublication publishes, schedules or unschedules an unpublished post. A
+// post published now takes the current time as its creation time, so it is listed
+// as new. A missing or already published post is a wrapped sql.ErrNoRows.
+func (p *Postgres) SetPostPublication(ctx context.Context, postID int, publication entity.PostPublication) error {
+	res, err := p.db.ExecContext(ctx, `
+        UPDATE posts
+        SET status = $2, publish_at = $3,
+            created_at = CASE WHEN $2 = 'published' THEN NOW() ELSE created_at END,
+            updated_at = CASE WHEN $2 = 'published' THEN NOW() ELSE updated_at END
+        WHERE id = $1 AND status <> 'published' AND deleted_at IS NULL
+    `, postID, publication.Status, publication.PublishAt)
+	if err != nil {
+		return fmt.Errorf("failed to set post publication: %w", err)
+	}
+	return requireAffected(res, "post")
+}
+
+// PublishDuePosts publishes up to limit scheduled posts whose time has come by now
+// and returns them. The posts are claimed and published in one statement, and rows
+// locked by a concurrent publisher are skipped, so every post is published once.
+// A published post is created and updated at its scheduled time.
+func (p *Postgres) PublishDuePosts(ctx context.Context, now time.Time, limit int) ([]*entity.Post, error) {
+	var posts []*entity.Post
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `
+            UPDATE posts p
+            SET status = 'published', publish_at = NULL, created_at = due.publish_at, updated_at = due.publish_at
+            FROM (
+                SELECT id, publish_at FROM posts
+                WHERE status = 'scheduled' AND publish_at <= $1 AND deleted_at IS NULL
+                ORDER BY publish_at
+                LIMIT $2
+                FOR UPDATE SKIP LOCKED
+            ) AS due
+            WHERE p.id = due.id
+            RETURNING p.id, p.title, p.content, p.user_id, p.category_id, p.created_at, p.updated_at
+        `, now, limit)
+		if err != nil {
+			return fmt.Errorf("failed to publish scheduled posts: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			post := entity.Post{Status: entity.PostPublished}
+			if err := rows.Scan(&post.ID, &post.Title, &post.Content, &post.UserID, &post.CategoryID, &post.CreatedAt, &post.UpdatedAt); err != nil {
+				return fmt.Errorf("failed to scan published post: %w", err)
+			}
+			posts = append(posts, &post)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
 }
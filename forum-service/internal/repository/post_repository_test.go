@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"testing"
 	"time"
@@ -39,17 +40,34 @@ func TestPostgresCreatePost(t *testing.T) {
 	require.NoError(t, err, "Failed to get user ID")
 
 	post := &entity.Post{
-		Title:   "Test Post",
-		Content: "This is a test post",
-		UserID:  userID,
+		Title:       "Test Post",
+		Content:     "This is a **test** post",
+		Format:      entity.FormatMarkdown,
+		ContentHTML: "<p>This is a <strong>test</strong> post</p>\n",
+		UserID:      userID,
+		CategoryID:  1,
 	}
 
 	err = repo.CreatePost(ctx, post)
 	assert.NoError(t, err)
 	assert.NotZero(t, post.ID)
+
+	// Формат и отрисованный HTML сохраняются вместе с исходным текстом
+	got, err := repo.GetPostByID(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.FormatMarkdown, got.Format)
+	assert.Equal(t, post.ContentHTML, got.ContentHTML)
+	assert.WithinDuration(t, got.CreatedAt, got.UpdatedAt, time.Second)
+
+	// Правка сдвигает время обновления
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, repo.UpdatePost(ctx, post.ID, userID, entity.PostUpdate{Title: "Edited", Content: "Text", Format: entity.FormatPlain, Version: got.Version}))
+	edited, err := repo.GetPostByID(ctx, post.ID)
+	require.NoError(t, err)
+	assert.True(t, edited.UpdatedAt.After(got.UpdatedAt))
 }
 
-func TestPostgresGetAllPosts(t *testing.T) {
+func TestPostgresListPosts(t *testing.T) {
 	repo, err := setupTestDB()
 	require.NoError(t, err, "Failed to setup test database")
 
@@ -72,19 +90,56 @@ func TestPostgresGetAllPosts(t *testing.T) {
 	err = repo.db.QueryRowContext(ctx, "SELECT id FROM users WHERE username = $1", username).Scan(&userID)
 	require.NoError(t, err, "Failed to get user ID")
 
-	// Вставляем тестовый пост
+	// Вставляем три поста с разным временем создания, у первого - комментарий
+	var postIDs []int
+	for i, age := range []string{"3 hours", "2 hours", "1 hour"} {
+		var id int
+		err = repo.db.QueryRowContext(ctx, `
+            INSERT INTO posts (title, content, user_id, category_id, created_at)
+            VALUES ($1, 'This is a test post', $2, 1, NOW() - $3::interval)
+            RETURNING id
+        `, fmt.Sprintf("Test Post %d", i), userID, age).Scan(&id)
+		require.NoError(t, err, "Failed to insert test post")
+		postIDs = append(postIDs, id)
+	}
 	_, err = repo.db.ExecContext(ctx, `
-        INSERT INTO posts (title, content, user_id, created_at)
-        VALUES ('Test Post', 'This is a test post', $1, NOW())
-    `, userID)
-	require.NoError(t, err, "Failed to insert test post")
+        INSERT INTO comments (content, post_id, user_id) VALUES ('Comment', $1, $2)
+    `, postIDs[0], userID)
+	require.NoError(t, err, "Failed to insert test comment")
 
-	// Тестируем получение постов
-	posts, err := repo.GetAllPosts(ctx)
+	// Первая страница по убыванию даты
+	posts, err := repo.ListPosts(ctx, entity.PostQuery{Sort: entity.PostSortNewest, Limit: 2})
 	assert.NoError(t, err)
-	assert.NotEmpty(t, posts)
-	assert.Equal(t, "Test Post", posts[0].Title)
+	require.Len(t, posts, 2)
+	assert.Equal(t, postIDs[2], posts[0].ID)
+	assert.Equal(t, postIDs[1], posts[1].ID)
 	assert.Equal(t, username, posts[0].Author)
+
+	// Следующая страница начинается после последнего поста
+	posts, err = repo.ListPosts(ctx, entity.PostQuery{
+		Sort:  entity.PostSortNewest,
+		After: &entity.PostCursor{Sort: entity.PostSortNewest, Time: posts[1].CreatedAt, ID: posts[1].ID},
+		Limit: 2,
+	})
+	assert.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, postIDs[0], posts[0].ID)
+
+	// Самый комментируемый пост идет первым
+	posts, err = repo.ListPosts(ctx, entity.PostQuery{Sort: entity.PostSortMostCommented, Limit: 1})
+	assert.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, postIDs[0], posts[0].ID)
+	assert.Equal(t, 1, posts[0].CommentCount)
+
+	// Фильтр по автору
+	posts, err = repo.ListPosts(ctx, entity.PostQuery{
+		Sort:   entity.PostSortOldest,
+		Filter: entity.PostFilter{Author: "nobody_" + username},
+		Limit:  10,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, posts)
 }
 
 func TestPostgresGetPostByID(t *testing.T) {
@@ -113,8 +168,8 @@ func TestPostgresGetPostByID(t *testing.T) {
 	// Вставляем тестовый пост
 	postTitle := fmt.Sprintf("Test Post %d", timestamp)
 	_, err = repo.db.ExecContext(ctx, `
-        INSERT INTO posts (title, content, user_id)
-        VALUES ($1, 'Content', $2)
+        INSERT INTO posts (title, content, user_id, category_id)
+        VALUES ($1, 'Content', $2, 1)
     `, postTitle, userID)
 	require.NoError(t, err, "Failed to insert test post")
 
@@ -155,8 +210,8 @@ func TestPostgresDeletePost(t *testing.T) {
 	// Создаем тестовый пост
 	postTitle := fmt.Sprintf("Test Post %d", timestamp)
 	_, err = repo.db.ExecContext(ctx, `
-        INSERT INTO posts (title, content, user_id)
-        VALUES ($1, 'This is a test post', $2)
+        INSERT INTO posts (title, content, user_id, category_id)
+        VALUES ($1, 'This is a test post', $2, 1)
     `, postTitle, userID)
 	require.NoError(t, err, "Failed to insert test post")
 
@@ -165,13 +220,88 @@ func TestPostgresDeletePost(t *testing.T) {
 	err = repo.db.QueryRowContext(ctx, "SELECT id FROM posts WHERE title = $1", postTitle).Scan(&postID)
 	require.NoError(t, err, "Failed to get post ID")
 
+	// Комментарий уходит в корзину вместе с постом
+	_, err = repo.db.ExecContext(ctx, `
+        INSERT INTO comments (content, post_id, user_id) VALUES ('Comment', $1, $2)
+    `, postID, userID)
+	require.NoError(t, err, "Failed to insert test comment")
+
 	// Тестируем удаление
-	err = repo.DeletePost(ctx, postID)
+	err = repo.DeletePost(ctx, postID, userID)
 	assert.NoError(t, err)
+	assert.ErrorIs(t, repo.DeletePost(ctx, postID, userID), sql.ErrNoRows)
 
-	// Проверяем, что пост действительно удален
-	var count int
-	err = repo.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM posts WHERE id = $1", postID).Scan(&count)
+	// Пост остается в таблице, но не читается
+	_, err = repo.GetPostByID(ctx, postID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	var deletedBy int
+	err = repo.db.QueryRowContext(ctx, "SELECT deleted_by FROM posts WHERE id = $1 AND deleted_at IS NOT NULL", postID).Scan(&deletedBy)
 	assert.NoError(t, err)
-	assert.Equal(t, 0, count)
+	assert.Equal(t, userID, deletedBy)
+	comments, err := repo.GetCommentsByPostID(ctx, postID)
+	assert.NoError(t, err)
+	assert.Empty(t, comments)
+}
+
+func TestPostgresDrafts(t *testing.T) {
+	repo, err := setupTestDB()
+	require.NoError(t, err, "Failed to setup test database")
+
+	ctx := context.Background()
+	require.NoError(t, setupTestData(ctx, repo))
+
+	draft := &entity.Post{Title: "Draft", Content: "Later", UserID: 1, CategoryID: 1, Status: entity.PostDraft}
+	require.NoError(t, repo.CreatePost(ctx, draft))
+	publishAt := time.Now().Add(time.Hour)
+	scheduled := &entity.Post{Title: "Scheduled", Content: "Soon", UserID: 1, CategoryID: 1, Status: entity.PostScheduled, PublishAt: &publishAt}
+	require.NoError(t, repo.CreatePost(ctx, scheduled))
+
+	// Неопубликованные посты не попадают в общую ленту, но читаются по ID
+	posts, err := repo.ListPosts(ctx, entity.PostQuery{Sort: entity.PostSortNewest, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, 1, posts[0].ID)
+	got, err := repo.GetPostByID(ctx, draft.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PostDraft, got.Status)
+	assert.Nil(t, got.PublishAt)
+
+	// Черновики автора, новые первыми, с курсором по ID
+	drafts, err := repo.ListDrafts(ctx, entity.DraftQuery{UserID: 1, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, drafts, 2)
+	assert.Equal(t, scheduled.ID, drafts[0].ID)
+	require.NotNil(t, drafts[0].PublishAt)
+	drafts, err = repo.ListDrafts(ctx, entity.DraftQuery{UserID: 1, BeforeID: scheduled.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, drafts, 1)
+	assert.Equal(t, draft.ID, drafts[0].ID)
+
+	// Публикация черновика; повторная публикация не проходит
+	publish := entity.PostPublication{Status: entity.PostPublished}
+	require.NoError(t, repo.SetPostPublication(ctx, draft.ID, publish))
+	assert.ErrorIs(t, repo.SetPostPublication(ctx, draft.ID, publish), sql.ErrNoRows)
+	posts, err = repo.ListPosts(ctx, entity.PostQuery{Sort: entity.PostSortNewest, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, draft.ID, posts[0].ID)
+
+	// Планировщик публикует только наступившие посты
+	published, err := repo.PublishDuePosts(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, published)
+	published, err = repo.PublishDuePosts(ctx, publishAt.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, published, 1)
+	assert.Equal(t, scheduled.ID, published[0].ID)
+	assert.WithinDuration(t, publishAt, published[0].CreatedAt, time.Second)
+	assert.WithinDuration(t, publishAt, published[0].UpdatedAt, time.Second)
+	got, err = repo.GetPostByID(ctx, scheduled.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PostPublished, got.Status)
+	assert.Nil(t, got.PublishAt)
+
+	drafts, err = repo.ListDrafts(ctx, entity.DraftQuery{UserID: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, drafts)
 }
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+type PostRevisionRepository interface {
+	ListPostRevisions(ctx context.Context, postID int) ([]entity.PostRevision, error)
+	GetPostRevision(ctx context.Context, postID, rev int) (*entity.PostRevision, error)
+}
+
+// insertPostRevision appends the next revision of a post. Callers hold the post row
+// (new or locked FOR UPDATE), so revision numbers cannot race.
+func insertPostRevision(ctx context.Context, tx *sql.Tx, postID, editorID int, title, content, summary string) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO post_revisions (post_id, rev, title, content, editor_id, summary)
+        SELECT $1, COALESCE(MAX(rev), 0) + 1, $2, $3, NULLIF($4, 0), $5
+        FROM post_revisions WHERE post_id = $1
+    `, postID, title, content, editorID, summary)
+	if err != nil {
+		return fmt.Errorf("failed to save post revision: %w", err)
+	}
+	return nil
+}
+
+// ListPostRevisions returns the revisions of a post without their content, newest first.
+// The history of a draft or scheduled post is not shown until it is published, nor
+// that of a post hidden by a moderator or in the trash.
+func (p *Postgres) ListPostRevisions(ctx context.Context, postID int) ([]entity.PostRevision, error) {
+	rows, err := p.db.QueryContext(ctx, `
+        SELECT r.id, r.post_id, r.rev, r.title, r.editor_id, COALESCE(u.username, ''), r.summary, r.created_at
+        FROM post_revisions r
+        JOIN posts p ON p.id = r.post_id AND p.status = 'published' AND p.deleted_at IS NULL AND p.hidden_at IS NULL
+        LEFT JOIN users u ON r.editor_id = u.id
+        WHERE r.post_id = $1
+        ORDER BY r.rev DESC
+    `, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revisions for post %d: %w", postID, err)
+	}
+	defer rows.Close()
+
+	revisions := []entity.PostRevision{}
+	for rows.Next() {
+		var rev entity.PostRevision
+		var editorID sql.NullInt64
+		err := rows.Scan(&rev.ID, &rev.PostID, &rev.Rev, &rev.Title, &editorID, &rev.Editor, &rev.Summary, &rev.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post revision: %w", err)
+		}
+		rev.EditorID = nullIntPtr(editorID)
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return revisions, nil
+}
+
+// GetPostRevision returns revision rev of a published post with its content. Hidden
+// posts and posts in the trash have no revisions to show.
+func (p *Postgres) GetPostRevision(ctx context.Context, postID, rev int) (*entity.PostRevision, error) {
+	var revision entity.PostRevision
+	var editorID sql.NullInt64
+	err := p.db.QueryRowContext(ctx, `
+        SELECT r.id, r.post_id, r.rev, r.title, r.content, r.editor_id, COALESCE(u.username, ''), r.summary, r.created_at
+        FROM post_revisions r
+        JOIN posts p ON p.id = r.post_id AND p.status = 'published' AND p.deleted_at IS NULL AND p.hidden_at IS NULL
+        LEFT JOIN users u ON r.editor_id = u.id
+        WHERE r.post_id = $1 AND r.rev = $2
+    `, postID, rev).Scan(
+		&revision.ID,
+		&revision.PostID,
+		&revision.Rev,
+		&revision.Title,
+		&revision.Content,
+		&editorID,
+		&revision.Editor,
+		&revision.Summary,
+		&revision.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision %d of post %d: %w", rev, postID, err)
+	}
+	revision.EditorID = nullIntPtr(editorID)
+	return &revision, nil
+}
+
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	id := int(v.Int64)
+	return &id
+}
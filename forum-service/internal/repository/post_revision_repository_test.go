@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresPostRevisions(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Устанавливаем тестовые данные
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+
+	post := &entity.Post{Title: "First", Content: "one", UserID: 1, CategoryID: 1}
+	require.NoError(t, repo.CreatePost(ctx, post))
+
+	require.NoError(t, repo.UpdatePost(ctx, post.ID, 1, entity.PostUpdate{Title: "First", Content: "two", Summary: "fix"}))
+	// Правка только меток не создает версию
+	require.NoError(t, repo.UpdatePost(ctx, post.ID, 1, entity.PostUpdate{Title: "First", Content: "two", Tags: []string{"go"}}))
+
+	revisions, err := repo.ListPostRevisions(ctx, post.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 2, revisions[0].Rev)
+	assert.Equal(t, "fix", revisions[0].Summary)
+	assert.Equal(t, "testuser", revisions[0].Editor)
+	assert.Empty(t, revisions[0].Content)
+
+	first, err := repo.GetPostRevision(ctx, post.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "one", first.Content)
+
+	_, err = repo.GetPostRevision(ctx, post.ID, 3)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Каждая правка повышает версию; правка устаревшей версии не записывается
+	stored, err := repo.GetPostByID(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored.Version)
+	err = repo.UpdatePost(ctx, post.ID, 1, entity.PostUpdate{Title: "Stale", Content: "stale", Version: 2})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	require.NoError(t, repo.UpdatePost(ctx, post.ID, 1, entity.PostUpdate{Title: "Fresh", Content: "fresh", Version: 3}))
+	stored, err = repo.GetPostByID(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Fresh", stored.Title)
+	assert.Equal(t, 4, stored.Version)
+
+	// История скрытого модератором поста не показывается
+	_, err = repo.db.ExecContext(ctx, `UPDATE posts SET hidden_at = NOW() WHERE id = $1`, post.ID)
+	require.NoError(t, err)
+	_, err = repo.GetPostRevision(ctx, post.ID, 1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	revisions, err = repo.ListPostRevisions(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+	_, err = repo.db.ExecContext(ctx, `UPDATE posts SET hidden_at = NULL WHERE id = $1`, post.ID)
+	require.NoError(t, err)
+
+	// История поста в корзине не показывается
+	require.NoError(t, repo.DeletePost(ctx, post.ID, 1))
+	_, err = repo.GetPostRevision(ctx, post.ID, 1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	revisions, err = repo.ListPostRevisions(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+}
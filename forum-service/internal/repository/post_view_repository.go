@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lib/pq"
+)
+
+type PostViewRepository interface {
+	AddPostViews(ctx context.Context, views []entity.PostViews) error
+	PrunePostViews(ctx context.Context, before time.Time) (int64, error)
+}
+
+// AddPostViews adds a batch of buffered views to the post totals and their hourly
+// counts in one transaction. Views of posts deleted since are dropped.
+func (p *Postgres) AddPostViews(ctx context.Context, views []entity.PostViews) error {
+	if len(views) == 0 {
+		return nil
+	}
+	postIDs := make([]int64, len(views))
+	hours := make([]int64, len(views))
+	counts := make([]int64, len(views))
+	for i, v := range views {
+		postIDs[i] = int64(v.PostID)
+		hours[i] = v.Hour.Unix()
+		counts[i] = int64(v.Views)
+	}
+
+	return p.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `
+            INSERT INTO post_views (post_id, hour, views)
+            SELECT b.post_id, to_timestamp(b.hour), SUM(b.views)
+            FROM unnest($1::int[], $2::bigint[], $3::int[]) AS b(post_id, hour, views)
+            JOIN posts p ON p.id = b.post_id
+            GROUP BY b.post_id, b.hour
+            ON CONFLICT (post_id, hour) DO UPDATE SET views = post_views.views + EXCLUDED.views
+        `, pq.Array(postIDs), pq.Array(hours), pq.Array(counts)); err != nil {
+			return fmt.Errorf("failed to add hourly post views: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+            UPDATE posts p SET view_count = p.view_count + b.views
+            FROM (
+                SELECT post_id, SUM(views) AS views
+                FROM unnest($1::int[], $2::int[]) AS v(post_id, views)
+                GROUP BY post_id
+            ) AS b
+            WHERE p.id = b.post_id
+        `, pq.Array(postIDs), pq.Array(counts)); err != nil {
+			return fmt.Errorf("failed to add post view counts: %w", err)
+		}
+		return nil
+	})
+}
+
+// PrunePostViews deletes hourly counts older than before. Post totals are kept.
+func (p *Postgres) PrunePostViews(ctx context.Context, before time.Time) (int64, error) {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM post_views WHERE hour < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune post views: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresPostViews(t *testing.T) {
+	repo, err := setupTestDB()
+	require.NoError(t, err, "Failed to setup test database")
+
+	ctx := context.Background()
+	require.NoError(t, setupTestData(ctx, repo))
+
+	other := &entity.Post{Title: "Other", Content: "Text", UserID: 1, CategoryID: 1}
+	require.NoError(t, repo.CreatePost(ctx, other))
+
+	// Пост 1 много смотрели неделю назад, второй пост - сейчас
+	hour := time.Now().Truncate(time.Hour)
+	weekAgo := hour.Add(-6 * 24 * time.Hour)
+	require.NoError(t, repo.AddPostViews(ctx, []entity.PostViews{
+		{PostID: 1, Hour: weekAgo, Views: 10},
+		{PostID: other.ID, Hour: hour, Views: 2},
+		{PostID: other.ID, Hour: hour, Views: 1},
+		{PostID: 999, Hour: hour, Views: 5}, // удаленный пост пропускается
+	}))
+
+	post, err := repo.GetPostByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 10, post.ViewCount)
+	post, err = repo.GetPostByID(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, post.ViewCount)
+
+	// За день лидирует второй пост, за неделю и за все время - первый
+	posts, err := repo.ListPosts(ctx, entity.PostQuery{Sort: entity.PostSortMostViewed, Window: entity.TopWindowDay, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, other.ID, posts[0].ID)
+	assert.Equal(t, 3.0, posts[0].Rank)
+	assert.Zero(t, posts[1].Rank)
+
+	posts, err = repo.ListPosts(ctx, entity.PostQuery{Sort: entity.PostSortMostViewed, Window: entity.TopWindowWeek, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, 1, posts[0].ID)
+
+	posts, err = repo.ListPosts(ctx, entity.PostQuery{
+		Sort:   entity.PostSortMostViewed,
+		Window: entity.TopWindowAll,
+		After:  &entity.PostCursor{Sort: entity.PostSortMostViewed, Window: entity.TopWindowAll, Rank: 10, ID: 1},
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, other.ID, posts[0].ID)
+
+	// Очистка удаляет почасовые счетчики, но не итог поста
+	pruned, err := repo.PrunePostViews(ctx, hour.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+	post, err = repo.GetPostByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 10, post.ViewCount)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+type SearchRepository interface {
+	Search(ctx context.Context, q entity.SearchQuery) ([]*entity.SearchResult, error)
+}
+
+// ts_headline marks matches with private-use runes instead of HTML tags so that
+// the snippet can be escaped before the markers are turned into <mark>.
+const (
+	highlightStart = "\uE000"
+	highlightStop  = "\uE001"
+	headlineOpts   = "StartSel=" + highlightStart + ", StopSel=" + highlightStop +
+		", MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" … \""
+)
+
+func (p *Postgres) Search(ctx context.Context, q entity.SearchQuery) ([]*entity.SearchResult, error) {
+	args := []interface{}{q.Query}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	// The same author and date filters apply to both branches of the union.
+	filters := func(alias string) string {
+		var conds []string
+		if q.Filter.Author != "" {
+			conds = append(conds, "u.username = "+arg(q.Filter.Author))
+		}
+		if !q.Filter.From.IsZero() {
+			conds = append(conds, alias+".created_at >= "+arg(q.Filter.From))
+		}
+		if !q.Filter.To.IsZero() {
+			conds = append(conds, alias+".created_at < "+arg(q.Filter.To))
+		}
+		if len(conds) == 0 {
+			return ""
+		}
+		return " AND " + strings.Join(conds, " AND ")
+	}
+
+	var branches []string
+	if q.Type != entity.SearchTypeComment {
+		branches = append(branches, `
+            SELECT 'post' AS type, p.id AS post_id, 0 AS comment_id, p.title, p.content AS body,
+                   p.user_id, COALESCE(u.username, '') AS author, p.created_at,
+                   ts_rank(p.search_vector, q.query) AS rank
+            FROM posts p
+            CROSS JOIN q
+            LEFT JOIN users u ON u.id = p.user_id
+            WHERE p.search_vector @@ q.query AND p.hidden_at IS NULL AND p.deleted_at IS NULL AND p.status = 'published'`+filters("p"))
+	}
+	if q.Type != entity.SearchTypePost {
+		branches = append(branches, `
+            SELECT 'comment' AS type, c.post_id, c.id AS comment_id, p.title, c.content AS body,
+                   c.user_id, COALESCE(u.username, '') AS author, c.created_at,
+                   ts_rank(c.search_vector, q.query) AS rank
+            FROM comments c
+            JOIN posts p ON p.id = c.post_id
+            CROSS JOIN q
+            LEFT JOIN users u ON u.id = c.user_id
+            WHERE c.search_vector @@ q.query AND c.hidden_at IS NULL AND c.deleted_at IS NULL
+              AND p.hidden_at IS NULL AND p.deleted_at IS NULL AND p.status = 'published'`+filters("c"))
+	}
+
+	// ts_headline is expensive, so it only runs for the rows of the requested page.
+	query := fmt.Sprintf(`
+        WITH q AS (SELECT websearch_to_tsquery('russian', $1) AS query),
+        matches AS (
+            SELECT * FROM (%s) AS found
+            ORDER BY rank DESC, created_at DESC, post_id DESC, comment_id DESC
+            LIMIT %s OFFSET %s
+        )
+        SELECT m.type, m.post_id, m.comment_id, m.title,
+               ts_headline('russian', m.body, q.query, %s),
+               m.user_id, m.author, m.created_at, m.rank
+        FROM matches m
+        CROSS JOIN q
+        ORDER BY m.rank DESC, m.created_at DESC, m.post_id DESC, m.comment_id DESC
+    `, strings.Join(branches, "\n            UNION ALL"), arg(q.Limit), arg(q.Offset), arg(headlineOpts))
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*entity.SearchResult
+	for rows.Next() {
+		var r entity.SearchResult
+		if err := rows.Scan(
+			&r.Type,
+			&r.PostID,
+			&r.CommentID,
+			&r.Title,
+			&r.Snippet,
+			&r.UserID,
+			&r.Author,
+			&r.CreatedAt,
+			&r.Rank,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		r.Snippet = highlight(r.Snippet)
+		results = append(results, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return results, nil
+}
+
+// highlight escapes a ts_headline fragment and replaces the match markers with <mark>.
+func highlight(snippet string) string {
+	return strings.NewReplacer(
+		highlightStart, "<mark>",
+		highlightStop, "</mark>",
+	).Replace(html.EscapeString(snippet))
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighlight(t *testing.T) {
+	snippet := "use <script>" + highlightStart + "goroutines" + highlightStop + " & channels"
+	assert.Equal(t, "use &lt;script&gt;<mark>goroutines</mark> &amp; channels", highlight(snippet))
+}
+
+func TestPostgresSearch(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Устанавливаем тестовые данные
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+
+	_, err = repo.db.ExecContext(ctx, `
+		INSERT INTO posts (id, title, content, user_id, category_id)
+		VALUES (2, 'Горутины в Go', 'Как работают каналы', 1, 1);
+		INSERT INTO comments (content, post_id, user_id)
+		VALUES ('Каналы лучше мьютексов', 2, 1);
+	`)
+	if err != nil {
+		t.Fatalf("не удалось создать тестовые данные: %v", err)
+	}
+
+	results, err := repo.Search(ctx, entity.SearchQuery{Query: "канал", Type: entity.SearchTypeAll, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.Equal(t, 2, r.PostID)
+		assert.Contains(t, r.Snippet, "<mark>")
+	}
+
+	results, err = repo.Search(ctx, entity.SearchQuery{Query: "канал", Type: entity.SearchTypeComment, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, entity.SearchTypeComment, results[0].Type)
+
+	results, err = repo.Search(ctx, entity.SearchQuery{
+		Query:  "канал",
+		Type:   entity.SearchTypeAll,
+		Filter: entity.PostFilter{Author: "nobody"},
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
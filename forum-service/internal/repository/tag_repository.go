@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+type TagRepository interface {
+	ListTags(ctx context.Context, prefix string, limit int) ([]*entity.Tag, error)
+	GetTagByName(ctx context.Context, name string) (*entity.Tag, error)
+	RenameTag(ctx context.Context, from, to string) (*entity.Tag, error)
+}
+
+// likeEscaper escapes the LIKE wildcards; tag names may contain '_'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ListTags returns tags that start with prefix and are used by at least one published
+// post outside the trash, most used first.
+func (p *Postgres) ListTags(ctx context.Context, prefix string, limit int) ([]*entity.Tag, error) {
+	query := `
+        SELECT t.id, t.name, COUNT(pt.post_id) AS post_count
+        FROM tags t
+        JOIN post_tags pt ON pt.tag_id = t.id
+        JOIN posts p ON p.id = pt.post_id AND p.deleted_at IS NULL AND p.status = 'published'
+        WHERE t.name LIKE $1 || '%'
+        GROUP BY t.id
+        ORDER BY post_count DESC, t.name
+        LIMIT $2
+    `
+	rows, err := p.db.QueryContext(ctx, query, likeEscaper.Replace(prefix), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []*entity.Tag
+	for rows.Next() {
+		var tag entity.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.PostCount); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, &tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return tags, nil
+}
+
+func (p *Postgres) GetTagByName(ctx context.Context, name string) (*entity.Tag, error) {
+	return getTagByName(ctx, p.db, name)
+}
+
+// queryRower is implemented by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func getTagByName(ctx context.Context, q queryRower, name string) (*entity.Tag, error) {
+	query := `
+        SELECT t.id, t.name, (SELECT COUNT(*) FROM post_tags pt WHERE pt.tag_id = t.id)
+        FROM tags t
+        WHERE t.name = $1
+    `
+	var tag entity.Tag
+	if err := q.QueryRowContext(ctx, query, name).Scan(&tag.ID, &tag.Name, &tag.PostCount); err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return &tag, nil
+}
+
+// RenameTag renames tag from to to. If a tag named to already exists, from is merged
+// into it: its posts are retagged and from is deleted.
+func (p *Postgres) RenameTag(ctx context.Context, from, to string) (*entity.Tag, error) {
+	var result *entity.Tag
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		source, err := getTagByName(ctx, tx, from)
+		if err != nil {
+			return err
+		}
+
+		target, err := getTagByName(ctx, tx, to)
+		if errors.Is(err, sql.ErrNoRows) {
+			if _, err := tx.ExecContext(ctx, `UPDATE tags SET name = $1 WHERE id = $2`, to, source.ID); err != nil {
+				return fmt.Errorf("failed to rename tag: %w", err)
+			}
+			source.Name = to
+			result = source
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO post_tags (post_id, tag_id)
+            SELECT post_id, $1 FROM post_tags WHERE tag_id = $2
+            ON CONFLICT DO NOTHING
+        `, target.ID, source.ID)
+		if err != nil {
+			return fmt.Errorf("failed to merge tags: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, source.ID); err != nil {
+			return fmt.Errorf("failed to delete merged tag: %w", err)
+		}
+
+		result, err = getTagByName(ctx, tx, to)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
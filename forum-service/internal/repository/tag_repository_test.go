@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresTags(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Устанавливаем тестовые данные
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+
+	first := &entity.Post{Title: "First", Content: "Content", UserID: 1, CategoryID: 1, Tags: []string{"go", "golang"}}
+	require.NoError(t, repo.CreatePost(ctx, first))
+	second := &entity.Post{Title: "Second", Content: "Content", UserID: 1, CategoryID: 1, Tags: []string{"go_web"}}
+	require.NoError(t, repo.CreatePost(ctx, second))
+
+	post, err := repo.GetPostByID(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "golang"}, post.Tags)
+
+	// '_' в префиксе не должен работать как шаблон LIKE
+	tags, err := repo.ListTags(ctx, "go_", 10)
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "go_web", tags[0].Name)
+
+	posts, err := repo.ListPosts(ctx, entity.PostQuery{
+		Sort:   entity.PostSortNewest,
+		Filter: entity.PostFilter{Tag: "go_web"},
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, second.ID, posts[0].ID)
+
+	// nil оставляет метки без изменений, пустой срез снимает их
+	require.NoError(t, repo.UpdatePost(ctx, second.ID, 1, entity.PostUpdate{Title: "Second", Content: "Updated"}))
+	post, err = repo.GetPostByID(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go_web"}, post.Tags)
+
+	require.NoError(t, repo.UpdatePost(ctx, second.ID, 1, entity.PostUpdate{Title: "Second", Content: "Updated", Tags: []string{"golang"}}))
+
+	// golang сливается с go: у первого поста остается одна метка
+	merged, err := repo.RenameTag(ctx, "golang", "go")
+	require.NoError(t, err)
+	assert.Equal(t, "go", merged.Name)
+	assert.Equal(t, 2, merged.PostCount)
+
+	post, err = repo.GetPostByID(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, post.Tags)
+
+	renamed, err := repo.RenameTag(ctx, "go", "golang")
+	require.NoError(t, err)
+	assert.Equal(t, "golang", renamed.Name)
+
+	_, err = repo.RenameTag(ctx, "missing", "go")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
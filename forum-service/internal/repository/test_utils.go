@@ -5,6 +5,23 @@ import (
 	"fmt"
 )
 
+// authUsersSchema - таблица users в том виде, в каком ее создают миграции
+// auth-service. Она нужна до миграций forum-service, которые на нее ссылаются.
+const authUsersSchema = `
+	CREATE TABLE IF NOT EXISTS users (
+		id SERIAL PRIMARY KEY,
+		username VARCHAR(50) NOT NULL UNIQUE,
+		email VARCHAR(100) NOT NULL UNIQUE,
+		password_hash VARCHAR(255) NOT NULL,
+		role VARCHAR(20) NOT NULL DEFAULT 'user',
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+`
+
+// setupTestDB пересоздает схему тестовой базы: сначала таблица users
+// auth-service, затем все миграции forum-service. Миграция 005 создает раздел
+// general, в пустой схеме он получает id 1.
 func setupTestDB() (*Postgres, error) {
 	// Строка подключения к тестовой базе данных
 	connStr := "user=postgres dbname=PG sslmode=disable password=postgres"
@@ -13,50 +30,20 @@ func setupTestDB() (*Postgres, error) {
 		return nil, fmt.Errorf("не удалось открыть базу данных: %v", err)
 	}
 
-	// Создаем необходимые таблицы, если они не существуют
-	_, err = db.Exec(`
-		-- Сначала удаляем зависимые таблицы
-		DROP TABLE IF EXISTS comments CASCADE;
-		DROP TABLE IF EXISTS chat_messages CASCADE;
-		DROP TABLE IF EXISTS posts CASCADE;
-		DROP TABLE IF EXISTS users CASCADE;
-
-		-- Теперь создаем таблицы в правильном порядке
-		CREATE TABLE IF NOT EXISTS users (
-			id SERIAL PRIMARY KEY,
-			username VARCHAR(255) NOT NULL,
-			email VARCHAR(255) NOT NULL UNIQUE,
-			password_hash VARCHAR(255) NOT NULL,
-			role VARCHAR(50) DEFAULT 'user'
-		);
-
-		CREATE TABLE IF NOT EXISTS posts (
-			id SERIAL PRIMARY KEY,
-			title VARCHAR(255) NOT NULL,
-			content TEXT NOT NULL,
-			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		);
-
-		CREATE TABLE IF NOT EXISTS comments (
-			id SERIAL PRIMARY KEY,
-			content TEXT NOT NULL,
-			post_id INTEGER REFERENCES posts(id) ON DELETE CASCADE,
-			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		);
-
-		CREATE TABLE IF NOT EXISTS chat_messages (
-			id SERIAL PRIMARY KEY,
-			user_id INTEGER,
-			author VARCHAR(255),
-			text TEXT,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		);
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("не удалось создать таблицы: %v", err)
+	// Начинаем с пустой схемы, чтобы миграции применялись с нуля
+	if _, err := db.Exec(`DROP SCHEMA IF EXISTS public CASCADE; CREATE SCHEMA public;`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("не удалось очистить схему: %v", err)
+	}
+	if _, err := db.Exec(authUsersSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("не удалось создать таблицу users: %v", err)
 	}
 
-	return &Postgres{db: db}, nil
+	repo := &Postgres{db: db}
+	if err := repo.RunMigrations(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("не удалось применить миграции: %v", err)
+	}
+	return repo, nil
 }
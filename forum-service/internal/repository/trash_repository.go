@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+type TrashRepository interface {
+	ListTrash(ctx context.Context, q entity.TrashQuery) ([]*entity.TrashItem, error)
+	RestoreFromTrash(ctx context.Context, entry *entity.ModerationLogEntry) error
+	PurgeDeleted(ctx context.Context, before time.Time) (entity.PurgeResult, error)
+}
+
+// ListTrash returns deleted posts and comments, most recently deleted first.
+// Comments of a deleted post are not listed on their own: they come back with the
+// post. PurgeAt is left for the caller.
+func (p *Postgres) ListTrash(ctx context.Context, q entity.TrashQuery) ([]*entity.TrashItem, error) {
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var branches []string
+	if q.TargetType == "" || q.TargetType == entity.ReportOnPost {
+		branches = append(branches, `
+            SELECT 'post' AS target_type, p.id AS target_id, p.user_id, p.id AS post_id, p.title,
+                   p.content, p.hidden_at IS NOT NULL AS hidden, p.deleted_at, p.deleted_by
+            FROM posts p
+            WHERE p.deleted_at IS NOT NULL`)
+	}
+	if q.TargetType == "" || q.TargetType == entity.ReportOnComment {
+		branches = append(branches, `
+            SELECT 'comment', c.id, c.user_id, c.post_id, p.title,
+                   c.content, c.hidden_at IS NOT NULL, c.deleted_at, c.deleted_by
+            FROM comments c
+            JOIN posts p ON p.id = c.post_id AND p.deleted_at IS NULL
+            WHERE c.deleted_at IS NOT NULL`)
+	}
+	if len(branches) == 0 {
+		return nil, fmt.Errorf("unsupported trash target %q", q.TargetType)
+	}
+
+	keyset := ""
+	if q.After != nil {
+		keyset = fmt.Sprintf("WHERE (t.deleted_at, t.target_type, t.target_id) < (%s, %s, %s)",
+			arg(q.After.DeletedAt), arg(string(q.After.TargetType)), arg(q.After.TargetID))
+	}
+
+	rows, err := p.db.QueryContext(ctx, fmt.Sprintf(`
+        SELECT t.target_type, t.target_id, t.user_id, COALESCE(u.username, ''), t.post_id, t.title,
+               t.content, t.hidden, t.deleted_at, t.deleted_by, COALESCE(d.username, '')
+        FROM (%s) AS t
+        LEFT JOIN users u ON u.id = t.user_id
+        LEFT JOIN users d ON d.id = t.deleted_by
+        %s
+        ORDER BY t.deleted_at DESC, t.target_type DESC, t.target_id DESC
+        LIMIT %s
+    `, strings.Join(branches, " UNION ALL "), keyset, arg(q.Limit)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*entity.TrashItem
+	for rows.Next() {
+		var item entity.TrashItem
+		var userID, deletedBy sql.NullInt64
+		var postID int
+		if err := rows.Scan(
+			&item.TargetType, &item.TargetID, &userID, &item.Content.Author, &postID, &item.Content.Title,
+			&item.Content.Excerpt, &item.Content.Hidden, &item.DeletedAt, &deletedBy, &item.DeletedBy,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan trash item: %w", err)
+		}
+		item.Content.AuthorID = nullIntPtr(userID)
+		item.Content.PostID = &postID
+		item.DeletedByID = nullIntPtr(deletedBy)
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return items, nil
+}
+
+// RestoreFromTrash brings the post or comment entry.TargetID back and records entry
+// in the moderation log, in one transaction; the author of entry is filled in. A
+// target not in the trash, or a comment of a deleted post, is a wrapped sql.ErrNoRows.
+func (p *Postgres) RestoreFromTrash(ctx context.Context, entry *entity.ModerationLogEntry) error {
+	return p.withTx(ctx, func(tx *sql.Tx) error {
+		var userID sql.NullInt64
+		var err error
+		switch entry.TargetType {
+		case entity.ReportOnPost:
+			err = tx.QueryRowContext(ctx, `
+                UPDATE posts SET deleted_at = NULL, deleted_by = NULL
+                WHERE id = $1 AND deleted_at IS NOT NULL
+                RETURNING user_id
+            `, entry.TargetID).Scan(&userID)
+			if err != nil {
+				err = fmt.Errorf("failed to restore post %d: %w", entry.TargetID, err)
+			}
+		case entity.ReportOnComment:
+			userID, err = restoreComment(ctx, tx, entry.TargetID)
+		default:
+			err = fmt.Errorf("unsupported trash target %q", entry.TargetType)
+		}
+		if err != nil {
+			return err
+		}
+
+		entry.TargetUserID = nullIntPtr(userID)
+		return insertModerationLog(ctx, tx, entry)
+	})
+}
+
+// PurgeDeleted removes posts and comments deleted before before for good. Comments
+// still shown as placeholders stay until their replies are gone. Foreign keys take
+// the rest: the comments of a post and the replies of a comment, which are in the
+// trash as well, revisions, votes and notifications; attachments are unlinked and
+// collected as orphans.
+func (p *Postgres) PurgeDeleted(ctx context.Context, before time.Time) (entity.PurgeResult, error) {
+	var result entity.PurgeResult
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+            DELETE FROM comments WHERE deleted_at < $1 AND NOT placeholder
+        `, before)
+		if err != nil {
+			return fmt.Errorf("failed to purge comments: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		result.Comments = int(n)
+
+		res, err = tx.ExecContext(ctx, `DELETE FROM posts WHERE deleted_at < $1`, before)
+		if err != nil {
+			return fmt.Errorf("failed to purge posts: %w", err)
+		}
+		n, err = res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		result.Posts = int(n)
+		return nil
+	})
+	if err != nil {
+		return entity.PurgeResult{}, err
+	}
+	return result, nil
+}
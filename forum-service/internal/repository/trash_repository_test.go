@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresTrash(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Устанавливаем тестовые данные
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+	_, err = repo.db.ExecContext(ctx, `
+		INSERT INTO users (id, username, email, password_hash, role)
+		VALUES (9, 'mod', 'mod@example.com', 'hashedpassword', 'moderator')
+	`)
+	require.NoError(t, err)
+	moderatorID := 9
+
+	root := &entity.Comment{Content: "root", PostID: 1, UserID: 1}
+	require.NoError(t, repo.CreateComment(ctx, root))
+	reply := &entity.Comment{Content: "reply", PostID: 1, UserID: 1, ParentID: &root.ID, Depth: 1}
+	require.NoError(t, repo.CreateComment(ctx, reply))
+
+	// Комментарий с ответом остается заглушкой, в корзине виден его текст
+	require.NoError(t, repo.DeleteComment(ctx, root.ID, 1))
+	items, err := repo.ListTrash(ctx, entity.TrashQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, entity.ReportOnComment, items[0].TargetType)
+	assert.Equal(t, "root", items[0].Content.Excerpt)
+	assert.Equal(t, "testuser", items[0].DeletedBy)
+
+	// Заглушка с ответами переживает очистку, опустевшая - нет
+	result, err := repo.PurgeDeleted(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, entity.PurgeResult{}, result)
+	require.NoError(t, repo.DeleteComment(ctx, reply.ID, 1))
+	_, err = repo.GetCommentByID(ctx, root.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Восстановленный ответ возвращает родителя в ветку заглушкой
+	restore := &entity.ModerationLogEntry{ModeratorID: &moderatorID, Action: entity.ModerationRestore, TargetType: entity.ReportOnComment, TargetID: reply.ID}
+	require.NoError(t, repo.RestoreFromTrash(ctx, restore))
+	assert.Equal(t, "mod", restore.Moderator)
+	assert.Equal(t, "testuser", restore.TargetUser)
+	parent, err := repo.GetCommentByID(ctx, root.ID)
+	require.NoError(t, err)
+	assert.True(t, parent.Deleted)
+	assert.ErrorIs(t, repo.RestoreFromTrash(ctx, restore), sql.ErrNoRows)
+
+	// Удаленный пост уносит комментарии; его комментарии нельзя вернуть по одному
+	require.NoError(t, repo.DeletePost(ctx, 1, moderatorID))
+	items, err = repo.ListTrash(ctx, entity.TrashQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, entity.ReportOnPost, items[0].TargetType)
+	assert.Equal(t, "mod", items[0].DeletedBy)
+	err = repo.RestoreFromTrash(ctx, &entity.ModerationLogEntry{ModeratorID: &moderatorID, Action: entity.ModerationRestore, TargetType: entity.ReportOnComment, TargetID: root.ID})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Курсор и фильтр по типу
+	after := &entity.TrashCursor{DeletedAt: items[0].DeletedAt, TargetType: items[0].TargetType, TargetID: items[0].TargetID}
+	items, err = repo.ListTrash(ctx, entity.TrashQuery{After: after, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, items)
+	items, err = repo.ListTrash(ctx, entity.TrashQuery{TargetType: entity.ReportOnComment, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	// Восстановление поста возвращает и его ветку
+	require.NoError(t, repo.RestoreFromTrash(ctx, &entity.ModerationLogEntry{ModeratorID: &moderatorID, Action: entity.ModerationRestore, TargetType: entity.ReportOnPost, TargetID: 1}))
+	_, err = repo.GetPostByID(ctx, 1)
+	require.NoError(t, err)
+	_, err = repo.GetCommentByID(ctx, reply.ID)
+	require.NoError(t, err)
+
+	// Очистка удаляет пост вместе со всеми комментариями
+	require.NoError(t, repo.DeletePost(ctx, 1, moderatorID))
+	result, err = repo.PurgeDeleted(ctx, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, entity.PurgeResult{}, result)
+	result, err = repo.PurgeDeleted(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Posts)
+	var count int
+	require.NoError(t, repo.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM comments`).Scan(&count))
+	assert.Zero(t, count)
+
+	log, err := repo.ListModerationLog(ctx, entity.ModerationLogQuery{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, log, 2)
+}
@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lib/pq"
+)
+
+type VoteRepository interface {
+	Vote(ctx context.Context, userID int, target entity.VoteTarget, value int) (*entity.VoteState, error)
+	ToggleReaction(ctx context.Context, userID int, target entity.VoteTarget, emoji string) (*entity.VoteState, error)
+	GetViewerFeedback(ctx context.Context, userID int, kind entity.TargetKind, ids []int) (map[int]int, map[int][]string, error)
+}
+
+// reactionCounts scans the JSONB reactions column (emoji -> count) into a map.
+type reactionCounts map[string]int
+
+func (r *reactionCounts) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*r = map[string]int{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported reactions type %T", src)
+	}
+	counts := map[string]int{}
+	if err := json.Unmarshal(data, &counts); err != nil {
+		return fmt.Errorf("failed to decode reactions: %w", err)
+	}
+	*r = counts
+	return nil
+}
+
+// voteTable returns the table holding the target and the column votes and reactions
+// use to reference it.
+func voteTable(kind entity.TargetKind) (table, column string, err error) {
+	switch kind {
+	case entity.TargetPost:
+		return "posts", "post_id", nil
+	case entity.TargetComment:
+		return "comments", "comment_id", nil
+	}
+	return "", "", fmt.Errorf("unknown vote target %q", kind)
+}
+
+// lockVoteTarget locks the target row so concurrent votes serialize on it. A missing,
+// hidden, deleted or unpublished post, or a comment that is hidden, deleted, belongs
+// to another post or sits under such a post, is reported as a wrapped sql.ErrNoRows.
+func lockVoteTarget(ctx context.Context, tx *sql.Tx, target entity.VoteTarget) error {
+	var query string
+	args := []interface{}{target.ID}
+	switch target.Kind {
+	case entity.TargetPost:
+		query = `
+            SELECT id FROM posts
+            WHERE id = $1 AND hidden_at IS NULL AND deleted_at IS NULL AND status = 'published'
+            FOR UPDATE`
+	case entity.TargetComment:
+		query = `
+            SELECT c.id FROM comments c
+            JOIN posts p ON p.id = c.post_id
+            WHERE c.id = $1 AND c.post_id = $2 AND c.hidden_at IS NULL AND c.deleted_at IS NULL
+              AND p.hidden_at IS NULL AND p.deleted_at IS NULL AND p.status = 'published'
+            FOR UPDATE OF c`
+		args = append(args, target.PostID)
+	default:
+		return fmt.Errorf("unknown vote target %q", target.Kind)
+	}
+	var id int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+		return fmt.Errorf("failed to lock %s %d: %w", target.Kind, target.ID, err)
+	}
+	return nil
+}
+
+// Vote toggles the user's vote on the target: repeating the same value withdraws the
+// vote, the opposite value flips it. The target's score is adjusted in the same
+// transaction.
+func (p *Postgres) Vote(ctx context.Context, userID int, target entity.VoteTarget, value int) (*entity.VoteState, error) {
+	table, column, err := voteTable(target.Kind)
+	if err != nil {
+		return nil, err
+	}
+	var state *entity.VoteState
+	err = p.withTx(ctx, func(tx *sql.Tx) error {
+		if err := lockVoteTarget(ctx, tx, target); err != nil {
+			return err
+		}
+
+		var current int
+		err := tx.QueryRowContext(ctx,
+			fmt.Sprintf(`SELECT value FROM votes WHERE user_id = $1 AND %s = $2`, column),
+			userID, target.ID).Scan(&current)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to get vote: %w", err)
+		}
+
+		delta := value - current
+		switch current {
+		case value:
+			delta = -value
+			_, err = tx.ExecContext(ctx,
+				fmt.Sprintf(`DELETE FROM votes WHERE user_id = $1 AND %s = $2`, column),
+				userID, target.ID)
+		case 0:
+			_, err = tx.ExecContext(ctx,
+				fmt.Sprintf(`INSERT INTO votes (user_id, %s, value) VALUES ($1, $2, $3)`, column),
+				userID, target.ID, value)
+		default:
+			_, err = tx.ExecContext(ctx,
+				fmt.Sprintf(`UPDATE votes SET value = $3, created_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND %s = $2`, column),
+				userID, target.ID, value)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save vote: %w", err)
+		}
+
+		state = &entity.VoteState{}
+		err = tx.QueryRowContext(ctx,
+			fmt.Sprintf(`UPDATE %s SET score = score + $1 WHERE id = $2 RETURNING score, reactions`, table),
+			delta, target.ID).Scan(&state.Score, (*reactionCounts)(&state.Reactions))
+		if err != nil {
+			return fmt.Errorf("failed to update score: %w", err)
+		}
+		return loadViewerState(ctx, tx, userID, column, target.ID, state)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// ToggleReaction adds the emoji reaction of the user to the target or removes it if
+// it is already there, keeping the target's reaction counts in step.
+func (p *Postgres) ToggleReaction(ctx context.Context, userID int, target entity.VoteTarget, emoji string) (*entity.VoteState, error) {
+	table, column, err := voteTable(target.Kind)
+	if err != nil {
+		return nil, err
+	}
+	var state *entity.VoteState
+	err = p.withTx(ctx, func(tx *sql.Tx) error {
+		if err := lockVoteTarget(ctx, tx, target); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx,
+			fmt.Sprintf(`DELETE FROM reactions WHERE user_id = $1 AND %s = $2 AND emoji = $3`, column),
+			userID, target.ID, emoji)
+		if err != nil {
+			return fmt.Errorf("failed to remove reaction: %w", err)
+		}
+		removed, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to remove reaction: %w", err)
+		}
+		delta := -1
+		if removed == 0 {
+			delta = 1
+			_, err = tx.ExecContext(ctx,
+				fmt.Sprintf(`INSERT INTO reactions (user_id, %s, emoji) VALUES ($1, $2, $3)`, column),
+				userID, target.ID, emoji)
+			if err != nil {
+				return fmt.Errorf("failed to add reaction: %w", err)
+			}
+		}
+
+		// Keys whose count drops to zero are removed, so the object only lists
+		// reactions somebody actually left.
+		state = &entity.VoteState{}
+		err = tx.QueryRowContext(ctx, fmt.Sprintf(`
+            UPDATE %s SET reactions = CASE
+                WHEN COALESCE((reactions->>$1::text)::int, 0) + $2::int <= 0 THEN reactions - $1::text
+                ELSE jsonb_set(reactions, ARRAY[$1::text], to_jsonb(COALESCE((reactions->>$1::text)::int, 0) + $2::int))
+            END
+            WHERE id = $3
+            RETURNING score, reactions
+        `, table), emoji, delta, target.ID).Scan(&state.Score, (*reactionCounts)(&state.Reactions))
+		if err != nil {
+			return fmt.Errorf("failed to update reaction counts: %w", err)
+		}
+		return loadViewerState(ctx, tx, userID, column, target.ID, state)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// loadViewerState fills the user's own vote and reactions on the target.
+func loadViewerState(ctx context.Context, tx *sql.Tx, userID int, column string, targetID int, state *entity.VoteState) error {
+	err := tx.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT value FROM votes WHERE user_id = $1 AND %s = $2`, column),
+		userID, targetID).Scan(&state.MyVote)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get vote: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		fmt.Sprintf(`SELECT emoji FROM reactions WHERE user_id = $1 AND %s = $2 ORDER BY created_at, emoji`, column),
+		userID, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to get reactions: %w", err)
+	}
+	defer rows.Close()
+	state.MyReactions = []string{}
+	for rows.Next() {
+		var emoji string
+		if err := rows.Scan(&emoji); err != nil {
+			return fmt.Errorf("failed to scan reaction: %w", err)
+		}
+		state.MyReactions = append(state.MyReactions, emoji)
+	}
+	return rows.Err()
+}
+
+// GetViewerFeedback returns the user's votes and reactions on the given targets,
+// keyed by target ID. Targets without feedback are absent from the maps.
+func (p *Postgres) GetViewerFeedback(ctx context.Context, userID int, kind entity.TargetKind, ids []int) (map[int]int, map[int][]string, error) {
+	_, column, err := voteTable(kind)
+	if err != nil {
+		return nil, nil, err
+	}
+	votes := make(map[int]int)
+	reactions := make(map[int][]string)
+	if len(ids) == 0 {
+		return votes, reactions, nil
+	}
+
+	rows, err := p.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT %[1]s, value FROM votes WHERE user_id = $1 AND %[1]s = ANY($2)`, column),
+		userID, pq.Array(ids))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query votes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, value int
+		if err := rows.Scan(&id, &value); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan vote: %w", err)
+		}
+		votes[id] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	rrows, err := p.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT %[1]s, emoji FROM reactions WHERE user_id = $1 AND %[1]s = ANY($2) ORDER BY created_at, emoji`, column),
+		userID, pq.Array(ids))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query reactions: %w", err)
+	}
+	defer rrows.Close()
+	for rrows.Next() {
+		var id int
+		var emoji string
+		if err := rrows.Scan(&id, &emoji); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan reaction: %w", err)
+		}
+		reactions[id] = append(reactions[id], emoji)
+	}
+	if err := rrows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("rows error: %w", err)
+	}
+	return votes, reactions, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresVotesAndReactions(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Устанавливаем тестовые данные
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+	_, err = repo.db.ExecContext(ctx, `
+		INSERT INTO users (id, username, email, password_hash, role)
+		VALUES (2, 'second', 'second@example.com', 'hashedpassword', 'user')
+	`)
+	require.NoError(t, err)
+
+	post := entity.VoteTarget{Kind: entity.TargetPost, ID: 1}
+
+	// Два голоса за, затем второй пользователь меняет голос на против
+	state, err := repo.Vote(ctx, 1, post, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, state.Score)
+	assert.Equal(t, 1, state.MyVote)
+	_, err = repo.Vote(ctx, 2, post, 1)
+	require.NoError(t, err)
+	state, err = repo.Vote(ctx, 2, post, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, state.Score)
+	assert.Equal(t, -1, state.MyVote)
+
+	// Повтор того же голоса снимает его
+	state, err = repo.Vote(ctx, 1, post, 1)
+	require.NoError(t, err)
+	assert.Equal(t, -1, state.Score)
+	assert.Equal(t, 0, state.MyVote)
+
+	// Реакции: счетчик растет и пропадает, когда реакций не осталось
+	state, err = repo.ToggleReaction(ctx, 1, post, "🎉")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"🎉": 1}, state.Reactions)
+	assert.Equal(t, []string{"🎉"}, state.MyReactions)
+	_, err = repo.ToggleReaction(ctx, 2, post, "🎉")
+	require.NoError(t, err)
+	state, err = repo.ToggleReaction(ctx, 1, post, "🎉")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"🎉": 1}, state.Reactions)
+	assert.Empty(t, state.MyReactions)
+	state, err = repo.ToggleReaction(ctx, 2, post, "🎉")
+	require.NoError(t, err)
+	assert.Empty(t, state.Reactions)
+
+	stored, err := repo.GetPostByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, -1, stored.Score)
+
+	// Комментарий чужого поста не найден
+	comment := &entity.Comment{Content: "c", PostID: 1, UserID: 1}
+	require.NoError(t, repo.CreateComment(ctx, comment))
+	_, err = repo.Vote(ctx, 1, entity.VoteTarget{Kind: entity.TargetComment, ID: comment.ID, PostID: 99}, 1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = repo.Vote(ctx, 2, entity.VoteTarget{Kind: entity.TargetComment, ID: comment.ID, PostID: 1}, 1)
+	require.NoError(t, err)
+	_, err = repo.ToggleReaction(ctx, 2, entity.VoteTarget{Kind: entity.TargetComment, ID: comment.ID, PostID: 1}, "👀")
+	require.NoError(t, err)
+
+	votes, reactions, err := repo.GetViewerFeedback(ctx, 2, entity.TargetComment, []int{comment.ID})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{comment.ID: 1}, votes)
+	assert.Equal(t, map[int][]string{comment.ID: {"👀"}}, reactions)
+
+	stored2, err := repo.GetCommentByID(ctx, comment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored2.Score)
+	assert.Equal(t, map[string]int{"👀": 1}, stored2.Reactions)
+}
+
+func TestPostgresVotesOnHiddenContent(t *testing.T) {
+	repo, err := setupTestDB()
+	if err != nil {
+		t.Fatalf("не удалось настроить тестовую базу данных: %v", err)
+	}
+
+	ctx := context.Background()
+	err = setupTestData(ctx, repo)
+	if err != nil {
+		t.Fatalf("не удалось установить тестовые данные: %v", err)
+	}
+
+	post := entity.VoteTarget{Kind: entity.TargetPost, ID: 1}
+	comment := &entity.Comment{Content: "c", PostID: 1, UserID: 1}
+	require.NoError(t, repo.CreateComment(ctx, comment))
+	commentTarget := entity.VoteTarget{Kind: entity.TargetComment, ID: comment.ID, PostID: 1}
+
+	// Скрытый модератором комментарий не принимает голосов и реакций
+	_, err = repo.db.ExecContext(ctx, `UPDATE comments SET hidden_at = NOW() WHERE id = $1`, comment.ID)
+	require.NoError(t, err)
+	_, err = repo.Vote(ctx, 1, commentTarget, 1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repo.ToggleReaction(ctx, 1, commentTarget, "👀")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Скрытый пост закрывает и его, и комментарии в нем
+	_, err = repo.db.ExecContext(ctx, `UPDATE comments SET hidden_at = NULL WHERE id = $1`, comment.ID)
+	require.NoError(t, err)
+	_, err = repo.db.ExecContext(ctx, `UPDATE posts SET hidden_at = NOW() WHERE id = 1`)
+	require.NoError(t, err)
+	_, err = repo.Vote(ctx, 1, post, 1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repo.ToggleReaction(ctx, 1, post, "🎉")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repo.Vote(ctx, 1, commentTarget, 1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Счетчики скрытых записей не изменились
+	var postScore, commentScore int
+	var postReactions, commentReactions string
+	require.NoError(t, repo.db.QueryRowContext(ctx, `SELECT score, reactions::text FROM posts WHERE id = 1`).Scan(&postScore, &postReactions))
+	require.NoError(t, repo.db.QueryRowContext(ctx, `SELECT score, reactions::text FROM comments WHERE id = $1`, comment.ID).Scan(&commentScore, &commentReactions))
+	assert.Equal(t, 0, postScore)
+	assert.Equal(t, "{}", postReactions)
+	assert.Equal(t, 0, commentScore)
+	assert.Equal(t, "{}", commentReactions)
+
+	// После снятия скрытия голосовать снова можно
+	_, err = repo.db.ExecContext(ctx, `UPDATE posts SET hidden_at = NULL WHERE id = 1`)
+	require.NoError(t, err)
+	state, err := repo.Vote(ctx, 1, commentTarget, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, state.Score)
+}
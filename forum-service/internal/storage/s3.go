@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// unsignedPayload lets uploads stream without hashing the body first
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	// emptyPayloadHash is the SHA-256 of an empty body
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	amzDateFormat    = "20060102T150405Z"
+)
+
+// S3Config addresses a bucket on AWS S3 or a compatible server such as MinIO.
+// Objects are addressed path-style: Endpoint/Bucket/key.
+type S3Config struct {
+	Endpoint        string `yaml:"endpoint"`
+	Region          string `yaml:"region"`
+	Bucket          string `yaml:"bucket"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+}
+
+// S3Store talks to the S3 REST API directly, signing requests with Signature
+// Version 4.
+type S3Store struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Store uses client for requests, or http.DefaultClient when it is nil.
+func NewS3Store(cfg S3Config, client *http.Client) *S3Store {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	return &S3Store{cfg: cfg, client: client, now: time.Now}
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(req, unsignedPayload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error("put", key, resp)
+	}
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	defer resp.Body.Close()
+	return nil, s3Error("get", key, resp)
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK &&
+		resp.StatusCode != http.StatusNotFound {
+		return s3Error("delete", key, resp)
+	}
+	return nil
+}
+
+func (s *S3Store) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if !validKey(key) {
+		return nil, fmt.Errorf("invalid blob key %q", key)
+	}
+	u, err := url.Parse(s.cfg.Endpoint + "/" + s.cfg.Bucket + "/" + key)
+	if err != nil {
+		return nil, fmt.Errorf("build S3 URL: %w", err)
+	}
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+func (s *S3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	signRequest(req, s.cfg, payloadHash, s.now().UTC())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("S3 %s request: %w", req.Method, err)
+	}
+	return resp, nil
+}
+
+func s3Error(op, key string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("S3 %s %q: %s: %s", op, key, resp.Status, strings.TrimSpace(string(body)))
+}
+
+// signRequest adds the x-amz-* headers and the Authorization header of AWS
+// Signature Version 4. Only host and the x-amz-* headers are signed.
+func signRequest(req *http.Request, cfg S3Config, payloadHash string, now time.Time) {
+	amzDate := now.Format(amzDateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := now.Format("20060102") + "/" + cfg.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+cfg.SecretAccessKey), now.Format("20060102"))
+	key = hmacSHA256(key, cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalURI escapes every path segment as S3 expects; slashes are kept.
+func canonicalURI(u *url.URL) string {
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+	}
+	if path := strings.Join(segments, "/"); path != "" {
+		return path
+	}
+	return "/"
+}
+
+func hexSHA256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrURLExpired       = errors.New("link has expired")
+)
+
+// URLSigner signs links to stored files so that they can be served without a
+// session and stop working after a while.
+type URLSigner struct {
+	key []byte
+}
+
+func NewURLSigner(key []byte) *URLSigner {
+	return &URLSigner{key: key}
+}
+
+// Sign returns the signature of resource valid until expires.
+func (s *URLSigner) Sign(resource string, expires time.Time) string {
+	return hex.EncodeToString(s.mac(resource, expires.Unix()))
+}
+
+// Verify checks a signature produced by Sign; expires is the Unix time passed
+// along with the link.
+func (s *URLSigner) Verify(resource, expires, signature string, now time.Time) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, s.mac(resource, unix)) {
+		return ErrInvalidSignature
+	}
+	if now.Unix() > unix {
+		return ErrURLExpired
+	}
+	return nil
+}
+
+func (s *URLSigner) mac(resource string, expires int64) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(resource + "\n" + strconv.FormatInt(expires, 10)))
+	return mac.Sum(nil)
+}
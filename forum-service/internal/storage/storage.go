@@ -0,0 +1,31 @@
+// Package storage keeps uploaded files in a blob store: a local directory or an
+// S3-compatible bucket.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+)
+
+// ErrNotFound is returned by Get when the key does not exist.
+var ErrNotFound = errors.New("blob not found")
+
+// keyPattern accepts slash-separated lowercase names such as "ab/cdef.png". Keys
+// are generated by the service, the check only guards against path traversal.
+var keyPattern = regexp.MustCompile(`^[a-z0-9_-]+(/[a-z0-9_-]+)*(\.[a-z0-9]+)?$`)
+
+// BlobStore stores opaque blobs by key.
+type BlobStore interface {
+	// Put stores size bytes read from r under key, replacing an existing blob.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob for reading; the caller closes it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+func validKey(key string) bool {
+	return keyPattern.MatchString(key)
+}
@@ -0,0 +1,374 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/storage"
+)
+
+const (
+	// MaxAttachmentsPerItem - сколько файлов можно прикрепить к одному посту,
+	// комментарию или сообщению
+	MaxAttachmentsPerItem = 10
+	// orphanBatchSize - сколько брошенных загрузок удаляется за один запрос
+	orphanBatchSize   = 100
+	maxFilenameLength = 255
+)
+
+// Варианты файла вложения в подписанной ссылке
+const (
+	AttachmentVariantFile      = "file"
+	AttachmentVariantThumbnail = "thumbnail"
+)
+
+var (
+	ErrEmptyUpload           = errors.New("file is empty")
+	ErrAttachmentTooLarge    = errors.New("file is too large")
+	ErrUnsupportedMediaType  = errors.New("unsupported file type: use PNG, JPEG, GIF, WebP, PDF or plain text")
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrTooManyAttachments    = errors.New("too many attachments: use at most 10")
+	ErrInvalidAttachmentLink = errors.New("invalid attachment link")
+	ErrAttachmentLinkExpired = errors.New("attachment link has expired")
+)
+
+// allowedUploadTypes - разрешенные типы содержимого, определенные по самим данным, и
+// расширения, с которыми файлы хранятся
+var allowedUploadTypes = map[string]string{
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+	"text/plain":      ".txt",
+}
+
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, attachment *entity.Attachment) error
+	GetAttachment(ctx context.Context, id int) (*entity.Attachment, error)
+	ListAttachments(ctx context.Context, target entity.AttachmentTarget, ids []int) ([]entity.Attachment, error)
+	ListOrphanedAttachments(ctx context.Context, before time.Time, limit int) ([]entity.Attachment, error)
+	DeleteOrphanedAttachment(ctx context.Context, id int) error
+}
+
+// AttachmentLoader возвращает файлы постов, комментариев или сообщений чата ids,
+// сгруппированные по id владельца, с подписанными ссылками
+type AttachmentLoader interface {
+	AttachmentsFor(ctx context.Context, target entity.AttachmentTarget, ids []int) (map[int][]entity.Attachment, error)
+}
+
+type AttachmentUseCase interface {
+	AttachmentLoader
+	Upload(ctx context.Context, userID int, filename string, r io.Reader) (*entity.Attachment, error)
+	Open(ctx context.Context, id int, variant, expires, signature string) (*entity.Attachment, io.ReadCloser, error)
+	MaxUploadSize() int64
+	CollectOrphans(ctx context.Context) (int, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// AttachmentConfig - ограничения загрузок: MaxSize - наибольший размер файла,
+// URLTTL - срок действия ссылок, OrphanTTL - через сколько неприкрепленная
+// загрузка удаляется
+type AttachmentConfig struct {
+	MaxSize   int64
+	URLTTL    time.Duration
+	OrphanTTL time.Duration
+}
+
+type AttachmentService struct {
+	repo   AttachmentRepository
+	store  storage.BlobStore
+	signer *storage.URLSigner
+	cfg    AttachmentConfig
+	now    func() time.Time
+}
+
+func NewAttachmentUseCase(repo AttachmentRepository, store storage.BlobStore, signer *storage.URLSigner, cfg AttachmentConfig) *AttachmentService {
+	return &AttachmentService{
+		repo:   repo,
+		store:  store,
+		signer: signer,
+		cfg:    cfg,
+		now:    time.Now,
+	}
+}
+
+func (s *AttachmentService) MaxUploadSize() int64 {
+	return s.cfg.MaxSize
+}
+
+// Upload сохраняет файл пользователя. Тип определяется по содержимому, а не по
+// имени; для изображений строится миниатюра. Загрузка остается брошенной, пока ее
+// не прикрепят к посту, комментарию или сообщению.
+func (s *AttachmentService) Upload(ctx context.Context, userID int, filename string, r io.Reader) (*entity.Attachment, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.cfg.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read upload: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, ErrEmptyUpload
+	}
+	if int64(len(data)) > s.cfg.MaxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return nil, ErrUnsupportedMediaType
+	}
+	ext, ok := allowedUploadTypes[mediaType]
+	if !ok {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	name, err := randomBlobName()
+	if err != nil {
+		return nil, err
+	}
+	attachment := &entity.Attachment{
+		UserID:      userID,
+		Filename:    cleanFilename(filename, ext),
+		ContentType: mediaType,
+		Size:        int64(len(data)),
+		Key:         name[:2] + "/" + name + ext,
+	}
+
+	var thumbnail []byte
+	if strings.HasPrefix(mediaType, "image/") {
+		thumbnail, attachment.Width, attachment.Height, err = makeThumbnail(data)
+		if err != nil {
+			return nil, err
+		}
+		attachment.ThumbnailKey = name[:2] + "/" + name + "_thumb.png"
+	}
+
+	if err := s.store.Put(ctx, attachment.Key, bytes.NewReader(data), attachment.Size, mediaType); err != nil {
+		return nil, err
+	}
+	if thumbnail != nil {
+		err := s.store.Put(ctx, attachment.ThumbnailKey, bytes.NewReader(thumbnail),
+			int64(len(thumbnail)), ThumbnailContentType)
+		if err != nil {
+			s.deleteBlobs(ctx, attachment)
+			return nil, err
+		}
+	}
+	if err := s.repo.CreateAttachment(ctx, attachment); err != nil {
+		s.deleteBlobs(ctx, attachment)
+		return nil, err
+	}
+	s.sign(attachment)
+	return attachment, nil
+}
+
+// Open проверяет подписанную ссылку и открывает файл или миниатюру вложения
+func (s *AttachmentService) Open(ctx context.Context, id int, variant, expires, signature string) (*entity.Attachment, io.ReadCloser, error) {
+	if variant != AttachmentVariantFile && variant != AttachmentVariantThumbnail {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	err := s.signer.Verify(attachmentResource(id, variant), expires, signature, s.now())
+	if errors.Is(err, storage.ErrURLExpired) {
+		return nil, nil, ErrAttachmentLinkExpired
+	}
+	if err != nil {
+		return nil, nil, ErrInvalidAttachmentLink
+	}
+
+	attachment, err := s.repo.GetAttachment(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	key := attachment.Key
+	if variant == AttachmentVariantThumbnail {
+		key = attachment.ThumbnailKey
+	}
+	if key == "" {
+		return nil, nil, ErrAttachmentNotFound
+	}
+
+	blob, err := s.store.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, blob, nil
+}
+
+// AttachmentsFor загружает вложения владельцев ids одним запросом
+func (s *AttachmentService) AttachmentsFor(ctx context.Context, target entity.AttachmentTarget, ids []int) (map[int][]entity.Attachment, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	attachments, err := s.repo.ListAttachments(ctx, target, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byOwner := make(map[int][]entity.Attachment)
+	for i := range attachments {
+		a := &attachments[i]
+		s.sign(a)
+		var owner *int
+		switch target {
+		case entity.AttachedToPost:
+			owner = a.PostID
+		case entity.AttachedToComment:
+			owner = a.CommentID
+		case entity.AttachedToChat:
+			owner = a.ChatMessageID
+		}
+		if owner != nil {
+			byOwner[*owner] = append(byOwner[*owner], *a)
+		}
+	}
+	return byOwner, nil
+}
+
+// CollectOrphans удаляет загрузки, которые дольше OrphanTTL ни к чему не
+// прикреплены, вместе с их файлами. Возвращает число удаленных загрузок.
+func (s *AttachmentService) CollectOrphans(ctx context.Context) (int, error) {
+	before := s.now().Add(-s.cfg.OrphanTTL)
+	deleted := 0
+	for {
+		orphans, err := s.repo.ListOrphanedAttachments(ctx, before, orphanBatchSize)
+		if err != nil {
+			return deleted, err
+		}
+		for i := range orphans {
+			err := s.repo.DeleteOrphanedAttachment(ctx, orphans[i].ID)
+			if errors.Is(err, sql.ErrNoRows) {
+				// Загрузку успели прикрепить
+				continue
+			}
+			if err != nil {
+				return deleted, err
+			}
+			s.deleteBlobs(ctx, &orphans[i])
+			deleted++
+		}
+		if len(orphans) < orphanBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// Run удаляет брошенные загрузки сразу и затем каждые interval, пока ctx не отменен
+func (s *AttachmentService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		n, err := s.CollectOrphans(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to collect orphaned attachments: %v", err)
+		}
+		if n > 0 {
+			log.Printf("[INFO] Deleted %d orphaned attachments", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deleteBlobs удаляет файлы вложения; строка в базе уже удалена или не создана,
+// поэтому ошибка только логируется
+func (s *AttachmentService) deleteBlobs(ctx context.Context, a *entity.Attachment) {
+	for _, key := range []string{a.Key, a.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("[ERROR] Failed to delete blob %s: %v", key, err)
+		}
+	}
+}
+
+// sign заполняет URL и ThumbnailURL ссылками, действующими URLTTL
+func (s *AttachmentService) sign(a *entity.Attachment) {
+	expires := s.now().Add(s.cfg.URLTTL)
+	a.URL = s.attachmentURL(a.ID, AttachmentVariantFile, expires)
+	if a.ThumbnailKey != "" {
+		a.ThumbnailURL = s.attachmentURL(a.ID, AttachmentVariantThumbnail, expires)
+	}
+}
+
+func (s *AttachmentService) attachmentURL(id int, variant string, expires time.Time) string {
+	return fmt.Sprintf("/attachments/%d/%s?expires=%d&signature=%s",
+		id, variant, expires.Unix(), s.signer.Sign(attachmentResource(id, variant), expires))
+}
+
+func attachmentResource(id int, variant string) string {
+	return fmt.Sprintf("%d/%s", id, variant)
+}
+
+func randomBlobName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate blob name: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// cleanFilename оставляет от имени файла клиента только последнюю часть пути без
+// управляющих символов. Пустое имя заменяется на file с расширением по типу.
+func cleanFilename(name, ext string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name))
+	if name == "" || name == "." || name == "/" {
+		return "file" + ext
+	}
+	for utf8.RuneCountInString(name) > maxFilenameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+// normalizeAttachmentIDs убирает повторы из прикрепляемых загрузок и проверяет их число
+func normalizeAttachmentIDs(ids []int) ([]int, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, ErrAttachmentNotFound
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > MaxAttachmentsPerItem {
+		return nil, ErrTooManyAttachments
+	}
+	return unique, nil
+}
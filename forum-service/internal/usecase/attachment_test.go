@@ -0,0 +1,338 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/storage"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) CreateAttachment(ctx context.Context, attachment *entity.Attachment) error {
+	return m.Called(ctx, attachment).Error(0)
+}
+
+func (m *MockAttachmentRepository) GetAttachment(ctx context.Context, id int) (*entity.Attachment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) ListAttachments(ctx context.Context, target entity.AttachmentTarget, ids []int) ([]entity.Attachment, error) {
+	args := m.Called(ctx, target, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) ListOrphanedAttachments(ctx context.Context, before time.Time, limit int) ([]entity.Attachment, error) {
+	args := m.Called(ctx, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) DeleteOrphanedAttachment(ctx context.Context, id int) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+type MockAttachmentLoader struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentLoader) AttachmentsFor(ctx context.Context, target entity.AttachmentTarget, ids []int) (map[int][]entity.Attachment, error) {
+	args := m.Called(ctx, target, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int][]entity.Attachment), args.Error(1)
+}
+
+func newAttachmentUseCase(t *testing.T, cfg usecase.AttachmentConfig) (*usecase.AttachmentService, *MockAttachmentRepository, storage.BlobStore) {
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	repo := new(MockAttachmentRepository)
+	if cfg.MaxSize == 0 {
+		cfg.MaxSize = 1 << 20
+	}
+	if cfg.URLTTL == 0 {
+		cfg.URLTTL = time.Hour
+	}
+	return usecase.NewAttachmentUseCase(repo, store, storage.NewURLSigner([]byte("key")), cfg), repo, store
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// openLink открывает вложение по подписанной ссылке, как это делает обработчик
+func openLink(uc *usecase.AttachmentService, id int, link string) (*entity.Attachment, io.ReadCloser, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, nil, err
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	return uc.Open(context.Background(), id, parts[2], u.Query().Get("expires"), u.Query().Get("signature"))
+}
+
+func TestAttachmentUseCase_UploadImage(t *testing.T) {
+	uc, repo, _ := newAttachmentUseCase(t, usecase.AttachmentConfig{})
+	data := testPNG(t, 800, 400)
+	repo.On("CreateAttachment", mock.Anything, mock.AnythingOfType("*entity.Attachment")).
+		Run(func(args mock.Arguments) { args.Get(1).(*entity.Attachment).ID = 5 }).Return(nil)
+
+	// Тип определяется по содержимому, путь в имени отбрасывается
+	a, err := uc.Upload(context.Background(), 2, `C:\photos\cat.jpg`, bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "cat.jpg", a.Filename)
+	assert.Equal(t, "image/png", a.ContentType)
+	assert.Equal(t, int64(len(data)), a.Size)
+	assert.Equal(t, 800, a.Width)
+	assert.Equal(t, 400, a.Height)
+	assert.True(t, strings.HasSuffix(a.Key, ".png"))
+	assert.NotEmpty(t, a.ThumbnailKey)
+	assert.Contains(t, a.URL, "/attachments/5/file?expires=")
+	assert.Contains(t, a.ThumbnailURL, "/attachments/5/thumbnail?expires=")
+
+	repo.On("GetAttachment", mock.Anything, 5).Return(a, nil)
+
+	_, blob, err := openLink(uc, 5, a.URL)
+	require.NoError(t, err)
+	got, _ := io.ReadAll(blob)
+	blob.Close()
+	assert.Equal(t, data, got)
+
+	_, blob, err = openLink(uc, 5, a.ThumbnailURL)
+	require.NoError(t, err)
+	thumb, err := png.DecodeConfig(blob)
+	blob.Close()
+	require.NoError(t, err)
+	assert.Equal(t, usecase.ThumbnailSize, thumb.Width)
+	assert.Equal(t, usecase.ThumbnailSize/2, thumb.Height)
+}
+
+func TestAttachmentUseCase_UploadRejected(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "Empty", data: nil, err: usecase.ErrEmptyUpload},
+		{name: "TooLarge", data: bytes.Repeat([]byte("a"), 101), err: usecase.ErrAttachmentTooLarge},
+		{name: "HTML", data: []byte("<html><script>alert(1)</script></html>"), err: usecase.ErrUnsupportedMediaType},
+		{name: "Binary", data: []byte{0x7f, 'E', 'L', 'F', 2, 1, 1, 0}, err: usecase.ErrUnsupportedMediaType},
+		{name: "BrokenImage", data: []byte("\x89PNG\r\n\x1a\nbroken"), err: usecase.ErrUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, _ := newAttachmentUseCase(t, usecase.AttachmentConfig{MaxSize: 100})
+
+			_, err := uc.Upload(context.Background(), 2, "f", bytes.NewReader(tt.data))
+			assert.ErrorIs(t, err, tt.err)
+			repo.AssertNotCalled(t, "CreateAttachment")
+		})
+	}
+}
+
+func TestAttachmentUseCase_UploadRepositoryErrorRemovesBlob(t *testing.T) {
+	uc, repo, store := newAttachmentUseCase(t, usecase.AttachmentConfig{})
+	var key string
+	repo.On("CreateAttachment", mock.Anything, mock.AnythingOfType("*entity.Attachment")).
+		Run(func(args mock.Arguments) { key = args.Get(1).(*entity.Attachment).Key }).
+		Return(errors.New("database error"))
+
+	_, err := uc.Upload(context.Background(), 2, "notes.txt", strings.NewReader("plain text"))
+	assert.EqualError(t, err, "database error")
+	_, err = store.Get(context.Background(), key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestAttachmentUseCase_Open(t *testing.T) {
+	t.Run("Expired", func(t *testing.T) {
+		uc, repo, _ := newAttachmentUseCase(t, usecase.AttachmentConfig{URLTTL: -time.Minute})
+		repo.On("CreateAttachment", mock.Anything, mock.Anything).Return(nil)
+		a, err := uc.Upload(context.Background(), 2, "a.txt", strings.NewReader("text"))
+		require.NoError(t, err)
+
+		_, _, err = openLink(uc, a.ID, a.URL)
+		assert.ErrorIs(t, err, usecase.ErrAttachmentLinkExpired)
+	})
+
+	t.Run("WrongAttachment", func(t *testing.T) {
+		uc, repo, _ := newAttachmentUseCase(t, usecase.AttachmentConfig{})
+		repo.On("CreateAttachment", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { args.Get(1).(*entity.Attachment).ID = 1 }).Return(nil)
+		a, err := uc.Upload(context.Background(), 2, "a.txt", strings.NewReader("text"))
+		require.NoError(t, err)
+
+		_, _, err = openLink(uc, 2, a.URL)
+		assert.ErrorIs(t, err, usecase.ErrInvalidAttachmentLink)
+		repo.AssertNotCalled(t, "GetAttachment")
+	})
+
+	t.Run("NoThumbnail", func(t *testing.T) {
+		uc, repo, _ := newAttachmentUseCase(t, usecase.AttachmentConfig{})
+		repo.On("CreateAttachment", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { args.Get(1).(*entity.Attachment).ID = 1 }).Return(nil)
+		a, err := uc.Upload(context.Background(), 2, "a.txt", strings.NewReader("text"))
+		require.NoError(t, err)
+		assert.Empty(t, a.ThumbnailURL)
+		repo.On("GetAttachment", mock.Anything, 1).Return(a, nil)
+
+		link := strings.Replace(a.URL, "/file?", "/thumbnail?", 1)
+		_, _, err = openLink(uc, 1, link)
+		assert.ErrorIs(t, err, usecase.ErrInvalidAttachmentLink)
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		uc, repo, _ := newAttachmentUseCase(t, usecase.AttachmentConfig{})
+		repo.On("CreateAttachment", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { args.Get(1).(*entity.Attachment).ID = 1 }).Return(nil)
+		a, err := uc.Upload(context.Background(), 2, "a.txt", strings.NewReader("text"))
+		require.NoError(t, err)
+		repo.On("GetAttachment", mock.Anything, 1).Return(nil, fmt.Errorf("get: %w", sql.ErrNoRows))
+
+		_, _, err = openLink(uc, 1, a.URL)
+		assert.ErrorIs(t, err, usecase.ErrAttachmentNotFound)
+	})
+}
+
+func TestAttachmentUseCase_AttachmentsFor(t *testing.T) {
+	uc, repo, _ := newAttachmentUseCase(t, usecase.AttachmentConfig{})
+	one, two := 1, 2
+	repo.On("ListAttachments", mock.Anything, entity.AttachedToComment, []int{1, 2, 3}).Return([]entity.Attachment{
+		{ID: 10, CommentID: &one},
+		{ID: 11, CommentID: &two, ThumbnailKey: "ab/t.png"},
+		{ID: 12, CommentID: &one},
+	}, nil)
+
+	got, err := uc.AttachmentsFor(context.Background(), entity.AttachedToComment, []int{1, 2, 3})
+	require.NoError(t, err)
+	require.Len(t, got[1], 2)
+	assert.Equal(t, 12, got[1][1].ID)
+	require.Len(t, got[2], 1)
+	assert.Contains(t, got[2][0].ThumbnailURL, "/attachments/11/thumbnail?")
+	assert.Empty(t, got[3])
+}
+
+func TestAttachmentUseCase_CollectOrphans(t *testing.T) {
+	uc, repo, store := newAttachmentUseCase(t, usecase.AttachmentConfig{OrphanTTL: time.Hour})
+	ctx := context.Background()
+	for _, key := range []string{"aa/one.txt", "bb/two.png", "bb/two_thumb.png"} {
+		require.NoError(t, store.Put(ctx, key, strings.NewReader("x"), 1, ""))
+	}
+
+	orphans := []entity.Attachment{
+		{ID: 1, Key: "aa/one.txt"},
+		{ID: 2, Key: "bb/two.png", ThumbnailKey: "bb/two_thumb.png"},
+	}
+	repo.On("ListOrphanedAttachments", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= time.Hour
+	}), 100).Return(orphans, nil)
+	// Первую загрузку успели прикрепить
+	repo.On("DeleteOrphanedAttachment", mock.Anything, 1).Return(fmt.Errorf("gone: %w", sql.ErrNoRows))
+	repo.On("DeleteOrphanedAttachment", mock.Anything, 2).Return(nil)
+
+	n, err := uc.CollectOrphans(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = store.Get(ctx, "aa/one.txt")
+	assert.NoError(t, err)
+	for _, key := range []string{"bb/two.png", "bb/two_thumb.png"} {
+		_, err = store.Get(ctx, key)
+		assert.ErrorIs(t, err, storage.ErrNotFound, key)
+	}
+}
+
+func TestPostUseCase_Attachments(t *testing.T) {
+	t.Run("CreateLinksAndLoads", func(t *testing.T) {
+		postRepo := new(MockPostRepository)
+		categoryRepo := new(MockCategoryRepository)
+		loader := new(MockAttachmentLoader)
+		uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), categoryRepo)
+		uc.SetAttachments(loader)
+		post := &entity.Post{Title: "Hi", Content: "pic", UserID: 2, CategoryID: 1, AttachmentIDs: []int{4, 4, 5}}
+		categoryRepo.On("GetCategoryByID", mock.Anything, 1).Return(&entity.Category{ID: 1}, nil)
+		postRepo.On("CreatePost", mock.Anything, mock.MatchedBy(func(p *entity.Post) bool {
+			return assert.ObjectsAreEqual([]int{4, 5}, p.AttachmentIDs)
+		})).Run(func(args mock.Arguments) { args.Get(1).(*entity.Post).ID = 7 }).Return(nil)
+		loader.On("AttachmentsFor", mock.Anything, entity.AttachedToPost, []int{7}).
+			Return(map[int][]entity.Attachment{7: {{ID: 4}, {ID: 5}}}, nil)
+
+		require.NoError(t, uc.CreatePost(context.Background(), post))
+		assert.Len(t, post.Attachments, 2)
+	})
+
+	t.Run("UnavailableUpload", func(t *testing.T) {
+		postRepo := new(MockPostRepository)
+		categoryRepo := new(MockCategoryRepository)
+		uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), categoryRepo)
+		categoryRepo.On("GetCategoryByID", mock.Anything, 1).Return(&entity.Category{ID: 1}, nil)
+		postRepo.On("CreatePost", mock.Anything, mock.Anything).Return(fmt.Errorf("link: %w", sql.ErrNoRows))
+
+		err := uc.CreatePost(context.Background(), &entity.Post{Title: "Hi", Content: "x", UserID: 2, CategoryID: 1, AttachmentIDs: []int{9}})
+		assert.ErrorIs(t, err, usecase.ErrAttachmentNotFound)
+	})
+
+	t.Run("TooMany", func(t *testing.T) {
+		uc := usecase.NewPostUseCase(new(MockPostRepository), new(MockUserRepository), new(MockCategoryRepository))
+		ids := make([]int, usecase.MaxAttachmentsPerItem+1)
+		for i := range ids {
+			ids[i] = i + 1
+		}
+
+		err := uc.CreatePost(context.Background(), &entity.Post{Title: "Hi", Content: "x", UserID: 2, CategoryID: 1, AttachmentIDs: ids})
+		assert.ErrorIs(t, err, usecase.ErrTooManyAttachments)
+	})
+}
+
+func TestCommentUseCase_TreeSkipsAttachmentsOfDeleted(t *testing.T) {
+	repo := new(MockCommentRepository)
+	loader := new(MockAttachmentLoader)
+	uc := usecase.NewCommentUseCase(repo, new(MockUserRepository))
+	uc.SetAttachments(loader)
+	expectOpenPost(repo, 1)
+	repo.On("ListCommentTree", mock.Anything, mock.Anything).Return([]*entity.Comment{
+		{ID: 1, PostID: 1},
+		{ID: 2, PostID: 1, Deleted: true},
+	}, nil)
+	loader.On("AttachmentsFor", mock.Anything, entity.AttachedToComment, []int{1}).
+		Return(map[int][]entity.Attachment{1: {{ID: 3}}}, nil)
+
+	page, err := uc.GetCommentTree(context.Background(), 1, entity.CommentTreeParams{})
+	require.NoError(t, err)
+	require.Len(t, page.Comments, 2)
+	assert.Len(t, page.Comments[0].Attachments, 1)
+	assert.Empty(t, page.Comments[1].Attachments)
+}
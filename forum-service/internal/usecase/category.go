@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+// Ограничения полей раздела
+const (
+	MaxCategorySlugLength = 64
+	MaxCategoryNameLength = 100
+)
+
+var (
+	ErrCategoryRequired = errors.New("category ID cannot be empty")
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryReadOnly = errors.New("category is read-only: only moderators can post here")
+	ErrInvalidSlug      = errors.New("invalid slug: use lowercase letters, digits and single hyphens")
+	ErrInvalidName      = errors.New("category name cannot be empty or longer than 100 characters")
+	ErrSlugTaken        = errors.New("category slug is already taken")
+	ErrInvalidParent    = errors.New("invalid parent: category cannot be nested into itself or its subcategory")
+	ErrCategoryNotEmpty = errors.New("category has posts or subcategories")
+	ErrAdminRequired    = errors.New("forbidden: admin role required")
+)
+
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// IsCategoryInputError сообщает, вызвана ли ошибка некорректными полями раздела
+func IsCategoryInputError(err error) bool {
+	for _, target := range []error{
+		ErrInvalidSlug,
+		ErrInvalidName,
+		ErrSlugTaken,
+		ErrInvalidParent,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+type CategoryRepository interface {
+	CreateCategory(ctx context.Context, category *entity.Category) error
+	GetCategoryByID(ctx context.Context, id int) (*entity.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error)
+	ListCategories(ctx context.Context) ([]*entity.Category, error)
+	UpdateCategory(ctx context.Context, category *entity.Category) error
+	DeleteCategory(ctx context.Context, id int) error
+	CategoryHasPosts(ctx context.Context, id int) (bool, error)
+}
+
+type CategoryUseCase interface {
+	ListCategories(ctx context.Context) ([]*entity.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error)
+	CreateCategory(ctx context.Context, userID int, input entity.CategoryInput) (*entity.Category, error)
+	UpdateCategory(ctx context.Context, userID, id int, input entity.CategoryInput) (*entity.Category, error)
+	DeleteCategory(ctx context.Context, userID, id int) error
+}
+
+type CategoryService struct {
+	repo     CategoryRepository
+	userRepo UserRepository
+}
+
+func NewCategoryUseCase(repo CategoryRepository, userRepo UserRepository) CategoryUseCase {
+	return &CategoryService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// ListCategories возвращает дерево разделов: корневые разделы со вложенными Children
+func (s *CategoryService) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	categories, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return buildCategoryTree(categories), nil
+}
+
+func (s *CategoryService) GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	category, err := s.repo.GetCategoryBySlug(ctx, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, userID int, input entity.CategoryInput) (*entity.Category, error) {
+	if err := requireAdmin(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	category := newCategory(input)
+	existing, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCategory(category, existing); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *CategoryService) UpdateCategory(ctx context.Context, userID, id int, input entity.CategoryInput) (*entity.Category, error) {
+	if err := requireAdmin(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if findCategory(existing, id) == nil {
+		return nil, ErrCategoryNotFound
+	}
+
+	category := newCategory(input)
+	category.ID = id
+	if err := validateCategory(category, existing); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateCategory(ctx, category); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+// DeleteCategory удаляет только пустой раздел: посты и подразделы нужно сначала перенести
+func (s *CategoryService) DeleteCategory(ctx context.Context, userID, id int) error {
+	if err := requireAdmin(ctx, s.userRepo, userID); err != nil {
+		return err
+	}
+
+	existing, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return err
+	}
+	category := findCategory(existing, id)
+	if category == nil {
+		return ErrCategoryNotFound
+	}
+	for _, c := range existing {
+		if c.ParentID != nil && *c.ParentID == id {
+			return ErrCategoryNotEmpty
+		}
+	}
+	// PostCount учитывает только видимые посты, а удалению мешают и черновики,
+	// и скрытые посты, и посты в корзине
+	hasPosts, err := s.repo.CategoryHasPosts(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hasPosts {
+		return ErrCategoryNotEmpty
+	}
+
+	err = s.repo.DeleteCategory(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCategoryNotFound
+	}
+	return err
+}
+
+// requireAdmin возвращает ErrAdminRequired, если пользователь не администратор
+func requireAdmin(ctx context.Context, userRepo UserRepository, userID int) error {
+	user, err := userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Role != entity.RoleAdmin {
+		return ErrAdminRequired
+	}
+	return nil
+}
+
+func newCategory(input entity.CategoryInput) *entity.Category {
+	return &entity.Category{
+		Slug:        strings.TrimSpace(input.Slug),
+		Name:        strings.TrimSpace(input.Name),
+		Description: strings.TrimSpace(input.Description),
+		Position:    input.Position,
+		ParentID:    input.ParentID,
+		ReadOnly:    input.ReadOnly,
+	}
+}
+
+// validateCategory проверяет поля раздела и его место в дереве existing.
+// Для нового раздела ID равен нулю.
+func validateCategory(category *entity.Category, existing []*entity.Category) error {
+	if len(category.Slug) > MaxCategorySlugLength || !categorySlugPattern.MatchString(category.Slug) {
+		return ErrInvalidSlug
+	}
+	if category.Name == "" || utf8.RuneCountInString(category.Name) > MaxCategoryNameLength {
+		return ErrInvalidName
+	}
+	for _, c := range existing {
+		if c.Slug == category.Slug && c.ID != category.ID {
+			return ErrSlugTaken
+		}
+	}
+
+	// Поднимаемся от нового родителя к корню: встретив сам раздел, получили бы цикл
+	seen := make(map[int]bool)
+	for parentID := category.ParentID; parentID != nil; {
+		if *parentID == category.ID || seen[*parentID] {
+			return ErrInvalidParent
+		}
+		seen[*parentID] = true
+		parent := findCategory(existing, *parentID)
+		if parent == nil {
+			return ErrInvalidParent
+		}
+		parentID = parent.ParentID
+	}
+	return nil
+}
+
+func findCategory(categories []*entity.Category, id int) *entity.Category {
+	for _, c := range categories {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// buildCategoryTree раскладывает плоский список по родителям, сохраняя его порядок
+func buildCategoryTree(categories []*entity.Category) []*entity.Category {
+	byID := make(map[int]*entity.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	roots := []*entity.Category{}
+	for _, c := range categories {
+		if c.ParentID != nil {
+			if parent, ok := byID[*c.ParentID]; ok {
+				parent.Children = append(parent.Children, c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+	return roots
+}
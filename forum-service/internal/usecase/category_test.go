@@ -0,0 +1,327 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) CreateCategory(ctx context.Context, category *entity.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) GetCategoryByID(ctx context.Context, id int) (*entity.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) UpdateCategory(ctx context.Context, category *entity.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) DeleteCategory(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) CategoryHasPosts(ctx context.Context, id int) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func intPtr(v int) *int { return &v }
+
+// categoryFixture - дерево general > go > go-web и отдельный раздел news
+func categoryFixture() []*entity.Category {
+	return []*entity.Category{
+		{ID: 1, Slug: "general", Name: "General", PostCount: 2},
+		{ID: 2, Slug: "go", Name: "Go", ParentID: intPtr(1)},
+		{ID: 4, Slug: "news", Name: "News", ReadOnly: true},
+		{ID: 3, Slug: "go-web", Name: "Go Web", ParentID: intPtr(2)},
+	}
+}
+
+var adminUser = &entity.User{ID: 1, Role: entity.RoleAdmin}
+
+func TestCategoryUseCase_ListCategories(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	repo.On("ListCategories", mock.Anything).Return(categoryFixture(), nil)
+	uc := usecase.NewCategoryUseCase(repo, new(MockUserRepository))
+
+	tree, err := uc.ListCategories(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, tree, 2)
+	assert.Equal(t, "general", tree[0].Slug)
+	assert.Equal(t, "news", tree[1].Slug)
+	require.Len(t, tree[0].Children, 1)
+	assert.Equal(t, "go", tree[0].Children[0].Slug)
+	require.Len(t, tree[0].Children[0].Children, 1)
+	assert.Equal(t, "go-web", tree[0].Children[0].Children[0].Slug)
+}
+
+func TestCategoryUseCase_GetCategoryBySlug_NotFound(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	repo.On("GetCategoryBySlug", mock.Anything, "missing").
+		Return(nil, fmt.Errorf("failed to get category: %w", sql.ErrNoRows))
+	uc := usecase.NewCategoryUseCase(repo, new(MockUserRepository))
+
+	_, err := uc.GetCategoryBySlug(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, usecase.ErrCategoryNotFound)
+}
+
+func TestCategoryUseCase_CreateCategory(t *testing.T) {
+	tests := []struct {
+		name        string
+		user        *entity.User
+		input       entity.CategoryInput
+		mockSetup   func(*MockCategoryRepository)
+		expectedErr error
+	}{
+		{
+			name:  "Success",
+			user:  adminUser,
+			input: entity.CategoryInput{Slug: " rust ", Name: "Rust", ParentID: intPtr(1)},
+			mockSetup: func(r *MockCategoryRepository) {
+				r.On("ListCategories", mock.Anything).Return(categoryFixture(), nil)
+				r.On("CreateCategory", mock.Anything, &entity.Category{Slug: "rust", Name: "Rust", ParentID: intPtr(1)}).Return(nil)
+			},
+		},
+		{
+			name:        "NotAdmin",
+			user:        &entity.User{ID: 1, Role: entity.RoleModerator},
+			input:       entity.CategoryInput{Slug: "rust", Name: "Rust"},
+			mockSetup:   func(r *MockCategoryRepository) {},
+			expectedErr: usecase.ErrAdminRequired,
+		},
+		{
+			name:  "InvalidSlug",
+			user:  adminUser,
+			input: entity.CategoryInput{Slug: "Rust Lang", Name: "Rust"},
+			mockSetup: func(r *MockCategoryRepository) {
+				r.On("ListCategories", mock.Anything).Return(categoryFixture(), nil)
+			},
+			expectedErr: usecase.ErrInvalidSlug,
+		},
+		{
+			name:  "EmptyName",
+			user:  adminUser,
+			input: entity.CategoryInput{Slug: "rust", Name: "  "},
+			mockSetup: func(r *MockCategoryRepository) {
+				r.On("ListCategories", mock.Anything).Return(categoryFixture(), nil)
+			},
+			expectedErr: usecase.ErrInvalidName,
+		},
+		{
+			name:  "SlugTaken",
+			user:  adminUser,
+			input: entity.CategoryInput{Slug: "go", Name: "Golang"},
+			mockSetup: func(r *MockCategoryRepository) {
+				r.On("ListCategories", mock.Anything).Return(categoryFixture(), nil)
+			},
+			expectedErr: usecase.ErrSlugTaken,
+		},
+		{
+			name:  "UnknownParent",
+			user:  adminUser,
+			input: entity.CategoryInput{Slug: "rust", Name: "Rust", ParentID: intPtr(99)},
+			mockSetup: func(r *MockCategoryRepository) {
+				r.On("ListCategories", mock.Anything).Return(categoryFixture(), nil)
+			},
+			expectedErr: usecase.ErrInvalidParent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCategoryRepository)
+			userRepo := new(MockUserRepository)
+			userRepo.On("GetUserByID", mock.Anything, tt.user.ID).Return(tt.user, nil)
+			tt.mockSetup(repo)
+			uc := usecase.NewCategoryUseCase(repo, userRepo)
+
+			category, err := uc.CreateCategory(context.Background(), tt.user.ID, tt.input)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "rust", category.Slug)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoryUseCase_UpdateCategory(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          int
+		input       entity.CategoryInput
+		mockSetup   func(*MockCategoryRepository)
+		expectedErr error
+	}{
+		{
+			name:  "Success_KeepsOwnSlug",
+			id:    2,
+			input: entity.CategoryInput{Slug: "go", Name: "Golang", Position: 5, ReadOnly: true},
+			mockSetup: func(r *MockCategoryRepository) {
+				r.On("UpdateCategory", mock.Anything, &entity.Category{
+					ID: 2, Slug: "go", Name: "Golang", Position: 5, ReadOnly: true,
+				}).Return(nil)
+			},
+		},
+		{
+			name:        "NotFound",
+			id:          99,
+			input:       entity.CategoryInput{Slug: "go", Name: "Go"},
+			mockSetup:   func(r *MockCategoryRepository) {},
+			expectedErr: usecase.ErrCategoryNotFound,
+		},
+		{
+			name:        "ParentIsItself",
+			id:          2,
+			input:       entity.CategoryInput{Slug: "go", Name: "Go", ParentID: intPtr(2)},
+			mockSetup:   func(r *MockCategoryRepository) {},
+			expectedErr: usecase.ErrInvalidParent,
+		},
+		{
+			name:        "ParentIsDescendant",
+			id:          1,
+			input:       entity.CategoryInput{Slug: "general", Name: "General", ParentID: intPtr(3)},
+			mockSetup:   func(r *MockCategoryRepository) {},
+			expectedErr: usecase.ErrInvalidParent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCategoryRepository)
+			userRepo := new(MockUserRepository)
+			userRepo.On("GetUserByID", mock.Anything, adminUser.ID).Return(adminUser, nil)
+			repo.On("ListCategories", mock.Anything).Return(categoryFixture(), nil)
+			tt.mockSetup(repo)
+			uc := usecase.NewCategoryUseCase(repo, userRepo)
+
+			_, err := uc.UpdateCategory(context.Background(), adminUser.ID, tt.id, tt.input)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoryUseCase_DeleteCategory(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          int
+		mockSetup   func(*MockCategoryRepository)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			id:   4,
+			mockSetup: func(r *MockCategoryRepository) {
+				r.On("CategoryHasPosts", mock.Anything, 4).Return(false, nil)
+				r.On("DeleteCategory", mock.Anything, 4).Return(nil)
+			},
+		},
+		{
+			name: "HasPosts",
+			id:   3,
+			mockSetup: func(r *MockCategoryRepository) {
+				r.On("CategoryHasPosts", mock.Anything, 3).Return(true, nil)
+			},
+			expectedErr: usecase.ErrCategoryNotEmpty,
+		},
+		{
+			// Черновики и посты в корзине не входят в PostCount, но удалению мешают
+			name: "HasOnlyHiddenPosts",
+			id:   4,
+			mockSetup: func(r *MockCategoryRepository) {
+				r.On("CategoryHasPosts", mock.Anything, 4).Return(true, nil)
+			},
+			expectedErr: usecase.ErrCategoryNotEmpty,
+		},
+		{
+			name:        "HasChildren",
+			id:          2,
+			mockSetup:   func(r *MockCategoryRepository) {},
+			expectedErr: usecase.ErrCategoryNotEmpty,
+		},
+		{
+			name:        "NotFound",
+			id:          99,
+			mockSetup:   func(r *MockCategoryRepository) {},
+			expectedErr: usecase.ErrCategoryNotFound,
+		},
+		{
+			name: "RepositoryError",
+			id:   3,
+			mockSetup: func(r *MockCategoryRepository) {
+				r.On("CategoryHasPosts", mock.Anything, 3).Return(false, nil)
+				r.On("DeleteCategory", mock.Anything, 3).Return(errors.New("database error"))
+			},
+			expectedErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCategoryRepository)
+			userRepo := new(MockUserRepository)
+			userRepo.On("GetUserByID", mock.Anything, adminUser.ID).Return(adminUser, nil)
+			repo.On("ListCategories", mock.Anything).Return(categoryFixture(), nil)
+			tt.mockSetup(repo)
+			uc := usecase.NewCategoryUseCase(repo, userRepo)
+
+			err := uc.DeleteCategory(context.Background(), adminUser.ID, tt.id)
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
@@ -2,12 +2,14 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
 	"net"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -100,9 +102,12 @@ type ChatRepository interface {
 }
 
 type ChatUseCase struct {
-	repo   ChatRepository
-	authUC AuthUseCaseInterface
-	hub    *WebSocketHub
+	repo        ChatRepository
+	authUC      AuthUseCaseInterface
+	hub         *WebSocketHub
+	mentions    MentionTracker
+	attachments AttachmentLoader
+	bans        BanChecker
 }
 
 type AuthUseCaseInterface interface {
@@ -120,6 +125,12 @@ type WebSocketHub struct {
 	connectionCount int
 	mutex           sync.Mutex
 	done            chan struct{} // Канал для сигнала завершения
+	closing         bool          // Защищено mutex; новые подключения отклоняются
+	metrics         HubMetrics
+	stopOnce        sync.Once
+	stopped         chan struct{} // Закрывается, когда run() завершился
+	clientsWG       sync.WaitGroup
+	running         atomic.Bool
 }
 
 type ChatUseCaseInterface interface {
@@ -128,12 +139,17 @@ type ChatUseCaseInterface interface {
 	HandleWebSocket(conn WebSocketConnection) // Используем интерфейс вместо *websocket.Conn
 }
 type WebSocketClient struct {
-	conn WebSocketConnection
-	send chan entity.ChatMessage
+	conn     WebSocketConnection
+	send     chan entity.ChatMessage
+	closeMsg []byte // Close-фрейм, отправляемый при закрытии send
 }
 
+// ErrChatShutdown возвращается, когда хаб уже остановлен
+var ErrChatShutdown = errors.New("chat is shutting down")
+
 func NewChatUseCase(repo ChatRepository, authUC AuthUseCaseInterface) *ChatUseCase {
 	hub := newWebSocketHub(100)
+	hub.running.Store(true)
 	go hub.run()
 
 	return &ChatUseCase{
@@ -162,10 +178,14 @@ func newWebSocketHub(maxConnections int) *WebSocketHub {
 		clients:        make(map[*WebSocketClient]bool),
 		maxConnections: maxConnections,
 		done:           make(chan struct{}),
+		stopped:        make(chan struct{}),
+		metrics:        nopHubMetrics{},
 	}
 }
 
 func (h *WebSocketHub) run() {
+	defer close(h.stopped)
+	defer h.running.Store(false)
 	for {
 		select {
 		case client := <-h.register:
@@ -176,17 +196,22 @@ func (h *WebSocketHub) run() {
 				delete(h.clients, client)
 			}
 		case message := <-h.broadcast:
+			start := time.Now()
 			for client := range h.clients {
 				select {
 				case client.send <- message:
 				default:
 					close(client.send)
 					delete(h.clients, client)
+					h.metrics.SlowClientDropped()
 				}
 			}
+			h.metrics.ObserveBroadcast(time.Since(start))
 		case <-h.done:
-			// Закрываем все клиентские соединения
+			// Закрываем все клиентские соединения с кодом "going away"
+			closeMsg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
 			for client := range h.clients {
+				client.closeMsg = closeMsg
 				close(client.send)
 				delete(h.clients, client)
 			}
@@ -195,8 +220,145 @@ func (h *WebSocketHub) run() {
 	}
 }
 
+// HubMetrics receives WebSocket hub events for monitoring.
+type HubMetrics interface {
+	SlowClientDropped()
+	ObserveBroadcast(d time.Duration)
+}
+
+type nopHubMetrics struct{}
+
+func (nopHubMetrics) SlowClientDropped()             {}
+func (nopHubMetrics) ObserveBroadcast(time.Duration) {}
+
+// SetMetrics installs hub metrics. It must be called before the hub starts
+// serving clients.
+func (uc *ChatUseCase) SetMetrics(m HubMetrics) {
+	uc.hub.metrics = m
+}
+
+// SetMentionTracker installs the handler of @mentions in chat messages. It must be
+// called before the hub starts serving clients.
+func (uc *ChatUseCase) SetMentionTracker(m MentionTracker) {
+	uc.mentions = m
+}
+
+// SetAttachments installs the loader of files attached to chat messages. It must
+// be called before the hub starts serving clients.
+func (uc *ChatUseCase) SetAttachments(l AttachmentLoader) {
+	uc.attachments = l
+}
+
+// SetBanChecker installs the check that keeps banned users from posting to the
+// chat. It must be called before the hub starts serving clients.
+func (uc *ChatUseCase) SetBanChecker(b BanChecker) {
+	uc.bans = b
+}
+
+// loadAttachments fills Attachments of messages when a loader is installed.
+func (uc *ChatUseCase) loadAttachments(ctx context.Context, messages []entity.ChatMessage) error {
+	if uc.attachments == nil || len(messages) == 0 {
+		return nil
+	}
+	ids := make([]int, len(messages))
+	for i := range messages {
+		ids[i] = messages[i].ID
+	}
+	attachments, err := uc.attachments.AttachmentsFor(ctx, entity.AttachedToChat, ids)
+	if err != nil {
+		return err
+	}
+	for i := range messages {
+		messages[i].Attachments = attachments[messages[i].ID]
+	}
+	return nil
+}
+
+// saveMessage saves msg with its attachments and loads them for the broadcast.
+// A banned author gets a *UserBannedError.
+func (uc *ChatUseCase) saveMessage(ctx context.Context, msg *entity.ChatMessage) error {
+	if uc.bans != nil {
+		if err := uc.bans.CheckNotBanned(ctx, msg.UserID); err != nil {
+			return err
+		}
+	}
+	ids, err := normalizeAttachmentIDs(msg.AttachmentIDs)
+	if err != nil {
+		return err
+	}
+	msg.AttachmentIDs = ids
+	if err := uc.repo.SaveChatMessage(ctx, msg); err != nil {
+		// Only an unavailable upload makes saving a message report sql.ErrNoRows
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAttachmentNotFound
+		}
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	messages := []entity.ChatMessage{*msg}
+	if err := uc.loadAttachments(ctx, messages); err != nil {
+		return err
+	}
+	msg.Attachments = messages[0].Attachments
+	return nil
+}
+
+func (uc *ChatUseCase) trackMentions(ctx context.Context, msg *entity.ChatMessage) {
+	if uc.mentions != nil {
+		source := entity.MentionSource{Kind: entity.MentionInChat, ID: msg.ID}
+		uc.mentions.TrackMentions(ctx, source, msg.UserID, msg.Text)
+	}
+}
+
+// ConnectedClients returns the number of open WebSocket connections.
+func (uc *ChatUseCase) ConnectedClients() int {
+	uc.hub.mutex.Lock()
+	defer uc.hub.mutex.Unlock()
+	return uc.hub.connectionCount
+}
+
+// HubHealth reports an error when the WebSocket hub loop is not running.
+func (uc *ChatUseCase) HubHealth(ctx context.Context) error {
+	if !uc.hub.running.Load() {
+		return errors.New("websocket hub is not running")
+	}
+	return nil
+}
+
+// Shutdown останавливает хаб: всем клиентам отправляется close-фрейм
+// "going away", после чего функция ждет завершения их writePump или отмены ctx.
+func (uc *ChatUseCase) Shutdown(ctx context.Context) error {
+	uc.hub.mutex.Lock()
+	uc.hub.closing = true
+	uc.hub.mutex.Unlock()
+	uc.hub.stopOnce.Do(func() { close(uc.hub.done) })
+
+	finished := make(chan struct{})
+	go func() {
+		<-uc.hub.stopped
+		uc.hub.clientsWG.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (uc *ChatUseCase) HandleWebSocket(conn WebSocketConnection) {
 	uc.hub.mutex.Lock()
+	if uc.hub.closing {
+		conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+		conn.Close()
+		uc.hub.mutex.Unlock()
+		return
+	}
 	if uc.hub.connectionCount >= uc.hub.maxConnections {
 		conn.WriteMessage(websocket.CloseMessage, []byte("too many connections"))
 		conn.Close()
@@ -204,15 +366,28 @@ func (uc *ChatUseCase) HandleWebSocket(conn WebSocketConnection) {
 		return
 	}
 	uc.hub.connectionCount++
+	uc.hub.clientsWG.Add(1)
 	uc.hub.mutex.Unlock()
 
 	client := &WebSocketClient{
 		conn: conn,
 		send: make(chan entity.ChatMessage, 256),
 	}
-	uc.hub.register <- client
+	select {
+	case uc.hub.register <- client:
+	case <-uc.hub.done:
+		defer uc.hub.clientsWG.Done()
+		conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+		conn.Close()
+		uc.hub.mutex.Lock()
+		uc.hub.connectionCount--
+		uc.hub.mutex.Unlock()
+		return
+	}
 
 	go func() {
+		defer uc.hub.clientsWG.Done()
 		client.writePump()
 		uc.hub.mutex.Lock()
 		uc.hub.connectionCount--
@@ -223,7 +398,10 @@ func (uc *ChatUseCase) HandleWebSocket(conn WebSocketConnection) {
 
 func (c *WebSocketClient) readPump(uc *ChatUseCase) {
 	defer func() {
-		uc.hub.unregister <- c
+		select {
+		case uc.hub.unregister <- c:
+		case <-uc.hub.done:
+		}
 		c.conn.Close()
 	}()
 
@@ -232,8 +410,9 @@ func (c *WebSocketClient) readPump(uc *ChatUseCase) {
 	_ = uc.repo.DeleteOldChatMessages(ctx, 30*time.Minute)
 	for {
 		var msg struct {
-			Text  string `json:"text"`
-			Token string `json:"token"`
+			Text          string `json:"text"`
+			Token         string `json:"token"`
+			AttachmentIDs []int  `json:"attachment_ids"`
 		}
 		err := c.conn.ReadJSON(&msg)
 		if err != nil {
@@ -256,19 +435,30 @@ func (c *WebSocketClient) readPump(uc *ChatUseCase) {
 		}
 
 		chatMsg := entity.ChatMessage{
-			UserID:    int(userID), // Convert int64 to int for entity
-			Author:    username,
-			Text:      msg.Text,
-			CreatedAt: time.Now(),
+			UserID:        int(userID), // Convert int64 to int for entity
+			Author:        username,
+			Text:          msg.Text,
+			CreatedAt:     time.Now(),
+			AttachmentIDs: msg.AttachmentIDs,
 		}
 
-		if err := uc.repo.SaveChatMessage(context.Background(), &chatMsg); err != nil {
+		if err := uc.saveMessage(context.Background(), &chatMsg); err != nil {
+			if errors.Is(err, ErrAttachmentNotFound) || errors.Is(err, ErrTooManyAttachments) ||
+				errors.Is(err, ErrUserBanned) {
+				c.conn.WriteJSON(map[string]string{"error": err.Error()})
+				continue
+			}
 			log.Printf("Error saving message: %v", err)
 			c.conn.WriteJSON(map[string]string{"error": "failed to save message"})
 			continue
 		}
+		uc.trackMentions(context.Background(), &chatMsg)
 
-		uc.hub.broadcast <- chatMsg
+		select {
+		case uc.hub.broadcast <- chatMsg:
+		case <-uc.hub.done:
+			return
+		}
 	}
 }
 
@@ -277,7 +467,11 @@ func (c *WebSocketClient) writePump() {
 	for {
 		message, ok := <-c.send
 		if !ok {
-			c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+			closeMsg := c.closeMsg
+			if closeMsg == nil {
+				closeMsg = []byte{}
+			}
+			c.conn.WriteMessage(websocket.CloseMessage, closeMsg)
 			return
 		}
 		c.conn.WriteJSON(message)
@@ -288,10 +482,15 @@ func (uc *ChatUseCase) SendMessage(ctx context.Context, message *entity.ChatMess
 	if message == nil {
 		return errors.New("message cannot be nil")
 	}
-	if err := uc.repo.SaveChatMessage(ctx, message); err != nil {
+	if err := uc.saveMessage(ctx, message); err != nil {
 		return err // Возвращаем ошибку из репозитория
 	}
-	uc.hub.broadcast <- *message
+	uc.trackMentions(ctx, message)
+	select {
+	case uc.hub.broadcast <- *message:
+	case <-uc.hub.done:
+		return ErrChatShutdown
+	}
 	return nil
 }
 
@@ -302,5 +501,12 @@ func (uc *ChatUseCase) GetMessages(ctx context.Context, limit int) ([]entity.Cha
 		return nil, err
 	}
 
-	return uc.repo.GetChatMessages(ctx, limit)
+	messages, err := uc.repo.GetChatMessages(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.loadAttachments(ctx, messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
 }
@@ -284,12 +284,13 @@ func TestChatUseCase_HandleWebSocket(t *testing.T) {
 				r.On("DeleteOldChatMessages", mock.Anything, 30*time.Minute).Return(nil).Once()
 
 				// Мокаем успешное чтение сообщения
-				m.On("ReadJSON", mock.AnythingOfType("*struct { Text string \"json:\\\"text\\\"\"; Token string \"json:\\\"token\\\"\" }")).
+				m.On("ReadJSON", mock.AnythingOfType("*struct { Text string \"json:\\\"text\\\"\"; Token string \"json:\\\"token\\\"\"; AttachmentIDs []int \"json:\\\"attachment_ids\\\"\" }")).
 					Return(nil).
 					Run(func(args mock.Arguments) {
 						msg := args.Get(0).(*struct {
-							Text  string `json:"text"`
-							Token string `json:"token"`
+							Text          string `json:"text"`
+							Token         string `json:"token"`
+							AttachmentIDs []int  `json:"attachment_ids"`
 						})
 						msg.Text = "test message"
 						msg.Token = "valid_token"
@@ -395,3 +396,42 @@ func TestWebSocketHub_Operations(t *testing.T) {
 	close(hub.done) // Закрываем канал done только один раз
 	<-hubDone       // Ждем завершения горутины hub.run()
 }
+
+func TestChatUseCase_HubHealth(t *testing.T) {
+	uc := NewChatUseCase(new(MockChatRepository), new(MockAuthUseCase))
+	assert.NoError(t, uc.HubHealth(context.Background()))
+}
+
+func TestChatUseCase_Shutdown(t *testing.T) {
+	mockRepo := new(MockChatRepository)
+	mockRepo.On("DeleteOldChatMessages", mock.Anything, 30*time.Minute).Return(nil).Maybe()
+	mockRepo.On("SaveChatMessage", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	mockConn := new(MockWebSocketConnection)
+	released := make(chan struct{})
+	var releaseOnce sync.Once
+	mockConn.On("ReadJSON", mock.Anything).
+		Run(func(mock.Arguments) { <-released }).
+		Return(errors.New("use of closed network connection"))
+	mockConn.On("WriteMessage", websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")).Return(nil).Once()
+	mockConn.On("Close").
+		Run(func(mock.Arguments) { releaseOnce.Do(func() { close(released) }) }).
+		Return(nil)
+
+	uc := NewChatUseCase(mockRepo, new(MockAuthUseCase))
+	go uc.HandleWebSocket(mockConn)
+	assert.Eventually(t, func() bool {
+		uc.hub.mutex.Lock()
+		defer uc.hub.mutex.Unlock()
+		return uc.hub.connectionCount == 1
+	}, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, uc.Shutdown(ctx))
+	assert.Error(t, uc.HubHealth(ctx))
+	assert.ErrorIs(t, uc.SendMessage(ctx, &entity.ChatMessage{Text: "late"}), ErrChatShutdown)
+
+	mockConn.AssertExpectations(t)
+}
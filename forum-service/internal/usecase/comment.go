@@ -4,34 +4,118 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
 )
 
+// Ограничения ветвей комментариев
+const (
+	// MaxCommentDepth - наибольшая глубина ответа; комментарии к посту имеют глубину 0
+	MaxCommentDepth        = 8
+	DefaultCommentPageSize = 20
+	MaxCommentPageSize     = 100
+	// CommentTreeDepth - сколько уровней ответов отдается за один запрос дерева
+	CommentTreeDepth = 3
+	// RepliesPerComment - сколько ответов на комментарий отдается сразу, остальные по курсору
+	RepliesPerComment = 5
+)
+
+var (
+	ErrParentCommentNotFound = errors.New("parent comment not found")
+	ErrCommentTooDeep        = errors.New("reply is nested too deeply")
+	ErrEmptyComment          = errors.New("comment content cannot be empty")
+	ErrCommentNotFound       = errors.New("comment not found")
+	ErrCommentEditForbidden  = errors.New("unauthorized: you can only edit your own comments")
+	ErrCommentHistoryDenied  = errors.New("only moderators and the author can view comment history")
+)
+
 type CommentUseCase struct {
-	repo CommentRepository
+	repo        CommentRepository
+	userRepo    UserRepository
+	notifier    CommentNotifier
+	mentions    MentionTracker
+	attachments AttachmentLoader
 }
 
 type CommentRepository interface {
 	CreateComment(ctx context.Context, comment *entity.Comment) error
+	GetCommentByID(ctx context.Context, id int) (*entity.Comment, error)
 	GetCommentsByPostID(ctx context.Context, postID int) ([]entity.Comment, error)
+	ListCommentTree(ctx context.Context, q entity.CommentTreeQuery) ([]*entity.Comment, error)
+	UpdateComment(ctx context.Context, comment *entity.Comment, editorID int) error
+	GetCommentRevisions(ctx context.Context, commentID int) ([]entity.CommentRevision, error)
 	DeleteComment(ctx context.Context, commentID int, userID int) error
+	GetPostStatus(ctx context.Context, postID int) (*entity.PostStatus, error)
 }
 type CommentUseCaseInterface interface {
 	CreateComment(ctx context.Context, comment *entity.Comment) error
 	GetCommentsByPostID(ctx context.Context, postID int) ([]entity.Comment, error)
+	GetCommentTree(ctx context.Context, postID int, params entity.CommentTreeParams) (*entity.CommentTreePage, error)
+	UpdateComment(ctx context.Context, postID, commentID, userID int, content string) (*entity.Comment, error)
+	GetCommentRevisions(ctx context.Context, postID, commentID, userID int) ([]entity.CommentRevision, error)
 	DeleteComment(ctx context.Context, commentID, userID int) error
 }
 
-func NewCommentUseCase(repo CommentRepository) *CommentUseCase {
-	return &CommentUseCase{repo: repo}
+func NewCommentUseCase(repo CommentRepository, userRepo UserRepository) *CommentUseCase {
+	return &CommentUseCase{repo: repo, userRepo: userRepo}
+}
+
+// SetNotifier устанавливает получателя новых комментариев. Вызывается до начала
+// обработки запросов.
+func (uc *CommentUseCase) SetNotifier(n CommentNotifier) {
+	uc.notifier = n
+}
+
+// SetMentionTracker устанавливает обработчик упоминаний в тексте комментариев.
+// Вызывается до начала обработки запросов.
+func (uc *CommentUseCase) SetMentionTracker(m MentionTracker) {
+	uc.mentions = m
+}
+
+// SetAttachments устанавливает загрузчик вложений комментариев. Вызывается до
+// начала обработки запросов.
+func (uc *CommentUseCase) SetAttachments(l AttachmentLoader) {
+	uc.attachments = l
+}
+
+// loadAttachments заполняет Attachments комментариев, кроме удаленных и скрытых,
+// если загрузчик вложений задан
+func (uc *CommentUseCase) loadAttachments(ctx context.Context, comments []*entity.Comment) error {
+	if uc.attachments == nil {
+		return nil
+	}
+	ids := make([]int, 0, len(comments))
+	for _, c := range comments {
+		if !c.Deleted && !c.Hidden {
+			ids = append(ids, c.ID)
+		}
+	}
+	attachments, err := uc.attachments.AttachmentsFor(ctx, entity.AttachedToComment, ids)
+	if err != nil {
+		return err
+	}
+	for _, c := range comments {
+		if !c.Deleted && !c.Hidden {
+			c.Attachments = attachments[c.ID]
+		}
+	}
+	return nil
+}
+
+func (uc *CommentUseCase) trackMentions(ctx context.Context, comment *entity.Comment) {
+	if uc.mentions != nil {
+		source := entity.MentionSource{Kind: entity.MentionInComment, ID: comment.ID, PostID: comment.PostID}
+		uc.mentions.TrackMentions(ctx, source, comment.UserID, comment.Content)
+	}
 }
+
 func (uc *CommentUseCase) CreateComment(ctx context.Context, comment *entity.Comment) error {
 	if comment == nil {
 		return errors.New("comment cannot be nil")
 	}
 	if comment.Content == "" {
-		return errors.New("comment content cannot be empty")
+		return ErrEmptyComment
 	}
 	if comment.PostID == 0 {
 		return errors.New("post ID cannot be empty")
@@ -39,15 +123,271 @@ func (uc *CommentUseCase) CreateComment(ctx context.Context, comment *entity.Com
 	if comment.UserID == 0 {
 		return errors.New("user ID cannot be empty")
 	}
-	return uc.repo.CreateComment(ctx, comment)
+	var err error
+	if comment.Format, comment.ContentHTML, err = renderContent(comment.Format, comment.Content); err != nil {
+		return err
+	}
+	if comment.AttachmentIDs, err = normalizeAttachmentIDs(comment.AttachmentIDs); err != nil {
+		return err
+	}
+	if err := uc.checkPostWritable(ctx, comment.PostID, true); err != nil {
+		return err
+	}
+
+	comment.Depth = 0
+	if comment.ParentID != nil {
+		parent, err := uc.repo.GetCommentByID(ctx, *comment.ParentID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrParentCommentNotFound
+		}
+		if err != nil {
+			return err
+		}
+		// Отвечать можно только на живой комментарий того же поста
+		if parent.PostID != comment.PostID || parent.Deleted {
+			return ErrParentCommentNotFound
+		}
+		if parent.Depth+1 > MaxCommentDepth {
+			return ErrCommentTooDeep
+		}
+		comment.Depth = parent.Depth + 1
+	}
+	if err := uc.repo.CreateComment(ctx, comment); err != nil {
+		// Единственная причина sql.ErrNoRows при создании - недоступная загрузка
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAttachmentNotFound
+		}
+		return err
+	}
+	if uc.notifier != nil {
+		uc.notifier.CommentCreated(ctx, comment)
+	}
+	uc.trackMentions(ctx, comment)
+	if len(comment.AttachmentIDs) > 0 {
+		return uc.loadAttachments(ctx, []*entity.Comment{comment})
+	}
+	return nil
 }
 
 func (uc *CommentUseCase) GetCommentsByPostID(ctx context.Context, postID int) ([]entity.Comment, error) {
 	if postID <= 0 {
 		return nil, errors.New("invalid post ID")
 	}
-	return uc.repo.GetCommentsByPostID(ctx, postID)
+	if err := uc.checkPostShown(ctx, postID); err != nil {
+		return nil, err
+	}
+	comments, err := uc.repo.GetCommentsByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	pointers := make([]*entity.Comment, len(comments))
+	for i := range comments {
+		pointers[i] = &comments[i]
+	}
+	if err := uc.loadAttachments(ctx, pointers); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// GetCommentTree возвращает страницу комментариев уровня, заданного курсором, с ответами
+// на CommentTreeDepth уровней вниз. Там, где загружены не все ответы, у комментария
+// заполняется RepliesCursor для их догрузки.
+func (uc *CommentUseCase) GetCommentTree(ctx context.Context, postID int, params entity.CommentTreeParams) (*entity.CommentTreePage, error) {
+	if postID <= 0 {
+		return nil, errors.New("invalid post ID")
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultCommentPageSize
+	}
+	if limit > MaxCommentPageSize {
+		limit = MaxCommentPageSize
+	}
+
+	var cursor entity.CommentCursor
+	if params.Cursor != "" {
+		decoded, err := decodeCommentCursor(params.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		cursor = *decoded
+	}
+	if err := uc.checkPostShown(ctx, postID); err != nil {
+		return nil, err
+	}
+
+	comments, err := uc.repo.ListCommentTree(ctx, entity.CommentTreeQuery{
+		PostID:  postID,
+		Cursor:  cursor,
+		Limit:   limit + 1,
+		Depth:   CommentTreeDepth,
+		Replies: RepliesPerComment,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.loadAttachments(ctx, comments); err != nil {
+		return nil, err
+	}
+
+	page := &entity.CommentTreePage{Comments: buildCommentTree(comments, cursor.ParentID)}
+	if len(page.Comments) > limit {
+		page.Comments = page.Comments[:limit]
+		last := page.Comments[limit-1]
+		page.NextCursor = encodeCommentCursor(&entity.CommentCursor{
+			ParentID: cursor.ParentID,
+			Time:     last.CreatedAt,
+			ID:       last.ID,
+		})
+	}
+	return page, nil
+}
+
+// buildCommentTree раскладывает плоский список по родителям. Корнями считаются
+// комментарии с родителем parentID (0 - верхний уровень).
+func buildCommentTree(comments []*entity.Comment, parentID int) []*entity.Comment {
+	byID := make(map[int]*entity.Comment, len(comments))
+	for _, c := range comments {
+		byID[c.ID] = c
+	}
+
+	roots := []*entity.Comment{}
+	for _, c := range comments {
+		if c.ParentID == nil || *c.ParentID == parentID {
+			roots = append(roots, c)
+			continue
+		}
+		if parent, ok := byID[*c.ParentID]; ok {
+			parent.Replies = append(parent.Replies, c)
+		}
+	}
+
+	for _, c := range comments {
+		if len(c.Replies) >= c.ReplyCount {
+			continue
+		}
+		cursor := &entity.CommentCursor{ParentID: c.ID}
+		if n := len(c.Replies); n > 0 {
+			cursor.Time = c.Replies[n-1].CreatedAt
+			cursor.ID = c.Replies[n-1].ID
+		}
+		c.RepliesCursor = encodeCommentCursor(cursor)
+	}
+	return roots
+}
+
+// UpdateComment меняет текст комментария. Править может автор или администратор;
+// прежний текст сохраняется в истории правок.
+func (uc *CommentUseCase) UpdateComment(ctx context.Context, postID, commentID, userID int, content string) (*entity.Comment, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, ErrEmptyComment
+	}
+
+	comment, err := uc.getPostComment(ctx, postID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Deleted || comment.Hidden {
+		return nil, ErrCommentNotFound
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID && user.Role != entity.RoleAdmin {
+		return nil, ErrCommentEditForbidden
+	}
+	if err := uc.checkPostWritable(ctx, postID, false); err != nil {
+		return nil, err
+	}
+
+	if comment.Content == content {
+		return comment, uc.loadAttachments(ctx, []*entity.Comment{comment})
+	}
+	// Правка не меняет формат комментария
+	var html string
+	if comment.Format, html, err = renderContent(comment.Format, content); err != nil {
+		return nil, err
+	}
+	comment.Content = content
+	comment.ContentHTML = html
+	if err := uc.repo.UpdateComment(ctx, comment, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	uc.trackMentions(ctx, comment)
+	return comment, uc.loadAttachments(ctx, []*entity.Comment{comment})
+}
+
+// GetCommentRevisions возвращает историю правок комментария. Она доступна модераторам
+// и автору комментария.
+func (uc *CommentUseCase) GetCommentRevisions(ctx context.Context, postID, commentID, userID int) ([]entity.CommentRevision, error) {
+	comment, err := uc.getPostComment(ctx, postID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID && !user.IsModerator() {
+		return nil, ErrCommentHistoryDenied
+	}
+
+	return uc.repo.GetCommentRevisions(ctx, commentID)
+}
+
+// checkPostWritable проверяет, что пост существует и его состояние допускает
+// новый комментарий (commenting) или правку прежнего
+func (uc *CommentUseCase) checkPostWritable(ctx context.Context, postID int, commenting bool) error {
+	status, err := uc.repo.GetPostStatus(ctx, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPostNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return checkPostWritable(postID, status.State, commenting)
+}
+
+// checkPostShown проверяет, что пост опубликован и не скрыт: комментарии черновика,
+// скрытого поста или поста в корзине не показываются
+func (uc *CommentUseCase) checkPostShown(ctx context.Context, postID int) error {
+	_, err := uc.repo.GetPostStatus(ctx, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPostNotFound
+	}
+	return err
+}
+
+// getPostComment загружает комментарий и проверяет, что он относится к посту postID
+func (uc *CommentUseCase) getPostComment(ctx context.Context, postID, commentID int) (*entity.Comment, error) {
+	if postID <= 0 {
+		return nil, errors.New("invalid post ID")
+	}
+	if commentID <= 0 {
+		return nil, errors.New("invalid comment ID")
+	}
+
+	comment, err := uc.repo.GetCommentByID(ctx, commentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if comment.PostID != postID {
+		return nil, ErrCommentNotFound
+	}
+	return comment, nil
 }
+
 func (uc *CommentUseCase) DeleteComment(ctx context.Context, commentID int, userID int) error {
 	if commentID <= 0 {
 		return errors.New("invalid comment ID")
@@ -56,8 +396,29 @@ func (uc *CommentUseCase) DeleteComment(ctx context.Context, commentID int, user
 		return errors.New("invalid user ID")
 	}
 
-	err := uc.repo.DeleteComment(ctx, commentID, userID)
-	if err == sql.ErrNoRows {
+	comment, err := uc.repo.GetCommentByID(ctx, commentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if comment.Deleted {
+		return ErrCommentNotFound
+	}
+	user, err := uc.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	// Комментарии архивного поста только читаются, удалить их может модератор
+	if !user.IsModerator() {
+		if err := uc.checkPostWritable(ctx, comment.PostID, false); err != nil {
+			return err
+		}
+	}
+
+	err = uc.repo.DeleteComment(ctx, commentID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
 		return errors.New("unauthorized: you can only delete your own comments")
 	}
 	return err
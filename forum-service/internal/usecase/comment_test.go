@@ -2,8 +2,11 @@ package usecase_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
@@ -29,11 +32,53 @@ func (m *MockCommentRepository) GetCommentsByPostID(ctx context.Context, postID
 	return args.Get(0).([]entity.Comment), args.Error(1)
 }
 
+func (m *MockCommentRepository) GetCommentByID(ctx context.Context, id int) (*entity.Comment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) ListCommentTree(ctx context.Context, q entity.CommentTreeQuery) ([]*entity.Comment, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) UpdateComment(ctx context.Context, comment *entity.Comment, editorID int) error {
+	args := m.Called(ctx, comment, editorID)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetCommentRevisions(ctx context.Context, commentID int) ([]entity.CommentRevision, error) {
+	args := m.Called(ctx, commentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.CommentRevision), args.Error(1)
+}
+
 func (m *MockCommentRepository) DeleteComment(ctx context.Context, commentID, userID int) error {
 	args := m.Called(ctx, commentID, userID)
 	return args.Error(0)
 }
 
+func (m *MockCommentRepository) GetPostStatus(ctx context.Context, postID int) (*entity.PostStatus, error) {
+	args := m.Called(ctx, postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PostStatus), args.Error(1)
+}
+
+// expectOpenPost разрешает комментирование поста postID
+func expectOpenPost(m *MockCommentRepository, postID int) {
+	m.On("GetPostStatus", mock.Anything, postID).Return(&entity.PostStatus{PostID: postID, State: entity.PostOpen}, nil)
+}
+
 func TestCommentUseCase_CreateComment(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -49,6 +94,7 @@ func TestCommentUseCase_CreateComment(t *testing.T) {
 				UserID:  1,
 			},
 			mockSetup: func(m *MockCommentRepository) {
+				expectOpenPost(m, 1)
 				m.On("CreateComment", mock.Anything, mock.AnythingOfType("*entity.Comment")).Return(nil)
 			},
 		},
@@ -90,11 +136,48 @@ func TestCommentUseCase_CreateComment(t *testing.T) {
 				UserID:  1,
 			},
 			mockSetup: func(m *MockCommentRepository) {
+				expectOpenPost(m, 1)
 				m.On("CreateComment", mock.Anything, mock.AnythingOfType("*entity.Comment")).
 					Return(errors.New("database error"))
 			},
 			expectedErr: "database error",
 		},
+		{
+			name: "PostLocked",
+			comment: &entity.Comment{
+				Content: "Test content",
+				PostID:  1,
+				UserID:  1,
+			},
+			mockSetup: func(m *MockCommentRepository) {
+				m.On("GetPostStatus", mock.Anything, 1).Return(&entity.PostStatus{PostID: 1, State: entity.PostLocked}, nil)
+			},
+			expectedErr: usecase.ErrPostLocked.Error(),
+		},
+		{
+			name: "PostArchived",
+			comment: &entity.Comment{
+				Content: "Test content",
+				PostID:  1,
+				UserID:  1,
+			},
+			mockSetup: func(m *MockCommentRepository) {
+				m.On("GetPostStatus", mock.Anything, 1).Return(&entity.PostStatus{PostID: 1, State: entity.PostArchived}, nil)
+			},
+			expectedErr: usecase.ErrPostArchived.Error(),
+		},
+		{
+			name: "PostNotFound",
+			comment: &entity.Comment{
+				Content: "Test content",
+				PostID:  1,
+				UserID:  1,
+			},
+			mockSetup: func(m *MockCommentRepository) {
+				m.On("GetPostStatus", mock.Anything, 1).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: usecase.ErrPostNotFound.Error(),
+		},
 		{
 			name:        "NilComment",
 			comment:     nil,
@@ -106,7 +189,7 @@ func TestCommentUseCase_CreateComment(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := new(MockCommentRepository)
-			uc := usecase.NewCommentUseCase(repo)
+			uc := usecase.NewCommentUseCase(repo, new(MockUserRepository))
 
 			tt.mockSetup(repo)
 
@@ -137,6 +220,7 @@ func TestCommentUseCase_GetCommentsByPostID(t *testing.T) {
 			name:   "Success",
 			postID: 1,
 			mockSetup: func(m *MockCommentRepository) {
+				expectOpenPost(m, 1)
 				m.On("GetCommentsByPostID", mock.Anything, 1).
 					Return([]entity.Comment{
 						{ID: 1, Content: "Comment 1"},
@@ -149,6 +233,7 @@ func TestCommentUseCase_GetCommentsByPostID(t *testing.T) {
 			name:   "SingleComment",
 			postID: 1,
 			mockSetup: func(m *MockCommentRepository) {
+				expectOpenPost(m, 1)
 				m.On("GetCommentsByPostID", mock.Anything, 1).
 					Return([]entity.Comment{
 						{ID: 1, Content: "Single Comment"},
@@ -160,6 +245,7 @@ func TestCommentUseCase_GetCommentsByPostID(t *testing.T) {
 			name:   "EmptyResult",
 			postID: 2,
 			mockSetup: func(m *MockCommentRepository) {
+				expectOpenPost(m, 2)
 				m.On("GetCommentsByPostID", mock.Anything, 2).
 					Return([]entity.Comment{}, nil)
 			},
@@ -183,18 +269,29 @@ func TestCommentUseCase_GetCommentsByPostID(t *testing.T) {
 			name:   "RepositoryError",
 			postID: 3,
 			mockSetup: func(m *MockCommentRepository) {
+				expectOpenPost(m, 3)
 				m.On("GetCommentsByPostID", mock.Anything, 3).
 					Return(nil, errors.New("database error"))
 			},
 			expectedErr: "database error",
 			expectError: true,
 		},
+		{
+			name:   "PostNotShown",
+			postID: 4,
+			mockSetup: func(m *MockCommentRepository) {
+				// Скрытый пост, черновик и пост в корзине не находятся
+				m.On("GetPostStatus", mock.Anything, 4).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: usecase.ErrPostNotFound.Error(),
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := new(MockCommentRepository)
-			uc := usecase.NewCommentUseCase(repo)
+			uc := usecase.NewCommentUseCase(repo, new(MockUserRepository))
 
 			tt.mockSetup(repo)
 
@@ -215,18 +312,25 @@ func TestCommentUseCase_GetCommentsByPostID(t *testing.T) {
 }
 
 func TestCommentUseCase_DeleteComment(t *testing.T) {
+	comment := &entity.Comment{ID: 1, PostID: 5, UserID: 1}
+	author := &entity.User{ID: 1, Role: entity.RoleUser}
+	archived := &entity.PostStatus{PostID: 5, State: entity.PostArchived}
+
 	tests := []struct {
 		name        string
 		commentID   int
 		userID      int
-		mockSetup   func(*MockCommentRepository)
+		mockSetup   func(*MockCommentRepository, *MockUserRepository)
 		expectedErr string
 	}{
 		{
 			name:      "Success",
 			commentID: 1,
 			userID:    1,
-			mockSetup: func(m *MockCommentRepository) {
+			mockSetup: func(m *MockCommentRepository, u *MockUserRepository) {
+				m.On("GetCommentByID", mock.Anything, 1).Return(comment, nil)
+				u.On("GetUserByID", mock.Anything, 1).Return(author, nil)
+				expectOpenPost(m, 5)
 				m.On("DeleteComment", mock.Anything, 1, 1).Return(nil)
 			},
 		},
@@ -234,28 +338,82 @@ func TestCommentUseCase_DeleteComment(t *testing.T) {
 			name:        "InvalidCommentID",
 			commentID:   0,
 			userID:      1,
-			mockSetup:   func(m *MockCommentRepository) {},
+			mockSetup:   func(m *MockCommentRepository, u *MockUserRepository) {},
 			expectedErr: "invalid comment ID",
 		},
 		{
 			name:        "NegativeCommentID",
 			commentID:   -1,
 			userID:      1,
-			mockSetup:   func(m *MockCommentRepository) {},
+			mockSetup:   func(m *MockCommentRepository, u *MockUserRepository) {},
 			expectedErr: "invalid comment ID",
 		},
 		{
 			name:        "InvalidUserID",
 			commentID:   1,
 			userID:      0,
-			mockSetup:   func(m *MockCommentRepository) {},
+			mockSetup:   func(m *MockCommentRepository, u *MockUserRepository) {},
 			expectedErr: "invalid user ID",
 		},
+		{
+			name:      "NotFound",
+			commentID: 3,
+			userID:    1,
+			mockSetup: func(m *MockCommentRepository, u *MockUserRepository) {
+				m.On("GetCommentByID", mock.Anything, 3).Return(nil, fmt.Errorf("failed to get comment: %w", sql.ErrNoRows))
+			},
+			expectedErr: usecase.ErrCommentNotFound.Error(),
+		},
+		{
+			name:      "AlreadyDeleted",
+			commentID: 1,
+			userID:    1,
+			mockSetup: func(m *MockCommentRepository, u *MockUserRepository) {
+				m.On("GetCommentByID", mock.Anything, 1).Return(&entity.Comment{ID: 1, PostID: 5, Deleted: true}, nil)
+			},
+			expectedErr: usecase.ErrCommentNotFound.Error(),
+		},
+		{
+			name:      "ArchivedPost",
+			commentID: 1,
+			userID:    1,
+			mockSetup: func(m *MockCommentRepository, u *MockUserRepository) {
+				m.On("GetCommentByID", mock.Anything, 1).Return(comment, nil)
+				u.On("GetUserByID", mock.Anything, 1).Return(author, nil)
+				m.On("GetPostStatus", mock.Anything, 5).Return(archived, nil)
+			},
+			expectedErr: usecase.ErrPostArchived.Error(),
+		},
+		{
+			name:      "ArchivedPostModerator",
+			commentID: 1,
+			userID:    7,
+			mockSetup: func(m *MockCommentRepository, u *MockUserRepository) {
+				m.On("GetCommentByID", mock.Anything, 1).Return(comment, nil)
+				u.On("GetUserByID", mock.Anything, 7).Return(&entity.User{ID: 7, Role: entity.RoleModerator}, nil)
+				m.On("DeleteComment", mock.Anything, 1, 7).Return(nil)
+			},
+		},
+		{
+			name:      "NotOwner",
+			commentID: 1,
+			userID:    2,
+			mockSetup: func(m *MockCommentRepository, u *MockUserRepository) {
+				m.On("GetCommentByID", mock.Anything, 1).Return(comment, nil)
+				u.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2, Role: entity.RoleUser}, nil)
+				expectOpenPost(m, 5)
+				m.On("DeleteComment", mock.Anything, 1, 2).Return(fmt.Errorf("transaction failed: %w", sql.ErrNoRows))
+			},
+			expectedErr: "unauthorized: you can only delete your own comments",
+		},
 		{
 			name:      "RepositoryError",
 			commentID: 2,
 			userID:    1,
-			mockSetup: func(m *MockCommentRepository) {
+			mockSetup: func(m *MockCommentRepository, u *MockUserRepository) {
+				m.On("GetCommentByID", mock.Anything, 2).Return(&entity.Comment{ID: 2, PostID: 5, UserID: 1}, nil)
+				u.On("GetUserByID", mock.Anything, 1).Return(author, nil)
+				expectOpenPost(m, 5)
 				m.On("DeleteComment", mock.Anything, 2, 1).
 					Return(errors.New("database error"))
 			},
@@ -266,9 +424,10 @@ func TestCommentUseCase_DeleteComment(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := new(MockCommentRepository)
-			uc := usecase.NewCommentUseCase(repo)
+			userRepo := new(MockUserRepository)
+			uc := usecase.NewCommentUseCase(repo, userRepo)
 
-			tt.mockSetup(repo)
+			tt.mockSetup(repo, userRepo)
 
 			err := uc.DeleteComment(context.Background(), tt.commentID, tt.userID)
 
@@ -280,17 +439,19 @@ func TestCommentUseCase_DeleteComment(t *testing.T) {
 			}
 
 			repo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
 		})
 	}
 }
 
 func TestNewCommentUseCase(t *testing.T) {
 	repo := new(MockCommentRepository)
-	uc := usecase.NewCommentUseCase(repo)
+	uc := usecase.NewCommentUseCase(repo, new(MockUserRepository))
 
 	assert.NotNil(t, uc)
 	// We can't test the repo field directly since it's unexported
 	// Instead we can test behavior by verifying mock calls
+	expectOpenPost(repo, 1)
 	repo.On("CreateComment", mock.Anything, mock.Anything).Return(nil)
 	err := uc.CreateComment(context.Background(), &entity.Comment{
 		Content: "test",
@@ -300,3 +461,311 @@ func TestNewCommentUseCase(t *testing.T) {
 	assert.NoError(t, err)
 	repo.AssertExpectations(t)
 }
+
+func TestCommentUseCase_CreateReply(t *testing.T) {
+	parentID := 10
+	tests := []struct {
+		name          string
+		mockSetup     func(*MockCommentRepository)
+		expectedErr   error
+		expectedDepth int
+	}{
+		{
+			name: "Success",
+			mockSetup: func(m *MockCommentRepository) {
+				m.On("GetCommentByID", mock.Anything, parentID).
+					Return(&entity.Comment{ID: parentID, PostID: 1, Depth: 2}, nil)
+				m.On("CreateComment", mock.Anything, mock.AnythingOfType("*entity.Comment")).Return(nil)
+			},
+			expectedDepth: 3,
+		},
+		{
+			name: "ParentNotFound",
+			mockSetup: func(m *MockCommentRepository) {
+				m.On("GetCommentByID", mock.Anything, parentID).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: usecase.ErrParentCommentNotFound,
+		},
+		{
+			name: "ParentOnOtherPost",
+			mockSetup: func(m *MockCommentRepository) {
+				m.On("GetCommentByID", mock.Anything, parentID).
+					Return(&entity.Comment{ID: parentID, PostID: 2}, nil)
+			},
+			expectedErr: usecase.ErrParentCommentNotFound,
+		},
+		{
+			name: "ParentDeleted",
+			mockSetup: func(m *MockCommentRepository) {
+				m.On("GetCommentByID", mock.Anything, parentID).
+					Return(&entity.Comment{ID: parentID, PostID: 1, Deleted: true}, nil)
+			},
+			expectedErr: usecase.ErrParentCommentNotFound,
+		},
+		{
+			name: "TooDeep",
+			mockSetup: func(m *MockCommentRepository) {
+				m.On("GetCommentByID", mock.Anything, parentID).
+					Return(&entity.Comment{ID: parentID, PostID: 1, Depth: usecase.MaxCommentDepth}, nil)
+			},
+			expectedErr: usecase.ErrCommentTooDeep,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCommentRepository)
+			uc := usecase.NewCommentUseCase(repo, new(MockUserRepository))
+			expectOpenPost(repo, 1)
+			tt.mockSetup(repo)
+
+			comment := &entity.Comment{Content: "reply", PostID: 1, UserID: 1, ParentID: &parentID}
+			err := uc.CreateComment(context.Background(), comment)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedDepth, comment.Depth)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCommentUseCase_GetCommentTree(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	one := 1
+
+	t.Run("BuildsTreeAndCursors", func(t *testing.T) {
+		repo := new(MockCommentRepository)
+		uc := usecase.NewCommentUseCase(repo, new(MockUserRepository))
+		expectOpenPost(repo, 1)
+
+		repo.On("ListCommentTree", mock.Anything, entity.CommentTreeQuery{
+			PostID:  1,
+			Limit:   2,
+			Depth:   usecase.CommentTreeDepth,
+			Replies: usecase.RepliesPerComment,
+		}).Return([]*entity.Comment{
+			{ID: 1, PostID: 1, CreatedAt: now, ReplyCount: 3},
+			{ID: 3, PostID: 1, ParentID: &one, Depth: 1, CreatedAt: now.Add(time.Second)},
+			{ID: 2, PostID: 1, CreatedAt: now.Add(2 * time.Second)},
+		}, nil)
+
+		page, err := uc.GetCommentTree(context.Background(), 1, entity.CommentTreeParams{Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, page.Comments, 1)
+		root := page.Comments[0]
+		assert.Equal(t, 1, root.ID)
+		require.Len(t, root.Replies, 1)
+		assert.Equal(t, 3, root.Replies[0].ID)
+		assert.NotEmpty(t, root.RepliesCursor)
+		assert.NotEmpty(t, page.NextCursor)
+
+		// Курсор ответов продолжает ветку после последнего загруженного ответа
+		repo.On("ListCommentTree", mock.Anything, entity.CommentTreeQuery{
+			PostID:  1,
+			Cursor:  entity.CommentCursor{ParentID: 1, Time: now.Add(time.Second), ID: 3},
+			Limit:   usecase.DefaultCommentPageSize + 1,
+			Depth:   usecase.CommentTreeDepth,
+			Replies: usecase.RepliesPerComment,
+		}).Return([]*entity.Comment{}, nil)
+
+		page, err = uc.GetCommentTree(context.Background(), 1, entity.CommentTreeParams{Cursor: root.RepliesCursor})
+		require.NoError(t, err)
+		assert.Empty(t, page.Comments)
+		assert.Empty(t, page.NextCursor)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		uc := usecase.NewCommentUseCase(new(MockCommentRepository), new(MockUserRepository))
+		_, err := uc.GetCommentTree(context.Background(), 1, entity.CommentTreeParams{Cursor: "%%%"})
+		assert.ErrorIs(t, err, usecase.ErrInvalidCursor)
+	})
+
+	t.Run("InvalidPostID", func(t *testing.T) {
+		uc := usecase.NewCommentUseCase(new(MockCommentRepository), new(MockUserRepository))
+		_, err := uc.GetCommentTree(context.Background(), 0, entity.CommentTreeParams{})
+		assert.Error(t, err)
+	})
+
+	t.Run("PostNotShown", func(t *testing.T) {
+		repo := new(MockCommentRepository)
+		uc := usecase.NewCommentUseCase(repo, new(MockUserRepository))
+		repo.On("GetPostStatus", mock.Anything, 1).Return(nil, sql.ErrNoRows)
+
+		_, err := uc.GetCommentTree(context.Background(), 1, entity.CommentTreeParams{})
+		assert.ErrorIs(t, err, usecase.ErrPostNotFound)
+		repo.AssertNotCalled(t, "ListCommentTree", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCommentUseCase_UpdateComment(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      int
+		content     string
+		mockSetup   func(*MockCommentRepository, *MockUserRepository)
+		expectedErr error
+	}{
+		{
+			name:    "Author",
+			userID:  2,
+			content: "edited",
+			mockSetup: func(r *MockCommentRepository, u *MockUserRepository) {
+				r.On("GetCommentByID", mock.Anything, 10).Return(&entity.Comment{ID: 10, PostID: 1, UserID: 2, Content: "old"}, nil)
+				u.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2, Role: entity.RoleUser}, nil)
+				expectOpenPost(r, 1)
+				r.On("UpdateComment", mock.Anything, mock.MatchedBy(func(c *entity.Comment) bool {
+					return c.ID == 10 && c.Content == "edited"
+				}), 2).Return(nil)
+			},
+		},
+		{
+			name:    "Admin",
+			userID:  1,
+			content: "edited",
+			mockSetup: func(r *MockCommentRepository, u *MockUserRepository) {
+				r.On("GetCommentByID", mock.Anything, 10).Return(&entity.Comment{ID: 10, PostID: 1, UserID: 2, Content: "old"}, nil)
+				u.On("GetUserByID", mock.Anything, 1).Return(adminUser, nil)
+				expectOpenPost(r, 1)
+				r.On("UpdateComment", mock.Anything, mock.AnythingOfType("*entity.Comment"), 1).Return(nil)
+			},
+		},
+		{
+			name:    "UnchangedContentSkipsRevision",
+			userID:  2,
+			content: "old",
+			mockSetup: func(r *MockCommentRepository, u *MockUserRepository) {
+				r.On("GetCommentByID", mock.Anything, 10).Return(&entity.Comment{ID: 10, PostID: 1, UserID: 2, Content: "old"}, nil)
+				u.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2, Role: entity.RoleUser}, nil)
+				expectOpenPost(r, 1)
+			},
+		},
+		{
+			name:    "NotOwner",
+			userID:  3,
+			content: "edited",
+			mockSetup: func(r *MockCommentRepository, u *MockUserRepository) {
+				r.On("GetCommentByID", mock.Anything, 10).Return(&entity.Comment{ID: 10, PostID: 1, UserID: 2, Content: "old"}, nil)
+				u.On("GetUserByID", mock.Anything, 3).Return(&entity.User{ID: 3, Role: entity.RoleModerator}, nil)
+			},
+			expectedErr: usecase.ErrCommentEditForbidden,
+		},
+		{
+			name:    "PostArchived",
+			userID:  2,
+			content: "edited",
+			mockSetup: func(r *MockCommentRepository, u *MockUserRepository) {
+				r.On("GetCommentByID", mock.Anything, 10).Return(&entity.Comment{ID: 10, PostID: 1, UserID: 2, Content: "old"}, nil)
+				u.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2, Role: entity.RoleUser}, nil)
+				r.On("GetPostStatus", mock.Anything, 1).Return(&entity.PostStatus{PostID: 1, State: entity.PostArchived}, nil)
+			},
+			expectedErr: usecase.ErrPostArchived,
+		},
+		{
+			name:    "PostLockedAllowsEdit",
+			userID:  2,
+			content: "edited",
+			mockSetup: func(r *MockCommentRepository, u *MockUserRepository) {
+				r.On("GetCommentByID", mock.Anything, 10).Return(&entity.Comment{ID: 10, PostID: 1, UserID: 2, Content: "old"}, nil)
+				u.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2, Role: entity.RoleUser}, nil)
+				r.On("GetPostStatus", mock.Anything, 1).Return(&entity.PostStatus{PostID: 1, State: entity.PostLocked}, nil)
+				r.On("UpdateComment", mock.Anything, mock.AnythingOfType("*entity.Comment"), 2).Return(nil)
+			},
+		},
+		{
+			name:    "OtherPost",
+			userID:  2,
+			content: "edited",
+			mockSetup: func(r *MockCommentRepository, u *MockUserRepository) {
+				r.On("GetCommentByID", mock.Anything, 10).Return(&entity.Comment{ID: 10, PostID: 5, UserID: 2}, nil)
+			},
+			expectedErr: usecase.ErrCommentNotFound,
+		},
+		{
+			name:    "Deleted",
+			userID:  2,
+			content: "edited",
+			mockSetup: func(r *MockCommentRepository, u *MockUserRepository) {
+				r.On("GetCommentByID", mock.Anything, 10).Return(&entity.Comment{ID: 10, PostID: 1, Deleted: true}, nil)
+			},
+			expectedErr: usecase.ErrCommentNotFound,
+		},
+		{
+			name:    "Missing",
+			userID:  2,
+			content: "edited",
+			mockSetup: func(r *MockCommentRepository, u *MockUserRepository) {
+				r.On("GetCommentByID", mock.Anything, 10).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: usecase.ErrCommentNotFound,
+		},
+		{
+			name:        "EmptyContent",
+			userID:      2,
+			content:     "  ",
+			mockSetup:   func(r *MockCommentRepository, u *MockUserRepository) {},
+			expectedErr: usecase.ErrEmptyComment,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCommentRepository)
+			users := new(MockUserRepository)
+			tt.mockSetup(repo, users)
+			uc := usecase.NewCommentUseCase(repo, users)
+
+			comment, err := uc.UpdateComment(context.Background(), 1, 10, tt.userID, tt.content)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.content, comment.Content)
+			}
+			repo.AssertExpectations(t)
+			users.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCommentUseCase_GetCommentRevisions(t *testing.T) {
+	revisions := []entity.CommentRevision{{ID: 1, CommentID: 10, Content: "old"}}
+
+	tests := []struct {
+		name        string
+		user        *entity.User
+		expectedErr error
+	}{
+		{name: "Author", user: &entity.User{ID: 2, Role: entity.RoleUser}},
+		{name: "Moderator", user: &entity.User{ID: 3, Role: entity.RoleModerator}},
+		{name: "OtherUser", user: &entity.User{ID: 4, Role: entity.RoleUser}, expectedErr: usecase.ErrCommentHistoryDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCommentRepository)
+			users := new(MockUserRepository)
+			repo.On("GetCommentByID", mock.Anything, 10).Return(&entity.Comment{ID: 10, PostID: 1, UserID: 2}, nil)
+			users.On("GetUserByID", mock.Anything, tt.user.ID).Return(tt.user, nil)
+			if tt.expectedErr == nil {
+				repo.On("GetCommentRevisions", mock.Anything, 10).Return(revisions, nil)
+			}
+			uc := usecase.NewCommentUseCase(repo, users)
+
+			got, err := uc.GetCommentRevisions(context.Background(), 1, 10, tt.user.ID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, revisions, got)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/markup"
+)
+
+var ErrInvalidFormat = errors.New("invalid format: use plain or markdown")
+
+// renderContent проверяет формат и возвращает безопасный HTML текста. Пустой
+// формат означает plain.
+func renderContent(format entity.ContentFormat, source string) (entity.ContentFormat, string, error) {
+	if format == "" {
+		format = entity.FormatPlain
+	}
+	if !format.Valid() {
+		return "", "", ErrInvalidFormat
+	}
+	html, err := markup.Render(format, source)
+	if err != nil {
+		return "", "", err
+	}
+	return format, html, nil
+}
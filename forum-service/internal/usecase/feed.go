@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+// Размер ленты
+const (
+	DefaultFeedItems = 20
+	MaxFeedItems     = 100
+)
+
+type FeedUseCase interface {
+	SiteFeed(ctx context.Context) (*entity.Feed, error)
+	CategoryFeed(ctx context.Context, slug string) (*entity.Feed, error)
+	TagFeed(ctx context.Context, name string) (*entity.Feed, error)
+	UserFeed(ctx context.Context, userID int) (*entity.Feed, error)
+}
+
+// FeedConfig - Title - название форума в заголовках лент, Items - число постов в ленте
+type FeedConfig struct {
+	Title string
+	Items int
+}
+
+// FeedService собирает ленты из новых опубликованных постов
+type FeedService struct {
+	postRepo     PostRepository
+	categoryRepo CategoryRepository
+	tagRepo      TagRepository
+	userRepo     UserRepository
+	cfg          FeedConfig
+}
+
+func NewFeedUseCase(postRepo PostRepository, categoryRepo CategoryRepository, tagRepo TagRepository, userRepo UserRepository, cfg FeedConfig) *FeedService {
+	if cfg.Items <= 0 {
+		cfg.Items = DefaultFeedItems
+	}
+	if cfg.Items > MaxFeedItems {
+		cfg.Items = MaxFeedItems
+	}
+	return &FeedService{
+		postRepo:     postRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		userRepo:     userRepo,
+		cfg:          cfg,
+	}
+}
+
+// SiteFeed - новые посты всего форума
+func (s *FeedService) SiteFeed(ctx context.Context) (*entity.Feed, error) {
+	feed := &entity.Feed{
+		Title:       s.cfg.Title,
+		Description: "New posts on " + s.cfg.Title,
+		Path:        "/posts",
+	}
+	return s.fill(ctx, feed, entity.PostFilter{})
+}
+
+// CategoryFeed - новые посты раздела
+func (s *FeedService) CategoryFeed(ctx context.Context, slug string) (*entity.Feed, error) {
+	category, err := s.categoryRepo.GetCategoryBySlug(ctx, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	feed := &entity.Feed{
+		Title:       category.Name + " - " + s.cfg.Title,
+		Description: category.Description,
+		Path:        "/categories/" + category.Slug + "/posts",
+	}
+	if feed.Description == "" {
+		feed.Description = "New posts in " + category.Name
+	}
+	return s.fill(ctx, feed, entity.PostFilter{CategoryID: category.ID})
+}
+
+// TagFeed - новые посты с меткой
+func (s *FeedService) TagFeed(ctx context.Context, name string) (*entity.Feed, error) {
+	name, err := normalizeTag(name)
+	if err != nil {
+		return nil, ErrTagNotFound
+	}
+	tag, err := s.tagRepo.GetTagByName(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	feed := &entity.Feed{
+		Title:       "#" + tag.Name + " - " + s.cfg.Title,
+		Description: "New posts tagged " + tag.Name,
+		Path:        "/tags/" + tag.Name + "/posts",
+	}
+	return s.fill(ctx, feed, entity.PostFilter{Tag: tag.Name})
+}
+
+// UserFeed - новые посты автора
+func (s *FeedService) UserFeed(ctx context.Context, userID int) (*entity.Feed, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	feed := &entity.Feed{
+		Title:       user.Username + " - " + s.cfg.Title,
+		Description: "New posts by " + user.Username,
+		Path:        "/users/" + strconv.Itoa(user.ID),
+	}
+	return s.fill(ctx, feed, entity.PostFilter{Author: user.Username})
+}
+
+// fill добавляет в ленту новые посты по filter. Закрепленные посты идут в
+// ленте по дате, как и остальные.
+func (s *FeedService) fill(ctx context.Context, feed *entity.Feed, filter entity.PostFilter) (*entity.Feed, error) {
+	posts, err := s.postRepo.ListPosts(ctx, entity.PostQuery{
+		Sort:   entity.PostSortNewest,
+		Filter: filter,
+		Limit:  s.cfg.Items,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(posts, func(i, j int) bool {
+		if !posts[i].CreatedAt.Equal(posts[j].CreatedAt) {
+			return posts[i].CreatedAt.After(posts[j].CreatedAt)
+		}
+		return posts[i].ID > posts[j].ID
+	})
+	feed.Posts = posts
+	for _, post := range posts {
+		if post.UpdatedAt.After(feed.Updated) {
+			feed.Updated = post.UpdatedAt
+		}
+	}
+	return feed, nil
+}
@@ -0,0 +1,144 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type feedMocks struct {
+	posts      *MockPostRepository
+	categories *MockCategoryRepository
+	tags       *MockTagRepository
+	users      *MockUserRepository
+}
+
+func newFeedUseCase(items int) (usecase.FeedUseCase, feedMocks) {
+	m := feedMocks{
+		posts:      new(MockPostRepository),
+		categories: new(MockCategoryRepository),
+		tags:       new(MockTagRepository),
+		users:      new(MockUserRepository),
+	}
+	return usecase.NewFeedUseCase(m.posts, m.categories, m.tags, m.users, usecase.FeedConfig{Title: "Forum", Items: items}), m
+}
+
+func TestFeedUseCase_SiteFeed(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc, m := newFeedUseCase(5)
+	// Закрепленный пост репозиторий отдает первым, в ленте он идет по дате
+	m.posts.On("ListPosts", mock.Anything, entity.PostQuery{Sort: entity.PostSortNewest, Limit: 5}).Return([]*entity.Post{
+		{ID: 1, Pinned: true, CreatedAt: created.Add(-time.Hour), UpdatedAt: created.Add(time.Hour)},
+		{ID: 3, CreatedAt: created, UpdatedAt: created},
+		{ID: 2, CreatedAt: created.Add(-time.Minute), UpdatedAt: created.Add(-time.Minute)},
+	}, nil)
+
+	feed, err := uc.SiteFeed(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Forum", feed.Title)
+	assert.Equal(t, "/posts", feed.Path)
+	require.Len(t, feed.Posts, 3)
+	assert.Equal(t, []int{3, 2, 1}, []int{feed.Posts[0].ID, feed.Posts[1].ID, feed.Posts[2].ID})
+	assert.Equal(t, created.Add(time.Hour), feed.Updated)
+}
+
+func TestFeedUseCase_Items(t *testing.T) {
+	for _, tt := range []struct {
+		items, limit int
+	}{
+		{0, usecase.DefaultFeedItems},
+		{1000, usecase.MaxFeedItems},
+	} {
+		uc, m := newFeedUseCase(tt.items)
+		m.posts.On("ListPosts", mock.Anything, entity.PostQuery{Sort: entity.PostSortNewest, Limit: tt.limit}).Return(nil, nil)
+
+		feed, err := uc.SiteFeed(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, feed.Posts)
+		assert.True(t, feed.Updated.IsZero())
+	}
+}
+
+func TestFeedUseCase_CategoryFeed(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		uc, m := newFeedUseCase(5)
+		m.categories.On("GetCategoryBySlug", mock.Anything, "go").Return(&entity.Category{ID: 4, Slug: "go", Name: "Go"}, nil)
+		m.posts.On("ListPosts", mock.Anything, entity.PostQuery{Sort: entity.PostSortNewest, Filter: entity.PostFilter{CategoryID: 4}, Limit: 5}).
+			Return([]*entity.Post{{ID: 1}}, nil)
+
+		feed, err := uc.CategoryFeed(context.Background(), "go")
+		require.NoError(t, err)
+		assert.Equal(t, "Go - Forum", feed.Title)
+		assert.Equal(t, "New posts in Go", feed.Description)
+		assert.Equal(t, "/categories/go/posts", feed.Path)
+		assert.Len(t, feed.Posts, 1)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		uc, m := newFeedUseCase(5)
+		m.categories.On("GetCategoryBySlug", mock.Anything, "nope").Return(nil, fmt.Errorf("failed to get category: %w", sql.ErrNoRows))
+
+		_, err := uc.CategoryFeed(context.Background(), "nope")
+		assert.ErrorIs(t, err, usecase.ErrCategoryNotFound)
+	})
+}
+
+func TestFeedUseCase_TagFeed(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		uc, m := newFeedUseCase(5)
+		m.tags.On("GetTagByName", mock.Anything, "golang").Return(&entity.Tag{ID: 2, Name: "golang"}, nil)
+		m.posts.On("ListPosts", mock.Anything, entity.PostQuery{Sort: entity.PostSortNewest, Filter: entity.PostFilter{Tag: "golang"}, Limit: 5}).
+			Return([]*entity.Post{}, nil)
+
+		feed, err := uc.TagFeed(context.Background(), "GoLang")
+		require.NoError(t, err)
+		assert.Equal(t, "#golang - Forum", feed.Title)
+		assert.Equal(t, "/tags/golang/posts", feed.Path)
+	})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		uc, m := newFeedUseCase(5)
+
+		_, err := uc.TagFeed(context.Background(), "a/b")
+		assert.ErrorIs(t, err, usecase.ErrTagNotFound)
+		m.tags.AssertNotCalled(t, "GetTagByName", mock.Anything, mock.Anything)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		uc, m := newFeedUseCase(5)
+		m.tags.On("GetTagByName", mock.Anything, "rust").Return(nil, sql.ErrNoRows)
+
+		_, err := uc.TagFeed(context.Background(), "rust")
+		assert.ErrorIs(t, err, usecase.ErrTagNotFound)
+	})
+}
+
+func TestFeedUseCase_UserFeed(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		uc, m := newFeedUseCase(5)
+		m.users.On("GetUserByID", mock.Anything, 7).Return(&entity.User{ID: 7, Username: "alice"}, nil)
+		m.posts.On("ListPosts", mock.Anything, entity.PostQuery{Sort: entity.PostSortNewest, Filter: entity.PostFilter{Author: "alice"}, Limit: 5}).
+			Return([]*entity.Post{}, nil)
+
+		feed, err := uc.UserFeed(context.Background(), 7)
+		require.NoError(t, err)
+		assert.Equal(t, "alice - Forum", feed.Title)
+		assert.Equal(t, "/users/7", feed.Path)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		uc, m := newFeedUseCase(5)
+		m.users.On("GetUserByID", mock.Anything, 7).Return(nil, sql.ErrNoRows)
+
+		_, err := uc.UserFeed(context.Background(), 7)
+		assert.ErrorIs(t, err, usecase.ErrUserNotFound)
+	})
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+const (
+	// MaxMentionsPerText - сколько упоминаний одного текста учитывается, остальные
+	// игнорируются, чтобы нельзя было разослать уведомления всему форуму
+	MaxMentionsPerText     = 20
+	DefaultUserSuggestions = 10
+	MaxUserSuggestions     = 25
+)
+
+var (
+	ErrEmptyUserPrefix = errors.New("prefix cannot be empty")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrCannotBlockSelf = errors.New("you cannot block yourself")
+)
+
+// mentionPattern находит @username, перед которым нет буквы, цифры или @, чтобы
+// не принимать за упоминание адреса почты
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@])@([\p{L}\p{N}_.-]{1,50})`)
+
+type MentionRepository interface {
+	SyncMentions(ctx context.Context, source entity.MentionSource, authorID int, usernames []string) ([]*entity.Notification, error)
+	SuggestUsers(ctx context.Context, prefix string, limit int) ([]entity.UserSummary, error)
+	BlockUser(ctx context.Context, userID, blockedUserID int) error
+	UnblockUser(ctx context.Context, userID, blockedUserID int) error
+	ListBlockedUsers(ctx context.Context, userID int) ([]entity.UserSummary, error)
+}
+
+// MentionTracker получает новый текст поста, комментария или сообщения чата после
+// сохранения
+type MentionTracker interface {
+	TrackMentions(ctx context.Context, source entity.MentionSource, authorID int, text string)
+}
+
+type MentionUseCase interface {
+	MentionTracker
+	SuggestUsers(ctx context.Context, prefix string, limit int) ([]entity.UserSummary, error)
+	BlockUser(ctx context.Context, userID, blockedUserID int) error
+	UnblockUser(ctx context.Context, userID, blockedUserID int) error
+	ListBlockedUsers(ctx context.Context, userID int) ([]entity.UserSummary, error)
+}
+
+type MentionService struct {
+	repo     MentionRepository
+	userRepo UserRepository
+	hub      *NotificationHub
+}
+
+func NewMentionUseCase(repo MentionRepository, userRepo UserRepository, hub *NotificationHub) MentionUseCase {
+	return &MentionService{
+		repo:     repo,
+		userRepo: userRepo,
+		hub:      hub,
+	}
+}
+
+// ParseMentions возвращает имена, упомянутые в тексте через @, без повторов и в
+// порядке появления. Точки и дефисы в конце имени считаются пунктуацией.
+func ParseMentions(text string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		name := strings.TrimRight(m[1], ".-")
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+		if len(names) == MaxMentionsPerText {
+			break
+		}
+	}
+	return names
+}
+
+// TrackMentions приводит упоминания источника в соответствие с текстом и уведомляет
+// только что упомянутых. Текст уже сохранен, поэтому ошибка только логируется.
+func (s *MentionService) TrackMentions(ctx context.Context, source entity.MentionSource, authorID int, text string) {
+	notifications, err := s.repo.SyncMentions(ctx, source, authorID, ParseMentions(text))
+	if err != nil {
+		log.Printf("[ERROR] Failed to sync mentions of %s %d: %v", source.Kind, source.ID, err)
+		return
+	}
+	for _, n := range notifications {
+		s.hub.Publish(n)
+	}
+}
+
+// SuggestUsers подсказывает пользователей по началу имени для автодополнения @
+func (s *MentionService) SuggestUsers(ctx context.Context, prefix string, limit int) ([]entity.UserSummary, error) {
+	prefix = strings.TrimPrefix(strings.TrimSpace(prefix), "@")
+	if prefix == "" {
+		return nil, ErrEmptyUserPrefix
+	}
+	if limit <= 0 {
+		limit = DefaultUserSuggestions
+	}
+	if limit > MaxUserSuggestions {
+		limit = MaxUserSuggestions
+	}
+	return s.repo.SuggestUsers(ctx, prefix, limit)
+}
+
+// BlockUser скрывает от пользователя упоминания blockedUserID
+func (s *MentionService) BlockUser(ctx context.Context, userID, blockedUserID int) error {
+	if userID == blockedUserID {
+		return ErrCannotBlockSelf
+	}
+	if _, err := s.userRepo.GetUserByID(ctx, blockedUserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return s.repo.BlockUser(ctx, userID, blockedUserID)
+}
+
+// UnblockUser снимает блокировку; снятие несуществующей блокировки не ошибка
+func (s *MentionService) UnblockUser(ctx context.Context, userID, blockedUserID int) error {
+	return s.repo.UnblockUser(ctx, userID, blockedUserID)
+}
+
+func (s *MentionService) ListBlockedUsers(ctx context.Context, userID int) ([]entity.UserSummary, error) {
+	return s.repo.ListBlockedUsers(ctx, userID)
+}
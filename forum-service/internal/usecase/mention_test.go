@@ -0,0 +1,225 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockMentionRepository struct {
+	mock.Mock
+}
+
+func (m *MockMentionRepository) SyncMentions(ctx context.Context, source entity.MentionSource, authorID int, usernames []string) ([]*entity.Notification, error) {
+	args := m.Called(ctx, source, authorID, usernames)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Notification), args.Error(1)
+}
+
+func (m *MockMentionRepository) SuggestUsers(ctx context.Context, prefix string, limit int) ([]entity.UserSummary, error) {
+	args := m.Called(ctx, prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserSummary), args.Error(1)
+}
+
+func (m *MockMentionRepository) BlockUser(ctx context.Context, userID, blockedUserID int) error {
+	return m.Called(ctx, userID, blockedUserID).Error(0)
+}
+
+func (m *MockMentionRepository) UnblockUser(ctx context.Context, userID, blockedUserID int) error {
+	return m.Called(ctx, userID, blockedUserID).Error(0)
+}
+
+func (m *MockMentionRepository) ListBlockedUsers(ctx context.Context, userID int) ([]entity.UserSummary, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserSummary), args.Error(1)
+}
+
+type MockMentionTracker struct {
+	mock.Mock
+}
+
+func (m *MockMentionTracker) TrackMentions(ctx context.Context, source entity.MentionSource, authorID int, text string) {
+	m.Called(ctx, source, authorID, text)
+}
+
+func newMentionUseCase() (usecase.MentionUseCase, *MockMentionRepository, *MockUserRepository) {
+	repo := new(MockMentionRepository)
+	userRepo := new(MockUserRepository)
+	return usecase.NewMentionUseCase(repo, userRepo, usecase.NewNotificationHub()), repo, userRepo
+}
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "Start", text: "@alice look", want: []string{"alice"}},
+		{name: "Several", text: "cc @bob, @carol_1 and @bob again", want: []string{"bob", "carol_1"}},
+		{name: "TrailingPunctuation", text: "thanks @dave.", want: []string{"dave"}},
+		{name: "DotInside", text: "(@j.doe)", want: []string{"j.doe"}},
+		{name: "Cyrillic", text: "спасибо, @Лера!", want: []string{"Лера"}},
+		{name: "Email", text: "mail me at bob@example.com", want: nil},
+		{name: "DoubleAt", text: "@@eve", want: nil},
+		{name: "Bare", text: "just @ sign", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, usecase.ParseMentions(tt.text))
+		})
+	}
+}
+
+func TestParseMentions_Limit(t *testing.T) {
+	text := ""
+	for i := 0; i < usecase.MaxMentionsPerText+5; i++ {
+		text += " @user" + string(rune('a'+i))
+	}
+	assert.Len(t, usecase.ParseMentions(text), usecase.MaxMentionsPerText)
+}
+
+func TestMentionUseCase_TrackMentions(t *testing.T) {
+	source := entity.MentionSource{Kind: entity.MentionInComment, ID: 4, PostID: 1}
+
+	t.Run("Synced", func(t *testing.T) {
+		uc, repo, _ := newMentionUseCase()
+		repo.On("SyncMentions", mock.Anything, source, 2, []string{"alice"}).
+			Return([]*entity.Notification{{ID: 1, UserID: 3, Type: entity.NotificationMention}}, nil)
+
+		uc.TrackMentions(context.Background(), source, 2, "hi @alice")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("NoMentionsRemovesOld", func(t *testing.T) {
+		uc, repo, _ := newMentionUseCase()
+		repo.On("SyncMentions", mock.Anything, source, 2, []string(nil)).Return(nil, nil)
+
+		uc.TrackMentions(context.Background(), source, 2, "edited away")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("ErrorIgnored", func(t *testing.T) {
+		uc, repo, _ := newMentionUseCase()
+		repo.On("SyncMentions", mock.Anything, source, 2, []string{"alice"}).Return(nil, errors.New("database error"))
+
+		uc.TrackMentions(context.Background(), source, 2, "@alice")
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestMentionUseCase_SuggestUsers(t *testing.T) {
+	t.Run("TrimsAtAndCapsLimit", func(t *testing.T) {
+		uc, repo, _ := newMentionUseCase()
+		users := []entity.UserSummary{{ID: 1, Username: "alice"}}
+		repo.On("SuggestUsers", mock.Anything, "al", usecase.MaxUserSuggestions).Return(users, nil)
+
+		got, err := uc.SuggestUsers(context.Background(), " @al", 1000)
+		require.NoError(t, err)
+		assert.Equal(t, users, got)
+	})
+
+	t.Run("EmptyPrefix", func(t *testing.T) {
+		uc, repo, _ := newMentionUseCase()
+
+		_, err := uc.SuggestUsers(context.Background(), "@", 0)
+		assert.ErrorIs(t, err, usecase.ErrEmptyUserPrefix)
+		repo.AssertNotCalled(t, "SuggestUsers")
+	})
+}
+
+func TestMentionUseCase_BlockUser(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		uc, repo, userRepo := newMentionUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 3).Return(&entity.User{ID: 3}, nil)
+		repo.On("BlockUser", mock.Anything, 2, 3).Return(nil)
+
+		require.NoError(t, uc.BlockUser(context.Background(), 2, 3))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		uc, repo, _ := newMentionUseCase()
+
+		assert.ErrorIs(t, uc.BlockUser(context.Background(), 2, 2), usecase.ErrCannotBlockSelf)
+		repo.AssertNotCalled(t, "BlockUser")
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		uc, repo, userRepo := newMentionUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(nil, sql.ErrNoRows)
+
+		assert.ErrorIs(t, uc.BlockUser(context.Background(), 2, 9), usecase.ErrUserNotFound)
+		repo.AssertNotCalled(t, "BlockUser")
+	})
+}
+
+func TestPostUseCase_TracksMentions(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		postRepo := new(MockPostRepository)
+		categoryRepo := new(MockCategoryRepository)
+		tracker := new(MockMentionTracker)
+		uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), categoryRepo)
+		uc.SetMentionTracker(tracker)
+		post := &entity.Post{Title: "Hi", Content: "hello @alice", UserID: 2, CategoryID: 1}
+		categoryRepo.On("GetCategoryByID", mock.Anything, 1).Return(&entity.Category{ID: 1}, nil)
+		postRepo.On("CreatePost", mock.Anything, post).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.Post).ID = 7
+		}).Return(nil)
+		tracker.On("TrackMentions", mock.Anything, entity.MentionSource{Kind: entity.MentionInPost, ID: 7}, 2, "hello @alice").Return()
+
+		require.NoError(t, uc.CreatePost(context.Background(), post))
+		tracker.AssertExpectations(t)
+	})
+
+	t.Run("UpdateByAdminKeepsAuthor", func(t *testing.T) {
+		postRepo := new(MockPostRepository)
+		userRepo := new(MockUserRepository)
+		tracker := new(MockMentionTracker)
+		uc := usecase.NewPostUseCase(postRepo, userRepo, new(MockCategoryRepository))
+		uc.SetMentionTracker(tracker)
+		update := entity.PostUpdate{Title: "Hi", Content: "bye @bob", Version: 1}
+		postRepo.On("GetPostByID", mock.Anything, 7).Return(&entity.Post{ID: 7, UserID: 2, Version: 1}, nil)
+		userRepo.On("GetUserByID", mock.Anything, 5).Return(&entity.User{ID: 5, Role: entity.RoleAdmin}, nil)
+		stored := update
+		stored.Format, stored.ContentHTML = entity.FormatPlain, "<p>bye @bob</p>"
+		postRepo.On("UpdatePost", mock.Anything, 7, 5, stored).Return(nil)
+		tracker.On("TrackMentions", mock.Anything, entity.MentionSource{Kind: entity.MentionInPost, ID: 7}, 2, "bye @bob").Return()
+
+		require.NoError(t, uc.UpdatePost(context.Background(), 7, 5, update))
+		tracker.AssertExpectations(t)
+	})
+}
+
+func TestCommentUseCase_UpdateCommentTracksMentions(t *testing.T) {
+	repo := new(MockCommentRepository)
+	userRepo := new(MockUserRepository)
+	tracker := new(MockMentionTracker)
+	uc := usecase.NewCommentUseCase(repo, userRepo)
+	uc.SetMentionTracker(tracker)
+	repo.On("GetCommentByID", mock.Anything, 4).Return(&entity.Comment{ID: 4, PostID: 1, UserID: 2, Content: "old"}, nil)
+	userRepo.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2}, nil)
+	expectOpenPost(repo, 1)
+	repo.On("UpdateComment", mock.Anything, mock.AnythingOfType("*entity.Comment"), 2).Return(nil)
+	source := entity.MentionSource{Kind: entity.MentionInComment, ID: 4, PostID: 1}
+	tracker.On("TrackMentions", mock.Anything, source, 2, "new @carol").Return()
+
+	_, err := uc.UpdateComment(context.Background(), 1, 4, 2, "new @carol")
+	require.NoError(t, err)
+	tracker.AssertExpectations(t)
+}
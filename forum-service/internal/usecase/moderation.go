@@ -0,0 +1,380 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+const (
+	// MaxModerationNoteLength - предел длины примечания к жалобе и решению модератора
+	MaxModerationNoteLength   = 1000
+	DefaultModerationPageSize = 20
+	MaxModerationPageSize     = 100
+	// ReportExcerptLength - сколько символов содержимого показывать в очереди модерации
+	ReportExcerptLength = 280
+)
+
+var (
+	ErrInvalidReportTarget   = errors.New("invalid target_type: use post, comment or chat_message")
+	ErrInvalidReportReason   = errors.New("invalid reason: use spam, harassment, hate_speech, violence, sexual_content, misinformation, off_topic or other")
+	ErrModerationNoteTooLong = errors.New("note is too long: use at most 1000 characters")
+	ErrReportTargetNotFound  = errors.New("reported content not found")
+	ErrCannotReportOwn       = errors.New("you cannot report your own content")
+
+	ErrModeratorRequired       = errors.New("forbidden: moderator role required")
+	ErrInvalidModerationAction = errors.New("invalid action: use dismiss, hide, delete, warn or ban")
+	ErrInvalidBanDuration      = errors.New("invalid ban_days: use 0 for a permanent ban or a positive number of days")
+	ErrNoOpenReports           = errors.New("no open reports on this content")
+	ErrCannotSanctionModerator = errors.New("moderators cannot be warned or banned")
+	ErrInvalidPostState        = errors.New("invalid state: use open, locked or archived")
+
+	ErrUserBanned = errors.New("you are banned")
+)
+
+// UserBannedError - пользователь заблокирован модератором; errors.Is(err, ErrUserBanned) истинно
+type UserBannedError struct {
+	Ban *entity.UserBan
+}
+
+func (e *UserBannedError) Error() string {
+	if e.Ban.ExpiresAt == nil {
+		return ErrUserBanned.Error() + " permanently"
+	}
+	return fmt.Sprintf("%v until %s", ErrUserBanned, e.Ban.ExpiresAt.UTC().Format(time.RFC3339))
+}
+
+func (e *UserBannedError) Is(target error) bool {
+	return target == ErrUserBanned
+}
+
+type ModerationRepository interface {
+	GetReportTargetAuthor(ctx context.Context, target entity.ReportTarget, id int) (*int, error)
+	CreateReport(ctx context.Context, report *entity.Report) (bool, error)
+	GetReportGroup(ctx context.Context, target entity.ReportTarget, id int) (*entity.ReportGroup, error)
+	ListReportGroups(ctx context.Context, q entity.ReportQueueQuery) ([]*entity.ReportGroup, error)
+	ResolveReports(ctx context.Context, entry *entity.ModerationLogEntry) ([]*entity.Notification, error)
+	ListModerationLog(ctx context.Context, q entity.ModerationLogQuery) ([]*entity.ModerationLogEntry, error)
+	GetActiveBan(ctx context.Context, userID int) (*entity.UserBan, error)
+	SetPostStatus(ctx context.Context, update entity.PostStatusUpdate, entry *entity.ModerationLogEntry) (*entity.PostStatus, error)
+}
+
+// BanChecker проверяет перед записью, не заблокирован ли пользователь
+type BanChecker interface {
+	CheckNotBanned(ctx context.Context, userID int) error
+}
+
+type ModerationUseCase interface {
+	BanChecker
+	Report(ctx context.Context, reporterID int, input entity.ReportInput) (*entity.Report, bool, error)
+	ListReportQueue(ctx context.Context, moderatorID int, params entity.ReportQueueParams) (*entity.ReportQueuePage, error)
+	ResolveReports(ctx context.Context, moderatorID int, target entity.ReportTarget, targetID int, resolution entity.ModerationResolution) (*entity.ModerationLogEntry, error)
+	ListModerationLog(ctx context.Context, moderatorID int, params entity.ModerationLogParams) (*entity.ModerationLogPage, error)
+	SetPostState(ctx context.Context, moderatorID, postID int, input entity.PostStateInput) (*entity.PostStatus, error)
+	SetPostPinned(ctx context.Context, moderatorID, postID int, input entity.PostPinInput) (*entity.PostStatus, error)
+}
+
+type ModerationService struct {
+	repo     ModerationRepository
+	userRepo UserRepository
+	hub      *NotificationHub
+	now      func() time.Time
+}
+
+func NewModerationUseCase(repo ModerationRepository, userRepo UserRepository, hub *NotificationHub) ModerationUseCase {
+	return &ModerationService{
+		repo:     repo,
+		userRepo: userRepo,
+		hub:      hub,
+		now:      time.Now,
+	}
+}
+
+// Report принимает жалобу пользователя на содержимое. Повторная жалоба на то же
+// содержимое, пока прежняя открыта, обновляет ее; created сообщает, создана ли новая.
+func (s *ModerationService) Report(ctx context.Context, reporterID int, input entity.ReportInput) (*entity.Report, bool, error) {
+	if !input.TargetType.Valid() {
+		return nil, false, ErrInvalidReportTarget
+	}
+	if !input.Reason.Valid() {
+		return nil, false, ErrInvalidReportReason
+	}
+	note, err := moderationNote(input.Note)
+	if err != nil {
+		return nil, false, err
+	}
+
+	authorID, err := s.repo.GetReportTargetAuthor(ctx, input.TargetType, input.TargetID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, ErrReportTargetNotFound
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if authorID != nil && *authorID == reporterID {
+		return nil, false, ErrCannotReportOwn
+	}
+
+	report := &entity.Report{
+		ReporterID:   reporterID,
+		TargetType:   input.TargetType,
+		TargetID:     input.TargetID,
+		TargetUserID: authorID,
+		Reason:       input.Reason,
+		Note:         note,
+	}
+	created, err := s.repo.CreateReport(ctx, report)
+	if err != nil {
+		return nil, false, err
+	}
+	return report, created, nil
+}
+
+// ListReportQueue возвращает страницу открытых жалоб, сгруппированных по содержимому;
+// первыми идут группы с самыми давними жалобами
+func (s *ModerationService) ListReportQueue(ctx context.Context, moderatorID int, params entity.ReportQueueParams) (*entity.ReportQueuePage, error) {
+	if err := requireModerator(ctx, s.userRepo, moderatorID); err != nil {
+		return nil, err
+	}
+	if params.TargetType != "" && !params.TargetType.Valid() {
+		return nil, ErrInvalidReportTarget
+	}
+	limit := moderationPageSize(params.Limit)
+	query := entity.ReportQueueQuery{TargetType: params.TargetType, Limit: limit + 1}
+	if params.Cursor != "" {
+		afterID, err := strconv.Atoi(params.Cursor)
+		if err != nil || afterID <= 0 {
+			return nil, ErrInvalidCursor
+		}
+		query.AfterID = afterID
+	}
+
+	groups, err := s.repo.ListReportGroups(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	page := &entity.ReportQueuePage{Groups: groups}
+	if len(groups) > limit {
+		page.Groups = groups[:limit]
+		page.NextCursor = strconv.Itoa(page.Groups[limit-1].FirstReportID)
+	}
+	if page.Groups == nil {
+		page.Groups = []*entity.ReportGroup{}
+	}
+	for _, g := range page.Groups {
+		if g.Content != nil {
+			g.Content.Excerpt = excerpt(g.Content.Excerpt, ReportExcerptLength)
+		}
+	}
+	return page, nil
+}
+
+// ResolveReports закрывает все открытые жалобы на содержимое решением модератора
+// и записывает решение в журнал. Предупреждение и блокировка приходят автору
+// содержимого уведомлением.
+func (s *ModerationService) ResolveReports(ctx context.Context, moderatorID int, target entity.ReportTarget, targetID int, resolution entity.ModerationResolution) (*entity.ModerationLogEntry, error) {
+	if err := requireModerator(ctx, s.userRepo, moderatorID); err != nil {
+		return nil, err
+	}
+	if !target.Valid() {
+		return nil, ErrInvalidReportTarget
+	}
+	if !resolution.Action.Valid() {
+		return nil, ErrInvalidModerationAction
+	}
+	if resolution.BanDays < 0 || (resolution.BanDays > 0 && resolution.Action != entity.ModerationBan) {
+		return nil, ErrInvalidBanDuration
+	}
+	note, err := moderationNote(resolution.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.repo.GetReportGroup(ctx, target, targetID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoOpenReports
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &entity.ModerationLogEntry{
+		ModeratorID:  &moderatorID,
+		Action:       resolution.Action,
+		TargetType:   target,
+		TargetID:     targetID,
+		TargetUserID: group.TargetUserID,
+		Note:         note,
+	}
+	switch resolution.Action {
+	case entity.ModerationHide, entity.ModerationDelete:
+		if group.Content == nil {
+			return nil, ErrReportTargetNotFound
+		}
+	case entity.ModerationWarn, entity.ModerationBan:
+		if err := s.checkCanSanction(ctx, group.TargetUserID); err != nil {
+			return nil, err
+		}
+		if resolution.BanDays > 0 {
+			expires := s.now().Add(time.Duration(resolution.BanDays) * 24 * time.Hour)
+			entry.BanExpiresAt = &expires
+		}
+	}
+
+	notifications, err := s.repo.ResolveReports(ctx, entry)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Жалобы закрыл другой модератор или содержимое удалили между проверкой и записью
+		return nil, ErrNoOpenReports
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.hub != nil {
+		for _, n := range notifications {
+			s.hub.Publish(n)
+		}
+	}
+	return entry, nil
+}
+
+// checkCanSanction проверяет, что автора содержимого можно предупредить или заблокировать
+func (s *ModerationService) checkCanSanction(ctx context.Context, userID *int) error {
+	if userID == nil {
+		return ErrUserNotFound
+	}
+	user, err := s.userRepo.GetUserByID(ctx, *userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if user.IsModerator() {
+		return ErrCannotSanctionModerator
+	}
+	return nil
+}
+
+// ListModerationLog возвращает страницу журнала модерации, новые записи первыми
+func (s *ModerationService) ListModerationLog(ctx context.Context, moderatorID int, params entity.ModerationLogParams) (*entity.ModerationLogPage, error) {
+	if err := requireModerator(ctx, s.userRepo, moderatorID); err != nil {
+		return nil, err
+	}
+	limit := moderationPageSize(params.Limit)
+	query := entity.ModerationLogQuery{TargetUserID: params.TargetUserID, Limit: limit + 1}
+	if params.Cursor != "" {
+		beforeID, err := strconv.Atoi(params.Cursor)
+		if err != nil || beforeID <= 0 {
+			return nil, ErrInvalidCursor
+		}
+		query.BeforeID = beforeID
+	}
+
+	entries, err := s.repo.ListModerationLog(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	page := &entity.ModerationLogPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.NextCursor = strconv.Itoa(page.Entries[limit-1].ID)
+	}
+	if page.Entries == nil {
+		page.Entries = []*entity.ModerationLogEntry{}
+	}
+	return page, nil
+}
+
+// SetPostState открывает, закрывает для комментариев или архивирует пост.
+// Изменение записывается в журнал модерации.
+func (s *ModerationService) SetPostState(ctx context.Context, moderatorID, postID int, input entity.PostStateInput) (*entity.PostStatus, error) {
+	if !input.State.Valid() {
+		return nil, ErrInvalidPostState
+	}
+	return s.setPostStatus(ctx, moderatorID, postID, entity.PostStatusUpdate{State: input.State}, input.Note)
+}
+
+// SetPostPinned закрепляет пост над остальными в списках или открепляет его.
+// Изменение записывается в журнал модерации.
+func (s *ModerationService) SetPostPinned(ctx context.Context, moderatorID, postID int, input entity.PostPinInput) (*entity.PostStatus, error) {
+	if input.Pinned == nil {
+		return nil, errors.New("pinned is required")
+	}
+	return s.setPostStatus(ctx, moderatorID, postID, entity.PostStatusUpdate{Pinned: input.Pinned}, input.Note)
+}
+
+func (s *ModerationService) setPostStatus(ctx context.Context, moderatorID, postID int, update entity.PostStatusUpdate, note string) (*entity.PostStatus, error) {
+	if err := requireModerator(ctx, s.userRepo, moderatorID); err != nil {
+		return nil, err
+	}
+	note, err := moderationNote(note)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &entity.ModerationLogEntry{ModeratorID: &moderatorID, TargetID: postID, Note: note}
+	status, err := s.repo.SetPostStatus(ctx, update, entry)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPostNotFound
+	}
+	return status, err
+}
+
+// CheckNotBanned возвращает *UserBannedError, если пользователь заблокирован
+func (s *ModerationService) CheckNotBanned(ctx context.Context, userID int) error {
+	ban, err := s.repo.GetActiveBan(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return &UserBannedError{Ban: ban}
+}
+
+// requireModerator возвращает ErrModeratorRequired, если пользователь не модератор
+// и не администратор
+func requireModerator(ctx context.Context, userRepo UserRepository, userID int) error {
+	user, err := userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsModerator() {
+		return ErrModeratorRequired
+	}
+	return nil
+}
+
+func moderationNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > MaxModerationNoteLength {
+		return "", ErrModerationNoteTooLong
+	}
+	return note, nil
+}
+
+func moderationPageSize(limit int) int {
+	if limit <= 0 {
+		return DefaultModerationPageSize
+	}
+	if limit > MaxModerationPageSize {
+		return MaxModerationPageSize
+	}
+	return limit
+}
+
+// excerpt обрезает текст до n символов, отмечая обрезку многоточием
+func excerpt(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:n])) + "…"
+}
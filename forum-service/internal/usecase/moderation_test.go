@@ -0,0 +1,439 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockModerationRepository struct {
+	mock.Mock
+}
+
+func (m *MockModerationRepository) GetReportTargetAuthor(ctx context.Context, target entity.ReportTarget, id int) (*int, error) {
+	args := m.Called(ctx, target, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*int), args.Error(1)
+}
+
+func (m *MockModerationRepository) CreateReport(ctx context.Context, report *entity.Report) (bool, error) {
+	args := m.Called(ctx, report)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockModerationRepository) GetReportGroup(ctx context.Context, target entity.ReportTarget, id int) (*entity.ReportGroup, error) {
+	args := m.Called(ctx, target, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ReportGroup), args.Error(1)
+}
+
+func (m *MockModerationRepository) ListReportGroups(ctx context.Context, q entity.ReportQueueQuery) ([]*entity.ReportGroup, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ReportGroup), args.Error(1)
+}
+
+func (m *MockModerationRepository) ResolveReports(ctx context.Context, entry *entity.ModerationLogEntry) ([]*entity.Notification, error) {
+	args := m.Called(ctx, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Notification), args.Error(1)
+}
+
+func (m *MockModerationRepository) ListModerationLog(ctx context.Context, q entity.ModerationLogQuery) ([]*entity.ModerationLogEntry, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ModerationLogEntry), args.Error(1)
+}
+
+func (m *MockModerationRepository) SetPostStatus(ctx context.Context, update entity.PostStatusUpdate, entry *entity.ModerationLogEntry) (*entity.PostStatus, error) {
+	args := m.Called(ctx, update, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PostStatus), args.Error(1)
+}
+
+func (m *MockModerationRepository) GetActiveBan(ctx context.Context, userID int) (*entity.UserBan, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserBan), args.Error(1)
+}
+
+var moderatorUser = &entity.User{ID: 9, Role: entity.RoleModerator}
+
+func newModerationUseCase() (usecase.ModerationUseCase, *MockModerationRepository, *MockUserRepository) {
+	repo := new(MockModerationRepository)
+	userRepo := new(MockUserRepository)
+	return usecase.NewModerationUseCase(repo, userRepo, usecase.NewNotificationHub()), repo, userRepo
+}
+
+func TestModerationUseCase_Report(t *testing.T) {
+	input := entity.ReportInput{TargetType: entity.ReportOnComment, TargetID: 4, Reason: entity.ReasonSpam, Note: "  link farm "}
+
+	t.Run("Created", func(t *testing.T) {
+		uc, repo, _ := newModerationUseCase()
+		repo.On("GetReportTargetAuthor", mock.Anything, entity.ReportOnComment, 4).Return(intPtr(3), nil)
+		repo.On("CreateReport", mock.Anything, mock.MatchedBy(func(r *entity.Report) bool {
+			return r.ReporterID == 2 && *r.TargetUserID == 3 && r.Note == "link farm"
+		})).Return(true, nil)
+
+		report, created, err := uc.Report(context.Background(), 2, input)
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, entity.ReasonSpam, report.Reason)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Coalesced", func(t *testing.T) {
+		uc, repo, _ := newModerationUseCase()
+		repo.On("GetReportTargetAuthor", mock.Anything, entity.ReportOnComment, 4).Return(intPtr(3), nil)
+		repo.On("CreateReport", mock.Anything, mock.Anything).Return(false, nil)
+
+		_, created, err := uc.Report(context.Background(), 2, input)
+		require.NoError(t, err)
+		assert.False(t, created)
+	})
+
+	tests := []struct {
+		name        string
+		input       entity.ReportInput
+		setup       func(*MockModerationRepository)
+		expectedErr error
+	}{
+		{
+			name:        "InvalidTarget",
+			input:       entity.ReportInput{TargetType: "user", TargetID: 1, Reason: entity.ReasonSpam},
+			expectedErr: usecase.ErrInvalidReportTarget,
+		},
+		{
+			name:        "InvalidReason",
+			input:       entity.ReportInput{TargetType: entity.ReportOnPost, TargetID: 1, Reason: "boring"},
+			expectedErr: usecase.ErrInvalidReportReason,
+		},
+		{
+			name:        "NoteTooLong",
+			input:       entity.ReportInput{TargetType: entity.ReportOnPost, TargetID: 1, Reason: entity.ReasonOther, Note: strings.Repeat("a", usecase.MaxModerationNoteLength+1)},
+			expectedErr: usecase.ErrModerationNoteTooLong,
+		},
+		{
+			name:  "TargetNotFound",
+			input: input,
+			setup: func(r *MockModerationRepository) {
+				r.On("GetReportTargetAuthor", mock.Anything, entity.ReportOnComment, 4).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: usecase.ErrReportTargetNotFound,
+		},
+		{
+			name:  "OwnContent",
+			input: input,
+			setup: func(r *MockModerationRepository) {
+				r.On("GetReportTargetAuthor", mock.Anything, entity.ReportOnComment, 4).Return(intPtr(2), nil)
+			},
+			expectedErr: usecase.ErrCannotReportOwn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, _ := newModerationUseCase()
+			if tt.setup != nil {
+				tt.setup(repo)
+			}
+
+			_, _, err := uc.Report(context.Background(), 2, tt.input)
+			assert.ErrorIs(t, err, tt.expectedErr)
+			repo.AssertNotCalled(t, "CreateReport", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestModerationUseCase_ListReportQueue(t *testing.T) {
+	t.Run("PageWithCursor", func(t *testing.T) {
+		uc, repo, userRepo := newModerationUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+		groups := []*entity.ReportGroup{
+			{TargetType: entity.ReportOnPost, TargetID: 1, FirstReportID: 3, Content: &entity.ReportedContent{Excerpt: strings.Repeat("x", usecase.ReportExcerptLength+10)}},
+			{TargetType: entity.ReportOnComment, TargetID: 2, FirstReportID: 5},
+			{TargetType: entity.ReportOnPost, TargetID: 6, FirstReportID: 8},
+		}
+		repo.On("ListReportGroups", mock.Anything, entity.ReportQueueQuery{AfterID: 1, Limit: 3}).Return(groups, nil)
+
+		page, err := uc.ListReportQueue(context.Background(), 9, entity.ReportQueueParams{Cursor: "1", Limit: 2})
+		require.NoError(t, err)
+		assert.Len(t, page.Groups, 2)
+		assert.Equal(t, "5", page.NextCursor)
+		assert.Equal(t, usecase.ReportExcerptLength+1, len([]rune(page.Groups[0].Content.Excerpt)))
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		uc, repo, userRepo := newModerationUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+		repo.On("ListReportGroups", mock.Anything, entity.ReportQueueQuery{TargetType: entity.ReportOnChatMessage, Limit: usecase.DefaultModerationPageSize + 1}).Return(nil, nil)
+
+		page, err := uc.ListReportQueue(context.Background(), 9, entity.ReportQueueParams{TargetType: entity.ReportOnChatMessage})
+		require.NoError(t, err)
+		assert.NotNil(t, page.Groups)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("NotModerator", func(t *testing.T) {
+		uc, repo, userRepo := newModerationUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2, Role: entity.RoleUser}, nil)
+
+		_, err := uc.ListReportQueue(context.Background(), 2, entity.ReportQueueParams{})
+		assert.ErrorIs(t, err, usecase.ErrModeratorRequired)
+		repo.AssertNotCalled(t, "ListReportGroups", mock.Anything, mock.Anything)
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		uc, _, userRepo := newModerationUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+
+		_, err := uc.ListReportQueue(context.Background(), 9, entity.ReportQueueParams{Cursor: "abc"})
+		assert.ErrorIs(t, err, usecase.ErrInvalidCursor)
+	})
+}
+
+func TestModerationUseCase_ResolveReports(t *testing.T) {
+	content := &entity.ReportedContent{AuthorID: intPtr(3), Excerpt: "spam"}
+
+	t.Run("Hide", func(t *testing.T) {
+		uc, repo, userRepo := newModerationUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+		repo.On("GetReportGroup", mock.Anything, entity.ReportOnComment, 4).
+			Return(&entity.ReportGroup{TargetUserID: intPtr(3), Content: content}, nil)
+		repo.On("ResolveReports", mock.Anything, mock.MatchedBy(func(e *entity.ModerationLogEntry) bool {
+			return e.Action == entity.ModerationHide && *e.ModeratorID == 9 && *e.TargetUserID == 3 && e.BanExpiresAt == nil
+		})).Return(nil, nil)
+
+		entry, err := uc.ResolveReports(context.Background(), 9, entity.ReportOnComment, 4, entity.ModerationResolution{Action: entity.ModerationHide})
+		require.NoError(t, err)
+		assert.Equal(t, 4, entry.TargetID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("TemporaryBan", func(t *testing.T) {
+		uc, repo, userRepo := newModerationUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+		userRepo.On("GetUserByID", mock.Anything, 3).Return(&entity.User{ID: 3, Role: entity.RoleUser}, nil)
+		repo.On("GetReportGroup", mock.Anything, entity.ReportOnPost, 1).
+			Return(&entity.ReportGroup{TargetUserID: intPtr(3), Content: content}, nil)
+		repo.On("ResolveReports", mock.Anything, mock.MatchedBy(func(e *entity.ModerationLogEntry) bool {
+			return e.BanExpiresAt != nil && time.Until(*e.BanExpiresAt) > 6*24*time.Hour
+		})).Return([]*entity.Notification{{ID: 1, UserID: 3, Type: entity.NotificationBan}}, nil)
+
+		_, err := uc.ResolveReports(context.Background(), 9, entity.ReportOnPost, 1,
+			entity.ModerationResolution{Action: entity.ModerationBan, BanDays: 7})
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name        string
+		target      entity.ReportTarget
+		resolution  entity.ModerationResolution
+		setup       func(*MockModerationRepository, *MockUserRepository)
+		expectedErr error
+	}{
+		{
+			name:        "InvalidAction",
+			target:      entity.ReportOnPost,
+			resolution:  entity.ModerationResolution{Action: "shrug"},
+			expectedErr: usecase.ErrInvalidModerationAction,
+		},
+		{
+			name:        "BanDaysWithoutBan",
+			target:      entity.ReportOnPost,
+			resolution:  entity.ModerationResolution{Action: entity.ModerationWarn, BanDays: 3},
+			expectedErr: usecase.ErrInvalidBanDuration,
+		},
+		{
+			name:       "NoOpenReports",
+			target:     entity.ReportOnPost,
+			resolution: entity.ModerationResolution{Action: entity.ModerationDismiss},
+			setup: func(r *MockModerationRepository, _ *MockUserRepository) {
+				r.On("GetReportGroup", mock.Anything, entity.ReportOnPost, 1).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: usecase.ErrNoOpenReports,
+		},
+		{
+			name:       "HideDeletedContent",
+			target:     entity.ReportOnPost,
+			resolution: entity.ModerationResolution{Action: entity.ModerationHide},
+			setup: func(r *MockModerationRepository, _ *MockUserRepository) {
+				r.On("GetReportGroup", mock.Anything, entity.ReportOnPost, 1).Return(&entity.ReportGroup{TargetUserID: intPtr(3)}, nil)
+			},
+			expectedErr: usecase.ErrReportTargetNotFound,
+		},
+		{
+			name:       "WarnModerator",
+			target:     entity.ReportOnPost,
+			resolution: entity.ModerationResolution{Action: entity.ModerationWarn},
+			setup: func(r *MockModerationRepository, u *MockUserRepository) {
+				r.On("GetReportGroup", mock.Anything, entity.ReportOnPost, 1).Return(&entity.ReportGroup{TargetUserID: intPtr(4), Content: content}, nil)
+				u.On("GetUserByID", mock.Anything, 4).Return(&entity.User{ID: 4, Role: entity.RoleAdmin}, nil)
+			},
+			expectedErr: usecase.ErrCannotSanctionModerator,
+		},
+		{
+			name:       "BanWithoutAuthor",
+			target:     entity.ReportOnChatMessage,
+			resolution: entity.ModerationResolution{Action: entity.ModerationBan},
+			setup: func(r *MockModerationRepository, _ *MockUserRepository) {
+				r.On("GetReportGroup", mock.Anything, entity.ReportOnChatMessage, 1).Return(&entity.ReportGroup{}, nil)
+			},
+			expectedErr: usecase.ErrUserNotFound,
+		},
+		{
+			name:       "ResolvedConcurrently",
+			target:     entity.ReportOnPost,
+			resolution: entity.ModerationResolution{Action: entity.ModerationDismiss},
+			setup: func(r *MockModerationRepository, _ *MockUserRepository) {
+				r.On("GetReportGroup", mock.Anything, entity.ReportOnPost, 1).Return(&entity.ReportGroup{Content: content}, nil)
+				r.On("ResolveReports", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: usecase.ErrNoOpenReports,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, userRepo := newModerationUseCase()
+			userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+			if tt.setup != nil {
+				tt.setup(repo, userRepo)
+			}
+
+			_, err := uc.ResolveReports(context.Background(), 9, tt.target, 1, tt.resolution)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestModerationUseCase_ListModerationLog(t *testing.T) {
+	uc, repo, userRepo := newModerationUseCase()
+	userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+	entries := []*entity.ModerationLogEntry{{ID: 10}, {ID: 7}}
+	repo.On("ListModerationLog", mock.Anything, entity.ModerationLogQuery{TargetUserID: 3, BeforeID: 12, Limit: 2}).Return(entries, nil)
+
+	page, err := uc.ListModerationLog(context.Background(), 9, entity.ModerationLogParams{TargetUserID: 3, Cursor: "12", Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, page.Entries, 1)
+	assert.Equal(t, "10", page.NextCursor)
+}
+
+func TestModerationUseCase_SetPostState(t *testing.T) {
+	t.Run("Locks", func(t *testing.T) {
+		uc, repo, userRepo := newModerationUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+		repo.On("SetPostStatus", mock.Anything, entity.PostStatusUpdate{State: entity.PostLocked}, mock.MatchedBy(func(e *entity.ModerationLogEntry) bool {
+			return *e.ModeratorID == 9 && e.TargetID == 4 && e.Note == "flame war"
+		})).Return(&entity.PostStatus{PostID: 4, State: entity.PostLocked}, nil)
+
+		status, err := uc.SetPostState(context.Background(), 9, 4, entity.PostStateInput{State: entity.PostLocked, Note: " flame war "})
+		require.NoError(t, err)
+		assert.Equal(t, entity.PostLocked, status.State)
+	})
+
+	t.Run("InvalidState", func(t *testing.T) {
+		uc, _, _ := newModerationUseCase()
+		_, err := uc.SetPostState(context.Background(), 9, 4, entity.PostStateInput{State: "closed"})
+		assert.ErrorIs(t, err, usecase.ErrInvalidPostState)
+	})
+
+	t.Run("NotModerator", func(t *testing.T) {
+		uc, _, userRepo := newModerationUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2, Role: entity.RoleUser}, nil)
+		_, err := uc.SetPostState(context.Background(), 2, 4, entity.PostStateInput{State: entity.PostArchived})
+		assert.ErrorIs(t, err, usecase.ErrModeratorRequired)
+	})
+
+	t.Run("PostNotFound", func(t *testing.T) {
+		uc, repo, userRepo := newModerationUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+		repo.On("SetPostStatus", mock.Anything, mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+		_, err := uc.SetPostState(context.Background(), 9, 4, entity.PostStateInput{State: entity.PostOpen})
+		assert.ErrorIs(t, err, usecase.ErrPostNotFound)
+	})
+}
+
+func TestModerationUseCase_SetPostPinned(t *testing.T) {
+	t.Run("Pins", func(t *testing.T) {
+		uc, repo, userRepo := newModerationUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+		pinned := true
+		repo.On("SetPostStatus", mock.Anything, entity.PostStatusUpdate{Pinned: &pinned}, mock.Anything).
+			Return(&entity.PostStatus{PostID: 4, State: entity.PostOpen, Pinned: true}, nil)
+
+		status, err := uc.SetPostPinned(context.Background(), 9, 4, entity.PostPinInput{Pinned: &pinned})
+		require.NoError(t, err)
+		assert.True(t, status.Pinned)
+	})
+
+	t.Run("MissingFlag", func(t *testing.T) {
+		uc, _, _ := newModerationUseCase()
+		_, err := uc.SetPostPinned(context.Background(), 9, 4, entity.PostPinInput{})
+		assert.Error(t, err)
+	})
+}
+
+func TestModerationUseCase_CheckNotBanned(t *testing.T) {
+	t.Run("NotBanned", func(t *testing.T) {
+		uc, repo, _ := newModerationUseCase()
+		repo.On("GetActiveBan", mock.Anything, 3).Return(nil, sql.ErrNoRows)
+
+		assert.NoError(t, uc.CheckNotBanned(context.Background(), 3))
+	})
+
+	t.Run("Banned", func(t *testing.T) {
+		uc, repo, _ := newModerationUseCase()
+		expires := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		repo.On("GetActiveBan", mock.Anything, 3).Return(&entity.UserBan{UserID: 3, ExpiresAt: &expires}, nil)
+
+		err := uc.CheckNotBanned(context.Background(), 3)
+		assert.ErrorIs(t, err, usecase.ErrUserBanned)
+		assert.Contains(t, err.Error(), "2030-01-02T03:04:05Z")
+	})
+
+	t.Run("RepositoryError", func(t *testing.T) {
+		uc, repo, _ := newModerationUseCase()
+		repo.On("GetActiveBan", mock.Anything, 3).Return(nil, errors.New("database error"))
+
+		err := uc.CheckNotBanned(context.Background(), 3)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, usecase.ErrUserBanned)
+	})
+}
+
+func TestChatUseCase_SendMessageBanned(t *testing.T) {
+	chatRepo := new(MockChatRepository)
+	chat := usecase.NewChatUseCase(chatRepo, new(mockAuthUC))
+	moderation, repo, _ := newModerationUseCase()
+	chat.SetBanChecker(moderation)
+	repo.On("GetActiveBan", mock.Anything, 3).Return(&entity.UserBan{UserID: 3}, nil)
+
+	err := chat.SendMessage(context.Background(), &entity.ChatMessage{UserID: 3, Text: "hi"})
+	assert.ErrorIs(t, err, usecase.ErrUserBanned)
+	chatRepo.AssertNotCalled(t, "SaveChatMessage", mock.Anything, mock.Anything)
+}
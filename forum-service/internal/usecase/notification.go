@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+const (
+	DefaultNotificationPageSize = 20
+	MaxNotificationPageSize     = 100
+)
+
+var (
+	ErrNotificationNotFound      = errors.New("notification not found")
+	ErrInvalidNotificationCursor = errors.New("invalid notification cursor")
+)
+
+type NotificationRepository interface {
+	Subscribe(ctx context.Context, userID, postID int) error
+	Unsubscribe(ctx context.Context, userID, postID int) error
+	IsSubscribed(ctx context.Context, userID, postID int) (bool, error)
+	CreateCommentNotifications(ctx context.Context, comment *entity.Comment) ([]*entity.Notification, error)
+	ListNotifications(ctx context.Context, q entity.NotificationQuery) ([]*entity.Notification, error)
+	MarkNotificationRead(ctx context.Context, userID, notificationID int) error
+	MarkAllNotificationsRead(ctx context.Context, userID int) (int, error)
+	CountUnreadNotifications(ctx context.Context, userID int) (int, error)
+}
+
+// CommentNotifier получает только что созданные комментарии
+type CommentNotifier interface {
+	CommentCreated(ctx context.Context, comment *entity.Comment)
+}
+
+type NotificationUseCase interface {
+	CommentNotifier
+	Subscribe(ctx context.Context, userID, postID int) error
+	Unsubscribe(ctx context.Context, userID, postID int) error
+	IsSubscribed(ctx context.Context, userID, postID int) (bool, error)
+	ListNotifications(ctx context.Context, userID int, params entity.NotificationListParams) (*entity.NotificationPage, error)
+	MarkRead(ctx context.Context, userID, notificationID int) error
+	MarkAllRead(ctx context.Context, userID int) (int, error)
+	UnreadCount(ctx context.Context, userID int) (int, error)
+	HandleWebSocket(userID int, conn WebSocketConnection)
+}
+
+type NotificationService struct {
+	repo     NotificationRepository
+	postRepo PostRepository
+	hub      *NotificationHub
+}
+
+func NewNotificationUseCase(repo NotificationRepository, postRepo PostRepository, hub *NotificationHub) NotificationUseCase {
+	return &NotificationService{
+		repo:     repo,
+		postRepo: postRepo,
+		hub:      hub,
+	}
+}
+
+// Subscribe подписывает пользователя на новые комментарии поста. Повторная подписка
+// ничего не меняет.
+func (s *NotificationService) Subscribe(ctx context.Context, userID, postID int) error {
+	if err := s.requirePost(ctx, postID); err != nil {
+		return err
+	}
+	return s.repo.Subscribe(ctx, userID, postID)
+}
+
+// Unsubscribe отменяет подписку; отмена несуществующей подписки не ошибка
+func (s *NotificationService) Unsubscribe(ctx context.Context, userID, postID int) error {
+	if err := s.requirePost(ctx, postID); err != nil {
+		return err
+	}
+	return s.repo.Unsubscribe(ctx, userID, postID)
+}
+
+func (s *NotificationService) IsSubscribed(ctx context.Context, userID, postID int) (bool, error) {
+	if err := s.requirePost(ctx, postID); err != nil {
+		return false, err
+	}
+	return s.repo.IsSubscribed(ctx, userID, postID)
+}
+
+// CommentCreated создает уведомления о новом комментарии и отправляет их подключенным
+// получателям. Комментарий уже сохранен, поэтому ошибка только логируется.
+func (s *NotificationService) CommentCreated(ctx context.Context, comment *entity.Comment) {
+	notifications, err := s.repo.CreateCommentNotifications(ctx, comment)
+	if err != nil {
+		log.Printf("[ERROR] Failed to create notifications for comment %d: %v", comment.ID, err)
+		return
+	}
+	for _, n := range notifications {
+		s.hub.Publish(n)
+	}
+}
+
+// ListNotifications возвращает страницу уведомлений пользователя, новые первыми,
+// и общее число непрочитанных
+func (s *NotificationService) ListNotifications(ctx context.Context, userID int, params entity.NotificationListParams) (*entity.NotificationPage, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultNotificationPageSize
+	}
+	if limit > MaxNotificationPageSize {
+		limit = MaxNotificationPageSize
+	}
+
+	q := entity.NotificationQuery{UserID: userID, UnreadOnly: params.UnreadOnly, Limit: limit + 1}
+	if params.Cursor != "" {
+		beforeID, err := strconv.Atoi(params.Cursor)
+		if err != nil || beforeID <= 0 {
+			return nil, ErrInvalidNotificationCursor
+		}
+		q.BeforeID = beforeID
+	}
+
+	notifications, err := s.repo.ListNotifications(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	page := &entity.NotificationPage{Notifications: notifications}
+	if len(notifications) > limit {
+		page.Notifications = notifications[:limit]
+		page.NextCursor = strconv.Itoa(page.Notifications[limit-1].ID)
+	}
+	if page.Notifications == nil {
+		page.Notifications = []*entity.Notification{}
+	}
+
+	page.UnreadCount, err = s.repo.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// MarkRead отмечает уведомление прочитанным. Чужое уведомление не найдено.
+func (s *NotificationService) MarkRead(ctx context.Context, userID, notificationID int) error {
+	err := s.repo.MarkNotificationRead(ctx, userID, notificationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotificationNotFound
+	}
+	return err
+}
+
+// MarkAllRead отмечает прочитанными все уведомления и возвращает, сколько их было
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID int) (int, error) {
+	return s.repo.MarkAllNotificationsRead(ctx, userID)
+}
+
+func (s *NotificationService) UnreadCount(ctx context.Context, userID int) (int, error) {
+	return s.repo.CountUnreadNotifications(ctx, userID)
+}
+
+// HandleWebSocket отправляет новые уведомления пользователя в conn, пока соединение открыто
+func (s *NotificationService) HandleWebSocket(userID int, conn WebSocketConnection) {
+	s.hub.Serve(userID, conn)
+}
+
+func (s *NotificationService) requirePost(ctx context.Context, postID int) error {
+	_, err := s.postRepo.GetPostByID(ctx, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPostNotFound
+	}
+	return err
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+// NotificationSendBuffer - сколько уведомлений может ждать отправки одному клиенту;
+// клиент, не успевающий их читать, отключается
+const NotificationSendBuffer = 32
+
+// NotificationHub рассылает новые уведомления открытым WebSocket-соединениям их
+// получателей. У одного пользователя может быть несколько соединений (вкладок).
+type NotificationHub struct {
+	mutex     sync.Mutex
+	clients   map[int]map[*notificationClient]struct{}
+	closing   bool
+	clientsWG sync.WaitGroup
+}
+
+type notificationClient struct {
+	conn     WebSocketConnection
+	send     chan *entity.Notification
+	closeMsg []byte // Close-фрейм, отправляемый при закрытии send
+}
+
+func NewNotificationHub() *NotificationHub {
+	return &NotificationHub{clients: make(map[int]map[*notificationClient]struct{})}
+}
+
+// Serve подключает conn к уведомлениям пользователя userID и блокируется, пока
+// соединение не закроется. Входящие сообщения клиента игнорируются.
+func (h *NotificationHub) Serve(userID int, conn WebSocketConnection) {
+	client := &notificationClient{
+		conn: conn,
+		send: make(chan *entity.Notification, NotificationSendBuffer),
+	}
+
+	h.mutex.Lock()
+	if h.closing {
+		h.mutex.Unlock()
+		conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+		conn.Close()
+		return
+	}
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*notificationClient]struct{})
+	}
+	h.clients[userID][client] = struct{}{}
+	h.clientsWG.Add(1)
+	h.mutex.Unlock()
+
+	go func() {
+		defer h.clientsWG.Done()
+		client.writePump()
+	}()
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure, websocket.CloseAbnormalClosure) {
+				log.Printf("Notification WebSocket error: %v", err)
+			}
+			break
+		}
+	}
+	h.remove(userID, client, nil)
+	conn.Close()
+}
+
+// Publish отправляет уведомление всем соединениям получателя, не блокируясь
+func (h *NotificationHub) Publish(n *entity.Notification) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for client := range h.clients[n.UserID] {
+		select {
+		case client.send <- n:
+		default:
+			log.Printf("[WARN] Dropping slow notification client of user %d", n.UserID)
+			h.removeLocked(n.UserID, client,
+				websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow"))
+		}
+	}
+}
+
+// ConnectedUsers возвращает число пользователей с открытыми соединениями
+func (h *NotificationHub) ConnectedUsers() int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return len(h.clients)
+}
+
+// Shutdown отправляет всем клиентам close-фрейм "going away" и ждет, пока они будут
+// отправлены, или отмены ctx. Новые подключения после этого отклоняются.
+func (h *NotificationHub) Shutdown(ctx context.Context) error {
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	h.mutex.Lock()
+	h.closing = true
+	for userID, clients := range h.clients {
+		for client := range clients {
+			h.removeLocked(userID, client, closeMsg)
+		}
+	}
+	h.mutex.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		h.clientsWG.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *NotificationHub) remove(userID int, client *notificationClient, closeMsg []byte) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.removeLocked(userID, client, closeMsg)
+}
+
+// removeLocked отключает клиента, если он еще подключен. Закрытие send завершает
+// его writePump.
+func (h *NotificationHub) removeLocked(userID int, client *notificationClient, closeMsg []byte) {
+	clients := h.clients[userID]
+	if _, ok := clients[client]; !ok {
+		return
+	}
+	delete(clients, client)
+	if len(clients) == 0 {
+		delete(h.clients, userID)
+	}
+	client.closeMsg = closeMsg
+	close(client.send)
+}
+
+func (c *notificationClient) writePump() {
+	defer c.conn.Close()
+	for n := range c.send {
+		c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if err := c.conn.WriteJSON(n); err != nil {
+			// Закрытие соединения завершит цикл чтения в Serve, и тот отключит
+			// клиента; до тех пор просто дочитываем канал
+			c.conn.Close()
+			for range c.send {
+			}
+			return
+		}
+	}
+	closeMsg := c.closeMsg
+	if closeMsg == nil {
+		closeMsg = []byte{}
+	}
+	c.conn.WriteMessage(websocket.CloseMessage, closeMsg)
+}
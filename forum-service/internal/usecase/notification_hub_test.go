@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingNotificationConn - соединение, чтение из которого блокируется до Close,
+// а отправленные уведомления попадают в sent
+func blockingNotificationConn(sent chan<- *entity.Notification) *MockWebSocketConnection {
+	conn := new(MockWebSocketConnection)
+	released := make(chan struct{})
+	var releaseOnce sync.Once
+	conn.On("ReadMessage").
+		Run(func(mock.Arguments) { <-released }).
+		Return(0, []byte(nil), errors.New("use of closed network connection"))
+	conn.On("SetWriteDeadline", mock.Anything).Return(nil)
+	conn.On("WriteJSON", mock.Anything).
+		Run(func(args mock.Arguments) { sent <- args.Get(0).(*entity.Notification) }).
+		Return(nil)
+	conn.On("Close").
+		Run(func(mock.Arguments) { releaseOnce.Do(func() { close(released) }) }).
+		Return(nil)
+	return conn
+}
+
+func TestNotificationHub_PublishAndShutdown(t *testing.T) {
+	hub := NewNotificationHub()
+	sent := make(chan *entity.Notification, 4)
+	conn := blockingNotificationConn(sent)
+	conn.On("WriteMessage", websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")).Return(nil).Once()
+
+	served := make(chan struct{})
+	go func() {
+		hub.Serve(1, conn)
+		close(served)
+	}()
+	require.Eventually(t, func() bool { return hub.ConnectedUsers() == 1 }, time.Second, 10*time.Millisecond)
+
+	// Уведомление другого пользователя не доставляется
+	hub.Publish(&entity.Notification{ID: 1, UserID: 2})
+	hub.Publish(&entity.Notification{ID: 2, UserID: 1})
+	select {
+	case n := <-sent:
+		assert.Equal(t, 2, n.ID)
+	case <-time.After(time.Second):
+		t.Fatal("notification was not delivered")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, hub.Shutdown(ctx))
+
+	select {
+	case <-served:
+	case <-time.After(time.Second):
+		t.Fatal("Serve did not return after shutdown")
+	}
+	assert.Equal(t, 0, hub.ConnectedUsers())
+	assert.Empty(t, sent)
+	conn.AssertExpectations(t)
+}
+
+func TestNotificationHub_RejectsAfterShutdown(t *testing.T) {
+	hub := NewNotificationHub()
+	require.NoError(t, hub.Shutdown(context.Background()))
+
+	conn := new(MockWebSocketConnection)
+	conn.On("WriteMessage", websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")).Return(nil).Once()
+	conn.On("Close").Return(nil).Once()
+
+	hub.Serve(1, conn)
+	assert.Equal(t, 0, hub.ConnectedUsers())
+	conn.AssertExpectations(t)
+}
@@ -0,0 +1,181 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Subscribe(ctx context.Context, userID, postID int) error {
+	return m.Called(ctx, userID, postID).Error(0)
+}
+
+func (m *MockNotificationRepository) Unsubscribe(ctx context.Context, userID, postID int) error {
+	return m.Called(ctx, userID, postID).Error(0)
+}
+
+func (m *MockNotificationRepository) IsSubscribed(ctx context.Context, userID, postID int) (bool, error) {
+	args := m.Called(ctx, userID, postID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) CreateCommentNotifications(ctx context.Context, comment *entity.Comment) ([]*entity.Notification, error) {
+	args := m.Called(ctx, comment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) ListNotifications(ctx context.Context, q entity.NotificationQuery) ([]*entity.Notification, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkNotificationRead(ctx context.Context, userID, notificationID int) error {
+	return m.Called(ctx, userID, notificationID).Error(0)
+}
+
+func (m *MockNotificationRepository) MarkAllNotificationsRead(ctx context.Context, userID int) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) CountUnreadNotifications(ctx context.Context, userID int) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+type MockCommentNotifier struct {
+	mock.Mock
+}
+
+func (m *MockCommentNotifier) CommentCreated(ctx context.Context, comment *entity.Comment) {
+	m.Called(ctx, comment)
+}
+
+func newNotificationUseCase() (usecase.NotificationUseCase, *MockNotificationRepository, *MockPostRepository) {
+	repo := new(MockNotificationRepository)
+	postRepo := new(MockPostRepository)
+	return usecase.NewNotificationUseCase(repo, postRepo, usecase.NewNotificationHub()), repo, postRepo
+}
+
+func TestNotificationUseCase_Subscribe(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		uc, repo, postRepo := newNotificationUseCase()
+		postRepo.On("GetPostByID", mock.Anything, 3).Return(&entity.Post{ID: 3}, nil)
+		repo.On("Subscribe", mock.Anything, 2, 3).Return(nil)
+
+		require.NoError(t, uc.Subscribe(context.Background(), 2, 3))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("PostNotFound", func(t *testing.T) {
+		uc, repo, postRepo := newNotificationUseCase()
+		postRepo.On("GetPostByID", mock.Anything, 3).Return(nil, sql.ErrNoRows)
+
+		assert.ErrorIs(t, uc.Subscribe(context.Background(), 2, 3), usecase.ErrPostNotFound)
+		_, err := uc.IsSubscribed(context.Background(), 2, 3)
+		assert.ErrorIs(t, err, usecase.ErrPostNotFound)
+		repo.AssertNotCalled(t, "Subscribe")
+	})
+}
+
+func TestNotificationUseCase_ListNotifications(t *testing.T) {
+	t.Run("FirstPageWithMore", func(t *testing.T) {
+		uc, repo, _ := newNotificationUseCase()
+		repo.On("ListNotifications", mock.Anything, entity.NotificationQuery{UserID: 2, UnreadOnly: true, Limit: 3}).
+			Return([]*entity.Notification{{ID: 9}, {ID: 7}, {ID: 4}}, nil)
+		repo.On("CountUnreadNotifications", mock.Anything, 2).Return(5, nil)
+
+		page, err := uc.ListNotifications(context.Background(), 2, entity.NotificationListParams{UnreadOnly: true, Limit: 2})
+		require.NoError(t, err)
+		assert.Len(t, page.Notifications, 2)
+		assert.Equal(t, "7", page.NextCursor)
+		assert.Equal(t, 5, page.UnreadCount)
+	})
+
+	t.Run("LastPage", func(t *testing.T) {
+		uc, repo, _ := newNotificationUseCase()
+		repo.On("ListNotifications", mock.Anything, entity.NotificationQuery{UserID: 2, BeforeID: 7, Limit: usecase.DefaultNotificationPageSize + 1}).
+			Return(nil, nil)
+		repo.On("CountUnreadNotifications", mock.Anything, 2).Return(0, nil)
+
+		page, err := uc.ListNotifications(context.Background(), 2, entity.NotificationListParams{Cursor: "7"})
+		require.NoError(t, err)
+		assert.NotNil(t, page.Notifications)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		uc, repo, _ := newNotificationUseCase()
+
+		for _, cursor := range []string{"abc", "-1", "0"} {
+			_, err := uc.ListNotifications(context.Background(), 2, entity.NotificationListParams{Cursor: cursor})
+			assert.ErrorIs(t, err, usecase.ErrInvalidNotificationCursor)
+		}
+		repo.AssertNotCalled(t, "ListNotifications")
+	})
+}
+
+func TestNotificationUseCase_MarkRead(t *testing.T) {
+	uc, repo, _ := newNotificationUseCase()
+	repo.On("MarkNotificationRead", mock.Anything, 2, 5).Return(nil)
+	repo.On("MarkNotificationRead", mock.Anything, 2, 6).Return(fmt.Errorf("notification: %w", sql.ErrNoRows))
+
+	require.NoError(t, uc.MarkRead(context.Background(), 2, 5))
+	assert.ErrorIs(t, uc.MarkRead(context.Background(), 2, 6), usecase.ErrNotificationNotFound)
+}
+
+func TestNotificationUseCase_CommentCreatedIgnoresErrors(t *testing.T) {
+	uc, repo, _ := newNotificationUseCase()
+	comment := &entity.Comment{ID: 1, PostID: 1, UserID: 2}
+	repo.On("CreateCommentNotifications", mock.Anything, comment).Return(nil, errors.New("database error"))
+
+	uc.CommentCreated(context.Background(), comment)
+	repo.AssertExpectations(t)
+}
+
+func TestCommentUseCase_CreateCommentNotifies(t *testing.T) {
+	t.Run("Created", func(t *testing.T) {
+		repo := new(MockCommentRepository)
+		notifier := new(MockCommentNotifier)
+		uc := usecase.NewCommentUseCase(repo, new(MockUserRepository))
+		uc.SetNotifier(notifier)
+		comment := &entity.Comment{Content: "hi", PostID: 1, UserID: 2}
+		expectOpenPost(repo, 1)
+		repo.On("CreateComment", mock.Anything, comment).Return(nil)
+		notifier.On("CommentCreated", mock.Anything, comment).Return()
+
+		require.NoError(t, uc.CreateComment(context.Background(), comment))
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("NotCreated", func(t *testing.T) {
+		repo := new(MockCommentRepository)
+		notifier := new(MockCommentNotifier)
+		uc := usecase.NewCommentUseCase(repo, new(MockUserRepository))
+		uc.SetNotifier(notifier)
+		comment := &entity.Comment{Content: "hi", PostID: 1, UserID: 2}
+		expectOpenPost(repo, 1)
+		repo.On("CreateComment", mock.Anything, comment).Return(errors.New("database error"))
+
+		require.Error(t, uc.CreateComment(context.Background(), comment))
+		notifier.AssertNotCalled(t, "CommentCreated")
+	})
+}
@@ -2,34 +2,144 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
 )
 
+// Размер страницы списка постов
+const (
+	DefaultPostPageSize = 20
+	MaxPostPageSize     = 100
+	// MaxEditSummaryLength - предел длины описания правки в истории версий
+	MaxEditSummaryLength = 255
+	// PublishBatchSize - сколько запланированных постов публикуется за один запрос к базе
+	PublishBatchSize = 100
+)
+
+var (
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidSort      = errors.New("invalid sort: use newest, oldest, most_commented, recently_active, hot, top, rising or most_viewed")
+	ErrInvalidWindow    = errors.New("invalid window: use day, week or all with sort=top or sort=most_viewed")
+	ErrInvalidDateRange = errors.New("invalid date range: from must be before to")
+
+	ErrPostNotFound        = errors.New("post not found")
+	ErrPostEditForbidden   = errors.New("unauthorized: you can only update your own posts")
+	ErrPostDeleteForbidden = errors.New("unauthorized: you can only delete your own posts")
+	ErrEditSummaryTooLong  = errors.New("edit summary is too long: use at most 255 characters")
+
+	ErrPostVersionRequired = errors.New("post version is required: send the post ETag in If-Match")
+	ErrPostVersionConflict = errors.New("post was modified by someone else")
+
+	ErrPostLocked   = errors.New("post is locked: new comments are not accepted")
+	ErrPostArchived = errors.New("post is archived and read-only")
+
+	ErrInvalidPublishStatus = errors.New("invalid status: use draft, scheduled or published")
+	ErrInvalidPublishAt     = errors.New("invalid publish_at: a scheduled post needs a time in the future, other statuses none")
+	ErrPostAlreadyPublished = errors.New("post is already published")
+)
+
+// PostClosedError - модератор закрыл пост для записи. errors.Is(err, ErrPostLocked)
+// истинно для закрытого обсуждения, errors.Is(err, ErrPostArchived) - для архивного.
+type PostClosedError struct {
+	PostID int
+	State  entity.PostState
+}
+
+func (e *PostClosedError) Error() string {
+	return e.sentinel().Error()
+}
+
+func (e *PostClosedError) Is(target error) bool {
+	return target == e.sentinel()
+}
+
+func (e *PostClosedError) sentinel() error {
+	if e.State == entity.PostArchived {
+		return ErrPostArchived
+	}
+	return ErrPostLocked
+}
+
+// checkPostWritable возвращает *PostClosedError, если состояние поста запрещает
+// запись. Закрытое обсуждение не принимает новых комментариев (commenting), но
+// пост и прежние комментарии в нем можно править; архивный пост только читается.
+func checkPostWritable(postID int, state entity.PostState, commenting bool) error {
+	if state == entity.PostArchived || (commenting && state == entity.PostLocked) {
+		return &PostClosedError{PostID: postID, State: state}
+	}
+	return nil
+}
+
+// PostVersionConflictError - правка основана на устаревшей версии поста.
+// Current - пост в текущем состоянии; errors.Is(err, ErrPostVersionConflict) истинно.
+type PostVersionConflictError struct {
+	Current *entity.Post
+}
+
+func (e *PostVersionConflictError) Error() string {
+	return fmt.Sprintf("%v: current version is %d", ErrPostVersionConflict, e.Current.Version)
+}
+
+func (e *PostVersionConflictError) Is(target error) bool {
+	return target == ErrPostVersionConflict
+}
+
+// IsPostListInputError сообщает, вызвана ли ошибка ListPosts некорректными параметрами запроса
+func IsPostListInputError(err error) bool {
+	for _, target := range []error{
+		ErrInvalidCursor,
+		ErrInvalidSort,
+		ErrInvalidWindow,
+		ErrInvalidDateRange,
+		ErrInvalidTag,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 type PostUseCase interface {
 	CreatePost(ctx context.Context, post *entity.Post) error
-	GetPostByID(ctx context.Context, id int) (*entity.Post, error)
-	GetAllPosts(ctx context.Context) ([]*entity.Post, error)
+	GetPostByID(ctx context.Context, id, viewerID int) (*entity.Post, error)
+	ListPosts(ctx context.Context, params entity.PostListParams) (*entity.PostPage, error)
 	DeletePost(ctx context.Context, postID, userID int) error
-	UpdatePost(ctx context.Context, postID int, userID int, title, content string) error
+	UpdatePost(ctx context.Context, postID int, userID int, update entity.PostUpdate) error
+	ListDrafts(ctx context.Context, userID int, params entity.DraftListParams) (*entity.PostPage, error)
+	SetPublication(ctx context.Context, postID, userID int, publication entity.PostPublication) (*entity.Post, error)
 }
 
 type PostRepository interface {
 	CreatePost(ctx context.Context, post *entity.Post) error
 	GetPostByID(ctx context.Context, id int) (*entity.Post, error)
-	GetAllPosts(ctx context.Context) ([]*entity.Post, error)
-	DeletePost(ctx context.Context, id int) error
-	UpdatePost(ctx context.Context, postID int, title, content string) error
+	ListPosts(ctx context.Context, q entity.PostQuery) ([]*entity.Post, error)
+	DeletePost(ctx context.Context, id, deletedBy int) error
+	UpdatePost(ctx context.Context, postID, editorID int, update entity.PostUpdate) error
+	ListDrafts(ctx context.Context, q entity.DraftQuery) ([]*entity.Post, error)
+	SetPostPublication(ctx context.Context, postID int, publication entity.PostPublication) error
+	PublishDuePosts(ctx context.Context, now time.Time, limit int) ([]*entity.Post, error)
 }
 
 type UserRepository interface {
 	GetUserByID(ctx context.Context, id int) (*entity.User, error)
 }
 type PostService struct {
-	postRepo PostRepository
-	userRepo UserRepository
+	postRepo     PostRepository
+	userRepo     UserRepository
+	categoryRepo CategoryRepository
+	mentions     MentionTracker
+	attachments  AttachmentLoader
+	now          func() time.Time
 }
 
 type JWTClaims struct {
@@ -41,6 +151,9 @@ type JWTClaims struct {
 // Реализация методов PostUseCase
 func (s *PostService) DeletePost(ctx context.Context, postID, userID int) error {
 	post, err := s.postRepo.GetPostByID(ctx, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPostNotFound
+	}
 	if err != nil {
 		return err
 	}
@@ -52,11 +165,21 @@ func (s *PostService) DeletePost(ctx context.Context, postID, userID int) error
 
 	// Allow deletion if user is admin or post owner
 	if post.UserID != userID && user.Role != "admin" {
-		return errors.New("unauthorized: you can only delete your own posts")
+		return ErrPostDeleteForbidden
+	}
+	// Архивный пост только читается, в корзину его убирают модераторы
+	if !user.IsModerator() {
+		if err := checkPostWritable(postID, post.State, false); err != nil {
+			return err
+		}
 	}
 
-	// Delete the post
-	return s.postRepo.DeletePost(ctx, postID)
+	// Move the post to the trash; a post trashed meanwhile is gone as well
+	err = s.postRepo.DeletePost(ctx, postID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPostNotFound
+	}
+	return err
 }
 func (s *PostService) CreatePost(ctx context.Context, post *entity.Post) error {
 	if post == nil {
@@ -71,19 +194,191 @@ func (s *PostService) CreatePost(ctx context.Context, post *entity.Post) error {
 	if post.UserID == 0 {
 		return errors.New("user ID cannot be empty")
 	}
-	return s.postRepo.CreatePost(ctx, post)
+	if post.CategoryID == 0 {
+		return ErrCategoryRequired
+	}
+	tags, err := normalizeTags(post.Tags)
+	if err != nil {
+		return err
+	}
+	post.Tags = tags
+	if post.Format, post.ContentHTML, err = renderContent(post.Format, post.Content); err != nil {
+		return err
+	}
+	if post.AttachmentIDs, err = normalizeAttachmentIDs(post.AttachmentIDs); err != nil {
+		return err
+	}
+	if post.Status == "" {
+		post.Status = entity.PostPublished
+	}
+	if err := s.checkPublication(entity.PostPublication{Status: post.Status, PublishAt: post.PublishAt}); err != nil {
+		return err
+	}
+	if err := s.checkCanPost(ctx, post.CategoryID, post.UserID); err != nil {
+		return err
+	}
+	if err := s.postRepo.CreatePost(ctx, post); err != nil {
+		// Единственная причина sql.ErrNoRows при создании - недоступная загрузка
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAttachmentNotFound
+		}
+		return err
+	}
+	// Упомянутые узнают о посте, когда он будет опубликован
+	if !post.Unpublished() {
+		s.trackMentions(ctx, post.ID, post.UserID, post.Content)
+	}
+	if len(post.AttachmentIDs) > 0 {
+		return s.loadAttachments(ctx, post)
+	}
+	return nil
 }
 
-func (s *PostService) GetPostByID(ctx context.Context, id int) (*entity.Post, error) {
-	return s.postRepo.GetPostByID(ctx, id)
+// checkCanPost проверяет, что раздел существует и пользователь может в нем писать
+func (s *PostService) checkCanPost(ctx context.Context, categoryID, userID int) error {
+	category, err := s.categoryRepo.GetCategoryByID(ctx, categoryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCategoryNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !category.ReadOnly {
+		return nil
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsModerator() {
+		return ErrCategoryReadOnly
+	}
+	return nil
+}
+
+// checkPublication проверяет статус и время публикации: время задается только
+// запланированному посту и должно быть в будущем
+func (s *PostService) checkPublication(publication entity.PostPublication) error {
+	if !publication.Status.Valid() {
+		return ErrInvalidPublishStatus
+	}
+	if publication.Status == entity.PostScheduled {
+		if publication.PublishAt == nil || !publication.PublishAt.After(s.now()) {
+			return ErrInvalidPublishAt
+		}
+	} else if publication.PublishAt != nil {
+		return ErrInvalidPublishAt
+	}
+	return nil
 }
 
-func (s *PostService) GetAllPosts(ctx context.Context) ([]*entity.Post, error) {
-	return s.postRepo.GetAllPosts(ctx)
+// GetPostByID возвращает пост. Черновик и запланированный пост видит только автор:
+// для остальных viewerID, в том числе 0 без авторизации, возвращается ErrPostNotFound.
+func (s *PostService) GetPostByID(ctx context.Context, id, viewerID int) (*entity.Post, error) {
+	post, err := s.postRepo.GetPostByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if post.Unpublished() && post.UserID != viewerID {
+		return nil, ErrPostNotFound
+	}
+	if err := s.loadAttachments(ctx, post); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// ListPosts возвращает страницу постов. Репозиторий запрашивается на один пост
+// больше лимита: если он пришел, по последнему посту страницы строится NextCursor.
+func (s *PostService) ListPosts(ctx context.Context, params entity.PostListParams) (*entity.PostPage, error) {
+	sort := params.Sort
+	if sort == "" {
+		sort = entity.PostSortNewest
+	}
+	if !sort.Valid() {
+		return nil, ErrInvalidSort
+	}
+	window := params.Window
+	if sort.Windowed() {
+		if window == "" {
+			window = entity.TopWindowDay
+		}
+		if !window.Valid() {
+			return nil, ErrInvalidWindow
+		}
+	} else if window != "" {
+		return nil, ErrInvalidWindow
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultPostPageSize
+	}
+	if limit > MaxPostPageSize {
+		limit = MaxPostPageSize
+	}
+
+	filter := params.Filter
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, ErrInvalidDateRange
+	}
+	if filter.Tag != "" {
+		var err error
+		if filter.Tag, err = normalizeTag(filter.Tag); err != nil {
+			return nil, err
+		}
+	}
+
+	query := entity.PostQuery{Sort: sort, Window: window, Filter: filter, Limit: limit + 1}
+	if params.Cursor != "" {
+		cursor, err := decodePostCursor(params.Cursor)
+		if err != nil || cursor.Sort != sort || cursor.Window != window {
+			return nil, ErrInvalidCursor
+		}
+		query.After = cursor
+	}
+
+	posts, err := s.postRepo.ListPosts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entity.PostPage{Posts: posts}
+	if len(posts) > limit {
+		page.Posts = posts[:limit]
+		cursor := newPostCursor(sort, posts[limit-1])
+		cursor.Window = window
+		page.NextCursor = encodePostCursor(cursor)
+	}
+	if page.Posts == nil {
+		page.Posts = []*entity.Post{}
+	}
+	return page, nil
 }
 
-func (s *PostService) UpdatePost(ctx context.Context, postID int, userID int, title, content string) error {
+// UpdatePost обновляет пост и сохраняет новую версию в истории правок;
+// метки заменяются, только если update.Tags не nil. update.Version должна совпадать
+// с текущей версией поста, иначе возвращается *PostVersionConflictError.
+func (s *PostService) UpdatePost(ctx context.Context, postID int, userID int, update entity.PostUpdate) error {
+	if update.Version <= 0 {
+		return ErrPostVersionRequired
+	}
+	if update.Tags != nil {
+		var err error
+		if update.Tags, err = normalizeTags(update.Tags); err != nil {
+			return err
+		}
+	}
+	update.Summary = strings.TrimSpace(update.Summary)
+	if utf8.RuneCountInString(update.Summary) > MaxEditSummaryLength {
+		return ErrEditSummaryTooLong
+	}
+
 	post, err := s.postRepo.GetPostByID(ctx, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPostNotFound
+	}
 	if err != nil {
 		return err
 	}
@@ -92,14 +387,189 @@ func (s *PostService) UpdatePost(ctx context.Context, postID int, userID int, ti
 		return err
 	}
 	if post.UserID != userID && user.Role != "admin" {
-		return errors.New("unauthorized: you can only update your own posts")
+		return ErrPostEditForbidden
 	}
-	return s.postRepo.UpdatePost(ctx, postID, title, content)
+	if err := checkPostWritable(postID, post.State, false); err != nil {
+		return err
+	}
+	if post.Version != update.Version {
+		return &PostVersionConflictError{Current: post}
+	}
+	if update.Format == "" {
+		update.Format = post.Format
+	}
+	if update.Format, update.ContentHTML, err = renderContent(update.Format, update.Content); err != nil {
+		return err
+	}
+
+	err = s.postRepo.UpdatePost(ctx, postID, userID, update)
+	if err == nil {
+		if !post.Unpublished() {
+			s.trackMentions(ctx, postID, post.UserID, update.Content)
+		}
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	// Пост изменили или удалили между проверкой и записью
+	current, getErr := s.postRepo.GetPostByID(ctx, postID)
+	if errors.Is(getErr, sql.ErrNoRows) {
+		return ErrPostNotFound
+	}
+	if getErr != nil {
+		return getErr
+	}
+	return &PostVersionConflictError{Current: current}
 }
 
-func NewPostUseCase(postRepo PostRepository, userRepo UserRepository) PostUseCase {
+func NewPostUseCase(postRepo PostRepository, userRepo UserRepository, categoryRepo CategoryRepository) *PostService {
 	return &PostService{
-		postRepo: postRepo,
-		userRepo: userRepo,
+		postRepo:     postRepo,
+		userRepo:     userRepo,
+		categoryRepo: categoryRepo,
+		now:          time.Now,
+	}
+}
+
+// ListDrafts возвращает страницу черновиков и запланированных постов пользователя,
+// новые первыми
+func (s *PostService) ListDrafts(ctx context.Context, userID int, params entity.DraftListParams) (*entity.PostPage, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultPostPageSize
+	}
+	if limit > MaxPostPageSize {
+		limit = MaxPostPageSize
+	}
+	query := entity.DraftQuery{UserID: userID, Limit: limit + 1}
+	if params.Cursor != "" {
+		beforeID, err := strconv.Atoi(params.Cursor)
+		if err != nil || beforeID <= 0 {
+			return nil, ErrInvalidCursor
+		}
+		query.BeforeID = beforeID
+	}
+
+	posts, err := s.postRepo.ListDrafts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	page := &entity.PostPage{Posts: posts}
+	if len(posts) > limit {
+		page.Posts = posts[:limit]
+		page.NextCursor = strconv.Itoa(page.Posts[limit-1].ID)
+	}
+	if page.Posts == nil {
+		page.Posts = []*entity.Post{}
+	}
+	return page, nil
+}
+
+// SetPublication публикует черновик автора сразу, планирует его публикацию или
+// возвращает запланированный пост в черновики. Опубликованный пост не меняется.
+func (s *PostService) SetPublication(ctx context.Context, postID, userID int, publication entity.PostPublication) (*entity.Post, error) {
+	if err := s.checkPublication(publication); err != nil {
+		return nil, err
+	}
+	post, err := s.postRepo.GetPostByID(ctx, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID != userID {
+		if post.Unpublished() {
+			return nil, ErrPostNotFound
+		}
+		return nil, ErrPostEditForbidden
+	}
+	if !post.Unpublished() {
+		return nil, ErrPostAlreadyPublished
+	}
+
+	err = s.postRepo.SetPostPublication(ctx, postID, publication)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Пост успел опубликовать планировщик
+		return nil, ErrPostAlreadyPublished
+	}
+	if err != nil {
+		return nil, err
+	}
+	if publication.Status == entity.PostPublished {
+		s.trackMentions(ctx, postID, post.UserID, post.Content)
+	}
+	return s.GetPostByID(ctx, postID, userID)
+}
+
+// PublishDue публикует запланированные посты, время которых пришло, и возвращает
+// их число. Упоминания в них обрабатываются так же, как при создании поста.
+func (s *PostService) PublishDue(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		posts, err := s.postRepo.PublishDuePosts(ctx, s.now(), PublishBatchSize)
+		if err != nil {
+			return published, err
+		}
+		for _, post := range posts {
+			s.trackMentions(ctx, post.ID, post.UserID, post.Content)
+		}
+		published += len(posts)
+		if len(posts) < PublishBatchSize {
+			return published, nil
+		}
+	}
+}
+
+// Run публикует запланированные посты сразу и затем каждые interval, пока ctx не отменен
+func (s *PostService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		n, err := s.PublishDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to publish scheduled posts: %v", err)
+		}
+		if n > 0 {
+			log.Printf("[INFO] Published %d scheduled posts", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SetMentionTracker устанавливает обработчик упоминаний в тексте постов. Вызывается
+// до начала обработки запросов.
+func (s *PostService) SetMentionTracker(m MentionTracker) {
+	s.mentions = m
+}
+
+// SetAttachments устанавливает загрузчик вложений постов. Вызывается до начала
+// обработки запросов.
+func (s *PostService) SetAttachments(l AttachmentLoader) {
+	s.attachments = l
+}
+
+// loadAttachments заполняет Attachments поста, если загрузчик вложений задан
+func (s *PostService) loadAttachments(ctx context.Context, post *entity.Post) error {
+	if s.attachments == nil {
+		return nil
+	}
+	attachments, err := s.attachments.AttachmentsFor(ctx, entity.AttachedToPost, []int{post.ID})
+	if err != nil {
+		return err
+	}
+	post.Attachments = attachments[post.ID]
+	return nil
+}
+
+// trackMentions передает текст поста автора authorID обработчику упоминаний, если он задан
+func (s *PostService) trackMentions(ctx context.Context, postID, authorID int, content string) {
+	if s.mentions != nil {
+		s.mentions.TrackMentions(ctx, entity.MentionSource{Kind: entity.MentionInPost, ID: postID}, authorID, content)
 	}
 }
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+// newPostCursor запоминает закрепление, ключ сортировки и id поста, после которого
+// начнется следующая страница
+func newPostCursor(sort entity.PostSort, post *entity.Post) *entity.PostCursor {
+	cursor := &entity.PostCursor{Sort: sort, Pinned: post.Pinned, ID: post.ID}
+	switch sort {
+	case entity.PostSortMostCommented:
+		cursor.Count = post.CommentCount
+	case entity.PostSortRecentlyActive:
+		cursor.Time = post.LastActivityAt
+	case entity.PostSortHot, entity.PostSortTop, entity.PostSortRising, entity.PostSortMostViewed:
+		cursor.Rank = post.Rank
+	default:
+		cursor.Time = post.CreatedAt
+	}
+	return cursor
+}
+
+// Курсор непрозрачен для клиентов: это base64url от JSON, формат может меняться
+func encodePostCursor(cursor *entity.PostCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePostCursor(s string) (*entity.PostCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var cursor entity.PostCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+func encodeCommentCursor(cursor *entity.CommentCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCommentCursor(s string) (*entity.CommentCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var cursor entity.CommentCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.ParentID < 0 || cursor.ID < 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
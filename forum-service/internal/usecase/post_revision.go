@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// DiffContextLines - сколько неизмененных строк показывать вокруг правок в unified diff
+const DiffContextLines = 3
+
+var (
+	ErrRevisionNotFound  = errors.New("revision not found")
+	ErrInvalidDiffFormat = errors.New("invalid diff format: use unified or word")
+)
+
+type PostRevisionRepository interface {
+	ListPostRevisions(ctx context.Context, postID int) ([]entity.PostRevision, error)
+	GetPostRevision(ctx context.Context, postID, rev int) (*entity.PostRevision, error)
+}
+
+type PostRevisionUseCase interface {
+	ListRevisions(ctx context.Context, postID int) ([]entity.PostRevision, error)
+	GetRevision(ctx context.Context, postID, rev int) (*entity.PostRevision, error)
+	DiffRevisions(ctx context.Context, postID, from, to int, format entity.DiffFormat) (*entity.PostDiff, error)
+	RestoreRevision(ctx context.Context, postID, rev, userID, version int) (*entity.Post, error)
+}
+
+type PostRevisionService struct {
+	repo     PostRevisionRepository
+	postRepo PostRepository
+	userRepo UserRepository
+	mentions MentionTracker
+}
+
+func NewPostRevisionUseCase(repo PostRevisionRepository, postRepo PostRepository, userRepo UserRepository) *PostRevisionService {
+	return &PostRevisionService{
+		repo:     repo,
+		postRepo: postRepo,
+		userRepo: userRepo,
+	}
+}
+
+// SetMentionTracker устанавливает обработчик упоминаний: восстановление версии
+// меняет текст поста так же, как правка. Вызывается до начала обработки запросов.
+func (s *PostRevisionService) SetMentionTracker(m MentionTracker) {
+	s.mentions = m
+}
+
+// ListRevisions возвращает историю правок поста, новые версии первыми
+func (s *PostRevisionService) ListRevisions(ctx context.Context, postID int) ([]entity.PostRevision, error) {
+	if _, err := s.getPost(ctx, postID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPostRevisions(ctx, postID)
+}
+
+// GetRevision возвращает версию rev поста вместе с текстом
+func (s *PostRevisionService) GetRevision(ctx context.Context, postID, rev int) (*entity.PostRevision, error) {
+	if _, err := s.getPost(ctx, postID); err != nil {
+		return nil, err
+	}
+	return s.getRevision(ctx, postID, rev)
+}
+
+// DiffRevisions сравнивает версии from и to поста построчно или пословно
+func (s *PostRevisionService) DiffRevisions(ctx context.Context, postID, from, to int, format entity.DiffFormat) (*entity.PostDiff, error) {
+	if format == "" {
+		format = entity.DiffFormatUnified
+	}
+	if format != entity.DiffFormatUnified && format != entity.DiffFormatWord {
+		return nil, ErrInvalidDiffFormat
+	}
+	if _, err := s.getPost(ctx, postID); err != nil {
+		return nil, err
+	}
+
+	a, err := s.getRevision(ctx, postID, from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.getRevision(ctx, postID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &entity.PostDiff{PostID: postID, From: from, To: to, Format: format}
+	if format == entity.DiffFormatWord {
+		diff.Title = wordDiff(a.Title, b.Title)
+		diff.Content = wordDiff(a.Content, b.Content)
+		return diff, nil
+	}
+
+	diff.Unified, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(revisionText(a)),
+		B:        difflib.SplitLines(revisionText(b)),
+		FromFile: fmt.Sprintf("rev %d", from),
+		ToFile:   fmt.Sprintf("rev %d", to),
+		Context:  DiffContextLines,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// RestoreRevision возвращает посту заголовок и текст версии rev. Восстановление
+// записывается новой версией, история не переписывается. Метки не меняются.
+// version, как и при правке, должна совпадать с текущей версией поста, иначе
+// возвращается *PostVersionConflictError.
+func (s *PostRevisionService) RestoreRevision(ctx context.Context, postID, rev, userID, version int) (*entity.Post, error) {
+	if version <= 0 {
+		return nil, ErrPostVersionRequired
+	}
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID != userID && user.Role != entity.RoleAdmin {
+		return nil, ErrPostEditForbidden
+	}
+	if err := checkPostWritable(postID, post.State, false); err != nil {
+		return nil, err
+	}
+	if post.Version != version {
+		return nil, &PostVersionConflictError{Current: post}
+	}
+
+	revision, err := s.getRevision(ctx, postID, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	// Версии не хранят формат: текст восстанавливается в текущем формате поста
+	format, html, err := renderContent(post.Format, revision.Content)
+	if err != nil {
+		return nil, err
+	}
+	err = s.postRepo.UpdatePost(ctx, postID, userID, entity.PostUpdate{
+		Title:       revision.Title,
+		Content:     revision.Content,
+		Summary:     fmt.Sprintf("Restored revision %d", rev),
+		Format:      format,
+		ContentHTML: html,
+		Version:     version,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Пост изменили или удалили между проверкой и записью
+		current, err := s.getPost(ctx, postID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &PostVersionConflictError{Current: current}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.mentions != nil {
+		source := entity.MentionSource{Kind: entity.MentionInPost, ID: postID}
+		s.mentions.TrackMentions(ctx, source, post.UserID, revision.Content)
+	}
+	return s.postRepo.GetPostByID(ctx, postID)
+}
+
+func (s *PostRevisionService) getPost(ctx context.Context, postID int) (*entity.Post, error) {
+	post, err := s.postRepo.GetPostByID(ctx, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPostNotFound
+	}
+	return post, err
+}
+
+func (s *PostRevisionService) getRevision(ctx context.Context, postID, rev int) (*entity.PostRevision, error) {
+	revision, err := s.repo.GetPostRevision(ctx, postID, rev)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRevisionNotFound
+	}
+	return revision, err
+}
+
+// revisionText - текст версии для построчного сравнения: заголовок первой строкой
+func revisionText(r *entity.PostRevision) string {
+	return r.Title + "\n\n" + r.Content
+}
+
+// wordDiff сравнивает тексты по словам. Пробелы остаются отдельными токенами,
+// поэтому склейка фрагментов дает исходные тексты.
+func wordDiff(a, b string) []entity.DiffSegment {
+	at, bt := splitWords(a), splitWords(b)
+	// Без autojunk: иначе частые токены (пробелы) выпадают из сравнения
+	m := difflib.NewMatcherWithJunk(at, bt, false, nil)
+
+	var segments []entity.DiffSegment
+	add := func(op entity.DiffOp, tokens []string) {
+		if len(tokens) == 0 {
+			return
+		}
+		text := strings.Join(tokens, "")
+		if n := len(segments); n > 0 && segments[n-1].Op == op {
+			segments[n-1].Text += text
+			return
+		}
+		segments = append(segments, entity.DiffSegment{Op: op, Text: text})
+	}
+
+	for _, op := range m.GetOpCodes() {
+		switch op.Tag {
+		case 'e':
+			add(entity.DiffEqual, at[op.I1:op.I2])
+		case 'd':
+			add(entity.DiffDelete, at[op.I1:op.I2])
+		case 'i':
+			add(entity.DiffInsert, bt[op.J1:op.J2])
+		case 'r':
+			add(entity.DiffDelete, at[op.I1:op.I2])
+			add(entity.DiffInsert, bt[op.J1:op.J2])
+		}
+	}
+	return segments
+}
+
+// splitWords разбивает текст на чередующиеся слова и пробельные промежутки
+func splitWords(s string) []string {
+	var tokens []string
+	start := 0
+	prevSpace := false
+	for i, r := range s {
+		space := unicode.IsSpace(r)
+		if i > 0 && space != prevSpace {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		prevSpace = space
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
@@ -0,0 +1,237 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPostRevisionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostRevisionRepository) ListPostRevisions(ctx context.Context, postID int) ([]entity.PostRevision, error) {
+	args := m.Called(ctx, postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.PostRevision), args.Error(1)
+}
+
+func (m *MockPostRevisionRepository) GetPostRevision(ctx context.Context, postID, rev int) (*entity.PostRevision, error) {
+	args := m.Called(ctx, postID, rev)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PostRevision), args.Error(1)
+}
+
+func newRevisionUseCase() (usecase.PostRevisionUseCase, *MockPostRevisionRepository, *MockPostRepository, *MockUserRepository) {
+	repo := new(MockPostRevisionRepository)
+	postRepo := new(MockPostRepository)
+	userRepo := new(MockUserRepository)
+	return usecase.NewPostRevisionUseCase(repo, postRepo, userRepo), repo, postRepo, userRepo
+}
+
+func TestPostRevisionUseCase_ListRevisions(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		uc, repo, postRepo, _ := newRevisionUseCase()
+		revisions := []entity.PostRevision{{Rev: 2}, {Rev: 1}}
+		postRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1}, nil)
+		repo.On("ListPostRevisions", mock.Anything, 1).Return(revisions, nil)
+
+		got, err := uc.ListRevisions(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, revisions, got)
+	})
+
+	t.Run("PostNotFound", func(t *testing.T) {
+		uc, _, postRepo, _ := newRevisionUseCase()
+		postRepo.On("GetPostByID", mock.Anything, 1).Return(nil, sql.ErrNoRows)
+
+		_, err := uc.ListRevisions(context.Background(), 1)
+		assert.ErrorIs(t, err, usecase.ErrPostNotFound)
+	})
+}
+
+func TestPostRevisionUseCase_DiffRevisions(t *testing.T) {
+	v1 := &entity.PostRevision{PostID: 1, Rev: 1, Title: "Hello", Content: "one two three\nfour\n"}
+	v2 := &entity.PostRevision{PostID: 1, Rev: 2, Title: "Hello world", Content: "one 2 three\nfour\n"}
+
+	setup := func() usecase.PostRevisionUseCase {
+		uc, repo, postRepo, _ := newRevisionUseCase()
+		postRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1}, nil)
+		repo.On("GetPostRevision", mock.Anything, 1, 1).Return(v1, nil)
+		repo.On("GetPostRevision", mock.Anything, 1, 2).Return(v2, nil)
+		repo.On("GetPostRevision", mock.Anything, 1, 3).Return(nil, sql.ErrNoRows)
+		return uc
+	}
+
+	t.Run("Unified", func(t *testing.T) {
+		diff, err := setup().DiffRevisions(context.Background(), 1, 1, 2, "")
+		require.NoError(t, err)
+		assert.Equal(t, entity.DiffFormatUnified, diff.Format)
+		assert.Contains(t, diff.Unified, "--- rev 1\n+++ rev 2\n")
+		assert.Contains(t, diff.Unified, "-Hello\n+Hello world\n")
+		assert.Contains(t, diff.Unified, "-one two three\n+one 2 three\n")
+		assert.NotContains(t, diff.Unified, "-four")
+	})
+
+	t.Run("Word", func(t *testing.T) {
+		diff, err := setup().DiffRevisions(context.Background(), 1, 1, 2, entity.DiffFormatWord)
+		require.NoError(t, err)
+		assert.Equal(t, []entity.DiffSegment{
+			{Op: entity.DiffEqual, Text: "Hello"},
+			{Op: entity.DiffInsert, Text: " world"},
+		}, diff.Title)
+		assert.Equal(t, []entity.DiffSegment{
+			{Op: entity.DiffEqual, Text: "one "},
+			{Op: entity.DiffDelete, Text: "two"},
+			{Op: entity.DiffInsert, Text: "2"},
+			{Op: entity.DiffEqual, Text: " three\nfour\n"},
+		}, diff.Content)
+
+		// Склейка фрагментов без вставок дает старый текст, без удалений - новый
+		var before, after strings.Builder
+		for _, seg := range diff.Content {
+			if seg.Op != entity.DiffInsert {
+				before.WriteString(seg.Text)
+			}
+			if seg.Op != entity.DiffDelete {
+				after.WriteString(seg.Text)
+			}
+		}
+		assert.Equal(t, v1.Content, before.String())
+		assert.Equal(t, v2.Content, after.String())
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		_, err := setup().DiffRevisions(context.Background(), 1, 1, 2, "side-by-side")
+		assert.ErrorIs(t, err, usecase.ErrInvalidDiffFormat)
+	})
+
+	t.Run("RevisionNotFound", func(t *testing.T) {
+		_, err := setup().DiffRevisions(context.Background(), 1, 1, 3, "")
+		assert.ErrorIs(t, err, usecase.ErrRevisionNotFound)
+	})
+
+	t.Run("PostInTrash", func(t *testing.T) {
+		uc, repo, postRepo, _ := newRevisionUseCase()
+		postRepo.On("GetPostByID", mock.Anything, 1).Return(nil, sql.ErrNoRows)
+
+		_, err := uc.DiffRevisions(context.Background(), 1, 1, 2, "")
+		assert.ErrorIs(t, err, usecase.ErrPostNotFound)
+		repo.AssertNotCalled(t, "GetPostRevision", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestPostRevisionUseCase_GetRevision(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		uc, repo, postRepo, _ := newRevisionUseCase()
+		revision := &entity.PostRevision{PostID: 1, Rev: 1, Title: "Hello", Content: "text"}
+		postRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1}, nil)
+		repo.On("GetPostRevision", mock.Anything, 1, 1).Return(revision, nil)
+
+		got, err := uc.GetRevision(context.Background(), 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, revision, got)
+	})
+
+	t.Run("PostInTrash", func(t *testing.T) {
+		uc, repo, postRepo, _ := newRevisionUseCase()
+		postRepo.On("GetPostByID", mock.Anything, 1).Return(nil, sql.ErrNoRows)
+
+		_, err := uc.GetRevision(context.Background(), 1, 1)
+		assert.ErrorIs(t, err, usecase.ErrPostNotFound)
+		repo.AssertNotCalled(t, "GetPostRevision", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestPostRevisionUseCase_RestoreRevision(t *testing.T) {
+	t.Run("SuccessOwner", func(t *testing.T) {
+		uc, repo, postRepo, userRepo := newRevisionUseCase()
+		post := &entity.Post{ID: 1, UserID: 2, Title: "New", Content: "new", Version: 4}
+		postRepo.On("GetPostByID", mock.Anything, 1).Return(post, nil)
+		userRepo.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2, Role: entity.RoleUser}, nil)
+		repo.On("GetPostRevision", mock.Anything, 1, 1).
+			Return(&entity.PostRevision{PostID: 1, Rev: 1, Title: "Old", Content: "old"}, nil)
+		postRepo.On("UpdatePost", mock.Anything, 1, 2, entity.PostUpdate{
+			Title:       "Old",
+			Content:     "old",
+			Summary:     "Restored revision 1",
+			Format:      entity.FormatPlain,
+			ContentHTML: "<p>old</p>",
+			Version:     4,
+		}).Return(nil)
+
+		got, err := uc.RestoreRevision(context.Background(), 1, 1, 2, 4)
+		require.NoError(t, err)
+		assert.Equal(t, post, got)
+		postRepo.AssertExpectations(t)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		uc, _, postRepo, userRepo := newRevisionUseCase()
+		postRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 2}, nil)
+		userRepo.On("GetUserByID", mock.Anything, 3).Return(&entity.User{ID: 3, Role: entity.RoleModerator}, nil)
+
+		_, err := uc.RestoreRevision(context.Background(), 1, 1, 3, 1)
+		assert.ErrorIs(t, err, usecase.ErrPostEditForbidden)
+		postRepo.AssertNotCalled(t, "UpdatePost", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("RevisionNotFound", func(t *testing.T) {
+		uc, repo, postRepo, userRepo := newRevisionUseCase()
+		postRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 2, Version: 1}, nil)
+		userRepo.On("GetUserByID", mock.Anything, 1).Return(adminUser, nil)
+		repo.On("GetPostRevision", mock.Anything, 1, 9).Return(nil, sql.ErrNoRows)
+
+		_, err := uc.RestoreRevision(context.Background(), 1, 9, 1, 1)
+		assert.ErrorIs(t, err, usecase.ErrRevisionNotFound)
+	})
+
+	t.Run("VersionRequired", func(t *testing.T) {
+		uc, _, postRepo, _ := newRevisionUseCase()
+
+		_, err := uc.RestoreRevision(context.Background(), 1, 1, 2, 0)
+		assert.ErrorIs(t, err, usecase.ErrPostVersionRequired)
+		postRepo.AssertNotCalled(t, "GetPostByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("StaleVersion", func(t *testing.T) {
+		uc, repo, postRepo, userRepo := newRevisionUseCase()
+		post := &entity.Post{ID: 1, UserID: 2, Version: 5}
+		postRepo.On("GetPostByID", mock.Anything, 1).Return(post, nil)
+		userRepo.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2, Role: entity.RoleUser}, nil)
+
+		_, err := uc.RestoreRevision(context.Background(), 1, 1, 2, 4)
+		var conflict *usecase.PostVersionConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, post, conflict.Current)
+		repo.AssertNotCalled(t, "GetPostRevision", mock.Anything, mock.Anything, mock.Anything)
+		postRepo.AssertNotCalled(t, "UpdatePost", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ConcurrentEdit", func(t *testing.T) {
+		uc, repo, postRepo, userRepo := newRevisionUseCase()
+		current := &entity.Post{ID: 1, UserID: 2, Version: 5}
+		postRepo.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 2, Version: 4}, nil).Once()
+		postRepo.On("GetPostByID", mock.Anything, 1).Return(current, nil).Once()
+		userRepo.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2, Role: entity.RoleUser}, nil)
+		repo.On("GetPostRevision", mock.Anything, 1, 1).
+			Return(&entity.PostRevision{PostID: 1, Rev: 1, Title: "Old", Content: "old"}, nil)
+		postRepo.On("UpdatePost", mock.Anything, 1, 2, mock.Anything).Return(sql.ErrNoRows)
+
+		_, err := uc.RestoreRevision(context.Background(), 1, 1, 2, 4)
+		var conflict *usecase.PostVersionConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, current, conflict.Current)
+	})
+}
@@ -3,8 +3,12 @@ package usecase_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
 	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
@@ -20,8 +24,8 @@ type MockPostRepository struct {
 	mock.Mock
 }
 
-func (m *MockPostRepository) UpdatePost(ctx context.Context, postID int, title, content string) error {
-	args := m.Called(ctx, postID, title, content)
+func (m *MockPostRepository) UpdatePost(ctx context.Context, postID, editorID int, update entity.PostUpdate) error {
+	args := m.Called(ctx, postID, editorID, update)
 	return args.Error(0)
 }
 func (m *MockPostRepository) CreatePost(ctx context.Context, post *entity.Post) error {
@@ -37,18 +41,38 @@ func (m *MockPostRepository) GetPostByID(ctx context.Context, id int) (*entity.P
 	return args.Get(0).(*entity.Post), args.Error(1)
 }
 
-func (m *MockPostRepository) GetAllPosts(ctx context.Context) ([]*entity.Post, error) {
-	args := m.Called(ctx)
+func (m *MockPostRepository) ListPosts(ctx context.Context, q entity.PostQuery) ([]*entity.Post, error) {
+	args := m.Called(ctx, q)
 	if args.Get(0) == nil {
-		return []*entity.Post{}, args.Error(1) // Возвращаем пустой срез в случае ошибки
+		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*entity.Post), args.Error(1)
 }
-func (m *MockPostRepository) DeletePost(ctx context.Context, id int) error {
-	args := m.Called(ctx, id)
+func (m *MockPostRepository) DeletePost(ctx context.Context, id, deletedBy int) error {
+	args := m.Called(ctx, id, deletedBy)
 	return args.Error(0)
 }
 
+func (m *MockPostRepository) ListDrafts(ctx context.Context, q entity.DraftQuery) ([]*entity.Post, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) SetPostPublication(ctx context.Context, postID int, publication entity.PostPublication) error {
+	return m.Called(ctx, postID, publication).Error(0)
+}
+
+func (m *MockPostRepository) PublishDuePosts(ctx context.Context, now time.Time, limit int) ([]*entity.Post, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Post), args.Error(1)
+}
+
 func (m *MockUserRepository) GetUserByID(ctx context.Context, id int) (*entity.User, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -57,8 +81,8 @@ func (m *MockUserRepository) GetUserByID(ctx context.Context, id int) (*entity.U
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
-func (m *MockPostUseCase) UpdatePost(ctx context.Context, postID, userID int, title, content string) error {
-	args := m.Called(ctx, postID, userID, title, content)
+func (m *MockPostUseCase) UpdatePost(ctx context.Context, postID, userID int, update entity.PostUpdate) error {
+	args := m.Called(ctx, postID, userID, update)
 	return args.Error(0)
 }
 
@@ -66,28 +90,117 @@ func TestPostUseCase_CreatePost(t *testing.T) {
 	tests := []struct {
 		name        string
 		post        *entity.Post
-		mockSetup   func(*MockPostRepository, *MockUserRepository)
+		mockSetup   func(*MockPostRepository, *MockUserRepository, *MockCategoryRepository)
 		expectedErr string
 	}{
 		{
 			name: "Success",
 			post: &entity.Post{
-				Title:   "Test Post",
-				Content: "Test Content",
-				UserID:  1,
+				Title:      "Test Post",
+				Content:    "Test Content",
+				UserID:     1,
+				CategoryID: 1,
 			},
-			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {
+				cr.On("GetCategoryByID", mock.Anything, 1).Return(&entity.Category{ID: 1}, nil)
+				pr.On("CreatePost", mock.Anything, mock.AnythingOfType("*entity.Post")).Return(nil)
+			},
+		},
+		{
+			name: "ReadOnlyCategory_Moderator",
+			post: &entity.Post{Title: "Rules", Content: "Be nice", UserID: 2, CategoryID: 3},
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {
+				cr.On("GetCategoryByID", mock.Anything, 3).Return(&entity.Category{ID: 3, ReadOnly: true}, nil)
+				ur.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2, Role: entity.RoleModerator}, nil)
 				pr.On("CreatePost", mock.Anything, mock.AnythingOfType("*entity.Post")).Return(nil)
 			},
 		},
+		{
+			name: "ReadOnlyCategory_User",
+			post: &entity.Post{Title: "Rules", Content: "Be nice", UserID: 1, CategoryID: 3},
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {
+				cr.On("GetCategoryByID", mock.Anything, 3).Return(&entity.Category{ID: 3, ReadOnly: true}, nil)
+				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: entity.RoleUser}, nil)
+			},
+			expectedErr: usecase.ErrCategoryReadOnly.Error(),
+		},
+		{
+			name: "UnknownCategory",
+			post: &entity.Post{Title: "Title", Content: "Content", UserID: 1, CategoryID: 42},
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {
+				cr.On("GetCategoryByID", mock.Anything, 42).Return(nil, fmt.Errorf("failed to get category: %w", sql.ErrNoRows))
+			},
+			expectedErr: usecase.ErrCategoryNotFound.Error(),
+		},
+		{
+			name: "NormalizesTags",
+			post: &entity.Post{
+				Title: "Title", Content: "Content", UserID: 1, CategoryID: 1,
+				Tags: []string{" Go ", "go", "Graceful Shutdown"},
+			},
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {
+				cr.On("GetCategoryByID", mock.Anything, 1).Return(&entity.Category{ID: 1}, nil)
+				pr.On("CreatePost", mock.Anything, mock.MatchedBy(func(p *entity.Post) bool {
+					return assert.ObjectsAreEqual([]string{"go", "graceful-shutdown"}, p.Tags)
+				})).Return(nil)
+			},
+		},
+		{
+			name: "RendersMarkdown",
+			post: &entity.Post{
+				Title: "Title", Content: "# Hi\n<script>alert(1)</script>", Format: entity.FormatMarkdown,
+				UserID: 1, CategoryID: 1,
+			},
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {
+				cr.On("GetCategoryByID", mock.Anything, 1).Return(&entity.Category{ID: 1}, nil)
+				pr.On("CreatePost", mock.Anything, mock.MatchedBy(func(p *entity.Post) bool {
+					return p.Format == entity.FormatMarkdown && !strings.Contains(p.ContentHTML, "<script>") &&
+						strings.Contains(p.ContentHTML, "<h1>Hi</h1>")
+				})).Return(nil)
+			},
+		},
+		{
+			name: "ValidationError_InvalidFormat",
+			post: &entity.Post{
+				Title: "Title", Content: "Content", Format: "html", UserID: 1, CategoryID: 1,
+			},
+			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {},
+			expectedErr: usecase.ErrInvalidFormat.Error(),
+		},
+		{
+			name: "ValidationError_TooManyTags",
+			post: &entity.Post{
+				Title: "Title", Content: "Content", UserID: 1, CategoryID: 1,
+				Tags: []string{"a", "b", "c", "d", "e", "f"},
+			},
+			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {},
+			expectedErr: usecase.ErrTooManyTags.Error(),
+		},
+		{
+			name: "ValidationError_InvalidTag",
+			post: &entity.Post{
+				Title: "Title", Content: "Content", UserID: 1, CategoryID: 1,
+				Tags: []string{"<script>"},
+			},
+			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {},
+			expectedErr: usecase.ErrInvalidTag.Error(),
+		},
+		{
+			name:        "ValidationError_EmptyCategory",
+			post:        &entity.Post{Title: "Title", Content: "Content", UserID: 1},
+			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {},
+			expectedErr: usecase.ErrCategoryRequired.Error(),
+		},
 		{
 			name: "RepositoryError",
 			post: &entity.Post{
-				Title:   "Test Post",
-				Content: "Test Content",
-				UserID:  1,
+				Title:      "Test Post",
+				Content:    "Test Content",
+				UserID:     1,
+				CategoryID: 1,
 			},
-			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {
+				cr.On("GetCategoryByID", mock.Anything, 1).Return(&entity.Category{ID: 1}, nil)
 				pr.On("CreatePost", mock.Anything, mock.AnythingOfType("*entity.Post")).Return(errors.New("database error"))
 			},
 			expectedErr: "database error",
@@ -95,7 +208,7 @@ func TestPostUseCase_CreatePost(t *testing.T) {
 		{
 			name:        "ValidationError_NilPost",
 			post:        nil,
-			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository) {},
+			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {},
 			expectedErr: "post cannot be nil",
 		},
 		{
@@ -105,7 +218,7 @@ func TestPostUseCase_CreatePost(t *testing.T) {
 				Content: "Content",
 				UserID:  1,
 			},
-			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository) {},
+			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {},
 			expectedErr: "post title cannot be empty",
 		},
 		{
@@ -115,7 +228,7 @@ func TestPostUseCase_CreatePost(t *testing.T) {
 				Content: "",
 				UserID:  1,
 			},
-			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository) {},
+			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {},
 			expectedErr: "post content cannot be empty",
 		},
 		{
@@ -125,7 +238,7 @@ func TestPostUseCase_CreatePost(t *testing.T) {
 				Content: "Content",
 				UserID:  0,
 			},
-			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository) {},
+			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository, cr *MockCategoryRepository) {},
 			expectedErr: "user ID cannot be empty",
 		},
 	}
@@ -134,10 +247,11 @@ func TestPostUseCase_CreatePost(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockPostRepo := new(MockPostRepository)
 			mockUserRepo := new(MockUserRepository)
-			uc := usecase.NewPostUseCase(mockPostRepo, mockUserRepo)
+			mockCategoryRepo := new(MockCategoryRepository)
+			uc := usecase.NewPostUseCase(mockPostRepo, mockUserRepo, mockCategoryRepo)
 
 			// Setup mocks
-			tt.mockSetup(mockPostRepo, mockUserRepo)
+			tt.mockSetup(mockPostRepo, mockUserRepo, mockCategoryRepo)
 
 			err := uc.CreatePost(context.Background(), tt.post)
 
@@ -150,6 +264,7 @@ func TestPostUseCase_CreatePost(t *testing.T) {
 
 			mockPostRepo.AssertExpectations(t)
 			mockUserRepo.AssertExpectations(t)
+			mockCategoryRepo.AssertExpectations(t)
 		})
 	}
 }
@@ -170,7 +285,7 @@ func TestPostUseCase_DeletePost(t *testing.T) {
 				pr.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 2}, nil)
 				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: "user"}, nil)
 			},
-			expectedErr: "unauthorized: you can only delete your own posts",
+			expectedErr: usecase.ErrPostDeleteForbidden.Error(),
 		},
 		{
 			name:   "SuccessAdmin",
@@ -179,7 +294,7 @@ func TestPostUseCase_DeletePost(t *testing.T) {
 			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
 				pr.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 2}, nil)
 				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: "admin"}, nil)
-				pr.On("DeletePost", mock.Anything, 1).Return(nil)
+				pr.On("DeletePost", mock.Anything, 1, 1).Return(nil)
 			},
 		},
 		{
@@ -189,7 +304,27 @@ func TestPostUseCase_DeletePost(t *testing.T) {
 			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
 				pr.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 1}, nil)
 				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: "user"}, nil)
-				pr.On("DeletePost", mock.Anything, 1).Return(nil)
+				pr.On("DeletePost", mock.Anything, 1, 1).Return(nil)
+			},
+		},
+		{
+			name:   "ArchivedPost",
+			postID: 1,
+			userID: 1,
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
+				pr.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 1, State: entity.PostArchived}, nil)
+				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: "user"}, nil)
+			},
+			expectedErr: usecase.ErrPostArchived.Error(),
+		},
+		{
+			name:   "ArchivedPostAdmin",
+			postID: 1,
+			userID: 1,
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
+				pr.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 2, State: entity.PostArchived}, nil)
+				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: "admin"}, nil)
+				pr.On("DeletePost", mock.Anything, 1, 1).Return(nil)
 			},
 		},
 		{
@@ -197,9 +332,20 @@ func TestPostUseCase_DeletePost(t *testing.T) {
 			postID: 1,
 			userID: 1,
 			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
-				pr.On("GetPostByID", mock.Anything, 1).Return(nil, errors.New("post not found"))
+				pr.On("GetPostByID", mock.Anything, 1).Return(nil, fmt.Errorf("failed to get post: %w", sql.ErrNoRows))
 			},
-			expectedErr: "post not found",
+			expectedErr: usecase.ErrPostNotFound.Error(),
+		},
+		{
+			name:   "AlreadyInTrash",
+			postID: 1,
+			userID: 1,
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
+				pr.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 1}, nil)
+				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: "user"}, nil)
+				pr.On("DeletePost", mock.Anything, 1, 1).Return(fmt.Errorf("failed to delete post: %w", sql.ErrNoRows))
+			},
+			expectedErr: usecase.ErrPostNotFound.Error(),
 		},
 		{
 			name:   "UserNotFound",
@@ -217,7 +363,7 @@ func TestPostUseCase_DeletePost(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockPostRepo := new(MockPostRepository)
 			mockUserRepo := new(MockUserRepository)
-			uc := usecase.NewPostUseCase(mockPostRepo, mockUserRepo)
+			uc := usecase.NewPostUseCase(mockPostRepo, mockUserRepo, new(MockCategoryRepository))
 
 			// Setup mocks
 			tt.mockSetup(mockPostRepo, mockUserRepo)
@@ -236,6 +382,132 @@ func TestPostUseCase_DeletePost(t *testing.T) {
 		})
 	}
 }
+
+func TestPostUseCase_UpdatePost(t *testing.T) {
+	tests := []struct {
+		name        string
+		update      entity.PostUpdate
+		mockSetup   func(*MockPostRepository, *MockUserRepository)
+		expectedErr error
+	}{
+		{
+			name:   "SuccessOwner",
+			update: entity.PostUpdate{Title: "T", Content: "C", Tags: []string{"Go"}, Summary: " typo ", Version: 3},
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
+				pr.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 1, Version: 3}, nil)
+				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: "user"}, nil)
+				pr.On("UpdatePost", mock.Anything, 1, 1, entity.PostUpdate{
+					Title: "T", Content: "C", Tags: []string{"go"}, Summary: "typo", Version: 3,
+					Format: entity.FormatPlain, ContentHTML: "<p>C</p>",
+				}).Return(nil)
+			},
+		},
+		{
+			name:   "KeepsMarkdownFormat",
+			update: entity.PostUpdate{Title: "T", Content: "**C**", Version: 2},
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
+				pr.On("GetPostByID", mock.Anything, 1).
+					Return(&entity.Post{ID: 1, UserID: 1, Version: 2, Format: entity.FormatMarkdown}, nil)
+				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: "user"}, nil)
+				pr.On("UpdatePost", mock.Anything, 1, 1, entity.PostUpdate{
+					Title: "T", Content: "**C**", Version: 2,
+					Format: entity.FormatMarkdown, ContentHTML: "<p><strong>C</strong></p>\n",
+				}).Return(nil)
+			},
+		},
+		{
+			name:   "InvalidFormat",
+			update: entity.PostUpdate{Title: "T", Content: "C", Format: "html", Version: 1},
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
+				pr.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 1, Version: 1}, nil)
+				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: "user"}, nil)
+			},
+			expectedErr: usecase.ErrInvalidFormat,
+		},
+		{
+			name:   "Unauthorized",
+			update: entity.PostUpdate{Title: "T", Content: "C", Version: 1},
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
+				pr.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 2, Version: 1}, nil)
+				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: "user"}, nil)
+			},
+			expectedErr: usecase.ErrPostEditForbidden,
+		},
+		{
+			name:   "Archived",
+			update: entity.PostUpdate{Title: "T", Content: "C", Version: 1},
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
+				pr.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 1, Version: 1, State: entity.PostArchived}, nil)
+				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: "user"}, nil)
+			},
+			expectedErr: usecase.ErrPostArchived,
+		},
+		{
+			name:        "SummaryTooLong",
+			update:      entity.PostUpdate{Title: "T", Content: "C", Summary: strings.Repeat("я", usecase.MaxEditSummaryLength+1), Version: 1},
+			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository) {},
+			expectedErr: usecase.ErrEditSummaryTooLong,
+		},
+		{
+			name:        "VersionRequired",
+			update:      entity.PostUpdate{Title: "T", Content: "C"},
+			mockSetup:   func(pr *MockPostRepository, ur *MockUserRepository) {},
+			expectedErr: usecase.ErrPostVersionRequired,
+		},
+		{
+			name:   "NotFound",
+			update: entity.PostUpdate{Title: "T", Content: "C", Version: 1},
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
+				pr.On("GetPostByID", mock.Anything, 1).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: usecase.ErrPostNotFound,
+		},
+		{
+			name:   "StaleVersion",
+			update: entity.PostUpdate{Title: "T", Content: "C", Version: 2},
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
+				pr.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 1, Version: 3}, nil)
+				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: "user"}, nil)
+			},
+			expectedErr: usecase.ErrPostVersionConflict,
+		},
+		{
+			name:   "ConcurrentUpdate",
+			update: entity.PostUpdate{Title: "T", Content: "C", Version: 3},
+			mockSetup: func(pr *MockPostRepository, ur *MockUserRepository) {
+				pr.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 1, Version: 3}, nil).Once()
+				ur.On("GetUserByID", mock.Anything, 1).Return(&entity.User{ID: 1, Role: "user"}, nil)
+				pr.On("UpdatePost", mock.Anything, 1, 1, mock.Anything).Return(fmt.Errorf("stale: %w", sql.ErrNoRows))
+				pr.On("GetPostByID", mock.Anything, 1).Return(&entity.Post{ID: 1, UserID: 1, Version: 4}, nil).Once()
+			},
+			expectedErr: usecase.ErrPostVersionConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPostRepo := new(MockPostRepository)
+			mockUserRepo := new(MockUserRepository)
+			uc := usecase.NewPostUseCase(mockPostRepo, mockUserRepo, new(MockCategoryRepository))
+			tt.mockSetup(mockPostRepo, mockUserRepo)
+
+			err := uc.UpdatePost(context.Background(), 1, 1, tt.update)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				var conflict *usecase.PostVersionConflictError
+				if errors.As(err, &conflict) {
+					assert.Greater(t, conflict.Current.Version, tt.update.Version)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+			mockPostRepo.AssertExpectations(t)
+			mockUserRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestPostUseCase_GetPostByID(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -266,12 +538,12 @@ func TestPostUseCase_GetPostByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockPostRepo := new(MockPostRepository)
 			mockUserRepo := new(MockUserRepository)
-			uc := usecase.NewPostUseCase(mockPostRepo, mockUserRepo)
+			uc := usecase.NewPostUseCase(mockPostRepo, mockUserRepo, new(MockCategoryRepository))
 
 			// Setup mocks
 			tt.mockSetup(mockPostRepo)
 
-			post, err := uc.GetPostByID(context.Background(), tt.postID)
+			post, err := uc.GetPostByID(context.Background(), tt.postID, 0)
 
 			if tt.expectedErr != "" {
 				require.Error(t, err)
@@ -285,32 +557,138 @@ func TestPostUseCase_GetPostByID(t *testing.T) {
 		})
 	}
 }
-func TestPostUseCase_GetAllPosts(t *testing.T) {
+func TestPostUseCase_ListPosts(t *testing.T) {
+	created := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	posts := []*entity.Post{
+		{ID: 3, Title: "Test Post 3", CreatedAt: created.Add(2 * time.Hour)},
+		{ID: 2, Title: "Test Post 2", CreatedAt: created.Add(time.Hour)},
+		{ID: 1, Title: "Test Post 1", CreatedAt: created},
+	}
+
 	tests := []struct {
 		name          string
+		params        entity.PostListParams
 		mockSetup     func(*MockPostRepository)
-		expectedErr   string
+		expectedErr   error
 		expectedPosts []*entity.Post
+		hasNext       bool
 	}{
 		{
-			name: "Success",
+			name:   "DefaultsToNewest",
+			params: entity.PostListParams{},
+			mockSetup: func(pr *MockPostRepository) {
+				pr.On("ListPosts", mock.Anything, entity.PostQuery{
+					Sort:  entity.PostSortNewest,
+					Limit: usecase.DefaultPostPageSize + 1,
+				}).Return(posts, nil)
+			},
+			expectedPosts: posts,
+		},
+		{
+			name:   "MoreThanLimit",
+			params: entity.PostListParams{Sort: entity.PostSortNewest, Limit: 2},
 			mockSetup: func(pr *MockPostRepository) {
-				pr.On("GetAllPosts", mock.Anything).Return([]*entity.Post{
-					{ID: 1, Title: "Test Post 1", Content: "Test Content 1", UserID: 1},
-					{ID: 2, Title: "Test Post 2", Content: "Test Content 2", UserID: 2},
-				}, nil)
+				pr.On("ListPosts", mock.Anything, entity.PostQuery{
+					Sort:  entity.PostSortNewest,
+					Limit: 3,
+				}).Return(posts, nil)
 			},
-			expectedPosts: []*entity.Post{
-				{ID: 1, Title: "Test Post 1", Content: "Test Content 1", UserID: 1},
-				{ID: 2, Title: "Test Post 2", Content: "Test Content 2", UserID: 2},
+			expectedPosts: posts[:2],
+			hasNext:       true,
+		},
+		{
+			name:   "LimitIsCapped",
+			params: entity.PostListParams{Sort: entity.PostSortOldest, Limit: 1000},
+			mockSetup: func(pr *MockPostRepository) {
+				pr.On("ListPosts", mock.Anything, entity.PostQuery{
+					Sort:  entity.PostSortOldest,
+					Limit: usecase.MaxPostPageSize + 1,
+				}).Return(nil, nil)
 			},
+			expectedPosts: []*entity.Post{},
 		},
 		{
-			name: "RepositoryError",
+			name:   "NormalizesTagFilter",
+			params: entity.PostListParams{Filter: entity.PostFilter{Tag: "Go"}},
 			mockSetup: func(pr *MockPostRepository) {
-				pr.On("GetAllPosts", mock.Anything).Return(nil, errors.New("database error"))
+				pr.On("ListPosts", mock.Anything, entity.PostQuery{
+					Sort:   entity.PostSortNewest,
+					Filter: entity.PostFilter{Tag: "go"},
+					Limit:  usecase.DefaultPostPageSize + 1,
+				}).Return(posts, nil)
 			},
-			expectedErr: "database error",
+			expectedPosts: posts,
+		},
+		{
+			name:        "InvalidTagFilter",
+			params:      entity.PostListParams{Filter: entity.PostFilter{Tag: "go?"}},
+			mockSetup:   func(pr *MockPostRepository) {},
+			expectedErr: usecase.ErrInvalidTag,
+		},
+		{
+			name:        "InvalidSort",
+			params:      entity.PostListParams{Sort: "random"},
+			mockSetup:   func(pr *MockPostRepository) {},
+			expectedErr: usecase.ErrInvalidSort,
+		},
+		{
+			name:   "TopDefaultsToDay",
+			params: entity.PostListParams{Sort: entity.PostSortTop},
+			mockSetup: func(pr *MockPostRepository) {
+				pr.On("ListPosts", mock.Anything, entity.PostQuery{
+					Sort:   entity.PostSortTop,
+					Window: entity.TopWindowDay,
+					Limit:  usecase.DefaultPostPageSize + 1,
+				}).Return(posts, nil)
+			},
+			expectedPosts: posts,
+		},
+		{
+			name:   "MostViewedDefaultsToDay",
+			params: entity.PostListParams{Sort: entity.PostSortMostViewed},
+			mockSetup: func(pr *MockPostRepository) {
+				pr.On("ListPosts", mock.Anything, entity.PostQuery{
+					Sort:   entity.PostSortMostViewed,
+					Window: entity.TopWindowDay,
+					Limit:  usecase.DefaultPostPageSize + 1,
+				}).Return(posts, nil)
+			},
+			expectedPosts: posts,
+		},
+		{
+			name:        "InvalidWindow",
+			params:      entity.PostListParams{Sort: entity.PostSortTop, Window: "month"},
+			mockSetup:   func(pr *MockPostRepository) {},
+			expectedErr: usecase.ErrInvalidWindow,
+		},
+		{
+			name:        "WindowWithoutTop",
+			params:      entity.PostListParams{Sort: entity.PostSortHot, Window: entity.TopWindowWeek},
+			mockSetup:   func(pr *MockPostRepository) {},
+			expectedErr: usecase.ErrInvalidWindow,
+		},
+		{
+			name:        "InvalidCursor",
+			params:      entity.PostListParams{Cursor: "not-a-cursor"},
+			mockSetup:   func(pr *MockPostRepository) {},
+			expectedErr: usecase.ErrInvalidCursor,
+		},
+		{
+			name: "InvalidDateRange",
+			params: entity.PostListParams{Filter: entity.PostFilter{
+				From: created,
+				To:   created.Add(-time.Hour),
+			}},
+			mockSetup:   func(pr *MockPostRepository) {},
+			expectedErr: usecase.ErrInvalidDateRange,
+		},
+		{
+			name:   "RepositoryError",
+			params: entity.PostListParams{},
+			mockSetup: func(pr *MockPostRepository) {
+				pr.On("ListPosts", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+			},
+			expectedErr: errors.New("database error"),
 		},
 	}
 
@@ -318,22 +696,318 @@ func TestPostUseCase_GetAllPosts(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockPostRepo := new(MockPostRepository)
 			mockUserRepo := new(MockUserRepository)
-			uc := usecase.NewPostUseCase(mockPostRepo, mockUserRepo)
+			uc := usecase.NewPostUseCase(mockPostRepo, mockUserRepo, new(MockCategoryRepository))
 
 			// Setup mocks
 			tt.mockSetup(mockPostRepo)
 
-			posts, err := uc.GetAllPosts(context.Background())
+			page, err := uc.ListPosts(context.Background(), tt.params)
 
-			if tt.expectedErr != "" {
+			if tt.expectedErr != nil {
 				require.Error(t, err)
-				assert.Contains(t, err.Error(), tt.expectedErr)
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
 			} else {
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedPosts, posts)
+				assert.Equal(t, tt.expectedPosts, page.Posts)
+				assert.Equal(t, tt.hasNext, page.NextCursor != "")
 			}
 
 			mockPostRepo.AssertExpectations(t)
 		})
 	}
 }
+
+func TestPostUseCase_ListPosts_NextCursor(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	uc := usecase.NewPostUseCase(mockPostRepo, new(MockUserRepository), new(MockCategoryRepository))
+
+	first := []*entity.Post{
+		{ID: 7, CommentCount: 5},
+		{ID: 4, CommentCount: 3},
+		{ID: 9, CommentCount: 3},
+	}
+	mockPostRepo.On("ListPosts", mock.Anything, entity.PostQuery{
+		Sort:  entity.PostSortMostCommented,
+		Limit: 3,
+	}).Return(first, nil).Once()
+	mockPostRepo.On("ListPosts", mock.Anything, entity.PostQuery{
+		Sort:  entity.PostSortMostCommented,
+		After: &entity.PostCursor{Sort: entity.PostSortMostCommented, Count: 3, ID: 4},
+		Limit: 3,
+	}).Return(first[2:], nil).Once()
+
+	page, err := uc.ListPosts(context.Background(), entity.PostListParams{Sort: entity.PostSortMostCommented, Limit: 2})
+	require.NoError(t, err)
+	require.NotEmpty(t, page.NextCursor)
+
+	page, err = uc.ListPosts(context.Background(), entity.PostListParams{
+		Sort:   entity.PostSortMostCommented,
+		Cursor: page.NextCursor,
+		Limit:  2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, first[2:], page.Posts)
+	assert.Empty(t, page.NextCursor)
+
+	// Курсор нельзя использовать с другой сортировкой
+	_, err = uc.ListPosts(context.Background(), entity.PostListParams{Sort: entity.PostSortNewest, Cursor: "eyJzIjoibW9zdF9jb21tZW50ZWQiLCJpZCI6NH0"})
+	assert.ErrorIs(t, err, usecase.ErrInvalidCursor)
+
+	mockPostRepo.AssertExpectations(t)
+}
+
+func TestPostUseCase_ListPosts_PinnedCursor(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	uc := usecase.NewPostUseCase(mockPostRepo, new(MockUserRepository), new(MockCategoryRepository))
+
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	posts := []*entity.Post{{ID: 3, Pinned: true, CreatedAt: created}, {ID: 8, CreatedAt: created.Add(time.Hour)}}
+	mockPostRepo.On("ListPosts", mock.Anything, entity.PostQuery{Sort: entity.PostSortNewest, Limit: 2}).
+		Return(posts, nil).Once()
+	// Курсор после закрепленного поста продолжает выдачу среди закрепленных
+	mockPostRepo.On("ListPosts", mock.Anything, entity.PostQuery{
+		Sort:  entity.PostSortNewest,
+		After: &entity.PostCursor{Sort: entity.PostSortNewest, Time: created, Pinned: true, ID: 3},
+		Limit: 2,
+	}).Return(posts[1:], nil).Once()
+
+	params := entity.PostListParams{Sort: entity.PostSortNewest, Limit: 1}
+	page, err := uc.ListPosts(context.Background(), params)
+	require.NoError(t, err)
+	require.NotEmpty(t, page.NextCursor)
+
+	params.Cursor = page.NextCursor
+	page, err = uc.ListPosts(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, posts[1:], page.Posts)
+
+	mockPostRepo.AssertExpectations(t)
+}
+
+func TestPostUseCase_ListPosts_RankedCursor(t *testing.T) {
+	mockPostRepo := new(MockPostRepository)
+	uc := usecase.NewPostUseCase(mockPostRepo, new(MockUserRepository), new(MockCategoryRepository))
+
+	ranked := []*entity.Post{{ID: 5, Rank: 12.5}, {ID: 2, Rank: 3.25}}
+	mockPostRepo.On("ListPosts", mock.Anything, entity.PostQuery{
+		Sort:   entity.PostSortTop,
+		Window: entity.TopWindowWeek,
+		Limit:  2,
+	}).Return(ranked, nil).Once()
+	mockPostRepo.On("ListPosts", mock.Anything, entity.PostQuery{
+		Sort:   entity.PostSortTop,
+		Window: entity.TopWindowWeek,
+		After:  &entity.PostCursor{Sort: entity.PostSortTop, Window: entity.TopWindowWeek, Rank: 12.5, ID: 5},
+		Limit:  2,
+	}).Return(ranked[1:], nil).Once()
+
+	params := entity.PostListParams{Sort: entity.PostSortTop, Window: entity.TopWindowWeek, Limit: 1}
+	page, err := uc.ListPosts(context.Background(), params)
+	require.NoError(t, err)
+	require.NotEmpty(t, page.NextCursor)
+
+	params.Cursor = page.NextCursor
+	page, err = uc.ListPosts(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, ranked[1:], page.Posts)
+
+	// Курсор привязан к периоду
+	params.Window = entity.TopWindowAll
+	_, err = uc.ListPosts(context.Background(), params)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCursor)
+
+	mockPostRepo.AssertExpectations(t)
+}
+
+func TestPostUseCase_CreateDraft(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	t.Run("DraftSkipsMentions", func(t *testing.T) {
+		postRepo := new(MockPostRepository)
+		categoryRepo := new(MockCategoryRepository)
+		tracker := new(MockMentionTracker)
+		uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), categoryRepo)
+		uc.SetMentionTracker(tracker)
+		categoryRepo.On("GetCategoryByID", mock.Anything, 1).Return(&entity.Category{ID: 1}, nil)
+		postRepo.On("CreatePost", mock.Anything, mock.MatchedBy(func(p *entity.Post) bool {
+			return p.Status == entity.PostScheduled && p.PublishAt.Equal(future)
+		})).Return(nil)
+
+		post := &entity.Post{Title: "Soon", Content: "hi @alice", UserID: 2, CategoryID: 1, Status: entity.PostScheduled, PublishAt: &future}
+		require.NoError(t, uc.CreatePost(context.Background(), post))
+		tracker.AssertNotCalled(t, "TrackMentions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("DefaultsToPublished", func(t *testing.T) {
+		postRepo := new(MockPostRepository)
+		categoryRepo := new(MockCategoryRepository)
+		uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), categoryRepo)
+		categoryRepo.On("GetCategoryByID", mock.Anything, 1).Return(&entity.Category{ID: 1}, nil)
+		postRepo.On("CreatePost", mock.Anything, mock.MatchedBy(func(p *entity.Post) bool {
+			return p.Status == entity.PostPublished && p.PublishAt == nil
+		})).Return(nil)
+
+		require.NoError(t, uc.CreatePost(context.Background(), &entity.Post{Title: "Now", Content: "Text", UserID: 2, CategoryID: 1}))
+	})
+
+	for _, tt := range []struct {
+		name      string
+		status    entity.PublishStatus
+		publishAt *time.Time
+		err       error
+	}{
+		{"UnknownStatus", "hidden", nil, usecase.ErrInvalidPublishStatus},
+		{"ScheduledWithoutTime", entity.PostScheduled, nil, usecase.ErrInvalidPublishAt},
+		{"ScheduledInPast", entity.PostScheduled, &past, usecase.ErrInvalidPublishAt},
+		{"DraftWithTime", entity.PostDraft, &future, usecase.ErrInvalidPublishAt},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			postRepo := new(MockPostRepository)
+			uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), new(MockCategoryRepository))
+
+			post := &entity.Post{Title: "T", Content: "C", UserID: 2, CategoryID: 1, Status: tt.status, PublishAt: tt.publishAt}
+			assert.ErrorIs(t, uc.CreatePost(context.Background(), post), tt.err)
+			postRepo.AssertNotCalled(t, "CreatePost", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPostUseCase_GetPostByID_Draft(t *testing.T) {
+	postRepo := new(MockPostRepository)
+	uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), new(MockCategoryRepository))
+	postRepo.On("GetPostByID", mock.Anything, 3).Return(&entity.Post{ID: 3, UserID: 2, Status: entity.PostDraft}, nil)
+
+	post, err := uc.GetPostByID(context.Background(), 3, 2)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PostDraft, post.Status)
+
+	_, err = uc.GetPostByID(context.Background(), 3, 5)
+	assert.ErrorIs(t, err, usecase.ErrPostNotFound)
+	_, err = uc.GetPostByID(context.Background(), 3, 0)
+	assert.ErrorIs(t, err, usecase.ErrPostNotFound)
+}
+
+func TestPostUseCase_ListDrafts(t *testing.T) {
+	t.Run("PageWithCursor", func(t *testing.T) {
+		postRepo := new(MockPostRepository)
+		uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), new(MockCategoryRepository))
+		postRepo.On("ListDrafts", mock.Anything, entity.DraftQuery{UserID: 2, BeforeID: 9, Limit: 3}).
+			Return([]*entity.Post{{ID: 8}, {ID: 5}, {ID: 4}}, nil)
+
+		page, err := uc.ListDrafts(context.Background(), 2, entity.DraftListParams{Cursor: "9", Limit: 2})
+		require.NoError(t, err)
+		assert.Len(t, page.Posts, 2)
+		assert.Equal(t, "5", page.NextCursor)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		postRepo := new(MockPostRepository)
+		uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), new(MockCategoryRepository))
+		postRepo.On("ListDrafts", mock.Anything, entity.DraftQuery{UserID: 2, Limit: usecase.DefaultPostPageSize + 1}).Return(nil, nil)
+
+		page, err := uc.ListDrafts(context.Background(), 2, entity.DraftListParams{})
+		require.NoError(t, err)
+		assert.NotNil(t, page.Posts)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		uc := usecase.NewPostUseCase(new(MockPostRepository), new(MockUserRepository), new(MockCategoryRepository))
+
+		_, err := uc.ListDrafts(context.Background(), 2, entity.DraftListParams{Cursor: "x"})
+		assert.ErrorIs(t, err, usecase.ErrInvalidCursor)
+	})
+}
+
+func TestPostUseCase_SetPublication(t *testing.T) {
+	draft := &entity.Post{ID: 3, UserID: 2, Content: "hi @alice", Status: entity.PostDraft}
+	publish := entity.PostPublication{Status: entity.PostPublished}
+
+	t.Run("PublishTracksMentions", func(t *testing.T) {
+		postRepo := new(MockPostRepository)
+		tracker := new(MockMentionTracker)
+		uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), new(MockCategoryRepository))
+		uc.SetMentionTracker(tracker)
+		postRepo.On("GetPostByID", mock.Anything, 3).Return(draft, nil).Once()
+		postRepo.On("SetPostPublication", mock.Anything, 3, publish).Return(nil)
+		postRepo.On("GetPostByID", mock.Anything, 3).Return(&entity.Post{ID: 3, UserID: 2, Status: entity.PostPublished}, nil).Once()
+		tracker.On("TrackMentions", mock.Anything, entity.MentionSource{Kind: entity.MentionInPost, ID: 3}, 2, "hi @alice").Return()
+
+		post, err := uc.SetPublication(context.Background(), 3, 2, publish)
+		require.NoError(t, err)
+		assert.Equal(t, entity.PostPublished, post.Status)
+		tracker.AssertExpectations(t)
+	})
+
+	t.Run("ScheduleSkipsMentions", func(t *testing.T) {
+		postRepo := new(MockPostRepository)
+		tracker := new(MockMentionTracker)
+		uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), new(MockCategoryRepository))
+		uc.SetMentionTracker(tracker)
+		at := time.Now().Add(time.Hour)
+		schedule := entity.PostPublication{Status: entity.PostScheduled, PublishAt: &at}
+		postRepo.On("GetPostByID", mock.Anything, 3).Return(draft, nil)
+		postRepo.On("SetPostPublication", mock.Anything, 3, schedule).Return(nil)
+
+		_, err := uc.SetPublication(context.Background(), 3, 2, schedule)
+		require.NoError(t, err)
+		tracker.AssertNotCalled(t, "TrackMentions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	for _, tt := range []struct {
+		name   string
+		post   *entity.Post
+		userID int
+		repo   error
+		err    error
+	}{
+		{"OtherUsersDraft", draft, 5, nil, usecase.ErrPostNotFound},
+		{"OtherUsersPost", &entity.Post{ID: 3, UserID: 2, Status: entity.PostPublished}, 5, nil, usecase.ErrPostEditForbidden},
+		{"AlreadyPublished", &entity.Post{ID: 3, UserID: 2, Status: entity.PostPublished}, 2, nil, usecase.ErrPostAlreadyPublished},
+		{"PublishedMeanwhile", draft, 2, fmt.Errorf("post 3: %w", sql.ErrNoRows), usecase.ErrPostAlreadyPublished},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			postRepo := new(MockPostRepository)
+			uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), new(MockCategoryRepository))
+			postRepo.On("GetPostByID", mock.Anything, 3).Return(tt.post, nil)
+			postRepo.On("SetPostPublication", mock.Anything, 3, publish).Return(tt.repo)
+
+			_, err := uc.SetPublication(context.Background(), 3, tt.userID, publish)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	t.Run("InvalidPublishAt", func(t *testing.T) {
+		postRepo := new(MockPostRepository)
+		uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), new(MockCategoryRepository))
+
+		_, err := uc.SetPublication(context.Background(), 3, 2, entity.PostPublication{Status: entity.PostScheduled})
+		assert.ErrorIs(t, err, usecase.ErrInvalidPublishAt)
+		postRepo.AssertNotCalled(t, "GetPostByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPostUseCase_PublishDue(t *testing.T) {
+	postRepo := new(MockPostRepository)
+	tracker := new(MockMentionTracker)
+	uc := usecase.NewPostUseCase(postRepo, new(MockUserRepository), new(MockCategoryRepository))
+	uc.SetMentionTracker(tracker)
+
+	// Полная пачка означает, что могут остаться еще посты
+	batch := make([]*entity.Post, usecase.PublishBatchSize)
+	for i := range batch {
+		batch[i] = &entity.Post{ID: i + 1, UserID: 2, Content: "text"}
+	}
+	postRepo.On("PublishDuePosts", mock.Anything, mock.AnythingOfType("time.Time"), usecase.PublishBatchSize).Return(batch, nil).Once()
+	postRepo.On("PublishDuePosts", mock.Anything, mock.AnythingOfType("time.Time"), usecase.PublishBatchSize).
+		Return([]*entity.Post{{ID: 500, UserID: 3, Content: "hi @alice"}}, nil).Once()
+	tracker.On("TrackMentions", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+
+	n, err := uc.PublishDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, usecase.PublishBatchSize+1, n)
+	tracker.AssertCalled(t, "TrackMentions", mock.Anything, entity.MentionSource{Kind: entity.MentionInPost, ID: 500}, 3, "hi @alice")
+	tracker.AssertNumberOfCalls(t, "TrackMentions", usecase.PublishBatchSize+1)
+	postRepo.AssertExpectations(t)
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+const (
+	// CommentWeight - вес комментария относительно голоса в hot и rising
+	CommentWeight = 0.5
+	// HotDecay - за столько секунд новизна поста дает столько же, сколько
+	// десятикратный рост активности (как в ранжировании Reddit)
+	HotDecay = 45000
+	// RisingWindow - за какой период учитывается свежая активность для rising
+	RisingWindow = 6 * time.Hour
+	// RisingGravity - как быстро rising теряет вес с возрастом поста
+	RisingGravity = 1.5
+)
+
+// hotEpoch - точка отсчета для hot: только сдвигает все значения, порядок не меняет
+var hotEpoch = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type PostRankingRepository interface {
+	ListPostActivity(ctx context.Context, recentSince time.Time) ([]entity.PostActivity, error)
+	SavePostRankings(ctx context.Context, rankings []entity.PostRanking) error
+}
+
+type PostRankingUseCase interface {
+	RefreshRankings(ctx context.Context) error
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type PostRankingService struct {
+	repo PostRankingRepository
+}
+
+func NewPostRankingUseCase(repo PostRankingRepository) PostRankingUseCase {
+	return &PostRankingService{repo: repo}
+}
+
+// RefreshRankings пересчитывает hot, top и rising всех постов по голосам и комментариям
+func (s *PostRankingService) RefreshRankings(ctx context.Context) error {
+	now := time.Now()
+	activity, err := s.repo.ListPostActivity(ctx, now.Add(-RisingWindow))
+	if err != nil {
+		return err
+	}
+
+	rankings := make([]entity.PostRanking, 0, len(activity))
+	for _, a := range activity {
+		rankings = append(rankings, entity.PostRanking{
+			PostID: a.PostID,
+			Hot:    HotRank(a),
+			Top:    float64(a.Score),
+			Rising: RisingRank(a, now),
+		})
+	}
+	return s.repo.SavePostRankings(ctx, rankings)
+}
+
+// Run пересчитывает рейтинги сразу и затем каждые interval, пока ctx не отменен.
+// Ошибка пересчета только логируется: выдача продолжит использовать прежние значения.
+func (s *PostRankingService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.RefreshRankings(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to refresh post rankings: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// HotRank - логарифм активности плюс новизна: чтобы обойти пост, опубликованный
+// на HotDecay секунд раньше, нужно вдесятеро больше активности
+func HotRank(a entity.PostActivity) float64 {
+	activity := float64(a.Score) + CommentWeight*float64(a.Comments)
+	order := math.Log10(math.Max(math.Abs(activity), 1))
+	sign := 0.0
+	if activity > 0 {
+		sign = 1
+	} else if activity < 0 {
+		sign = -1
+	}
+	return sign*order + a.CreatedAt.Sub(hotEpoch).Seconds()/HotDecay
+}
+
+// RisingRank - свежая активность, деленная на возраст поста в степени RisingGravity.
+// Посты без положительной свежей активности получают 0.
+func RisingRank(a entity.PostActivity, now time.Time) float64 {
+	activity := float64(a.RecentScore) + CommentWeight*float64(a.RecentComments)
+	if activity <= 0 {
+		return 0
+	}
+	ageHours := math.Max(now.Sub(a.CreatedAt).Hours(), 0)
+	return activity / math.Pow(ageHours+2, RisingGravity)
+}
@@ -0,0 +1,94 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPostRankingRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostRankingRepository) ListPostActivity(ctx context.Context, recentSince time.Time) ([]entity.PostActivity, error) {
+	args := m.Called(ctx, recentSince)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.PostActivity), args.Error(1)
+}
+
+func (m *MockPostRankingRepository) SavePostRankings(ctx context.Context, rankings []entity.PostRanking) error {
+	args := m.Called(ctx, rankings)
+	return args.Error(0)
+}
+
+func TestHotRank(t *testing.T) {
+	created := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	base := usecase.HotRank(entity.PostActivity{CreatedAt: created})
+
+	// Десятикратная активность стоит HotDecay секунд новизны
+	popular := usecase.HotRank(entity.PostActivity{CreatedAt: created, Score: 10})
+	newer := usecase.HotRank(entity.PostActivity{CreatedAt: created.Add(usecase.HotDecay * time.Second)})
+	assert.InDelta(t, newer, popular, 1e-9)
+	assert.Greater(t, popular, base)
+
+	// Комментарии добавляют активность, отрицательный счет ее отнимает
+	discussed := usecase.HotRank(entity.PostActivity{CreatedAt: created, Comments: 20})
+	assert.InDelta(t, popular, discussed, 1e-9)
+	disliked := usecase.HotRank(entity.PostActivity{CreatedAt: created, Score: -10})
+	assert.Less(t, disliked, base)
+}
+
+func TestRisingRank(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Zero(t, usecase.RisingRank(entity.PostActivity{CreatedAt: now, Score: 50}, now))
+	assert.Zero(t, usecase.RisingRank(entity.PostActivity{CreatedAt: now, RecentScore: -3}, now))
+
+	fresh := usecase.RisingRank(entity.PostActivity{CreatedAt: now.Add(-time.Hour), RecentScore: 4}, now)
+	old := usecase.RisingRank(entity.PostActivity{CreatedAt: now.Add(-48 * time.Hour), RecentScore: 4}, now)
+	assert.Greater(t, fresh, old)
+	assert.Greater(t, old, 0.0)
+}
+
+func TestPostRankingUseCase_RefreshRankings(t *testing.T) {
+	created := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockPostRankingRepository)
+		uc := usecase.NewPostRankingUseCase(repo)
+		activity := []entity.PostActivity{
+			{PostID: 1, CreatedAt: created, Score: 7, Comments: 2},
+			{PostID: 2, CreatedAt: created, Score: -1},
+		}
+		recentSince := mock.MatchedBy(func(since time.Time) bool {
+			return time.Since(since) >= usecase.RisingWindow && time.Since(since) < usecase.RisingWindow+time.Minute
+		})
+		repo.On("ListPostActivity", mock.Anything, recentSince).Return(activity, nil)
+		repo.On("SavePostRankings", mock.Anything, mock.MatchedBy(func(rankings []entity.PostRanking) bool {
+			return len(rankings) == 2 &&
+				rankings[0].PostID == 1 && rankings[0].Top == 7 && rankings[0].Hot == usecase.HotRank(activity[0]) &&
+				rankings[1].PostID == 2 && rankings[1].Top == -1 && rankings[1].Rising == 0
+		})).Return(nil)
+
+		require.NoError(t, uc.RefreshRankings(context.Background()))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("RepositoryError", func(t *testing.T) {
+		repo := new(MockPostRankingRepository)
+		uc := usecase.NewPostRankingUseCase(repo)
+		repo.On("ListPostActivity", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+		assert.Error(t, uc.RefreshRankings(context.Background()))
+		repo.AssertNotCalled(t, "SavePostRankings")
+	})
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+// Ограничения поискового запроса
+const (
+	DefaultSearchPageSize = 20
+	MaxSearchPageSize     = 50
+	MaxSearchQueryLength  = 256
+)
+
+var (
+	ErrEmptySearchQuery   = errors.New("search query cannot be empty")
+	ErrSearchQueryTooLong = errors.New("search query is too long")
+	ErrInvalidSearchType  = errors.New("invalid type: use all, post or comment")
+)
+
+// IsSearchInputError сообщает, вызвана ли ошибка некорректными параметрами поиска
+func IsSearchInputError(err error) bool {
+	for _, target := range []error{
+		ErrEmptySearchQuery,
+		ErrSearchQueryTooLong,
+		ErrInvalidSearchType,
+		ErrInvalidDateRange,
+		ErrInvalidCursor,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+type SearchRepository interface {
+	Search(ctx context.Context, q entity.SearchQuery) ([]*entity.SearchResult, error)
+}
+
+type SearchUseCase interface {
+	Search(ctx context.Context, params entity.SearchParams) (*entity.SearchPage, error)
+}
+
+type SearchService struct {
+	repo SearchRepository
+}
+
+func NewSearchUseCase(repo SearchRepository) SearchUseCase {
+	return &SearchService{repo: repo}
+}
+
+// Search ищет по постам и комментариям. Результаты упорядочены по релевантности,
+// поэтому курсор хранит смещение, а не ключ последней записи.
+func (s *SearchService) Search(ctx context.Context, params entity.SearchParams) (*entity.SearchPage, error) {
+	query := strings.TrimSpace(params.Query)
+	if query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+	if utf8.RuneCountInString(query) > MaxSearchQueryLength {
+		return nil, ErrSearchQueryTooLong
+	}
+
+	searchType := params.Type
+	if searchType == "" {
+		searchType = entity.SearchTypeAll
+	}
+	if !searchType.Valid() {
+		return nil, ErrInvalidSearchType
+	}
+
+	filter := params.Filter
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, ErrInvalidDateRange
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultSearchPageSize
+	}
+	if limit > MaxSearchPageSize {
+		limit = MaxSearchPageSize
+	}
+
+	offset := 0
+	if params.Cursor != "" {
+		var err error
+		if offset, err = decodeSearchCursor(params.Cursor); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	results, err := s.repo.Search(ctx, entity.SearchQuery{
+		Query:  query,
+		Type:   searchType,
+		Filter: filter,
+		Offset: offset,
+		Limit:  limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entity.SearchPage{Results: results}
+	if len(results) > limit {
+		page.Results = results[:limit]
+		page.NextCursor = encodeSearchCursor(offset + limit)
+	}
+	if page.Results == nil {
+		page.Results = []*entity.SearchResult{}
+	}
+	return page, nil
+}
+
+type searchCursor struct {
+	Offset int `json:"o"`
+}
+
+func encodeSearchCursor(offset int) string {
+	data, _ := json.Marshal(searchCursor{Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSearchCursor(s string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, err
+	}
+	var cursor searchCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return 0, err
+	}
+	if cursor.Offset <= 0 {
+		return 0, ErrInvalidCursor
+	}
+	return cursor.Offset, nil
+}
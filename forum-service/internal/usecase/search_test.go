@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepository) Search(ctx context.Context, q entity.SearchQuery) ([]*entity.SearchResult, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.SearchResult), args.Error(1)
+}
+
+func TestSearchUseCase_Search(t *testing.T) {
+	results := []*entity.SearchResult{
+		{Type: entity.SearchTypePost, PostID: 1, Rank: 0.9},
+		{Type: entity.SearchTypeComment, PostID: 1, CommentID: 5, Rank: 0.5},
+		{Type: entity.SearchTypePost, PostID: 2, Rank: 0.1},
+	}
+	day := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		params          entity.SearchParams
+		mockSetup       func(*MockSearchRepository)
+		expectedErr     error
+		expectedResults []*entity.SearchResult
+		hasNext         bool
+	}{
+		{
+			name:   "Defaults",
+			params: entity.SearchParams{Query: "  golang  "},
+			mockSetup: func(r *MockSearchRepository) {
+				r.On("Search", mock.Anything, entity.SearchQuery{
+					Query: "golang",
+					Type:  entity.SearchTypeAll,
+					Limit: usecase.DefaultSearchPageSize + 1,
+				}).Return(results, nil)
+			},
+			expectedResults: results,
+		},
+		{
+			name: "FiltersAndNextPage",
+			params: entity.SearchParams{
+				Query:  `"graceful shutdown" -java`,
+				Type:   entity.SearchTypeComment,
+				Filter: entity.PostFilter{Author: "alice", From: day},
+				Limit:  2,
+			},
+			mockSetup: func(r *MockSearchRepository) {
+				r.On("Search", mock.Anything, entity.SearchQuery{
+					Query:  `"graceful shutdown" -java`,
+					Type:   entity.SearchTypeComment,
+					Filter: entity.PostFilter{Author: "alice", From: day},
+					Limit:  3,
+				}).Return(results, nil)
+			},
+			expectedResults: results[:2],
+			hasNext:         true,
+		},
+		{
+			name:        "EmptyQuery",
+			params:      entity.SearchParams{Query: "   "},
+			mockSetup:   func(r *MockSearchRepository) {},
+			expectedErr: usecase.ErrEmptySearchQuery,
+		},
+		{
+			name:        "QueryTooLong",
+			params:      entity.SearchParams{Query: strings.Repeat("я", usecase.MaxSearchQueryLength+1)},
+			mockSetup:   func(r *MockSearchRepository) {},
+			expectedErr: usecase.ErrSearchQueryTooLong,
+		},
+		{
+			name:        "InvalidType",
+			params:      entity.SearchParams{Query: "golang", Type: "user"},
+			mockSetup:   func(r *MockSearchRepository) {},
+			expectedErr: usecase.ErrInvalidSearchType,
+		},
+		{
+			name:        "InvalidCursor",
+			params:      entity.SearchParams{Query: "golang", Cursor: "%%%"},
+			mockSetup:   func(r *MockSearchRepository) {},
+			expectedErr: usecase.ErrInvalidCursor,
+		},
+		{
+			name:   "RepositoryError",
+			params: entity.SearchParams{Query: "golang"},
+			mockSetup: func(r *MockSearchRepository) {
+				r.On("Search", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+			},
+			expectedErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockSearchRepository)
+			tt.mockSetup(repo)
+			uc := usecase.NewSearchUseCase(repo)
+
+			page, err := uc.Search(context.Background(), tt.params)
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedResults, page.Results)
+				assert.Equal(t, tt.hasNext, page.NextCursor != "")
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchUseCase_Search_NextCursor(t *testing.T) {
+	repo := new(MockSearchRepository)
+	uc := usecase.NewSearchUseCase(repo)
+
+	repo.On("Search", mock.Anything, entity.SearchQuery{
+		Query: "golang", Type: entity.SearchTypeAll, Limit: 3,
+	}).Return([]*entity.SearchResult{{PostID: 1}, {PostID: 2}, {PostID: 3}}, nil).Once()
+	repo.On("Search", mock.Anything, entity.SearchQuery{
+		Query: "golang", Type: entity.SearchTypeAll, Offset: 2, Limit: 3,
+	}).Return([]*entity.SearchResult{{PostID: 3}}, nil).Once()
+
+	page, err := uc.Search(context.Background(), entity.SearchParams{Query: "golang", Limit: 2})
+	require.NoError(t, err)
+	require.NotEmpty(t, page.NextCursor)
+
+	page, err = uc.Search(context.Background(), entity.SearchParams{Query: "golang", Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Len(t, page.Results, 1)
+	assert.Empty(t, page.NextCursor)
+	repo.AssertExpectations(t)
+}
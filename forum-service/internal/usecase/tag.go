@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+// Ограничения меток
+const (
+	MaxTagsPerPost         = 5
+	MaxTagLength           = 32
+	DefaultTagSuggestLimit = 10
+	MaxTagSuggestLimit     = 50
+)
+
+var (
+	ErrInvalidTag  = errors.New("invalid tag: use up to 32 letters, digits and - _ + # . characters")
+	ErrTooManyTags = errors.New("too many tags: a post can have at most 5")
+	ErrTagNotFound = errors.New("tag not found")
+)
+
+type TagRepository interface {
+	ListTags(ctx context.Context, prefix string, limit int) ([]*entity.Tag, error)
+	GetTagByName(ctx context.Context, name string) (*entity.Tag, error)
+	RenameTag(ctx context.Context, from, to string) (*entity.Tag, error)
+}
+
+type TagUseCase interface {
+	SuggestTags(ctx context.Context, prefix string, limit int) ([]*entity.Tag, error)
+	GetTag(ctx context.Context, name string) (*entity.Tag, error)
+	RenameTag(ctx context.Context, userID int, from, to string) (*entity.Tag, error)
+}
+
+type TagService struct {
+	repo     TagRepository
+	userRepo UserRepository
+}
+
+func NewTagUseCase(repo TagRepository, userRepo UserRepository) TagUseCase {
+	return &TagService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// SuggestTags подсказывает метки по префиксу, самые популярные первыми.
+// Пустой префикс возвращает самые популярные метки вообще.
+func (s *TagService) SuggestTags(ctx context.Context, prefix string, limit int) ([]*entity.Tag, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix != "" {
+		var err error
+		if prefix, err = normalizeTag(prefix); err != nil {
+			return nil, err
+		}
+	}
+
+	if limit <= 0 {
+		limit = DefaultTagSuggestLimit
+	}
+	if limit > MaxTagSuggestLimit {
+		limit = MaxTagSuggestLimit
+	}
+
+	tags, err := s.repo.ListTags(ctx, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []*entity.Tag{}
+	}
+	return tags, nil
+}
+
+func (s *TagService) GetTag(ctx context.Context, name string) (*entity.Tag, error) {
+	name, err := normalizeTag(name)
+	if err != nil {
+		return nil, ErrTagNotFound
+	}
+	tag, err := s.repo.GetTagByName(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTagNotFound
+	}
+	return tag, err
+}
+
+// RenameTag переименовывает метку. Если метка с новым именем уже есть,
+// старая сливается с ней.
+func (s *TagService) RenameTag(ctx context.Context, userID int, from, to string) (*entity.Tag, error) {
+	if err := requireAdmin(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	from, err := normalizeTag(from)
+	if err != nil {
+		return nil, ErrTagNotFound
+	}
+	if to, err = normalizeTag(to); err != nil {
+		return nil, err
+	}
+
+	var tag *entity.Tag
+	if from == to {
+		tag, err = s.repo.GetTagByName(ctx, from)
+	} else {
+		tag, err = s.repo.RenameTag(ctx, from, to)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTagNotFound
+	}
+	return tag, err
+}
+
+// normalizeTag приводит метку к каноническому виду: нижний регистр,
+// пробелы внутри заменены дефисом
+func normalizeTag(name string) (string, error) {
+	name = strings.ToLower(strings.Join(strings.Fields(name), "-"))
+	if name == "" || utf8.RuneCountInString(name) > MaxTagLength {
+		return "", ErrInvalidTag
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_+#.", r) {
+			return "", ErrInvalidTag
+		}
+	}
+	return name, nil
+}
+
+// normalizeTags нормализует метки поста и убирает повторы, сохраняя порядок
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		name, err := normalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	if len(normalized) > MaxTagsPerPost {
+		return nil, ErrTooManyTags
+	}
+	return normalized, nil
+}
@@ -0,0 +1,197 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) ListTags(ctx context.Context, prefix string, limit int) ([]*entity.Tag, error) {
+	args := m.Called(ctx, prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetTagByName(ctx context.Context, name string) (*entity.Tag, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) RenameTag(ctx context.Context, from, to string) (*entity.Tag, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Tag), args.Error(1)
+}
+
+func TestTagUseCase_SuggestTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		prefix      string
+		limit       int
+		mockSetup   func(*MockTagRepository)
+		expectedErr error
+	}{
+		{
+			name:   "NormalizesPrefix",
+			prefix: " Go Con",
+			limit:  5,
+			mockSetup: func(r *MockTagRepository) {
+				r.On("ListTags", mock.Anything, "go-con", 5).Return([]*entity.Tag{{Name: "go-concurrency", PostCount: 3}}, nil)
+			},
+		},
+		{
+			name:   "DefaultLimit",
+			prefix: "",
+			mockSetup: func(r *MockTagRepository) {
+				r.On("ListTags", mock.Anything, "", usecase.DefaultTagSuggestLimit).Return(nil, nil)
+			},
+		},
+		{
+			name:   "ClampsLimit",
+			prefix: "go",
+			limit:  1000,
+			mockSetup: func(r *MockTagRepository) {
+				r.On("ListTags", mock.Anything, "go", usecase.MaxTagSuggestLimit).Return(nil, nil)
+			},
+		},
+		{
+			name:        "InvalidPrefix",
+			prefix:      "go%",
+			mockSetup:   func(r *MockTagRepository) {},
+			expectedErr: usecase.ErrInvalidTag,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTagRepository)
+			tt.mockSetup(repo)
+			uc := usecase.NewTagUseCase(repo, new(MockUserRepository))
+
+			tags, err := uc.SuggestTags(context.Background(), tt.prefix, tt.limit)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, tags)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTagUseCase_GetTag_NotFound(t *testing.T) {
+	repo := new(MockTagRepository)
+	repo.On("GetTagByName", mock.Anything, "rust").Return(nil, fmt.Errorf("failed to get tag: %w", sql.ErrNoRows))
+	uc := usecase.NewTagUseCase(repo, new(MockUserRepository))
+
+	_, err := uc.GetTag(context.Background(), "Rust")
+
+	assert.ErrorIs(t, err, usecase.ErrTagNotFound)
+	repo.AssertExpectations(t)
+}
+
+func TestTagUseCase_RenameTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		user        *entity.User
+		from, to    string
+		mockSetup   func(*MockTagRepository)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			user: adminUser,
+			from: "Golang",
+			to:   "Go Lang",
+			mockSetup: func(r *MockTagRepository) {
+				r.On("RenameTag", mock.Anything, "golang", "go-lang").Return(&entity.Tag{ID: 1, Name: "go-lang"}, nil)
+			},
+		},
+		{
+			name: "SameName",
+			user: adminUser,
+			from: "go",
+			to:   "GO",
+			mockSetup: func(r *MockTagRepository) {
+				r.On("GetTagByName", mock.Anything, "go").Return(&entity.Tag{ID: 1, Name: "go"}, nil)
+			},
+		},
+		{
+			name: "NotFound",
+			user: adminUser,
+			from: "golang",
+			to:   "go",
+			mockSetup: func(r *MockTagRepository) {
+				r.On("RenameTag", mock.Anything, "golang", "go").Return(nil, fmt.Errorf("failed to get tag: %w", sql.ErrNoRows))
+			},
+			expectedErr: usecase.ErrTagNotFound,
+		},
+		{
+			name:        "InvalidTarget",
+			user:        adminUser,
+			from:        "golang",
+			to:          "go!",
+			mockSetup:   func(r *MockTagRepository) {},
+			expectedErr: usecase.ErrInvalidTag,
+		},
+		{
+			name:        "NotAdmin",
+			user:        &entity.User{ID: 1, Role: entity.RoleUser},
+			from:        "golang",
+			to:          "go",
+			mockSetup:   func(r *MockTagRepository) {},
+			expectedErr: usecase.ErrAdminRequired,
+		},
+		{
+			name: "RepositoryError",
+			user: adminUser,
+			from: "golang",
+			to:   "go",
+			mockSetup: func(r *MockTagRepository) {
+				r.On("RenameTag", mock.Anything, "golang", "go").Return(nil, errors.New("database error"))
+			},
+			expectedErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTagRepository)
+			userRepo := new(MockUserRepository)
+			userRepo.On("GetUserByID", mock.Anything, tt.user.ID).Return(tt.user, nil)
+			tt.mockSetup(repo)
+			uc := usecase.NewTagUseCase(repo, userRepo)
+
+			_, err := uc.RenameTag(context.Background(), tt.user.ID, tt.from, tt.to)
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// ThumbnailSize - наибольшая сторона миниатюры в пикселях
+	ThumbnailSize = 320
+	// ThumbnailContentType - формат миниатюр
+	ThumbnailContentType = "image/png"
+	// maxImagePixels защищает от изображений, которые при декодировании займут
+	// слишком много памяти
+	maxImagePixels = 40_000_000
+)
+
+// makeThumbnail возвращает миниатюру изображения в PNG и размеры оригинала.
+// Изображения не больше ThumbnailSize не увеличиваются.
+func makeThumbnail(data []byte) (thumbnail []byte, width, height int, err error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, ErrUnsupportedMediaType
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, 0, 0, ErrUnsupportedMediaType
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, 0, 0, ErrAttachmentTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, ErrUnsupportedMediaType
+	}
+
+	w, h := thumbnailSize(cfg.Width, cfg.Height)
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, 0, 0, fmt.Errorf("encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), cfg.Width, cfg.Height, nil
+}
+
+// thumbnailSize вписывает width x height в квадрат ThumbnailSize с сохранением пропорций
+func thumbnailSize(width, height int) (int, int) {
+	if width <= ThumbnailSize && height <= ThumbnailSize {
+		return width, height
+	}
+	if width >= height {
+		return ThumbnailSize, max(1, height*ThumbnailSize/width)
+	}
+	return max(1, width*ThumbnailSize/height), ThumbnailSize
+}
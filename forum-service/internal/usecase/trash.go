@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+var (
+	ErrInvalidTrashTarget = errors.New("invalid target_type: use post or comment")
+	ErrTrashItemNotFound  = errors.New("content not found in trash")
+)
+
+type TrashRepository interface {
+	ListTrash(ctx context.Context, q entity.TrashQuery) ([]*entity.TrashItem, error)
+	RestoreFromTrash(ctx context.Context, entry *entity.ModerationLogEntry) error
+	PurgeDeleted(ctx context.Context, before time.Time) (entity.PurgeResult, error)
+}
+
+type TrashUseCase interface {
+	ListTrash(ctx context.Context, moderatorID int, params entity.TrashParams) (*entity.TrashPage, error)
+	Restore(ctx context.Context, moderatorID int, target entity.ReportTarget, targetID int) (*entity.ModerationLogEntry, error)
+	PurgeExpired(ctx context.Context) (entity.PurgeResult, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// TrashService хранит удаленные посты и комментарии retention, после чего
+// удаляет их окончательно
+type TrashService struct {
+	repo      TrashRepository
+	userRepo  UserRepository
+	retention time.Duration
+	now       func() time.Time
+}
+
+func NewTrashUseCase(repo TrashRepository, userRepo UserRepository, retention time.Duration) *TrashService {
+	return &TrashService{
+		repo:      repo,
+		userRepo:  userRepo,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// ListTrash возвращает страницу корзины, недавно удаленное первым
+func (s *TrashService) ListTrash(ctx context.Context, moderatorID int, params entity.TrashParams) (*entity.TrashPage, error) {
+	if err := requireModerator(ctx, s.userRepo, moderatorID); err != nil {
+		return nil, err
+	}
+	if params.TargetType != "" && !validTrashTarget(params.TargetType) {
+		return nil, ErrInvalidTrashTarget
+	}
+	limit := moderationPageSize(params.Limit)
+	query := entity.TrashQuery{TargetType: params.TargetType, Limit: limit + 1}
+	if params.Cursor != "" {
+		cursor, err := decodeTrashCursor(params.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		query.After = cursor
+	}
+
+	items, err := s.repo.ListTrash(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	page := &entity.TrashPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		last := page.Items[limit-1]
+		page.NextCursor = encodeTrashCursor(&entity.TrashCursor{
+			DeletedAt:  last.DeletedAt,
+			TargetType: last.TargetType,
+			TargetID:   last.TargetID,
+		})
+	}
+	if page.Items == nil {
+		page.Items = []*entity.TrashItem{}
+	}
+	for _, item := range page.Items {
+		item.Content.Excerpt = excerpt(item.Content.Excerpt, ReportExcerptLength)
+		item.PurgeAt = item.DeletedAt.Add(s.retention)
+	}
+	return page, nil
+}
+
+// Restore возвращает пост или комментарий из корзины и записывает это в журнал
+// модерации. Комментарий удаленного поста восстанавливается только вместе с постом.
+func (s *TrashService) Restore(ctx context.Context, moderatorID int, target entity.ReportTarget, targetID int) (*entity.ModerationLogEntry, error) {
+	if err := requireModerator(ctx, s.userRepo, moderatorID); err != nil {
+		return nil, err
+	}
+	if !validTrashTarget(target) {
+		return nil, ErrInvalidTrashTarget
+	}
+
+	entry := &entity.ModerationLogEntry{
+		ModeratorID: &moderatorID,
+		Action:      entity.ModerationRestore,
+		TargetType:  target,
+		TargetID:    targetID,
+	}
+	err := s.repo.RestoreFromTrash(ctx, entry)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTrashItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// PurgeExpired окончательно удаляет то, что пролежало в корзине дольше retention
+func (s *TrashService) PurgeExpired(ctx context.Context) (entity.PurgeResult, error) {
+	return s.repo.PurgeDeleted(ctx, s.now().Add(-s.retention))
+}
+
+// Run очищает корзину сразу и затем каждые interval, пока ctx не отменен
+func (s *TrashService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		result, err := s.PurgeExpired(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to purge trash: %v", err)
+		}
+		if result.Posts > 0 || result.Comments > 0 {
+			log.Printf("[INFO] Purged %d posts and %d comments from trash", result.Posts, result.Comments)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func validTrashTarget(target entity.ReportTarget) bool {
+	return target == entity.ReportOnPost || target == entity.ReportOnComment
+}
+
+func encodeTrashCursor(cursor *entity.TrashCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeTrashCursor(s string) (*entity.TrashCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var cursor entity.TrashCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.TargetID <= 0 || !validTrashTarget(cursor.TargetType) {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
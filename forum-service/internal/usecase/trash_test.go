@@ -0,0 +1,171 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTrashRepository struct {
+	mock.Mock
+}
+
+func (m *MockTrashRepository) ListTrash(ctx context.Context, q entity.TrashQuery) ([]*entity.TrashItem, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.TrashItem), args.Error(1)
+}
+
+func (m *MockTrashRepository) RestoreFromTrash(ctx context.Context, entry *entity.ModerationLogEntry) error {
+	return m.Called(ctx, entry).Error(0)
+}
+
+func (m *MockTrashRepository) PurgeDeleted(ctx context.Context, before time.Time) (entity.PurgeResult, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(entity.PurgeResult), args.Error(1)
+}
+
+const trashRetention = 30 * 24 * time.Hour
+
+func newTrashUseCase() (usecase.TrashUseCase, *MockTrashRepository, *MockUserRepository) {
+	repo := new(MockTrashRepository)
+	userRepo := new(MockUserRepository)
+	return usecase.NewTrashUseCase(repo, userRepo, trashRetention), repo, userRepo
+}
+
+func TestTrashUseCase_ListTrash(t *testing.T) {
+	deletedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("PageWithCursor", func(t *testing.T) {
+		uc, repo, userRepo := newTrashUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+		items := []*entity.TrashItem{
+			{TargetType: entity.ReportOnPost, TargetID: 4, DeletedAt: deletedAt,
+				Content: entity.ReportedContent{Excerpt: strings.Repeat("x", usecase.ReportExcerptLength+10)}},
+			{TargetType: entity.ReportOnComment, TargetID: 7, DeletedAt: deletedAt.Add(-time.Minute)},
+			{TargetType: entity.ReportOnPost, TargetID: 2, DeletedAt: deletedAt.Add(-time.Hour)},
+		}
+		repo.On("ListTrash", mock.Anything, entity.TrashQuery{Limit: 3}).Return(items, nil).Once()
+
+		page, err := uc.ListTrash(context.Background(), 9, entity.TrashParams{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 2)
+		assert.Equal(t, usecase.ReportExcerptLength+1, len([]rune(page.Items[0].Content.Excerpt)))
+		assert.Equal(t, deletedAt.Add(trashRetention), page.Items[0].PurgeAt)
+		require.NotEmpty(t, page.NextCursor)
+
+		// Курсор ведет на элементы после последнего на странице
+		after := &entity.TrashCursor{DeletedAt: deletedAt.Add(-time.Minute), TargetType: entity.ReportOnComment, TargetID: 7}
+		repo.On("ListTrash", mock.Anything, mock.MatchedBy(func(q entity.TrashQuery) bool {
+			return q.After != nil && q.After.DeletedAt.Equal(after.DeletedAt) &&
+				q.After.TargetType == after.TargetType && q.After.TargetID == after.TargetID && q.Limit == 3
+		})).Return(items[2:], nil).Once()
+
+		page, err = uc.ListTrash(context.Background(), 9, entity.TrashParams{Cursor: page.NextCursor, Limit: 2})
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 1)
+		assert.Empty(t, page.NextCursor)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		uc, repo, userRepo := newTrashUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+		repo.On("ListTrash", mock.Anything, entity.TrashQuery{TargetType: entity.ReportOnComment, Limit: usecase.DefaultModerationPageSize + 1}).Return(nil, nil)
+
+		page, err := uc.ListTrash(context.Background(), 9, entity.TrashParams{TargetType: entity.ReportOnComment})
+		require.NoError(t, err)
+		assert.NotNil(t, page.Items)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("NotModerator", func(t *testing.T) {
+		uc, repo, userRepo := newTrashUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2, Role: entity.RoleUser}, nil)
+
+		_, err := uc.ListTrash(context.Background(), 2, entity.TrashParams{})
+		assert.ErrorIs(t, err, usecase.ErrModeratorRequired)
+		repo.AssertNotCalled(t, "ListTrash", mock.Anything, mock.Anything)
+	})
+
+	t.Run("InvalidTarget", func(t *testing.T) {
+		uc, _, userRepo := newTrashUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+
+		_, err := uc.ListTrash(context.Background(), 9, entity.TrashParams{TargetType: entity.ReportOnChatMessage})
+		assert.ErrorIs(t, err, usecase.ErrInvalidTrashTarget)
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		uc, _, userRepo := newTrashUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+
+		_, err := uc.ListTrash(context.Background(), 9, entity.TrashParams{Cursor: "abc"})
+		assert.ErrorIs(t, err, usecase.ErrInvalidCursor)
+	})
+}
+
+func TestTrashUseCase_Restore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		uc, repo, userRepo := newTrashUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+		repo.On("RestoreFromTrash", mock.Anything, mock.MatchedBy(func(e *entity.ModerationLogEntry) bool {
+			return *e.ModeratorID == 9 && e.Action == entity.ModerationRestore &&
+				e.TargetType == entity.ReportOnComment && e.TargetID == 5
+		})).Return(nil)
+
+		entry, err := uc.Restore(context.Background(), 9, entity.ReportOnComment, 5)
+		require.NoError(t, err)
+		assert.Equal(t, entity.ModerationRestore, entry.Action)
+	})
+
+	t.Run("NotInTrash", func(t *testing.T) {
+		uc, repo, userRepo := newTrashUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+		repo.On("RestoreFromTrash", mock.Anything, mock.Anything).Return(fmt.Errorf("failed to restore post 3: %w", sql.ErrNoRows))
+
+		_, err := uc.Restore(context.Background(), 9, entity.ReportOnPost, 3)
+		assert.ErrorIs(t, err, usecase.ErrTrashItemNotFound)
+	})
+
+	t.Run("InvalidTarget", func(t *testing.T) {
+		uc, repo, userRepo := newTrashUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 9).Return(moderatorUser, nil)
+
+		_, err := uc.Restore(context.Background(), 9, entity.ReportOnChatMessage, 3)
+		assert.ErrorIs(t, err, usecase.ErrInvalidTrashTarget)
+		repo.AssertNotCalled(t, "RestoreFromTrash", mock.Anything, mock.Anything)
+	})
+
+	t.Run("NotModerator", func(t *testing.T) {
+		uc, repo, userRepo := newTrashUseCase()
+		userRepo.On("GetUserByID", mock.Anything, 2).Return(&entity.User{ID: 2, Role: entity.RoleUser}, nil)
+
+		_, err := uc.Restore(context.Background(), 2, entity.ReportOnPost, 3)
+		assert.ErrorIs(t, err, usecase.ErrModeratorRequired)
+		repo.AssertNotCalled(t, "RestoreFromTrash", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTrashUseCase_PurgeExpired(t *testing.T) {
+	uc, repo, _ := newTrashUseCase()
+	start := time.Now()
+	repo.On("PurgeDeleted", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		return !before.Before(start.Add(-trashRetention)) && !before.After(time.Now().Add(-trashRetention))
+	})).Return(entity.PurgeResult{Posts: 1, Comments: 3}, nil)
+
+	result, err := uc.PurgeExpired(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, entity.PurgeResult{Posts: 1, Comments: 3}, result)
+}
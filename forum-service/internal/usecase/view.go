@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+type PostViewRepository interface {
+	AddPostViews(ctx context.Context, views []entity.PostViews) error
+	PrunePostViews(ctx context.Context, before time.Time) (int64, error)
+}
+
+// DefaultMaxViewers - сколько пар пост-читатель помнится для отсева повторов
+const DefaultMaxViewers = 100000
+
+type ViewUseCase interface {
+	RecordView(postID int, viewer string)
+	Flush(ctx context.Context) error
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// ViewConfig - DedupWindow - в течение какого времени повторный просмотр того же
+// читателя не засчитывается, Retention - сколько хранятся почасовые просмотры
+// (не меньше недели, самого длинного периода most_viewed), MaxViewers - сколько
+// читателей помнится одновременно
+type ViewConfig struct {
+	DedupWindow time.Duration
+	Retention   time.Duration
+	MaxViewers  int
+}
+
+type viewKey struct {
+	postID int
+	viewer string
+}
+
+// viewEntry - когда читатель последний раз засчитан
+type viewEntry struct {
+	key viewKey
+	at  time.Time
+}
+
+type viewBucket struct {
+	postID int
+	hour   time.Time
+}
+
+// ViewService считает просмотры постов в памяти и периодически пишет их в базу
+// одной пачкой, чтобы просмотр не вызывал запись. Повторы отсеиваются в пределах
+// одного экземпляра сервиса.
+type ViewService struct {
+	repo PostViewRepository
+	cfg  ViewConfig
+	now  func() time.Time
+
+	mu      sync.Mutex
+	seen    map[viewKey]*list.Element
+	order   *list.List // *viewEntry, давно засчитанные впереди
+	pending map[viewBucket]int
+}
+
+func NewViewUseCase(repo PostViewRepository, cfg ViewConfig) *ViewService {
+	if week := entity.TopWindowWeek.Duration() + time.Hour; cfg.Retention < week {
+		cfg.Retention = week
+	}
+	if cfg.MaxViewers <= 0 {
+		cfg.MaxViewers = DefaultMaxViewers
+	}
+	return &ViewService{
+		repo:    repo,
+		cfg:     cfg,
+		now:     time.Now,
+		seen:    make(map[viewKey]*list.Element),
+		order:   list.New(),
+		pending: make(map[viewBucket]int),
+	}
+}
+
+// RecordView засчитывает просмотр поста читателем viewer - пользователем или
+// отпечатком анонимного клиента, если он не смотрел пост последние DedupWindow
+func (s *ViewService) RecordView(postID int, viewer string) {
+	now := s.now()
+	key := viewKey{postID: postID, viewer: viewer}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.seen[key]; ok {
+		entry := el.Value.(*viewEntry)
+		if now.Sub(entry.at) < s.cfg.DedupWindow {
+			return
+		}
+		entry.at = now
+		s.order.MoveToBack(el)
+	} else {
+		// Отпечаток анонима задает клиент. Когда читателей набралось MaxViewers,
+		// забывается засчитанный раньше всех, чтобы смена User-Agent не раздувала память
+		if s.order.Len() >= s.cfg.MaxViewers {
+			s.forget(s.order.Front())
+		}
+		s.seen[key] = s.order.PushBack(&viewEntry{key: key, at: now})
+	}
+	s.pending[viewBucket{postID: postID, hour: now.Truncate(time.Hour)}]++
+}
+
+// Flush записывает накопленные просмотры и забывает читателей, чье окно истекло.
+// Если запись не удалась, просмотры остаются в буфере до следующей попытки.
+func (s *ViewService) Flush(ctx context.Context) error {
+	now := s.now()
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[viewBucket]int)
+	for el := s.order.Front(); el != nil && now.Sub(el.Value.(*viewEntry).at) >= s.cfg.DedupWindow; el = s.order.Front() {
+		s.forget(el)
+	}
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	views := make([]entity.PostViews, 0, len(pending))
+	for bucket, n := range pending {
+		views = append(views, entity.PostViews{PostID: bucket.postID, Hour: bucket.hour, Views: n})
+	}
+	if err := s.repo.AddPostViews(ctx, views); err != nil {
+		s.mu.Lock()
+		for bucket, n := range pending {
+			s.pending[bucket] += n
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// forget убирает читателя из памяти, вызывается под s.mu
+func (s *ViewService) forget(el *list.Element) {
+	delete(s.seen, el.Value.(*viewEntry).key)
+	s.order.Remove(el)
+}
+
+// Run пишет просмотры каждые interval и удаляет устаревшие почасовые счетчики,
+// пока ctx не отменен. Остаток буфера при остановке пишется вызовом Flush.
+func (s *ViewService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := s.Flush(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to flush post views: %v", err)
+		}
+		if _, err := s.repo.PrunePostViews(ctx, s.now().Add(-s.cfg.Retention)); err != nil && ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to prune post views: %v", err)
+		}
+	}
+}
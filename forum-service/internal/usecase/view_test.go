@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeViewRepository запоминает записанные пачки просмотров
+type fakeViewRepository struct {
+	batches [][]entity.PostViews
+	err     error
+}
+
+func (r *fakeViewRepository) AddPostViews(ctx context.Context, views []entity.PostViews) error {
+	if r.err != nil {
+		return r.err
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].PostID != views[j].PostID {
+			return views[i].PostID < views[j].PostID
+		}
+		return views[i].Hour.Before(views[j].Hour)
+	})
+	r.batches = append(r.batches, views)
+	return nil
+}
+
+func (r *fakeViewRepository) PrunePostViews(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newTestViewService(repo PostViewRepository, now *time.Time) *ViewService {
+	s := NewViewUseCase(repo, ViewConfig{DedupWindow: 30 * time.Minute})
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestViewService_DeduplicatesAndBuffers(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 50, 0, 0, time.UTC)
+	repo := &fakeViewRepository{}
+	s := newTestViewService(repo, &now)
+
+	s.RecordView(1, "user:2")
+	s.RecordView(1, "user:2") // повтор в окне не засчитывается
+	s.RecordView(1, "anon:abc")
+	s.RecordView(2, "user:2")
+	assert.Empty(t, repo.batches, "просмотры не пишутся до Flush")
+
+	// Через окно тот же читатель засчитывается снова, уже в следующий час
+	now = now.Add(31 * time.Minute)
+	s.RecordView(1, "user:2")
+
+	require.NoError(t, s.Flush(context.Background()))
+	require.Len(t, repo.batches, 1)
+	hour := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, []entity.PostViews{
+		{PostID: 1, Hour: hour, Views: 2},
+		{PostID: 1, Hour: hour.Add(time.Hour), Views: 1},
+		{PostID: 2, Hour: hour, Views: 1},
+	}, repo.batches[0])
+
+	// Пустой буфер не вызывает запись
+	require.NoError(t, s.Flush(context.Background()))
+	assert.Len(t, repo.batches, 1)
+}
+
+func TestViewService_FlushForgetsExpiredViewers(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestViewService(&fakeViewRepository{}, &now)
+
+	s.RecordView(1, "user:2")
+	now = now.Add(10 * time.Minute)
+	s.RecordView(1, "user:3")
+	now = now.Add(25 * time.Minute)
+	require.NoError(t, s.Flush(context.Background()))
+
+	assert.Len(t, s.seen, 1)
+	assert.Contains(t, s.seen, viewKey{postID: 1, viewer: "user:3"})
+}
+
+func TestViewService_MaxViewers(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeViewRepository{}
+	s := NewViewUseCase(repo, ViewConfig{DedupWindow: 30 * time.Minute, MaxViewers: 2})
+	s.now = func() time.Time { return now }
+
+	s.RecordView(1, "user:2")
+	now = now.Add(time.Minute)
+	s.RecordView(1, "anon:a")
+	now = now.Add(time.Minute)
+	// Память заполнена: новый читатель вытесняет засчитанного раньше всех и
+	// сам засчитывается один раз
+	for i := 0; i < 3; i++ {
+		s.RecordView(1, "anon:b")
+	}
+	assert.Len(t, s.seen, 2)
+	assert.NotContains(t, s.seen, viewKey{postID: 1, viewer: "user:2"})
+	assert.Contains(t, s.seen, viewKey{postID: 1, viewer: "anon:b"})
+
+	// Повторный просмотр обновляет место читателя в очереди на вытеснение
+	now = now.Add(29 * time.Minute)
+	s.RecordView(1, "anon:a")
+	s.RecordView(1, "anon:c")
+	assert.Len(t, s.seen, 2)
+	assert.NotContains(t, s.seen, viewKey{postID: 1, viewer: "anon:b"})
+
+	require.NoError(t, s.Flush(context.Background()))
+	require.Len(t, repo.batches, 1)
+	assert.Equal(t, []entity.PostViews{{PostID: 1, Hour: now.Truncate(time.Hour), Views: 5}}, repo.batches[0])
+}
+
+func TestViewService_FlushKeepsViewsOnError(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeViewRepository{err: errors.New("db down")}
+	s := newTestViewService(repo, &now)
+
+	s.RecordView(1, "user:2")
+	assert.Error(t, s.Flush(context.Background()))
+	s.RecordView(1, "user:3")
+
+	repo.err = nil
+	require.NoError(t, s.Flush(context.Background()))
+	require.Len(t, repo.batches, 1)
+	assert.Equal(t, []entity.PostViews{{PostID: 1, Hour: now, Views: 2}}, repo.batches[0])
+}
+
+func TestNewViewUseCase_MinimumRetention(t *testing.T) {
+	s := NewViewUseCase(&fakeViewRepository{}, ViewConfig{Retention: time.Hour})
+	assert.GreaterOrEqual(t, s.cfg.Retention, entity.TopWindowWeek.Duration())
+	assert.Equal(t, DefaultMaxViewers, s.cfg.MaxViewers)
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+)
+
+// AllowedReactions - эмодзи, которыми можно реагировать на посты и комментарии
+var AllowedReactions = []string{"👍", "👎", "😄", "🎉", "😕", "❤️", "🚀", "👀"}
+
+var (
+	ErrInvalidVote        = errors.New("vote value must be 1 or -1")
+	ErrInvalidReaction    = errors.New("reaction is not allowed")
+	ErrVoteTargetNotFound = errors.New("vote target not found")
+)
+
+type VoteRepository interface {
+	Vote(ctx context.Context, userID int, target entity.VoteTarget, value int) (*entity.VoteState, error)
+	ToggleReaction(ctx context.Context, userID int, target entity.VoteTarget, emoji string) (*entity.VoteState, error)
+	GetViewerFeedback(ctx context.Context, userID int, kind entity.TargetKind, ids []int) (map[int]int, map[int][]string, error)
+}
+
+type VoteUseCase interface {
+	Vote(ctx context.Context, userID int, target entity.VoteTarget, value int) (*entity.VoteState, error)
+	ToggleReaction(ctx context.Context, userID int, target entity.VoteTarget, emoji string) (*entity.VoteState, error)
+	AllowedReactions() []string
+	AnnotatePosts(ctx context.Context, viewerID int, posts ...*entity.Post) error
+	AnnotateComments(ctx context.Context, viewerID int, comments []*entity.Comment) error
+}
+
+type VoteService struct {
+	repo VoteRepository
+}
+
+func NewVoteUseCase(repo VoteRepository) VoteUseCase {
+	return &VoteService{repo: repo}
+}
+
+// Vote переключает голос пользователя: повтор того же значения снимает голос,
+// противоположное значение меняет его
+func (s *VoteService) Vote(ctx context.Context, userID int, target entity.VoteTarget, value int) (*entity.VoteState, error) {
+	if value != 1 && value != -1 {
+		return nil, ErrInvalidVote
+	}
+	state, err := s.repo.Vote(ctx, userID, target, value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrVoteTargetNotFound
+	}
+	return state, err
+}
+
+// ToggleReaction ставит реакцию emoji или снимает ее, если она уже стоит
+func (s *VoteService) ToggleReaction(ctx context.Context, userID int, target entity.VoteTarget, emoji string) (*entity.VoteState, error) {
+	if !isAllowedReaction(emoji) {
+		return nil, ErrInvalidReaction
+	}
+	state, err := s.repo.ToggleReaction(ctx, userID, target, emoji)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrVoteTargetNotFound
+	}
+	return state, err
+}
+
+func (s *VoteService) AllowedReactions() []string {
+	return append([]string(nil), AllowedReactions...)
+}
+
+// AnnotatePosts заполняет MyVote и MyReactions постов для пользователя viewerID
+func (s *VoteService) AnnotatePosts(ctx context.Context, viewerID int, posts ...*entity.Post) error {
+	if viewerID == 0 || len(posts) == 0 {
+		return nil
+	}
+	ids := make([]int, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+	votes, reactions, err := s.repo.GetViewerFeedback(ctx, viewerID, entity.TargetPost, ids)
+	if err != nil {
+		return err
+	}
+	for _, post := range posts {
+		post.MyVote = votes[post.ID]
+		post.MyReactions = reactions[post.ID]
+	}
+	return nil
+}
+
+// AnnotateComments заполняет MyVote и MyReactions комментариев, включая вложенные ответы
+func (s *VoteService) AnnotateComments(ctx context.Context, viewerID int, comments []*entity.Comment) error {
+	if viewerID == 0 || len(comments) == 0 {
+		return nil
+	}
+	var all []*entity.Comment
+	var collect func(list []*entity.Comment)
+	collect = func(list []*entity.Comment) {
+		for _, comment := range list {
+			all = append(all, comment)
+			collect(comment.Replies)
+		}
+	}
+	collect(comments)
+
+	ids := make([]int, 0, len(all))
+	for _, comment := range all {
+		ids = append(ids, comment.ID)
+	}
+	votes, reactions, err := s.repo.GetViewerFeedback(ctx, viewerID, entity.TargetComment, ids)
+	if err != nil {
+		return err
+	}
+	for _, comment := range all {
+		comment.MyVote = votes[comment.ID]
+		comment.MyReactions = reactions[comment.ID]
+	}
+	return nil
+}
+
+func isAllowedReaction(emoji string) bool {
+	for _, allowed := range AllowedReactions {
+		if emoji == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/entity"
+	"github.com/lera-guryan2222/fooorum/forum-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockVoteRepository struct {
+	mock.Mock
+}
+
+func (m *MockVoteRepository) Vote(ctx context.Context, userID int, target entity.VoteTarget, value int) (*entity.VoteState, error) {
+	args := m.Called(ctx, userID, target, value)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.VoteState), args.Error(1)
+}
+
+func (m *MockVoteRepository) ToggleReaction(ctx context.Context, userID int, target entity.VoteTarget, emoji string) (*entity.VoteState, error) {
+	args := m.Called(ctx, userID, target, emoji)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.VoteState), args.Error(1)
+}
+
+func (m *MockVoteRepository) GetViewerFeedback(ctx context.Context, userID int, kind entity.TargetKind, ids []int) (map[int]int, map[int][]string, error) {
+	args := m.Called(ctx, userID, kind, ids)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(map[int]int), args.Get(1).(map[int][]string), args.Error(2)
+}
+
+func TestVoteUseCase_Vote(t *testing.T) {
+	target := entity.VoteTarget{Kind: entity.TargetPost, ID: 1}
+
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockVoteRepository)
+		uc := usecase.NewVoteUseCase(repo)
+		state := &entity.VoteState{Score: 1, MyVote: 1}
+		repo.On("Vote", mock.Anything, 2, target, 1).Return(state, nil)
+
+		got, err := uc.Vote(context.Background(), 2, target, 1)
+		require.NoError(t, err)
+		assert.Equal(t, state, got)
+	})
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		repo := new(MockVoteRepository)
+		uc := usecase.NewVoteUseCase(repo)
+
+		for _, value := range []int{0, 2, -5} {
+			_, err := uc.Vote(context.Background(), 2, target, value)
+			assert.ErrorIs(t, err, usecase.ErrInvalidVote)
+		}
+		repo.AssertNotCalled(t, "Vote")
+	})
+
+	t.Run("TargetNotFound", func(t *testing.T) {
+		repo := new(MockVoteRepository)
+		uc := usecase.NewVoteUseCase(repo)
+		repo.On("Vote", mock.Anything, 2, target, -1).Return(nil, fmt.Errorf("lock: %w", sql.ErrNoRows))
+
+		_, err := uc.Vote(context.Background(), 2, target, -1)
+		assert.ErrorIs(t, err, usecase.ErrVoteTargetNotFound)
+	})
+}
+
+func TestVoteUseCase_ToggleReaction(t *testing.T) {
+	target := entity.VoteTarget{Kind: entity.TargetComment, ID: 5, PostID: 1}
+
+	t.Run("Allowed", func(t *testing.T) {
+		repo := new(MockVoteRepository)
+		uc := usecase.NewVoteUseCase(repo)
+		state := &entity.VoteState{Reactions: map[string]int{"🚀": 1}, MyReactions: []string{"🚀"}}
+		repo.On("ToggleReaction", mock.Anything, 2, target, "🚀").Return(state, nil)
+
+		got, err := uc.ToggleReaction(context.Background(), 2, target, "🚀")
+		require.NoError(t, err)
+		assert.Equal(t, state, got)
+	})
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		repo := new(MockVoteRepository)
+		uc := usecase.NewVoteUseCase(repo)
+
+		_, err := uc.ToggleReaction(context.Background(), 2, target, "🍕")
+		assert.ErrorIs(t, err, usecase.ErrInvalidReaction)
+		repo.AssertNotCalled(t, "ToggleReaction")
+	})
+}
+
+func TestVoteUseCase_AnnotateComments(t *testing.T) {
+	repo := new(MockVoteRepository)
+	uc := usecase.NewVoteUseCase(repo)
+	reply := &entity.Comment{ID: 3}
+	comments := []*entity.Comment{{ID: 1, Replies: []*entity.Comment{reply}}, {ID: 2}}
+	repo.On("GetViewerFeedback", mock.Anything, 9, entity.TargetComment, []int{1, 3, 2}).
+		Return(map[int]int{1: 1, 3: -1}, map[int][]string{2: {"👀"}}, nil)
+
+	require.NoError(t, uc.AnnotateComments(context.Background(), 9, comments))
+	assert.Equal(t, 1, comments[0].MyVote)
+	assert.Equal(t, -1, reply.MyVote)
+	assert.Equal(t, 0, comments[1].MyVote)
+	assert.Equal(t, []string{"👀"}, comments[1].MyReactions)
+}
+
+func TestVoteUseCase_AnnotatePostsAnonymous(t *testing.T) {
+	repo := new(MockVoteRepository)
+	uc := usecase.NewVoteUseCase(repo)
+
+	require.NoError(t, uc.AnnotatePosts(context.Background(), 0, &entity.Post{ID: 1}))
+	repo.AssertNotCalled(t, "GetViewerFeedback")
+}
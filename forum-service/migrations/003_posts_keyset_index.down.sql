@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_posts_user_id_created_at;
+DROP INDEX IF EXISTS idx_posts_created_at_id;
+CREATE INDEX IF NOT EXISTS idx_posts_created_at ON posts(created_at DESC);
@@ -0,0 +1,4 @@
+-- Keyset pagination orders by (created_at, id), so the index must cover the tie-breaker.
+DROP INDEX IF EXISTS idx_posts_created_at;
+CREATE INDEX IF NOT EXISTS idx_posts_created_at_id ON posts(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_posts_user_id_created_at ON posts(user_id, created_at);
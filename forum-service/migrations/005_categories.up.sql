@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    parent_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT,
+    read_only BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_position ON categories(parent_id, position);
+
+-- Existing posts are moved into a default category so that category_id can be required.
+INSERT INTO categories (slug, name, description)
+VALUES ('general', 'General', 'General discussion')
+ON CONFLICT (slug) DO NOTHING;
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS category_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT;
+UPDATE posts SET category_id = (SELECT id FROM categories WHERE slug = 'general') WHERE category_id IS NULL;
+ALTER TABLE posts ALTER COLUMN category_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_posts_category_created_at_id ON posts(category_id, created_at, id);
@@ -0,0 +1,9 @@
+-- Replies point at their parent comment; depth is stored to enforce the nesting limit
+-- without walking the chain on every insert.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES comments(id);
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS depth INTEGER NOT NULL DEFAULT 0;
+-- A deleted comment that still has replies is kept as a "[deleted]" placeholder.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_comments_parent_created_at_id ON comments(parent_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_comments_post_roots ON comments(post_id, created_at, id) WHERE parent_id IS NULL;
@@ -0,0 +1,13 @@
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP WITH TIME ZONE;
+
+-- Each row keeps the content a comment had before an edit, together with who
+-- replaced it and when. The current content stays in comments.
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id SERIAL PRIMARY KEY,
+    comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    edited_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    edited_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_revisions_comment_id ON comment_revisions(comment_id, edited_at, id);
@@ -0,0 +1,18 @@
+-- Every saved version of a post's title and content, numbered per post from 1.
+-- The latest revision always matches the current row in posts.
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id SERIAL PRIMARY KEY,
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    rev INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    editor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    summary VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (post_id, rev)
+);
+
+-- Existing posts start their history with what they contain now.
+INSERT INTO post_revisions (post_id, rev, title, content, editor_id, created_at)
+SELECT id, 1, title, content, user_id, created_at FROM posts
+ON CONFLICT (post_id, rev) DO NOTHING;
@@ -0,0 +1,3 @@
+-- Incremented on every update; clients send it back in If-Match so that
+-- concurrent edits are detected instead of silently overwriting each other.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
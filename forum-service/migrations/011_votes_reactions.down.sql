@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS reactions;
+DROP TABLE IF EXISTS votes;
+ALTER TABLE comments DROP COLUMN IF EXISTS reactions;
+ALTER TABLE comments DROP COLUMN IF EXISTS score;
+ALTER TABLE posts DROP COLUMN IF EXISTS reactions;
+ALTER TABLE posts DROP COLUMN IF EXISTS score;
@@ -0,0 +1,33 @@
+-- Denormalized aggregates, kept in step with votes and reactions in the same transaction.
+-- reactions maps an emoji to the number of users who reacted with it.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS score INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS reactions JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS score INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS reactions JSONB NOT NULL DEFAULT '{}';
+
+-- A vote or reaction targets either a post or a comment. Separate nullable foreign keys
+-- keep the cascades working; the partial unique indexes allow one vote per user per
+-- target and one reaction per user, target and emoji.
+CREATE TABLE IF NOT EXISTS votes (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id INTEGER REFERENCES posts(id) ON DELETE CASCADE,
+    comment_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+    value SMALLINT NOT NULL CHECK (value IN (-1, 1)),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((post_id IS NULL) <> (comment_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_votes_user_post ON votes(user_id, post_id) WHERE post_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_votes_user_comment ON votes(user_id, comment_id) WHERE comment_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS reactions (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id INTEGER REFERENCES posts(id) ON DELETE CASCADE,
+    comment_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+    emoji VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((post_id IS NULL) <> (comment_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reactions_user_post ON reactions(user_id, post_id, emoji) WHERE post_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reactions_user_comment ON reactions(user_id, comment_id, emoji) WHERE comment_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_votes_post_created;
+DROP TABLE IF EXISTS post_rankings;
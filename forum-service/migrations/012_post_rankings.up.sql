@@ -0,0 +1,16 @@
+-- Precomputed ranking scores for the hot, top and rising feeds, refreshed by a background
+-- job. Posts without a row yet rank as 0 until the next refresh.
+CREATE TABLE IF NOT EXISTS post_rankings (
+    post_id INTEGER PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    hot DOUBLE PRECISION NOT NULL DEFAULT 0,
+    top DOUBLE PRECISION NOT NULL DEFAULT 0,
+    rising DOUBLE PRECISION NOT NULL DEFAULT 0,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_rankings_hot ON post_rankings(hot DESC, post_id DESC);
+CREATE INDEX IF NOT EXISTS idx_post_rankings_top ON post_rankings(top DESC, post_id DESC);
+CREATE INDEX IF NOT EXISTS idx_post_rankings_rising ON post_rankings(rising DESC, post_id DESC);
+
+-- Recent-vote lookups of the refresh job; comments are covered by idx_comments_post_id.
+CREATE INDEX IF NOT EXISTS idx_votes_post_created ON votes(post_id, created_at) WHERE post_id IS NOT NULL;
@@ -0,0 +1,26 @@
+-- user_blocks lists whom a user has blocked: their @mentions of the user are ignored.
+CREATE TABLE IF NOT EXISTS user_blocks (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, blocked_user_id),
+    CHECK (user_id <> blocked_user_id)
+);
+
+-- A mention of user_id in exactly one post, comment or chat message. The partial
+-- unique indexes keep one row per mentioned user per source.
+CREATE TABLE IF NOT EXISTS mentions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    author_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    post_id INTEGER REFERENCES posts(id) ON DELETE CASCADE,
+    comment_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+    chat_message_id INTEGER REFERENCES chat_messages(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (num_nonnulls(post_id, comment_id, chat_message_id) = 1)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mentions_post ON mentions(post_id, user_id) WHERE post_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mentions_comment ON mentions(comment_id, user_id) WHERE comment_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mentions_chat_message ON mentions(chat_message_id, user_id) WHERE chat_message_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_mentions_user_id ON mentions(user_id);
@@ -0,0 +1,15 @@
+-- format is the markup of content; content_html caches its rendered, sanitized
+-- HTML and is refreshed by the service on every create and update.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS format VARCHAR(16) NOT NULL DEFAULT 'plain';
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS content_html TEXT NOT NULL DEFAULT '';
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS format VARCHAR(16) NOT NULL DEFAULT 'plain';
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS content_html TEXT NOT NULL DEFAULT '';
+
+-- Existing rows are plain text. This mirrors markup.RenderPlain: escape the
+-- characters html.EscapeString escapes, then turn newlines into <br>.
+UPDATE posts SET content_html = '<p>' || replace(replace(replace(replace(replace(replace(
+    content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '''', '&#39;'), '"', '&#34;'), E'\n', '<br>') || '</p>'
+WHERE content_html = '';
+UPDATE comments SET content_html = '<p>' || replace(replace(replace(replace(replace(replace(
+    content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '''', '&#39;'), '"', '&#34;'), E'\n', '<br>') || '</p>'
+WHERE content_html = '';
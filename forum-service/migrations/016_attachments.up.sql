@@ -0,0 +1,25 @@
+-- Uploaded files. An attachment belongs to at most one post, comment or chat
+-- message; deleting the owner unlinks it and the orphan collector removes the
+-- row and its blobs later, as it does for uploads that were never attached.
+CREATE TABLE IF NOT EXISTS attachments (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blob_key VARCHAR(255) NOT NULL UNIQUE,
+    thumbnail_key VARCHAR(255),
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    width INTEGER,
+    height INTEGER,
+    post_id INTEGER REFERENCES posts(id) ON DELETE SET NULL,
+    comment_id INTEGER REFERENCES comments(id) ON DELETE SET NULL,
+    chat_message_id INTEGER REFERENCES chat_messages(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (num_nonnulls(post_id, comment_id, chat_message_id) <= 1)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_post_id ON attachments(post_id) WHERE post_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_attachments_comment_id ON attachments(comment_id) WHERE comment_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_attachments_chat_message_id ON attachments(chat_message_id) WHERE chat_message_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_attachments_orphaned ON attachments(created_at)
+    WHERE post_id IS NULL AND comment_id IS NULL AND chat_message_id IS NULL;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS user_bans;
+DROP TABLE IF EXISTS reports;
+DROP TABLE IF EXISTS moderation_log;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS note;
+ALTER TABLE chat_messages DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS hidden_at;
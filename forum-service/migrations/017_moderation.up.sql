@@ -0,0 +1,55 @@
+-- Content hidden by a moderator stays in place but is no longer shown.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP WITH TIME ZONE;
+
+-- Warnings and bans carry the moderator's note.
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS note TEXT NOT NULL DEFAULT '';
+
+-- Every resolution of reports. Targets are not foreign keys: the log outlives
+-- the content it describes.
+CREATE TABLE IF NOT EXISTS moderation_log (
+    id SERIAL PRIMARY KEY,
+    moderator_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('dismiss', 'hide', 'delete', 'warn', 'ban')),
+    target_type VARCHAR(16) NOT NULL,
+    target_id INTEGER NOT NULL,
+    target_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT NOT NULL DEFAULT '',
+    report_count INTEGER NOT NULL DEFAULT 0,
+    ban_expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_log_target_user ON moderation_log(target_user_id, id DESC);
+
+-- A user's report on a post, comment or chat message. target_user_id is the
+-- author at report time, so the author can still be sanctioned after the content
+-- is gone. A user has at most one open report per target.
+CREATE TABLE IF NOT EXISTS reports (
+    id SERIAL PRIMARY KEY,
+    reporter_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_type VARCHAR(16) NOT NULL CHECK (target_type IN ('post', 'comment', 'chat_message')),
+    target_id INTEGER NOT NULL,
+    target_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    reason VARCHAR(32) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved')),
+    resolution_id INTEGER REFERENCES moderation_log(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_open_reporter ON reports(reporter_id, target_type, target_id)
+    WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_reports_open_target ON reports(target_type, target_id, id)
+    WHERE status = 'open';
+
+-- A banned user cannot write; expires_at is NULL for a permanent ban.
+CREATE TABLE IF NOT EXISTS user_bans (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    banned_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE
+);
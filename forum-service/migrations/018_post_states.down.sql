@@ -0,0 +1,7 @@
+DELETE FROM moderation_log WHERE action IN ('pin', 'unpin', 'lock', 'unlock', 'archive', 'unarchive');
+ALTER TABLE moderation_log DROP CONSTRAINT IF EXISTS moderation_log_action_check;
+ALTER TABLE moderation_log ADD CONSTRAINT moderation_log_action_check
+    CHECK (action IN ('dismiss', 'hide', 'delete', 'warn', 'ban'));
+
+ALTER TABLE posts DROP COLUMN IF EXISTS pinned;
+ALTER TABLE posts DROP COLUMN IF EXISTS state;
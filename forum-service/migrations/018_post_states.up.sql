@@ -0,0 +1,12 @@
+-- Moderators pin announcements to the top of listings, lock threads against new
+-- comments and archive old threads as read-only.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS state VARCHAR(16) NOT NULL DEFAULT 'open'
+    CHECK (state IN ('open', 'locked', 'archived'));
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- State changes are recorded in the moderation log next to report resolutions.
+ALTER TABLE moderation_log DROP CONSTRAINT IF EXISTS moderation_log_action_check;
+ALTER TABLE moderation_log ADD CONSTRAINT moderation_log_action_check CHECK (action IN (
+    'dismiss', 'hide', 'delete', 'warn', 'ban',
+    'pin', 'unpin', 'lock', 'unlock', 'archive', 'unarchive'
+));
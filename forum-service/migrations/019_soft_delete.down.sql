@@ -0,0 +1,25 @@
+DELETE FROM moderation_log WHERE action = 'restore';
+ALTER TABLE moderation_log DROP CONSTRAINT IF EXISTS moderation_log_action_check;
+ALTER TABLE moderation_log ADD CONSTRAINT moderation_log_action_check CHECK (action IN (
+    'dismiss', 'hide', 'delete', 'warn', 'ban',
+    'pin', 'unpin', 'lock', 'unlock', 'archive', 'unarchive'
+));
+
+-- Without the trash deleted content is gone for good; placeholders lose their text.
+DELETE FROM posts WHERE deleted_at IS NOT NULL;
+DELETE FROM comments WHERE deleted_at IS NOT NULL AND NOT placeholder;
+UPDATE comments SET content = '', content_html = '' WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_parent_id_fkey;
+ALTER TABLE comments ADD CONSTRAINT comments_parent_id_fkey
+    FOREIGN KEY (parent_id) REFERENCES comments(id);
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_post_id_fkey;
+ALTER TABLE comments ADD CONSTRAINT comments_post_id_fkey
+    FOREIGN KEY (post_id) REFERENCES posts(id);
+
+DROP INDEX IF EXISTS idx_comments_deleted_at;
+DROP INDEX IF EXISTS idx_posts_deleted_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS placeholder;
+ALTER TABLE comments DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE posts DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE posts DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,29 @@
+-- Deleted posts and comments go to the trash: they stay in place, filtered out of
+-- every read, until moderators restore them or the purge job removes them.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS deleted_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS deleted_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+-- A deleted comment stays in its thread as a "[deleted]" placeholder while it has
+-- live replies below it. Until now only such comments were kept.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS placeholder BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE comments SET placeholder = TRUE WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_posts_deleted_at ON posts(deleted_at, id) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_deleted_at ON comments(deleted_at, id) WHERE deleted_at IS NOT NULL;
+
+-- The comments table may have been created by the auth-service migration, which
+-- has no cascades; purging a post takes its comments, purging a comment its replies.
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_post_id_fkey;
+ALTER TABLE comments ADD CONSTRAINT comments_post_id_fkey
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE;
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_parent_id_fkey;
+ALTER TABLE comments ADD CONSTRAINT comments_parent_id_fkey
+    FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE;
+
+-- Restores from the trash are recorded in the moderation log.
+ALTER TABLE moderation_log DROP CONSTRAINT IF EXISTS moderation_log_action_check;
+ALTER TABLE moderation_log ADD CONSTRAINT moderation_log_action_check CHECK (action IN (
+    'dismiss', 'hide', 'delete', 'warn', 'ban',
+    'pin', 'unpin', 'lock', 'unlock', 'archive', 'unarchive',
+    'restore'
+));
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_posts_unpublished;
+DROP INDEX IF EXISTS idx_posts_publish_at;
+
+-- Unpublished posts were never shown and have nowhere to go.
+DELETE FROM posts WHERE status <> 'published';
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_publish_at_check;
+ALTER TABLE posts DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS status;
@@ -0,0 +1,11 @@
+-- Authors save unfinished posts as drafts or schedule them; only published posts
+-- are shown to others. publish_at is set exactly while a post is scheduled.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'scheduled', 'published'));
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE posts ADD CONSTRAINT posts_publish_at_check
+    CHECK ((status = 'scheduled') = (publish_at IS NOT NULL));
+
+-- The publisher picks due posts; authors list their own unpublished ones.
+CREATE INDEX IF NOT EXISTS idx_posts_publish_at ON posts (publish_at) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_posts_unpublished ON posts (user_id, id) WHERE status <> 'published';
@@ -0,0 +1,9 @@
+-- updated_at is when a post was published or last edited; feeds report it.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE;
+UPDATE posts p SET updated_at = COALESCE(
+    (SELECT MAX(r.created_at) FROM post_revisions r WHERE r.post_id = p.id AND r.created_at > p.created_at),
+    p.created_at,
+    CURRENT_TIMESTAMP
+);
+ALTER TABLE posts ALTER COLUMN updated_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE posts ALTER COLUMN updated_at SET NOT NULL;
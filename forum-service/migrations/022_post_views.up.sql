@@ -0,0 +1,12 @@
+-- view_count is the total of deduplicated views of a post; post_views keeps
+-- hourly counts for the most viewed listing over a window and is pruned.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS view_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS post_views (
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    hour TIMESTAMP WITH TIME ZONE NOT NULL,
+    views INTEGER NOT NULL,
+    PRIMARY KEY (post_id, hour)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_views_hour ON post_views (hour);
@@ -0,0 +1,16 @@
+// Package migrations contains the SQL migrations for the forum-service schema,
+// embedded into the binary via embed.FS.
+//
+// The forum tables reference the users table owned by auth-service, so the
+// auth-service migrations must be applied first and the forum-service ones
+// second. Rolling back goes the other way: forum-service down before
+// auth-service down.
+package migrations
+
+import "embed"
+
+// FS holds the migration files in golang-migrate format
+// (<version>_<name>.up.sql / <version>_<name>.down.sql).
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,35 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Pinger is implemented by anything that can verify its connection, e.g. *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// DBChecker checks that the database answers a ping.
+func DBChecker(db Pinger) Checker {
+	return CheckerFunc(db.PingContext)
+}
+
+// GRPCChecker asks a remote grpc.health.v1 service whether the given service
+// ("" for the whole server) is SERVING.
+func GRPCChecker(conn grpc.ClientConnInterface, service string) Checker {
+	client := healthpb.NewHealthClient(conn)
+	return CheckerFunc(func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return err
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("remote status %s", resp.GetStatus())
+		}
+		return nil
+	})
+}
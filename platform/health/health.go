@@ -0,0 +1,208 @@
+// Package health implements liveness/readiness probes backed by named checkers
+// and keeps the standard grpc.health.v1 service in sync with their results.
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// ErrShuttingDown is reported by readiness while the instance is draining.
+var ErrShuttingDown = errors.New("instance is shutting down")
+
+// Checker verifies one dependency of the service.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a plain function to the Checker interface.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// CheckResult is the outcome of a single checker.
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report aggregates the results of all registered checkers.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Healthy reports whether every check passed.
+func (r Report) Healthy() bool {
+	return r.Status == StatusUp
+}
+
+// Health runs the registered checkers and publishes their aggregate state
+// over HTTP and the gRPC health service.
+type Health struct {
+	mu       sync.RWMutex
+	checkers map[string]Checker
+	timeout  time.Duration
+	services []string
+	grpc     *grpchealth.Server
+	draining atomic.Bool
+}
+
+// New creates a Health whose checks are bounded by timeout. The given gRPC
+// service names get their serving status updated together with the overall "" entry.
+func New(timeout time.Duration, services ...string) *Health {
+	h := &Health{
+		checkers: make(map[string]Checker),
+		timeout:  timeout,
+		services: append([]string{""}, services...),
+		grpc:     grpchealth.NewServer(),
+	}
+	h.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return h
+}
+
+// Register adds a named checker. Registering the same name twice replaces the checker.
+func (h *Health) Register(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checkers[name] = checker
+}
+
+// GRPCServer returns the grpc.health.v1 implementation to register on a gRPC server.
+func (h *Health) GRPCServer() healthpb.HealthServer {
+	return h.grpc
+}
+
+// Check runs all checkers concurrently and updates the gRPC serving status.
+func (h *Health) Check(ctx context.Context) Report {
+	h.mu.RLock()
+	names := make([]string, 0, len(h.checkers))
+	for name := range h.checkers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checkers := make([]Checker, len(names))
+	for i, name := range names {
+		checkers[i] = h.checkers[name]
+	}
+	h.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	results := make([]CheckResult, len(names))
+	var wg sync.WaitGroup
+	for i := range checkers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, checkers[i])
+		}(i)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(names))}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	if h.draining.Load() {
+		report.Status = StatusDown
+		report.Checks["shutdown"] = CheckResult{Status: StatusDown, Error: ErrShuttingDown.Error()}
+	}
+
+	if report.Healthy() {
+		h.setServingStatus(healthpb.HealthCheckResponse_SERVING)
+	} else {
+		h.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return report
+}
+
+// Watch re-runs the checks every interval until ctx is cancelled so that
+// gRPC health clients see state changes without polling /readyz.
+func (h *Health) Watch(ctx context.Context, interval time.Duration) {
+	h.Check(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.Check(ctx)
+		}
+	}
+}
+
+// Drain marks the instance as not ready so load balancers stop routing to it.
+// Liveness is unaffected.
+func (h *Health) Drain() {
+	h.draining.Store(true)
+	h.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+// Shutdown drains the instance and freezes the gRPC health status.
+func (h *Health) Shutdown() {
+	h.Drain()
+	h.grpc.Shutdown()
+}
+
+// LivenessHandler answers /livez: the process is up and serving HTTP.
+func (h *Health) LivenessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": StatusUp})
+	}
+}
+
+// ReadinessHandler answers /readyz with per-check details and 503 when any check fails.
+func (h *Health) ReadinessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := h.Check(c.Request.Context())
+		status := http.StatusOK
+		if !report.Healthy() {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
+}
+
+func (h *Health) setServingStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	if status == healthpb.HealthCheckResponse_SERVING && h.draining.Load() {
+		return
+	}
+	for _, service := range h.services {
+		h.grpc.SetServingStatus(service, status)
+	}
+}
+
+func runCheck(ctx context.Context, checker Checker) CheckResult {
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := CheckResult{Status: StatusUp, Duration: time.Since(start).String()}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
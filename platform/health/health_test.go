@@ -0,0 +1,149 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func okChecker() Checker {
+	return CheckerFunc(func(ctx context.Context) error { return nil })
+}
+
+func failingChecker(msg string) Checker {
+	return CheckerFunc(func(ctx context.Context) error { return errors.New(msg) })
+}
+
+func grpcStatus(t *testing.T, h *Health, service string) healthpb.HealthCheckResponse_ServingStatus {
+	resp, err := h.GRPCServer().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return resp.GetStatus()
+}
+
+func TestHealth_Check(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkers   map[string]Checker
+		wantStatus string
+		wantGRPC   healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{
+			name:       "AllUp",
+			checkers:   map[string]Checker{"postgres": okChecker(), "chat_hub": okChecker()},
+			wantStatus: StatusUp,
+			wantGRPC:   healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			name:       "OneDown",
+			checkers:   map[string]Checker{"postgres": failingChecker("connection refused"), "chat_hub": okChecker()},
+			wantStatus: StatusDown,
+			wantGRPC:   healthpb.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			name:       "NoCheckers",
+			checkers:   map[string]Checker{},
+			wantStatus: StatusUp,
+			wantGRPC:   healthpb.HealthCheckResponse_SERVING,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(time.Second, "user.UserService")
+			for name, c := range tt.checkers {
+				h.Register(name, c)
+			}
+
+			report := h.Check(context.Background())
+			assert.Equal(t, tt.wantStatus, report.Status)
+			assert.Len(t, report.Checks, len(tt.checkers))
+			assert.Equal(t, tt.wantGRPC, grpcStatus(t, h, ""))
+			assert.Equal(t, tt.wantGRPC, grpcStatus(t, h, "user.UserService"))
+		})
+	}
+}
+
+func TestHealth_CheckTimeout(t *testing.T) {
+	h := New(10 * time.Millisecond)
+	h.Register("slow", CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	report := h.Check(context.Background())
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+}
+
+func TestHealth_Drain(t *testing.T) {
+	h := New(time.Second)
+	h.Register("postgres", okChecker())
+	h.Drain()
+
+	report := h.Check(context.Background())
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, ErrShuttingDown.Error(), report.Checks["shutdown"].Error)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, grpcStatus(t, h, ""))
+}
+
+func TestHealth_Handlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := New(time.Second)
+	h.Register("postgres", failingChecker("connection refused"))
+
+	router := gin.New()
+	router.GET("/livez", h.LivenessHandler())
+	router.GET("/readyz", h.ReadinessHandler())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, "connection refused", report.Checks["postgres"].Error)
+}
+
+func TestGRPCChecker(t *testing.T) {
+	remote := New(time.Second)
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, remote.GRPCServer())
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	checker := GRPCChecker(conn, "")
+	assert.Error(t, checker.Check(context.Background()))
+
+	remote.Check(context.Background())
+	assert.NoError(t, checker.Check(context.Background()))
+}
@@ -0,0 +1,170 @@
+// Package lifecycle coordinates background goroutines and the ordered
+// teardown of servers and connections on shutdown.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Logger is the subset of the service logger used by the manager.
+type Logger interface {
+	Infow(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+type stopHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Manager runs named background tasks and stops registered components
+// in reverse registration order, so that whatever was started last is stopped first.
+type Manager struct {
+	log     Logger
+	timeout time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	errCh  chan error
+
+	mu    sync.Mutex
+	hooks []stopHook
+	once  sync.Once
+}
+
+// New creates a Manager whose whole shutdown sequence is bounded by timeout.
+func New(log Logger, timeout time.Duration) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		log:     log,
+		timeout: timeout,
+		ctx:     ctx,
+		cancel:  cancel,
+		errCh:   make(chan error, 1),
+	}
+}
+
+// Context is cancelled once shutdown begins.
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// Go starts fn in a goroutine with the manager context. A non-nil error
+// returned before shutdown triggers the shutdown of the whole service.
+func (m *Manager) Go(name string, fn func(ctx context.Context) error) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		if err := fn(m.ctx); err != nil && m.ctx.Err() == nil {
+			select {
+			case m.errCh <- fmt.Errorf("%s: %w", name, err):
+			default:
+			}
+		}
+	}()
+}
+
+// OnStop registers a teardown step. Steps run in reverse registration order.
+// Background tasks keep running until the StopTasks step, so resources they use
+// must be registered before it.
+func (m *Manager) OnStop(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, stopHook{name: name, fn: fn})
+}
+
+// Wait blocks until ctx is done (usually on SIGINT/SIGTERM) or a background
+// task fails, then shuts everything down. It returns the task error, if any,
+// joined with the shutdown errors.
+func (m *Manager) Wait(ctx context.Context) error {
+	var cause error
+	select {
+	case <-ctx.Done():
+		m.log.Infow("shutdown requested")
+	case cause = <-m.errCh:
+		m.log.Errorw("background task failed, shutting down", "error", cause)
+	}
+	return errors.Join(cause, m.Shutdown())
+}
+
+// StopTasks cancels the manager context and waits for the tasks started with Go.
+// Register it with OnStop after the resources the tasks use and before the
+// servers, whose Serve loops return only once they are stopped. Tasks still
+// running after the last hook are stopped by Shutdown.
+func (m *Manager) StopTasks(ctx context.Context) error {
+	m.cancel()
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown runs the stop hooks in reverse order and then stops the remaining
+// background tasks. It is safe to call more than once.
+func (m *Manager) Shutdown() error {
+	var err error
+	m.once.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+		defer cancel()
+
+		m.mu.Lock()
+		hooks := m.hooks
+		m.mu.Unlock()
+
+		var errs []error
+		for i := len(hooks) - 1; i >= 0; i-- {
+			hook := hooks[i]
+			start := time.Now()
+			if hookErr := hook.fn(ctx); hookErr != nil {
+				m.log.Errorw("shutdown step failed", "step", hook.name, "error", hookErr)
+				errs = append(errs, fmt.Errorf("%s: %w", hook.name, hookErr))
+				continue
+			}
+			m.log.Infow("shutdown step completed", "step", hook.name, "duration", time.Since(start))
+		}
+
+		if tasksErr := m.StopTasks(ctx); tasksErr != nil {
+			errs = append(errs, fmt.Errorf("background tasks: %w", tasksErr))
+		}
+		err = errors.Join(errs...)
+	})
+	return err
+}
+
+// GRPCServer is the part of *grpc.Server needed to stop it.
+type GRPCServer interface {
+	GracefulStop()
+	Stop()
+}
+
+// StopGRPC returns a stop hook that drains in-flight RPCs and falls back
+// to a hard stop when the shutdown deadline expires.
+func StopGRPC(srv GRPCServer) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			srv.Stop()
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Infow(string, ...interface{})  {}
+func (nopLogger) Errorw(string, ...interface{}) {}
+
+func TestManager_StopOrder(t *testing.T) {
+	m := New(nopLogger{}, time.Second)
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+	m.OnStop("postgres", record("postgres"))
+	m.OnStop("grpc_server", record("grpc_server"))
+	m.OnStop("http_server", record("http_server"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.NoError(t, m.Wait(ctx))
+	assert.Equal(t, []string{"http_server", "grpc_server", "postgres"}, order)
+}
+
+func TestManager_TaskFailureTriggersShutdown(t *testing.T) {
+	m := New(nopLogger{}, time.Second)
+
+	stopped := false
+	m.OnStop("http_server", func(context.Context) error {
+		stopped = true
+		return nil
+	})
+	m.Go("watcher", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	m.Go("grpc_server", func(context.Context) error {
+		return errors.New("address already in use")
+	})
+
+	err := m.Wait(context.Background())
+	assert.ErrorContains(t, err, "grpc_server: address already in use")
+	assert.True(t, stopped)
+	assert.Error(t, m.Context().Err())
+}
+
+func TestManager_ShutdownErrorsAndIdempotence(t *testing.T) {
+	m := New(nopLogger{}, time.Second)
+
+	calls := 0
+	m.OnStop("postgres", func(context.Context) error {
+		calls++
+		return errors.New("close failed")
+	})
+
+	assert.ErrorContains(t, m.Shutdown(), "postgres: close failed")
+	assert.NoError(t, m.Shutdown())
+	assert.Equal(t, 1, calls)
+}
+
+func TestManager_StopTasksBeforeResources(t *testing.T) {
+	m := New(nopLogger{}, time.Second)
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+	}
+
+	m.Go("post_views", func(ctx context.Context) error {
+		<-ctx.Done()
+		record("post_views done")
+		return nil
+	})
+	m.OnStop("postgres", func(context.Context) error {
+		record("postgres")
+		return nil
+	})
+	m.OnStop("background_tasks", m.StopTasks)
+	m.OnStop("http_server", func(context.Context) error {
+		record("http_server")
+		return nil
+	})
+
+	assert.NoError(t, m.Shutdown())
+	assert.Equal(t, []string{"http_server", "post_views done", "postgres"}, order)
+}
+
+func TestManager_ShutdownTimeout(t *testing.T) {
+	m := New(nopLogger{}, 20*time.Millisecond)
+	release := make(chan struct{})
+	defer close(release)
+
+	m.Go("stuck", func(context.Context) error {
+		<-release
+		return nil
+	})
+
+	assert.ErrorIs(t, m.Shutdown(), context.DeadlineExceeded)
+}
+
+type fakeGRPCServer struct {
+	block   chan struct{}
+	stopped bool
+}
+
+func (s *fakeGRPCServer) GracefulStop() { <-s.block }
+func (s *fakeGRPCServer) Stop() {
+	s.stopped = true
+	close(s.block)
+}
+
+func TestStopGRPC_FallsBackToStop(t *testing.T) {
+	srv := &fakeGRPCServer{block: make(chan struct{})}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := StopGRPC(srv)(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, srv.stopped)
+}
@@ -1,3 +1,5 @@
+// Package logger wraps zap for the services and logs HTTP requests and unary
+// RPCs. Request log lines carry the trace_id and span_id of the request span.
 package logger
 
 import (
@@ -5,6 +7,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
@@ -85,6 +88,7 @@ func GinLogger(log *Logger) gin.HandlerFunc {
 
 		end := time.Now()
 		latency := end.Sub(start)
+		log := log.WithContext(c.Request.Context())
 
 		if len(c.Errors) > 0 {
 			for _, e := range c.Errors.Errors() {
@@ -115,6 +119,7 @@ func GRPCLoggingInterceptor(log *Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		start := time.Now()
 		resp, err = handler(ctx, req)
+		log := log.WithContext(ctx)
 
 		if err != nil {
 			log.Errorw("gRPC request failed",
@@ -133,12 +138,21 @@ func GRPCLoggingInterceptor(log *Logger) grpc.UnaryServerInterceptor {
 	}
 }
 
+// WithContext adds the request ID and, when ctx carries a span,
+// the trace_id and span_id fields used to correlate logs with traces.
 func (l *Logger) WithContext(ctx context.Context) *Logger {
 	if ctx == nil {
 		return l
 	}
+	var fields []interface{}
 	if reqID, ok := ctx.Value("request_id").(string); ok {
-		return l.With("request_id", reqID)
+		fields = append(fields, "request_id", reqID)
 	}
-	return l
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields, "trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
+	}
+	if len(fields) == 0 {
+		return l
+	}
+	return l.With(fields...)
 }
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// spanContext returns a context carrying a fixed sampled span.
+func spanContext() context.Context {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
+
+func TestLogger_WithContext(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	log := &Logger{zap.New(core).Sugar()}
+	ctx := spanContext()
+
+	log.WithContext(ctx).Info("with span")
+	log.WithContext(context.Background()).Info("without span")
+
+	entries := logs.All()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, map[string]interface{}{
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":  "00f067aa0ba902b7",
+	}, entries[0].ContextMap())
+	assert.Empty(t, entries[1].ContextMap())
+}
+
+func TestGinLogger_TraceFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zap.InfoLevel)
+	router := gin.New()
+	router.Use(GinLogger(&Logger{zap.New(core).Sugar()}))
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil).WithContext(spanContext())
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.All()
+	assert.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", fields["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", fields["span_id"])
+	assert.Equal(t, int64(http.StatusOK), fields["status"])
+}
@@ -0,0 +1,138 @@
+// Package metrics exposes the Prometheus metrics every service reports: HTTP,
+// gRPC and the database pool. Services add their own collectors through
+// Registerer.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics owns a dedicated registry and the collectors shared by the service.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	grpcServerHandled  *prometheus.CounterVec
+	grpcServerDuration *prometheus.HistogramVec
+	grpcClientHandled  *prometheus.CounterVec
+	grpcClientDuration *prometheus.HistogramVec
+}
+
+// New creates the metrics for a service. namespace prefixes every metric name.
+func New(namespace string) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route template and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		grpcServerHandled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_server_handled_total",
+			Help:      "Unary RPCs completed on the server by method and status code.",
+		}, []string{"method", "code"}),
+		grpcServerDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_server_handling_seconds",
+			Help:      "Unary RPC latency on the server by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		grpcClientHandled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_client_handled_total",
+			Help:      "Unary RPCs completed by the client by method and status code.",
+		}, []string{"method", "code"}),
+		grpcClientDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_client_handling_seconds",
+			Help:      "Unary RPC latency seen by the client by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.grpcServerHandled,
+		m.grpcServerDuration,
+		m.grpcClientHandled,
+		m.grpcClientDuration,
+	)
+	return m
+}
+
+// Registerer allows other packages to add their own collectors.
+func (m *Metrics) Registerer() prometheus.Registerer {
+	return m.registry
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() gin.HandlerFunc {
+	h := promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+	return gin.WrapH(h)
+}
+
+// GinMiddleware records request count and latency labelled by the route
+// template (e.g. /posts/:id) so that path parameters do not blow up cardinality.
+func (m *Metrics) GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		code := strconv.Itoa(c.Writer.Status())
+		m.httpRequests.WithLabelValues(c.Request.Method, route, code).Inc()
+		m.httpDuration.WithLabelValues(c.Request.Method, route, code).Observe(time.Since(start).Seconds())
+	}
+}
+
+// UnaryServerInterceptor records server-side RPC metrics.
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.grpcServerHandled.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		m.grpcServerDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+// UnaryClientInterceptor records client-side RPC metrics.
+func (m *Metrics) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		m.grpcClientHandled.WithLabelValues(method, status.Code(err).String()).Inc()
+		m.grpcClientDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// RegisterDBStats exports the database/sql connection pool statistics.
+func (m *Metrics) RegisterDBStats(db *sql.DB, dbName string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
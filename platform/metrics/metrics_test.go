@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	router := gin.New()
+	router.GET("/metrics", m.Handler())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestMetrics_GinMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := New("forum")
+
+	router := gin.New()
+	router.Use(m.GinMiddleware())
+	router.GET("/posts/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/posts/1", "/posts/2", "/missing"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `forum_http_requests_total{method="GET",route="/posts/:id",status="200"} 2`)
+	assert.Contains(t, body, `forum_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `forum_http_request_duration_seconds_count{method="GET",route="/posts/:id",status="200"} 2`)
+}
+
+func TestMetrics_GRPCInterceptors(t *testing.T) {
+	m := New("forum")
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/post.PostService/GetPostWithAuthor"}
+	_, _ = m.UnaryServerInterceptor()(context.Background(), nil, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "post not found")
+		})
+
+	_ = m.UnaryClientInterceptor()(context.Background(), "/user.UserService/GetUsername", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return nil
+		})
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `forum_grpc_server_handled_total{code="NotFound",method="/post.PostService/GetPostWithAuthor"} 1`)
+	assert.Contains(t, body, `forum_grpc_client_handled_total{code="OK",method="/user.UserService/GetUsername"} 1`)
+}
+
+func TestMetrics_InterceptorKeepsError(t *testing.T) {
+	m := New("forum")
+	wantErr := errors.New("boom")
+
+	err := m.UnaryClientInterceptor()(context.Background(), "/user.UserService/GetUsername", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return wantErr
+		})
+	assert.ErrorIs(t, err, wantErr)
+	assert.Contains(t, scrape(t, m), `forum_grpc_client_handled_total{code="Unknown",method="/user.UserService/GetUsername"} 1`)
+}
@@ -0,0 +1,131 @@
+// Package tracing configures OpenTelemetry with W3C trace-context propagation
+// and provides the HTTP, gRPC and database instrumentation used by the service.
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/XSAM/otelsql"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"google.golang.org/grpc"
+)
+
+// Supported exporters.
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterNone   = "none"
+)
+
+// Config selects where spans are sent.
+type Config struct {
+	// Exporter is one of otlp, stdout or none. With none spans are still
+	// created and propagated to downstream services, but never exported.
+	Exporter string `yaml:"exporter"`
+	// Endpoint is the OTLP/gRPC collector address, e.g. localhost:4317.
+	Endpoint string `yaml:"endpoint"`
+	// SampleRatio is the fraction of new traces that are sampled.
+	// Traces started upstream follow the caller's sampling decision.
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// NewExporter builds the span exporter described by cfg.
+// It returns a nil exporter for ExporterNone.
+func NewExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		return otlptracegrpc.New(ctx,
+			otlptracegrpc.WithEndpoint(cfg.Endpoint),
+			otlptracegrpc.WithInsecure(),
+		)
+	case ExporterStdout:
+		return stdouttrace.New()
+	case ExporterNone, "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+}
+
+// NewProvider creates a tracer provider for serviceName. Tests pass an
+// in-memory exporter from go.opentelemetry.io/otel/sdk/trace/tracetest.
+func NewProvider(serviceName string, exporter sdktrace.SpanExporter, sampleRatio float64) *sdktrace.TracerProvider {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+// Setup installs the global tracer provider and the W3C trace-context and
+// baggage propagators. The returned function flushes and stops the provider.
+func Setup(ctx context.Context, serviceName string, cfg Config) (func(context.Context) error, error) {
+	exporter, err := NewExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	tp := NewProvider(serviceName, exporter, cfg.SampleRatio)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	return tp.Shutdown, nil
+}
+
+// untracedPaths are polled by infrastructure and would only add noise.
+var untracedPaths = map[string]bool{
+	"/livez":   true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// GinMiddleware starts a server span per request, continuing the trace from
+// an incoming traceparent header. It must run before the request logger.
+func GinMiddleware(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName,
+		otelgin.WithFilter(func(r *http.Request) bool {
+			return !untracedPaths[r.URL.Path]
+		}),
+	)
+}
+
+// ServerOption instruments a gRPC server and extracts the caller's trace context.
+func ServerOption() grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler())
+}
+
+// DialOption instruments a gRPC client and injects the trace context into
+// outgoing metadata.
+func DialOption() grpc.DialOption {
+	return grpc.WithStatsHandler(otelgrpc.NewClientHandler())
+}
+
+// OpenDB opens a database/sql pool whose queries are recorded as client spans
+// under the span found in the query context.
+func OpenDB(driverName, dsn string) (*sql.DB, error) {
+	return otelsql.Open(driverName, dsn,
+		otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:  true,
+			OmitConnPrepare: true,
+			OmitRows:        true,
+		}),
+	)
+}
@@ -0,0 +1,116 @@
+package tracing
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// setupTest installs a provider backed by an in-memory exporter.
+func setupTest(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := NewProvider("auth-service-test", exporter, 1)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return exporter
+}
+
+func flush(t *testing.T) {
+	tp, ok := otel.GetTracerProvider().(interface{ ForceFlush(context.Context) error })
+	require.True(t, ok)
+	require.NoError(t, tp.ForceFlush(context.Background()))
+}
+
+func TestNewExporter(t *testing.T) {
+	exporter, err := NewExporter(context.Background(), Config{Exporter: ExporterNone})
+	assert.NoError(t, err)
+	assert.Nil(t, exporter)
+
+	exporter, err = NewExporter(context.Background(), Config{Exporter: ExporterStdout})
+	assert.NoError(t, err)
+	assert.NotNil(t, exporter)
+
+	_, err = NewExporter(context.Background(), Config{Exporter: "jaeger"})
+	assert.EqualError(t, err, `unknown trace exporter "jaeger"`)
+}
+
+func TestGinMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	exporter := setupTest(t)
+
+	var handlerSpan trace.SpanContext
+	router := gin.New()
+	router.Use(GinMiddleware("auth-service"))
+	router.GET("/auth/validate", func(c *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	router.GET("/readyz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/validate", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	flush(t)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "/auth/validate", spans[0].Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+	assert.Equal(t, spans[0].SpanContext.SpanID(), handlerSpan.SpanID())
+}
+
+func TestGRPCPropagation(t *testing.T) {
+	exporter := setupTest(t)
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(ServerOption())
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		DialOption(),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "GetUsername")
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	parent.End()
+	flush(t)
+
+	spans := exporter.GetSpans()
+	byKind := map[trace.SpanKind]tracetest.SpanStub{}
+	for _, s := range spans {
+		assert.Equal(t, parent.SpanContext().TraceID(), s.SpanContext.TraceID())
+		byKind[s.SpanKind] = s
+	}
+	client, server := byKind[trace.SpanKindClient], byKind[trace.SpanKindServer]
+	assert.Equal(t, "grpc.health.v1.Health/Check", client.Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), client.Parent.SpanID())
+	assert.Equal(t, client.SpanContext.SpanID(), server.Parent.SpanID())
+}